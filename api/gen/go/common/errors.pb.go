@@ -70,6 +70,12 @@ const (
 	ErrorCode_DATA_INVALID    ErrorCode = 10503
 	ErrorCode_DATA_DUPLICATE  ErrorCode = 10504
 	ErrorCode_DATA_CONSTRAINT ErrorCode = 10505
+	// 订阅相关错误 (10600-10699)
+	ErrorCode_SUBSCRIPTION_NOT_FOUND ErrorCode = 10601
+	ErrorCode_SUBSCRIPTION_EXPIRED   ErrorCode = 10602
+	ErrorCode_SUBSCRIPTION_SUSPENDED ErrorCode = 10603
+	ErrorCode_QUOTA_EXCEEDED         ErrorCode = 10604
+	ErrorCode_FEATURE_DISABLED       ErrorCode = 10605
 	// 系统相关错误 (19900-19999)
 	ErrorCode_SYSTEM_ERROR        ErrorCode = 19901
 	ErrorCode_SERVICE_UNAVAILABLE ErrorCode = 19902
@@ -118,54 +124,64 @@ var (
 		10503: "DATA_INVALID",
 		10504: "DATA_DUPLICATE",
 		10505: "DATA_CONSTRAINT",
+		10601: "SUBSCRIPTION_NOT_FOUND",
+		10602: "SUBSCRIPTION_EXPIRED",
+		10603: "SUBSCRIPTION_SUSPENDED",
+		10604: "QUOTA_EXCEEDED",
+		10605: "FEATURE_DISABLED",
 		19901: "SYSTEM_ERROR",
 		19902: "SERVICE_UNAVAILABLE",
 		19903: "DATABASE_ERROR",
 		19904: "NETWORK_ERROR",
 	}
 	ErrorCode_value = map[string]int32{
-		"SUCCESS":               0,
-		"USER_NOT_FOUND":        10001,
-		"USER_ALREADY_EXISTS":   10002,
-		"INVALID_PASSWORD":      10003,
-		"USER_DISABLED":         10004,
-		"USER_DELETED":          10005,
-		"TENANT_NOT_FOUND":      10101,
-		"TENANT_ALREADY_EXISTS": 10102,
-		"TENANT_DISABLED":       10103,
-		"TENANT_PENDING":        10104,
-		"TENANT_REJECTED":       10105,
-		"PERMISSION_DENIED":     10201,
-		"ROLE_NOT_FOUND":        10202,
-		"ROLE_DISABLED":         10203,
-		"PERMISSION_NOT_FOUND":  10204,
-		"INVALID_CREDENTIALS":   10301,
-		"TOKEN_EXPIRED":         10302,
-		"TOKEN_INVALID":         10303,
-		"TOKEN_REVOKED":         10304,
-		"ACCOUNT_LOCKED":        10305,
-		"AUTH_HEADER_MISSING":   10306,
-		"AUTH_HEADER_INVALID":   10307,
-		"AUTH_SERVICE_ERROR":    10308,
-		"USER_TYPE_UNDEFINED":   10309,
-		"ACCESS_FORBIDDEN":      10310,
-		"TENANT_MISSING":        10311,
-		"TENANT_INVALID":        10312,
-		"REGISTER_FAILED":       10313,
-		"INVALID_PARAMETER":     10401,
-		"MISSING_PARAMETER":     10402,
-		"INVALID_FORMAT":        10403,
-		"INVALID_EMAIL":         10404,
-		"INVALID_PHONE":         10405,
-		"DATA_NOT_FOUND":        10501,
-		"DATA_CONFLICT":         10502,
-		"DATA_INVALID":          10503,
-		"DATA_DUPLICATE":        10504,
-		"DATA_CONSTRAINT":       10505,
-		"SYSTEM_ERROR":          19901,
-		"SERVICE_UNAVAILABLE":   19902,
-		"DATABASE_ERROR":        19903,
-		"NETWORK_ERROR":         19904,
+		"SUCCESS":                0,
+		"USER_NOT_FOUND":         10001,
+		"USER_ALREADY_EXISTS":    10002,
+		"INVALID_PASSWORD":       10003,
+		"USER_DISABLED":          10004,
+		"USER_DELETED":           10005,
+		"TENANT_NOT_FOUND":       10101,
+		"TENANT_ALREADY_EXISTS":  10102,
+		"TENANT_DISABLED":        10103,
+		"TENANT_PENDING":         10104,
+		"TENANT_REJECTED":        10105,
+		"PERMISSION_DENIED":      10201,
+		"ROLE_NOT_FOUND":         10202,
+		"ROLE_DISABLED":          10203,
+		"PERMISSION_NOT_FOUND":   10204,
+		"INVALID_CREDENTIALS":    10301,
+		"TOKEN_EXPIRED":          10302,
+		"TOKEN_INVALID":          10303,
+		"TOKEN_REVOKED":          10304,
+		"ACCOUNT_LOCKED":         10305,
+		"AUTH_HEADER_MISSING":    10306,
+		"AUTH_HEADER_INVALID":    10307,
+		"AUTH_SERVICE_ERROR":     10308,
+		"USER_TYPE_UNDEFINED":    10309,
+		"ACCESS_FORBIDDEN":       10310,
+		"TENANT_MISSING":         10311,
+		"TENANT_INVALID":         10312,
+		"REGISTER_FAILED":        10313,
+		"INVALID_PARAMETER":      10401,
+		"MISSING_PARAMETER":      10402,
+		"INVALID_FORMAT":         10403,
+		"INVALID_EMAIL":          10404,
+		"INVALID_PHONE":          10405,
+		"DATA_NOT_FOUND":         10501,
+		"DATA_CONFLICT":          10502,
+		"DATA_INVALID":           10503,
+		"DATA_DUPLICATE":         10504,
+		"DATA_CONSTRAINT":        10505,
+		"SUBSCRIPTION_NOT_FOUND": 10601,
+		"SUBSCRIPTION_EXPIRED":   10602,
+		"SUBSCRIPTION_SUSPENDED": 10603,
+		"QUOTA_EXCEEDED":         10604,
+		"FEATURE_DISABLED":       10605,
+		"SYSTEM_ERROR":           19901,
+		"SERVICE_UNAVAILABLE":    19902,
+		"DATABASE_ERROR":         19903,
+		"NETWORK_ERROR":          19904,
 	}
 )
 
@@ -372,7 +388,7 @@ const file_common_errors_proto_rawDesc = "" +
 	"\adetails\x18\x05 \x03(\v2\".common.ErrorResponse.DetailsEntryR\adetails\x1a:\n" +
 	"\fDetailsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xac\a\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xad\b\n" +
 	"\tErrorCode\x12\v\n" +
 	"\aSUCCESS\x10\x00\x12\x13\n" +
 	"\x0eUSER_NOT_FOUND\x10\x91N\x12\x18\n" +
@@ -411,7 +427,12 @@ const file_common_errors_proto_rawDesc = "" +
 	"\rDATA_CONFLICT\x10\x86R\x12\x11\n" +
 	"\fDATA_INVALID\x10\x87R\x12\x13\n" +
 	"\x0eDATA_DUPLICATE\x10\x88R\x12\x14\n" +
-	"\x0fDATA_CONSTRAINT\x10\x89R\x12\x12\n" +
+	"\x0fDATA_CONSTRAINT\x10\x89R\x12\x1b\n" +
+	"\x16SUBSCRIPTION_NOT_FOUND\x10\xe9R\x12\x19\n" +
+	"\x14SUBSCRIPTION_EXPIRED\x10\xeaR\x12\x1b\n" +
+	"\x16SUBSCRIPTION_SUSPENDED\x10\xebR\x12\x13\n" +
+	"\x0eQUOTA_EXCEEDED\x10\xecR\x12\x15\n" +
+	"\x10FEATURE_DISABLED\x10\xedR\x12\x12\n" +
 	"\fSYSTEM_ERROR\x10\xbd\x9b\x01\x12\x19\n" +
 	"\x13SERVICE_UNAVAILABLE\x10\xbe\x9b\x01\x12\x14\n" +
 	"\x0eDATABASE_ERROR\x10\xbf\x9b\x01\x12\x13\n" +
@@ -1524,6 +1524,250 @@ func (x *InternalGetSubscriptionStatsResponse) GetMonthPrice() int64 {
 	return 0
 }
 
+// 消耗配额请求
+type InternalConsumeQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantCode    string                 `protobuf:"bytes,1,opt,name=tenant_code,json=tenantCode,proto3" json:"tenant_code,omitempty"`       // 商户code
+	ProductCode   string                 `protobuf:"bytes,2,opt,name=product_code,json=productCode,proto3" json:"product_code,omitempty"`    // 产品编码
+	DimensionKey  string                 `protobuf:"bytes,3,opt,name=dimension_key,json=dimensionKey,proto3" json:"dimension_key,omitempty"` // 维度键
+	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`                                // 消耗数量
+	RequestId     string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`          // 幂等键
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InternalConsumeQuotaRequest) Reset() {
+	*x = InternalConsumeQuotaRequest{}
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InternalConsumeQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InternalConsumeQuotaRequest) ProtoMessage() {}
+
+func (x *InternalConsumeQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InternalConsumeQuotaRequest.ProtoReflect.Descriptor instead.
+func (*InternalConsumeQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_subscribe_v1_subscription_internal_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *InternalConsumeQuotaRequest) GetTenantCode() string {
+	if x != nil {
+		return x.TenantCode
+	}
+	return ""
+}
+
+func (x *InternalConsumeQuotaRequest) GetProductCode() string {
+	if x != nil {
+		return x.ProductCode
+	}
+	return ""
+}
+
+func (x *InternalConsumeQuotaRequest) GetDimensionKey() string {
+	if x != nil {
+		return x.DimensionKey
+	}
+	return ""
+}
+
+func (x *InternalConsumeQuotaRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *InternalConsumeQuotaRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// 消耗配额回复
+type InternalConsumeQuotaResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	QuotaUsage    *InternalQuotaUsageInfo `protobuf:"bytes,1,opt,name=quota_usage,json=quotaUsage,proto3" json:"quota_usage,omitempty"` // 消耗后的配额使用信息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InternalConsumeQuotaResponse) Reset() {
+	*x = InternalConsumeQuotaResponse{}
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InternalConsumeQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InternalConsumeQuotaResponse) ProtoMessage() {}
+
+func (x *InternalConsumeQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InternalConsumeQuotaResponse.ProtoReflect.Descriptor instead.
+func (*InternalConsumeQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_subscribe_v1_subscription_internal_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *InternalConsumeQuotaResponse) GetQuotaUsage() *InternalQuotaUsageInfo {
+	if x != nil {
+		return x.QuotaUsage
+	}
+	return nil
+}
+
+// 释放配额请求
+type InternalReleaseQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantCode    string                 `protobuf:"bytes,1,opt,name=tenant_code,json=tenantCode,proto3" json:"tenant_code,omitempty"`       // 商户code
+	ProductCode   string                 `protobuf:"bytes,2,opt,name=product_code,json=productCode,proto3" json:"product_code,omitempty"`    // 产品编码
+	DimensionKey  string                 `protobuf:"bytes,3,opt,name=dimension_key,json=dimensionKey,proto3" json:"dimension_key,omitempty"` // 维度键
+	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`                                // 释放数量
+	RequestId     string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`          // 幂等键
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InternalReleaseQuotaRequest) Reset() {
+	*x = InternalReleaseQuotaRequest{}
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InternalReleaseQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InternalReleaseQuotaRequest) ProtoMessage() {}
+
+func (x *InternalReleaseQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InternalReleaseQuotaRequest.ProtoReflect.Descriptor instead.
+func (*InternalReleaseQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_subscribe_v1_subscription_internal_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *InternalReleaseQuotaRequest) GetTenantCode() string {
+	if x != nil {
+		return x.TenantCode
+	}
+	return ""
+}
+
+func (x *InternalReleaseQuotaRequest) GetProductCode() string {
+	if x != nil {
+		return x.ProductCode
+	}
+	return ""
+}
+
+func (x *InternalReleaseQuotaRequest) GetDimensionKey() string {
+	if x != nil {
+		return x.DimensionKey
+	}
+	return ""
+}
+
+func (x *InternalReleaseQuotaRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *InternalReleaseQuotaRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// 释放配额回复
+type InternalReleaseQuotaResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	QuotaUsage    *InternalQuotaUsageInfo `protobuf:"bytes,1,opt,name=quota_usage,json=quotaUsage,proto3" json:"quota_usage,omitempty"` // 释放后的配额使用信息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InternalReleaseQuotaResponse) Reset() {
+	*x = InternalReleaseQuotaResponse{}
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InternalReleaseQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InternalReleaseQuotaResponse) ProtoMessage() {}
+
+func (x *InternalReleaseQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscribe_v1_subscription_internal_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InternalReleaseQuotaResponse.ProtoReflect.Descriptor instead.
+func (*InternalReleaseQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_subscribe_v1_subscription_internal_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *InternalReleaseQuotaResponse) GetQuotaUsage() *InternalQuotaUsageInfo {
+	if x != nil {
+		return x.QuotaUsage
+	}
+	return nil
+}
+
 var File_subscribe_v1_subscription_internal_proto protoreflect.FileDescriptor
 
 const file_subscribe_v1_subscription_internal_proto_rawDesc = "" +
@@ -1690,7 +1934,29 @@ const file_subscribe_v1_subscription_internal_proto_rawDesc = "" +
 	"trialCount\x12.\n" +
 	"\x13expiring_soon_count\x18\x03 \x01(\x05R\x11expiringSooncount\x12\x1f\n" +
 	"\vmonth_price\x18\x04 \x01(\x03R\n" +
-	"monthPrice*\x9d\x02\n" +
+	"monthPrice\"\xbd\x01\n" +
+	"\x1bInternalConsumeQuotaRequest\x12\x1f\n" +
+	"\vtenant_code\x18\x01 \x01(\tR\n" +
+	"tenantCode\x12!\n" +
+	"\fproduct_code\x18\x02 \x01(\tR\vproductCode\x12#\n" +
+	"\rdimension_key\x18\x03 \x01(\tR\fdimensionKey\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\"l\n" +
+	"\x1cInternalConsumeQuotaResponse\x12L\n" +
+	"\vquota_usage\x18\x01 \x01(\v2+.api.subscription.v1.InternalQuotaUsageInfoR\n" +
+	"quotaUsage\"\xbd\x01\n" +
+	"\x1bInternalReleaseQuotaRequest\x12\x1f\n" +
+	"\vtenant_code\x18\x01 \x01(\tR\n" +
+	"tenantCode\x12!\n" +
+	"\fproduct_code\x18\x02 \x01(\tR\vproductCode\x12#\n" +
+	"\rdimension_key\x18\x03 \x01(\tR\fdimensionKey\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\"l\n" +
+	"\x1cInternalReleaseQuotaResponse\x12L\n" +
+	"\vquota_usage\x18\x01 \x01(\v2+.api.subscription.v1.InternalQuotaUsageInfoR\n" +
+	"quotaUsage*\x9d\x02\n" +
 	"\x1aInternalSubscriptionStatus\x12,\n" +
 	"(INTERNAL_SUBSCRIPTION_STATUS_UNSPECIFIED\x10\x00\x12'\n" +
 	"#INTERNAL_SUBSCRIPTION_STATUS_ACTIVE\x10\x01\x12&\n" +
@@ -1721,13 +1987,15 @@ const file_subscribe_v1_subscription_internal_proto_rawDesc = "" +
 	"\x1aINTERNAL_ORDER_STATUS_PAID\x10\x02\x12#\n" +
 	"\x1fINTERNAL_ORDER_STATUS_CANCELLED\x10\x03\x12\"\n" +
 	"\x1eINTERNAL_ORDER_STATUS_REFUNDED\x10\x04\x12 \n" +
-	"\x1cINTERNAL_ORDER_STATUS_FAILED\x10\x052\xf0\x05\n" +
+	"\x1cINTERNAL_ORDER_STATUS_FAILED\x10\x052\xea\a\n" +
 	"\x1bSubscriptionInternalService\x12\x8a\x01\n" +
 	"\x19InternalListSubscriptions\x125.api.subscription.v1.InternalListSubscriptionsRequest\x1a6.api.subscription.v1.InternalListSubscriptionsResponse\x12\x8d\x01\n" +
 	"\x1aInternalCreateSubscription\x126.api.subscription.v1.InternalCreateSubscriptionRequest\x1a7.api.subscription.v1.InternalCreateSubscriptionResponse\x12\x8a\x01\n" +
 	"\x19InternalReNewSubscription\x125.api.subscription.v1.InternalReNewSubscriptionRequest\x1a6.api.subscription.v1.InternalReNewSubscriptionResponse\x12\x90\x01\n" +
 	"\x1bInternalUpgradeSubscription\x127.api.subscription.v1.InternalUpgradeSubscriptionRequest\x1a8.api.subscription.v1.InternalUpgradeSubscriptionResponse\x12\x93\x01\n" +
-	"\x1cInternalGetSubscriptionStats\x128.api.subscription.v1.InternalGetSubscriptionStatsRequest\x1a9.api.subscription.v1.InternalGetSubscriptionStatsResponseB\xe5\x01\n" +
+	"\x1cInternalGetSubscriptionStats\x128.api.subscription.v1.InternalGetSubscriptionStatsRequest\x1a9.api.subscription.v1.InternalGetSubscriptionStatsResponse\x12{\n" +
+	"\x14InternalConsumeQuota\x120.api.subscription.v1.InternalConsumeQuotaRequest\x1a1.api.subscription.v1.InternalConsumeQuotaResponse\x12{\n" +
+	"\x14InternalReleaseQuota\x120.api.subscription.v1.InternalReleaseQuotaRequest\x1a1.api.subscription.v1.InternalReleaseQuotaResponseB\xe5\x01\n" +
 	"\x17com.api.subscription.v1B\x19SubscriptionInternalProtoP\x01ZAgithub.com/heyinLab/common/api/gen/go/subscribe/v1;subscriptionv1\xa2\x02\x03ASX\xaa\x02\x13Api.Subscription.V1\xca\x02\x13Api\\Subscription\\V1\xe2\x02\x1fApi\\Subscription\\V1\\GPBMetadata\xea\x02\x15Api::Subscription::V1b\x06proto3"
 
 var (
@@ -1743,7 +2011,7 @@ func file_subscribe_v1_subscription_internal_proto_rawDescGZIP() []byte {
 }
 
 var file_subscribe_v1_subscription_internal_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_subscribe_v1_subscription_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_subscribe_v1_subscription_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_subscribe_v1_subscription_internal_proto_goTypes = []any{
 	(InternalSubscriptionStatus)(0),              // 0: api.subscription.v1.InternalSubscriptionStatus
 	(InternalQuotaType)(0),                       // 1: api.subscription.v1.InternalQuotaType
@@ -1763,60 +2031,70 @@ var file_subscribe_v1_subscription_internal_proto_goTypes = []any{
 	(*InternalUpgradeSubscriptionResponse)(nil),  // 15: api.subscription.v1.InternalUpgradeSubscriptionResponse
 	(*InternalGetSubscriptionStatsRequest)(nil),  // 16: api.subscription.v1.InternalGetSubscriptionStatsRequest
 	(*InternalGetSubscriptionStatsResponse)(nil), // 17: api.subscription.v1.InternalGetSubscriptionStatsResponse
-	(*structpb.Struct)(nil),                      // 18: google.protobuf.Struct
-	(*timestamppb.Timestamp)(nil),                // 19: google.protobuf.Timestamp
-	(*durationpb.Duration)(nil),                  // 20: google.protobuf.Duration
+	(*InternalConsumeQuotaRequest)(nil),          // 18: api.subscription.v1.InternalConsumeQuotaRequest
+	(*InternalConsumeQuotaResponse)(nil),         // 19: api.subscription.v1.InternalConsumeQuotaResponse
+	(*InternalReleaseQuotaRequest)(nil),          // 20: api.subscription.v1.InternalReleaseQuotaRequest
+	(*InternalReleaseQuotaResponse)(nil),         // 21: api.subscription.v1.InternalReleaseQuotaResponse
+	(*structpb.Struct)(nil),                      // 22: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),                // 23: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),                  // 24: google.protobuf.Duration
 }
 var file_subscribe_v1_subscription_internal_proto_depIdxs = []int32{
-	18, // 0: api.subscription.v1.InternalSubscriptionInfo.product_i18n:type_name -> google.protobuf.Struct
-	18, // 1: api.subscription.v1.InternalSubscriptionInfo.plan_i18n:type_name -> google.protobuf.Struct
+	22, // 0: api.subscription.v1.InternalSubscriptionInfo.product_i18n:type_name -> google.protobuf.Struct
+	22, // 1: api.subscription.v1.InternalSubscriptionInfo.plan_i18n:type_name -> google.protobuf.Struct
 	0,  // 2: api.subscription.v1.InternalSubscriptionInfo.status:type_name -> api.subscription.v1.InternalSubscriptionStatus
-	19, // 3: api.subscription.v1.InternalSubscriptionInfo.start_date:type_name -> google.protobuf.Timestamp
-	19, // 4: api.subscription.v1.InternalSubscriptionInfo.end_date:type_name -> google.protobuf.Timestamp
-	19, // 5: api.subscription.v1.InternalSubscriptionInfo.trial_end_date:type_name -> google.protobuf.Timestamp
-	18, // 6: api.subscription.v1.InternalSubscriptionInfo.quota_snapshot:type_name -> google.protobuf.Struct
+	23, // 3: api.subscription.v1.InternalSubscriptionInfo.start_date:type_name -> google.protobuf.Timestamp
+	23, // 4: api.subscription.v1.InternalSubscriptionInfo.end_date:type_name -> google.protobuf.Timestamp
+	23, // 5: api.subscription.v1.InternalSubscriptionInfo.trial_end_date:type_name -> google.protobuf.Timestamp
+	22, // 6: api.subscription.v1.InternalSubscriptionInfo.quota_snapshot:type_name -> google.protobuf.Struct
 	6,  // 7: api.subscription.v1.InternalSubscriptionInfo.quota_usages:type_name -> api.subscription.v1.InternalQuotaUsageInfo
-	19, // 8: api.subscription.v1.InternalSubscriptionInfo.create_time:type_name -> google.protobuf.Timestamp
-	19, // 9: api.subscription.v1.InternalSubscriptionInfo.update_time:type_name -> google.protobuf.Timestamp
-	18, // 10: api.subscription.v1.InternalQuotaUsageInfo.dimension_i18n:type_name -> google.protobuf.Struct
+	23, // 8: api.subscription.v1.InternalSubscriptionInfo.create_time:type_name -> google.protobuf.Timestamp
+	23, // 9: api.subscription.v1.InternalSubscriptionInfo.update_time:type_name -> google.protobuf.Timestamp
+	22, // 10: api.subscription.v1.InternalQuotaUsageInfo.dimension_i18n:type_name -> google.protobuf.Struct
 	1,  // 11: api.subscription.v1.InternalQuotaUsageInfo.quota_type:type_name -> api.subscription.v1.InternalQuotaType
 	2,  // 12: api.subscription.v1.InternalSubscriptionOrderInfo.order_type:type_name -> api.subscription.v1.InternalOrderType
 	3,  // 13: api.subscription.v1.InternalSubscriptionOrderInfo.billing_cycle:type_name -> api.subscription.v1.InternalBillingCycle
 	4,  // 14: api.subscription.v1.InternalSubscriptionOrderInfo.status:type_name -> api.subscription.v1.InternalOrderStatus
-	19, // 15: api.subscription.v1.InternalSubscriptionOrderInfo.paid_at:type_name -> google.protobuf.Timestamp
-	19, // 16: api.subscription.v1.InternalSubscriptionOrderInfo.cancelled_at:type_name -> google.protobuf.Timestamp
-	19, // 17: api.subscription.v1.InternalSubscriptionOrderInfo.refunded_at:type_name -> google.protobuf.Timestamp
-	19, // 18: api.subscription.v1.InternalSubscriptionOrderInfo.service_start_date:type_name -> google.protobuf.Timestamp
-	19, // 19: api.subscription.v1.InternalSubscriptionOrderInfo.service_end_date:type_name -> google.protobuf.Timestamp
-	18, // 20: api.subscription.v1.InternalSubscriptionOrderInfo.invoice_info:type_name -> google.protobuf.Struct
+	23, // 15: api.subscription.v1.InternalSubscriptionOrderInfo.paid_at:type_name -> google.protobuf.Timestamp
+	23, // 16: api.subscription.v1.InternalSubscriptionOrderInfo.cancelled_at:type_name -> google.protobuf.Timestamp
+	23, // 17: api.subscription.v1.InternalSubscriptionOrderInfo.refunded_at:type_name -> google.protobuf.Timestamp
+	23, // 18: api.subscription.v1.InternalSubscriptionOrderInfo.service_start_date:type_name -> google.protobuf.Timestamp
+	23, // 19: api.subscription.v1.InternalSubscriptionOrderInfo.service_end_date:type_name -> google.protobuf.Timestamp
+	22, // 20: api.subscription.v1.InternalSubscriptionOrderInfo.invoice_info:type_name -> google.protobuf.Struct
 	0,  // 21: api.subscription.v1.InternalListSubscriptionsRequest.status:type_name -> api.subscription.v1.InternalSubscriptionStatus
 	5,  // 22: api.subscription.v1.InternalListSubscriptionsResponse.subscriptions:type_name -> api.subscription.v1.InternalSubscriptionInfo
-	19, // 23: api.subscription.v1.InternalCreateSubscriptionRequest.start_date:type_name -> google.protobuf.Timestamp
-	19, // 24: api.subscription.v1.InternalCreateSubscriptionRequest.end_date:type_name -> google.protobuf.Timestamp
+	23, // 23: api.subscription.v1.InternalCreateSubscriptionRequest.start_date:type_name -> google.protobuf.Timestamp
+	23, // 24: api.subscription.v1.InternalCreateSubscriptionRequest.end_date:type_name -> google.protobuf.Timestamp
 	7,  // 25: api.subscription.v1.InternalCreateSubscriptionRequest.order:type_name -> api.subscription.v1.InternalSubscriptionOrderInfo
 	5,  // 26: api.subscription.v1.InternalCreateSubscriptionResponse.subscription:type_name -> api.subscription.v1.InternalSubscriptionInfo
-	20, // 27: api.subscription.v1.InternalReNewSubscriptionRequest.re_new_time:type_name -> google.protobuf.Duration
+	24, // 27: api.subscription.v1.InternalReNewSubscriptionRequest.re_new_time:type_name -> google.protobuf.Duration
 	7,  // 28: api.subscription.v1.InternalReNewSubscriptionRequest.order:type_name -> api.subscription.v1.InternalSubscriptionOrderInfo
 	5,  // 29: api.subscription.v1.InternalReNewSubscriptionResponse.subscription:type_name -> api.subscription.v1.InternalSubscriptionInfo
-	19, // 30: api.subscription.v1.InternalUpgradeSubscriptionRequest.start_date:type_name -> google.protobuf.Timestamp
-	19, // 31: api.subscription.v1.InternalUpgradeSubscriptionRequest.end_date:type_name -> google.protobuf.Timestamp
+	23, // 30: api.subscription.v1.InternalUpgradeSubscriptionRequest.start_date:type_name -> google.protobuf.Timestamp
+	23, // 31: api.subscription.v1.InternalUpgradeSubscriptionRequest.end_date:type_name -> google.protobuf.Timestamp
 	7,  // 32: api.subscription.v1.InternalUpgradeSubscriptionRequest.order:type_name -> api.subscription.v1.InternalSubscriptionOrderInfo
 	5,  // 33: api.subscription.v1.InternalUpgradeSubscriptionResponse.subscription:type_name -> api.subscription.v1.InternalSubscriptionInfo
-	8,  // 34: api.subscription.v1.SubscriptionInternalService.InternalListSubscriptions:input_type -> api.subscription.v1.InternalListSubscriptionsRequest
-	10, // 35: api.subscription.v1.SubscriptionInternalService.InternalCreateSubscription:input_type -> api.subscription.v1.InternalCreateSubscriptionRequest
-	12, // 36: api.subscription.v1.SubscriptionInternalService.InternalReNewSubscription:input_type -> api.subscription.v1.InternalReNewSubscriptionRequest
-	14, // 37: api.subscription.v1.SubscriptionInternalService.InternalUpgradeSubscription:input_type -> api.subscription.v1.InternalUpgradeSubscriptionRequest
-	16, // 38: api.subscription.v1.SubscriptionInternalService.InternalGetSubscriptionStats:input_type -> api.subscription.v1.InternalGetSubscriptionStatsRequest
-	9,  // 39: api.subscription.v1.SubscriptionInternalService.InternalListSubscriptions:output_type -> api.subscription.v1.InternalListSubscriptionsResponse
-	11, // 40: api.subscription.v1.SubscriptionInternalService.InternalCreateSubscription:output_type -> api.subscription.v1.InternalCreateSubscriptionResponse
-	13, // 41: api.subscription.v1.SubscriptionInternalService.InternalReNewSubscription:output_type -> api.subscription.v1.InternalReNewSubscriptionResponse
-	15, // 42: api.subscription.v1.SubscriptionInternalService.InternalUpgradeSubscription:output_type -> api.subscription.v1.InternalUpgradeSubscriptionResponse
-	17, // 43: api.subscription.v1.SubscriptionInternalService.InternalGetSubscriptionStats:output_type -> api.subscription.v1.InternalGetSubscriptionStatsResponse
-	39, // [39:44] is the sub-list for method output_type
-	34, // [34:39] is the sub-list for method input_type
-	34, // [34:34] is the sub-list for extension type_name
-	34, // [34:34] is the sub-list for extension extendee
-	0,  // [0:34] is the sub-list for field type_name
+	6,  // 34: api.subscription.v1.InternalConsumeQuotaResponse.quota_usage:type_name -> api.subscription.v1.InternalQuotaUsageInfo
+	6,  // 35: api.subscription.v1.InternalReleaseQuotaResponse.quota_usage:type_name -> api.subscription.v1.InternalQuotaUsageInfo
+	8,  // 36: api.subscription.v1.SubscriptionInternalService.InternalListSubscriptions:input_type -> api.subscription.v1.InternalListSubscriptionsRequest
+	10, // 37: api.subscription.v1.SubscriptionInternalService.InternalCreateSubscription:input_type -> api.subscription.v1.InternalCreateSubscriptionRequest
+	12, // 38: api.subscription.v1.SubscriptionInternalService.InternalReNewSubscription:input_type -> api.subscription.v1.InternalReNewSubscriptionRequest
+	14, // 39: api.subscription.v1.SubscriptionInternalService.InternalUpgradeSubscription:input_type -> api.subscription.v1.InternalUpgradeSubscriptionRequest
+	16, // 40: api.subscription.v1.SubscriptionInternalService.InternalGetSubscriptionStats:input_type -> api.subscription.v1.InternalGetSubscriptionStatsRequest
+	18, // 41: api.subscription.v1.SubscriptionInternalService.InternalConsumeQuota:input_type -> api.subscription.v1.InternalConsumeQuotaRequest
+	20, // 42: api.subscription.v1.SubscriptionInternalService.InternalReleaseQuota:input_type -> api.subscription.v1.InternalReleaseQuotaRequest
+	9,  // 43: api.subscription.v1.SubscriptionInternalService.InternalListSubscriptions:output_type -> api.subscription.v1.InternalListSubscriptionsResponse
+	11, // 44: api.subscription.v1.SubscriptionInternalService.InternalCreateSubscription:output_type -> api.subscription.v1.InternalCreateSubscriptionResponse
+	13, // 45: api.subscription.v1.SubscriptionInternalService.InternalReNewSubscription:output_type -> api.subscription.v1.InternalReNewSubscriptionResponse
+	15, // 46: api.subscription.v1.SubscriptionInternalService.InternalUpgradeSubscription:output_type -> api.subscription.v1.InternalUpgradeSubscriptionResponse
+	17, // 47: api.subscription.v1.SubscriptionInternalService.InternalGetSubscriptionStats:output_type -> api.subscription.v1.InternalGetSubscriptionStatsResponse
+	19, // 48: api.subscription.v1.SubscriptionInternalService.InternalConsumeQuota:output_type -> api.subscription.v1.InternalConsumeQuotaResponse
+	21, // 49: api.subscription.v1.SubscriptionInternalService.InternalReleaseQuota:output_type -> api.subscription.v1.InternalReleaseQuotaResponse
+	43, // [43:50] is the sub-list for method output_type
+	36, // [36:43] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_subscribe_v1_subscription_internal_proto_init() }
@@ -1836,7 +2114,7 @@ func file_subscribe_v1_subscription_internal_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_subscribe_v1_subscription_internal_proto_rawDesc), len(file_subscribe_v1_subscription_internal_proto_rawDesc)),
 			NumEnums:      5,
-			NumMessages:   13,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2407,3 +2407,493 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = InternalGetSubscriptionStatsResponseValidationError{}
+
+// Validate checks the field values on InternalConsumeQuotaRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *InternalConsumeQuotaRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on InternalConsumeQuotaRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// InternalConsumeQuotaRequestMultiError, or nil if none found.
+func (m *InternalConsumeQuotaRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *InternalConsumeQuotaRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TenantCode
+
+	// no validation rules for ProductCode
+
+	// no validation rules for DimensionKey
+
+	// no validation rules for Amount
+
+	// no validation rules for RequestId
+
+	if len(errors) > 0 {
+		return InternalConsumeQuotaRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// InternalConsumeQuotaRequestMultiError is an error wrapping multiple
+// validation errors returned by InternalConsumeQuotaRequest.ValidateAll() if
+// the designated constraints aren't met.
+type InternalConsumeQuotaRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m InternalConsumeQuotaRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m InternalConsumeQuotaRequestMultiError) AllErrors() []error { return m }
+
+// InternalConsumeQuotaRequestValidationError is the validation error returned
+// by InternalConsumeQuotaRequest.Validate if the designated constraints
+// aren't met.
+type InternalConsumeQuotaRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e InternalConsumeQuotaRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e InternalConsumeQuotaRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e InternalConsumeQuotaRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e InternalConsumeQuotaRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e InternalConsumeQuotaRequestValidationError) ErrorName() string {
+	return "InternalConsumeQuotaRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e InternalConsumeQuotaRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sInternalConsumeQuotaRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = InternalConsumeQuotaRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = InternalConsumeQuotaRequestValidationError{}
+
+// Validate checks the field values on InternalConsumeQuotaResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *InternalConsumeQuotaResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on InternalConsumeQuotaResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// InternalConsumeQuotaResponseMultiError, or nil if none found.
+func (m *InternalConsumeQuotaResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *InternalConsumeQuotaResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetQuotaUsage()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, InternalConsumeQuotaResponseValidationError{
+					field:  "QuotaUsage",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, InternalConsumeQuotaResponseValidationError{
+					field:  "QuotaUsage",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetQuotaUsage()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return InternalConsumeQuotaResponseValidationError{
+				field:  "QuotaUsage",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return InternalConsumeQuotaResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// InternalConsumeQuotaResponseMultiError is an error wrapping multiple
+// validation errors returned by InternalConsumeQuotaResponse.ValidateAll() if
+// the designated constraints aren't met.
+type InternalConsumeQuotaResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m InternalConsumeQuotaResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m InternalConsumeQuotaResponseMultiError) AllErrors() []error { return m }
+
+// InternalConsumeQuotaResponseValidationError is the validation error returned
+// by InternalConsumeQuotaResponse.Validate if the designated constraints
+// aren't met.
+type InternalConsumeQuotaResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e InternalConsumeQuotaResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e InternalConsumeQuotaResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e InternalConsumeQuotaResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e InternalConsumeQuotaResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e InternalConsumeQuotaResponseValidationError) ErrorName() string {
+	return "InternalConsumeQuotaResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e InternalConsumeQuotaResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sInternalConsumeQuotaResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = InternalConsumeQuotaResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = InternalConsumeQuotaResponseValidationError{}
+
+// Validate checks the field values on InternalReleaseQuotaRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *InternalReleaseQuotaRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on InternalReleaseQuotaRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// InternalReleaseQuotaRequestMultiError, or nil if none found.
+func (m *InternalReleaseQuotaRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *InternalReleaseQuotaRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for TenantCode
+
+	// no validation rules for ProductCode
+
+	// no validation rules for DimensionKey
+
+	// no validation rules for Amount
+
+	// no validation rules for RequestId
+
+	if len(errors) > 0 {
+		return InternalReleaseQuotaRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// InternalReleaseQuotaRequestMultiError is an error wrapping multiple
+// validation errors returned by InternalReleaseQuotaRequest.ValidateAll() if
+// the designated constraints aren't met.
+type InternalReleaseQuotaRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m InternalReleaseQuotaRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m InternalReleaseQuotaRequestMultiError) AllErrors() []error { return m }
+
+// InternalReleaseQuotaRequestValidationError is the validation error returned
+// by InternalReleaseQuotaRequest.Validate if the designated constraints
+// aren't met.
+type InternalReleaseQuotaRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e InternalReleaseQuotaRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e InternalReleaseQuotaRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e InternalReleaseQuotaRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e InternalReleaseQuotaRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e InternalReleaseQuotaRequestValidationError) ErrorName() string {
+	return "InternalReleaseQuotaRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e InternalReleaseQuotaRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sInternalReleaseQuotaRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = InternalReleaseQuotaRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = InternalReleaseQuotaRequestValidationError{}
+
+// Validate checks the field values on InternalReleaseQuotaResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *InternalReleaseQuotaResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on InternalReleaseQuotaResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// InternalReleaseQuotaResponseMultiError, or nil if none found.
+func (m *InternalReleaseQuotaResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *InternalReleaseQuotaResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetQuotaUsage()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, InternalReleaseQuotaResponseValidationError{
+					field:  "QuotaUsage",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, InternalReleaseQuotaResponseValidationError{
+					field:  "QuotaUsage",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetQuotaUsage()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return InternalReleaseQuotaResponseValidationError{
+				field:  "QuotaUsage",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return InternalReleaseQuotaResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// InternalReleaseQuotaResponseMultiError is an error wrapping multiple
+// validation errors returned by InternalReleaseQuotaResponse.ValidateAll() if
+// the designated constraints aren't met.
+type InternalReleaseQuotaResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m InternalReleaseQuotaResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m InternalReleaseQuotaResponseMultiError) AllErrors() []error { return m }
+
+// InternalReleaseQuotaResponseValidationError is the validation error returned
+// by InternalReleaseQuotaResponse.Validate if the designated constraints
+// aren't met.
+type InternalReleaseQuotaResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e InternalReleaseQuotaResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e InternalReleaseQuotaResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e InternalReleaseQuotaResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e InternalReleaseQuotaResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e InternalReleaseQuotaResponseValidationError) ErrorName() string {
+	return "InternalReleaseQuotaResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e InternalReleaseQuotaResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sInternalReleaseQuotaResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = InternalReleaseQuotaResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = InternalReleaseQuotaResponseValidationError{}
@@ -24,6 +24,8 @@ const (
 	SubscriptionInternalService_InternalReNewSubscription_FullMethodName    = "/api.subscription.v1.SubscriptionInternalService/InternalReNewSubscription"
 	SubscriptionInternalService_InternalUpgradeSubscription_FullMethodName  = "/api.subscription.v1.SubscriptionInternalService/InternalUpgradeSubscription"
 	SubscriptionInternalService_InternalGetSubscriptionStats_FullMethodName = "/api.subscription.v1.SubscriptionInternalService/InternalGetSubscriptionStats"
+	SubscriptionInternalService_InternalConsumeQuota_FullMethodName         = "/api.subscription.v1.SubscriptionInternalService/InternalConsumeQuota"
+	SubscriptionInternalService_InternalReleaseQuota_FullMethodName         = "/api.subscription.v1.SubscriptionInternalService/InternalReleaseQuota"
 )
 
 // SubscriptionInternalServiceClient is the client API for SubscriptionInternalService service.
@@ -42,6 +44,10 @@ type SubscriptionInternalServiceClient interface {
 	InternalUpgradeSubscription(ctx context.Context, in *InternalUpgradeSubscriptionRequest, opts ...grpc.CallOption) (*InternalUpgradeSubscriptionResponse, error)
 	// InternalGetSubscriptionStats 获取商户订阅状态
 	InternalGetSubscriptionStats(ctx context.Context, in *InternalGetSubscriptionStatsRequest, opts ...grpc.CallOption) (*InternalGetSubscriptionStatsResponse, error)
+	// InternalConsumeQuota 消耗配额，request_id 相同的请求只会扣减一次
+	InternalConsumeQuota(ctx context.Context, in *InternalConsumeQuotaRequest, opts ...grpc.CallOption) (*InternalConsumeQuotaResponse, error)
+	// InternalReleaseQuota 释放配额，request_id 相同的请求只会释放一次
+	InternalReleaseQuota(ctx context.Context, in *InternalReleaseQuotaRequest, opts ...grpc.CallOption) (*InternalReleaseQuotaResponse, error)
 }
 
 type subscriptionInternalServiceClient struct {
@@ -102,6 +108,26 @@ func (c *subscriptionInternalServiceClient) InternalGetSubscriptionStats(ctx con
 	return out, nil
 }
 
+func (c *subscriptionInternalServiceClient) InternalConsumeQuota(ctx context.Context, in *InternalConsumeQuotaRequest, opts ...grpc.CallOption) (*InternalConsumeQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InternalConsumeQuotaResponse)
+	err := c.cc.Invoke(ctx, SubscriptionInternalService_InternalConsumeQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionInternalServiceClient) InternalReleaseQuota(ctx context.Context, in *InternalReleaseQuotaRequest, opts ...grpc.CallOption) (*InternalReleaseQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InternalReleaseQuotaResponse)
+	err := c.cc.Invoke(ctx, SubscriptionInternalService_InternalReleaseQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubscriptionInternalServiceServer is the server API for SubscriptionInternalService service.
 // All implementations must embed UnimplementedSubscriptionInternalServiceServer
 // for forward compatibility.
@@ -118,6 +144,10 @@ type SubscriptionInternalServiceServer interface {
 	InternalUpgradeSubscription(context.Context, *InternalUpgradeSubscriptionRequest) (*InternalUpgradeSubscriptionResponse, error)
 	// InternalGetSubscriptionStats 获取商户订阅状态
 	InternalGetSubscriptionStats(context.Context, *InternalGetSubscriptionStatsRequest) (*InternalGetSubscriptionStatsResponse, error)
+	// InternalConsumeQuota 消耗配额，request_id 相同的请求只会扣减一次
+	InternalConsumeQuota(context.Context, *InternalConsumeQuotaRequest) (*InternalConsumeQuotaResponse, error)
+	// InternalReleaseQuota 释放配额，request_id 相同的请求只会释放一次
+	InternalReleaseQuota(context.Context, *InternalReleaseQuotaRequest) (*InternalReleaseQuotaResponse, error)
 	mustEmbedUnimplementedSubscriptionInternalServiceServer()
 }
 
@@ -143,6 +173,12 @@ func (UnimplementedSubscriptionInternalServiceServer) InternalUpgradeSubscriptio
 func (UnimplementedSubscriptionInternalServiceServer) InternalGetSubscriptionStats(context.Context, *InternalGetSubscriptionStatsRequest) (*InternalGetSubscriptionStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method InternalGetSubscriptionStats not implemented")
 }
+func (UnimplementedSubscriptionInternalServiceServer) InternalConsumeQuota(context.Context, *InternalConsumeQuotaRequest) (*InternalConsumeQuotaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method InternalConsumeQuota not implemented")
+}
+func (UnimplementedSubscriptionInternalServiceServer) InternalReleaseQuota(context.Context, *InternalReleaseQuotaRequest) (*InternalReleaseQuotaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method InternalReleaseQuota not implemented")
+}
 func (UnimplementedSubscriptionInternalServiceServer) mustEmbedUnimplementedSubscriptionInternalServiceServer() {
 }
 func (UnimplementedSubscriptionInternalServiceServer) testEmbeddedByValue() {}
@@ -255,6 +291,42 @@ func _SubscriptionInternalService_InternalGetSubscriptionStats_Handler(srv inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SubscriptionInternalService_InternalConsumeQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InternalConsumeQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionInternalServiceServer).InternalConsumeQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionInternalService_InternalConsumeQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionInternalServiceServer).InternalConsumeQuota(ctx, req.(*InternalConsumeQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionInternalService_InternalReleaseQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InternalReleaseQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionInternalServiceServer).InternalReleaseQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionInternalService_InternalReleaseQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionInternalServiceServer).InternalReleaseQuota(ctx, req.(*InternalReleaseQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SubscriptionInternalService_ServiceDesc is the grpc.ServiceDesc for SubscriptionInternalService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -282,6 +354,14 @@ var SubscriptionInternalService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "InternalGetSubscriptionStats",
 			Handler:    _SubscriptionInternalService_InternalGetSubscriptionStats_Handler,
 		},
+		{
+			MethodName: "InternalConsumeQuota",
+			Handler:    _SubscriptionInternalService_InternalConsumeQuota_Handler,
+		},
+		{
+			MethodName: "InternalReleaseQuota",
+			Handler:    _SubscriptionInternalService_InternalReleaseQuota_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "subscribe/v1/subscription_internal.proto",
@@ -192,6 +192,112 @@ func (x *InternalGetCountryInfoResponse) GetCountry() *InternalCountry {
 	return nil
 }
 
+type InternalListCountriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 只返回启用的国家
+	IsActive *bool `protobuf:"varint,1,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	// 所属区域
+	Region        *InternalRegion `protobuf:"varint,2,opt,name=region,proto3,enum=api.system.v1.InternalRegion,oneof" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InternalListCountriesRequest) Reset() {
+	*x = InternalListCountriesRequest{}
+	mi := &file_system_v1_system_internal_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InternalListCountriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InternalListCountriesRequest) ProtoMessage() {}
+
+func (x *InternalListCountriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_system_v1_system_internal_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InternalListCountriesRequest.ProtoReflect.Descriptor instead.
+func (*InternalListCountriesRequest) Descriptor() ([]byte, []int) {
+	return file_system_v1_system_internal_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InternalListCountriesRequest) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+func (x *InternalListCountriesRequest) GetRegion() InternalRegion {
+	if x != nil && x.Region != nil {
+		return *x.Region
+	}
+	return InternalRegion_INTERNAL_REGION_UNSPECIFIED
+}
+
+type InternalListCountriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Countries     []*InternalCountry     `protobuf:"bytes,1,rep,name=countries,proto3" json:"countries,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InternalListCountriesResponse) Reset() {
+	*x = InternalListCountriesResponse{}
+	mi := &file_system_v1_system_internal_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InternalListCountriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InternalListCountriesResponse) ProtoMessage() {}
+
+func (x *InternalListCountriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_system_v1_system_internal_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InternalListCountriesResponse.ProtoReflect.Descriptor instead.
+func (*InternalListCountriesResponse) Descriptor() ([]byte, []int) {
+	return file_system_v1_system_internal_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InternalListCountriesResponse) GetCountries() []*InternalCountry {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *InternalListCountriesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 // 国家
 type InternalCountry struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -228,7 +334,7 @@ type InternalCountry struct {
 
 func (x *InternalCountry) Reset() {
 	*x = InternalCountry{}
-	mi := &file_system_v1_system_internal_proto_msgTypes[2]
+	mi := &file_system_v1_system_internal_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -240,7 +346,7 @@ func (x *InternalCountry) String() string {
 func (*InternalCountry) ProtoMessage() {}
 
 func (x *InternalCountry) ProtoReflect() protoreflect.Message {
-	mi := &file_system_v1_system_internal_proto_msgTypes[2]
+	mi := &file_system_v1_system_internal_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -253,7 +359,7 @@ func (x *InternalCountry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InternalCountry.ProtoReflect.Descriptor instead.
 func (*InternalCountry) Descriptor() ([]byte, []int) {
-	return file_system_v1_system_internal_proto_rawDescGZIP(), []int{2}
+	return file_system_v1_system_internal_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *InternalCountry) GetId() uint32 {
@@ -369,7 +475,16 @@ const file_system_v1_system_internal_proto_rawDesc = "" +
 	"\x1eInternalGetCountryInfoResponse\x12=\n" +
 	"\acountry\x18\x01 \x01(\v2\x1e.api.system.v1.InternalCountryH\x00R\acountry\x88\x01\x01B\n" +
 	"\n" +
-	"\b_country\"\xa0\x04\n" +
+	"\b_country\"\x95\x01\n" +
+	"\x1cInternalListCountriesRequest\x12 \n" +
+	"\tis_active\x18\x01 \x01(\bH\x00R\bisActive\x88\x01\x01\x12:\n" +
+	"\x06region\x18\x02 \x01(\x0e2\x1d.api.system.v1.InternalRegionH\x01R\x06region\x88\x01\x01B\f\n" +
+	"\n" +
+	"_is_activeB\t\n" +
+	"\a_region\"s\n" +
+	"\x1dInternalListCountriesResponse\x12<\n" +
+	"\tcountries\x18\x01 \x03(\v2\x1e.api.system.v1.InternalCountryR\tcountries\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xa0\x04\n" +
 	"\x0fInternalCountry\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
 	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
@@ -401,9 +516,10 @@ const file_system_v1_system_internal_proto_rawDesc = "" +
 	"\x16INTERNAL_SOUTH_AMERICA\x10\x04\x12\x14\n" +
 	"\x10INTERNAL_OCEANIA\x10\x05\x12\x13\n" +
 	"\x0fINTERNAL_AFRICA\x10\x06\x12\x17\n" +
-	"\x13INTERNAL_Antarctica\x10\a2\x8e\x01\n" +
+	"\x13INTERNAL_Antarctica\x10\a2\x82\x02\n" +
 	"\x15SystemInternalService\x12u\n" +
-	"\x16InternalGetCountryInfo\x12,.api.system.v1.InternalGetCountryInfoRequest\x1a-.api.system.v1.InternalGetCountryInfoResponseB\xb8\x01\n" +
+	"\x16InternalGetCountryInfo\x12,.api.system.v1.InternalGetCountryInfoRequest\x1a-.api.system.v1.InternalGetCountryInfoResponse\x12r\n" +
+	"\x15InternalListCountries\x12+.api.system.v1.InternalListCountriesRequest\x1a,.api.system.v1.InternalListCountriesResponseB\xb8\x01\n" +
 	"\x11com.api.system.v1B\x13SystemInternalProtoP\x01Z8github.com/heyinLab/common/api/gen/go/system/v1;systemv1\xa2\x02\x03ASX\xaa\x02\rApi.System.V1\xca\x02\rApi\\System\\V1\xe2\x02\x19Api\\System\\V1\\GPBMetadata\xea\x02\x0fApi::System::V1b\x06proto3"
 
 var (
@@ -419,26 +535,32 @@ func file_system_v1_system_internal_proto_rawDescGZIP() []byte {
 }
 
 var file_system_v1_system_internal_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_system_v1_system_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_system_v1_system_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_system_v1_system_internal_proto_goTypes = []any{
 	(InternalRegion)(0),                    // 0: api.system.v1.InternalRegion
 	(*InternalGetCountryInfoRequest)(nil),  // 1: api.system.v1.InternalGetCountryInfoRequest
 	(*InternalGetCountryInfoResponse)(nil), // 2: api.system.v1.InternalGetCountryInfoResponse
-	(*InternalCountry)(nil),                // 3: api.system.v1.InternalCountry
-	(*timestamppb.Timestamp)(nil),          // 4: google.protobuf.Timestamp
+	(*InternalListCountriesRequest)(nil),   // 3: api.system.v1.InternalListCountriesRequest
+	(*InternalListCountriesResponse)(nil),  // 4: api.system.v1.InternalListCountriesResponse
+	(*InternalCountry)(nil),                // 5: api.system.v1.InternalCountry
+	(*timestamppb.Timestamp)(nil),          // 6: google.protobuf.Timestamp
 }
 var file_system_v1_system_internal_proto_depIdxs = []int32{
-	3, // 0: api.system.v1.InternalGetCountryInfoResponse.country:type_name -> api.system.v1.InternalCountry
-	0, // 1: api.system.v1.InternalCountry.region:type_name -> api.system.v1.InternalRegion
-	4, // 2: api.system.v1.InternalCountry.created_at:type_name -> google.protobuf.Timestamp
-	4, // 3: api.system.v1.InternalCountry.updated_at:type_name -> google.protobuf.Timestamp
-	1, // 4: api.system.v1.SystemInternalService.InternalGetCountryInfo:input_type -> api.system.v1.InternalGetCountryInfoRequest
-	2, // 5: api.system.v1.SystemInternalService.InternalGetCountryInfo:output_type -> api.system.v1.InternalGetCountryInfoResponse
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	5, // 0: api.system.v1.InternalGetCountryInfoResponse.country:type_name -> api.system.v1.InternalCountry
+	0, // 1: api.system.v1.InternalListCountriesRequest.region:type_name -> api.system.v1.InternalRegion
+	5, // 2: api.system.v1.InternalListCountriesResponse.countries:type_name -> api.system.v1.InternalCountry
+	0, // 3: api.system.v1.InternalCountry.region:type_name -> api.system.v1.InternalRegion
+	6, // 4: api.system.v1.InternalCountry.created_at:type_name -> google.protobuf.Timestamp
+	6, // 5: api.system.v1.InternalCountry.updated_at:type_name -> google.protobuf.Timestamp
+	1, // 6: api.system.v1.SystemInternalService.InternalGetCountryInfo:input_type -> api.system.v1.InternalGetCountryInfoRequest
+	3, // 7: api.system.v1.SystemInternalService.InternalListCountries:input_type -> api.system.v1.InternalListCountriesRequest
+	2, // 8: api.system.v1.SystemInternalService.InternalGetCountryInfo:output_type -> api.system.v1.InternalGetCountryInfoResponse
+	4, // 9: api.system.v1.SystemInternalService.InternalListCountries:output_type -> api.system.v1.InternalListCountriesResponse
+	8, // [8:10] is the sub-list for method output_type
+	6, // [6:8] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_system_v1_system_internal_proto_init() }
@@ -449,13 +571,14 @@ func file_system_v1_system_internal_proto_init() {
 	file_system_v1_system_internal_proto_msgTypes[0].OneofWrappers = []any{}
 	file_system_v1_system_internal_proto_msgTypes[1].OneofWrappers = []any{}
 	file_system_v1_system_internal_proto_msgTypes[2].OneofWrappers = []any{}
+	file_system_v1_system_internal_proto_msgTypes[4].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_system_v1_system_internal_proto_rawDesc), len(file_system_v1_system_internal_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   3,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
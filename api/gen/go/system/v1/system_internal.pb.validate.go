@@ -286,6 +286,256 @@ var _ interface {
 	ErrorName() string
 } = InternalGetCountryInfoResponseValidationError{}
 
+// Validate checks the field values on InternalListCountriesRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *InternalListCountriesRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on InternalListCountriesRequest with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// InternalListCountriesRequestMultiError, or nil if none found.
+func (m *InternalListCountriesRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *InternalListCountriesRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.IsActive != nil {
+		// no validation rules for IsActive
+	}
+
+	if m.Region != nil {
+		// no validation rules for Region
+	}
+
+	if len(errors) > 0 {
+		return InternalListCountriesRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// InternalListCountriesRequestMultiError is an error wrapping multiple
+// validation errors returned by InternalListCountriesRequest.ValidateAll() if
+// the designated constraints aren't met.
+type InternalListCountriesRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m InternalListCountriesRequestMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m InternalListCountriesRequestMultiError) AllErrors() []error { return m }
+
+// InternalListCountriesRequestValidationError is the validation error returned
+// by InternalListCountriesRequest.Validate if the designated constraints
+// aren't met.
+type InternalListCountriesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e InternalListCountriesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e InternalListCountriesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e InternalListCountriesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e InternalListCountriesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e InternalListCountriesRequestValidationError) ErrorName() string {
+	return "InternalListCountriesRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e InternalListCountriesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sInternalListCountriesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = InternalListCountriesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = InternalListCountriesRequestValidationError{}
+
+// Validate checks the field values on InternalListCountriesResponse with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *InternalListCountriesResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on InternalListCountriesResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, the result is a list of violation errors wrapped in
+// InternalListCountriesResponseMultiError, or nil if none found.
+func (m *InternalListCountriesResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *InternalListCountriesResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetCountries() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, InternalListCountriesResponseValidationError{
+						field:  fmt.Sprintf("Countries[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, InternalListCountriesResponseValidationError{
+						field:  fmt.Sprintf("Countries[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return InternalListCountriesResponseValidationError{
+					field:  fmt.Sprintf("Countries[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for Total
+
+	if len(errors) > 0 {
+		return InternalListCountriesResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// InternalListCountriesResponseMultiError is an error wrapping multiple
+// validation errors returned by InternalListCountriesResponse.ValidateAll()
+// if the designated constraints aren't met.
+type InternalListCountriesResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m InternalListCountriesResponseMultiError) Error() string {
+	msgs := make([]string, 0, len(m))
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m InternalListCountriesResponseMultiError) AllErrors() []error { return m }
+
+// InternalListCountriesResponseValidationError is the validation error
+// returned by InternalListCountriesResponse.Validate if the designated
+// constraints aren't met.
+type InternalListCountriesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e InternalListCountriesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e InternalListCountriesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e InternalListCountriesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e InternalListCountriesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e InternalListCountriesResponseValidationError) ErrorName() string {
+	return "InternalListCountriesResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e InternalListCountriesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sInternalListCountriesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = InternalListCountriesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = InternalListCountriesResponseValidationError{}
+
 // Validate checks the field values on InternalCountry with the rules defined
 // in the proto definition for this message. If any rules are violated, the
 // first error encountered is returned, or nil if there are no violations.
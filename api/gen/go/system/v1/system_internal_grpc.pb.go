@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	SystemInternalService_InternalGetCountryInfo_FullMethodName = "/api.system.v1.SystemInternalService/InternalGetCountryInfo"
+	SystemInternalService_InternalListCountries_FullMethodName  = "/api.system.v1.SystemInternalService/InternalListCountries"
 )
 
 // SystemInternalServiceClient is the client API for SystemInternalService service.
@@ -28,6 +29,8 @@ const (
 type SystemInternalServiceClient interface {
 	// 获取详情
 	InternalGetCountryInfo(ctx context.Context, in *InternalGetCountryInfoRequest, opts ...grpc.CallOption) (*InternalGetCountryInfoResponse, error)
+	// 获取全部国家
+	InternalListCountries(ctx context.Context, in *InternalListCountriesRequest, opts ...grpc.CallOption) (*InternalListCountriesResponse, error)
 }
 
 type systemInternalServiceClient struct {
@@ -48,12 +51,24 @@ func (c *systemInternalServiceClient) InternalGetCountryInfo(ctx context.Context
 	return out, nil
 }
 
+func (c *systemInternalServiceClient) InternalListCountries(ctx context.Context, in *InternalListCountriesRequest, opts ...grpc.CallOption) (*InternalListCountriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InternalListCountriesResponse)
+	err := c.cc.Invoke(ctx, SystemInternalService_InternalListCountries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SystemInternalServiceServer is the server API for SystemInternalService service.
 // All implementations must embed UnimplementedSystemInternalServiceServer
 // for forward compatibility.
 type SystemInternalServiceServer interface {
 	// 获取详情
 	InternalGetCountryInfo(context.Context, *InternalGetCountryInfoRequest) (*InternalGetCountryInfoResponse, error)
+	// 获取全部国家
+	InternalListCountries(context.Context, *InternalListCountriesRequest) (*InternalListCountriesResponse, error)
 	mustEmbedUnimplementedSystemInternalServiceServer()
 }
 
@@ -67,6 +82,9 @@ type UnimplementedSystemInternalServiceServer struct{}
 func (UnimplementedSystemInternalServiceServer) InternalGetCountryInfo(context.Context, *InternalGetCountryInfoRequest) (*InternalGetCountryInfoResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method InternalGetCountryInfo not implemented")
 }
+func (UnimplementedSystemInternalServiceServer) InternalListCountries(context.Context, *InternalListCountriesRequest) (*InternalListCountriesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method InternalListCountries not implemented")
+}
 func (UnimplementedSystemInternalServiceServer) mustEmbedUnimplementedSystemInternalServiceServer() {}
 func (UnimplementedSystemInternalServiceServer) testEmbeddedByValue()                               {}
 
@@ -106,6 +124,24 @@ func _SystemInternalService_InternalGetCountryInfo_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SystemInternalService_InternalListCountries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InternalListCountriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SystemInternalServiceServer).InternalListCountries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SystemInternalService_InternalListCountries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SystemInternalServiceServer).InternalListCountries(ctx, req.(*InternalListCountriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SystemInternalService_ServiceDesc is the grpc.ServiceDesc for SystemInternalService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -117,6 +153,10 @@ var SystemInternalService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "InternalGetCountryInfo",
 			Handler:    _SystemInternalService_InternalGetCountryInfo_Handler,
 		},
+		{
+			MethodName: "InternalListCountries",
+			Handler:    _SystemInternalService_InternalListCountries_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "system/v1/system_internal.proto",
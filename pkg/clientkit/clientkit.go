@@ -0,0 +1,233 @@
+// Package clientkit 内部服务 gRPC 客户端的通用构建
+//
+// 各服务客户端（platform、merchant、product、subscribe、system、resource）统一使用 New 创建，
+// 由 clientkit 负责配置校验、日志、连接生命周期、同端点连接复用、延迟拨号、健康检查、
+// 按方法的超时配置以及错误转换，服务客户端只需提供 Factory 包装生成的 gRPC 客户端。
+//
+// 示例:
+//
+//	client, err := clientkit.New(newProductClient, config, discovery, clientkit.WithModule("product-client"))
+//	if err != nil {
+//		return nil, err
+//	}
+//	defer client.Close()
+//	plan, err := client.Service().GetPlan(ctx, "pro", nil)
+package clientkit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/heyinLab/common/pkg/common"
+)
+
+var (
+	// ErrNilConfig 配置为空
+	ErrNilConfig = fmt.Errorf("服务客户端配置不能为空")
+
+	// ErrDiscoveryRequired 使用服务发现创建客户端时未提供服务发现实例
+	ErrDiscoveryRequired = fmt.Errorf("服务发现实例不能为空")
+
+	// ErrClosed 客户端已关闭
+	ErrClosed = fmt.Errorf("服务客户端已关闭")
+)
+
+// Factory 使用连接创建服务客户端
+type Factory[T any] func(conn grpc.ClientConnInterface, logger *log.Helper, config *common.ServiceConfig) T
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type options struct {
+	module         string
+	logger         log.Logger
+	lazy           bool
+	share          bool
+	dialer         Dialer
+	dialerKey      string
+	methodTimeouts map[string]time.Duration
+}
+
+// Option 客户端配置项
+type Option func(*options)
+
+// WithModule 设置日志的 module 字段，默认为 ServiceName + "-client"
+func WithModule(module string) Option {
+	return func(o *options) { o.module = module }
+}
+
+// WithLogger 设置日志，默认使用全局日志
+func WithLogger(logger log.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithLazyDial 延迟到第一次调用时再建立连接
+func WithLazyDial() Option {
+	return func(o *options) { o.lazy = true }
+}
+
+// WithoutSharing 不与其他客户端共享连接
+func WithoutSharing() Option {
+	return func(o *options) { o.share = false }
+}
+
+// WithDialer 设置拨号方法，默认为 DefaultDialer
+//
+// 拨号方法无法比较是否相同（如闭包携带不同的 TLS 配置或拦截器），
+// 使用自定义拨号方法的客户端不与其他客户端共享连接，需要共享时使用 WithSharedDialer。
+func WithDialer(dialer Dialer) Option {
+	return func(o *options) {
+		o.dialer = dialer
+		o.dialerKey = ""
+	}
+}
+
+// WithSharedDialer 设置拨号方法，并与使用相同 key 的客户端共享连接
+//
+// key 标识拨号方法及其 TLS、拦截器等配置，配置不同的拨号方法必须使用不同的 key。
+func WithSharedDialer(key string, dialer Dialer) Option {
+	return func(o *options) {
+		o.dialer = dialer
+		o.dialerKey = key
+	}
+}
+
+// WithMethodTimeout 设置单个方法的超时时间，优先于配置中的 MethodTimeouts
+func WithMethodTimeout(method string, timeout time.Duration) Option {
+	return func(o *options) {
+		if o.methodTimeouts == nil {
+			o.methodTimeouts = make(map[string]time.Duration)
+		}
+		o.methodTimeouts[method] = timeout
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Client 内部服务客户端
+type Client[T any] struct {
+	config  *common.ServiceConfig
+	conn    *Conn
+	logger  *log.Helper
+	service T
+}
+
+// New 创建服务客户端
+//
+// discovery 为 nil 时直连 config.Endpoint。同一端点、同一服务发现实例的客户端默认共享一个连接，
+// 连接在最后一个客户端 Close 时关闭。
+//
+// 配置了 config.Regions 时按调用的区域（NewRegionContext 指定或 Claims.RegionName）选择端点，
+// 未携带区域或区域未配置时使用 config.HomeRegion。config 校验通过后会被 Normalize 规范化。
+func New[T any](factory Factory[T], config *common.ServiceConfig, discovery registry.Discovery, opts ...Option) (*Client[T], error) {
+	if config == nil {
+		return nil, ErrNilConfig
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	config.Normalize()
+
+	o := &options{share: true, dialer: DefaultDialer}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.module == "" {
+		o.module = config.ServiceName + "-client"
+	}
+	if o.logger == nil {
+		o.logger = log.GetLogger()
+	}
+	logger := log.NewHelper(log.With(o.logger, "module", o.module))
+
+	methodTimeouts := make(map[string]time.Duration, len(config.MethodTimeouts)+len(o.methodTimeouts))
+	for method, timeout := range config.MethodTimeouts {
+		methodTimeouts[method] = timeout
+	}
+	for method, timeout := range o.methodTimeouts {
+		methodTimeouts[method] = timeout
+	}
+
+	conn := &Conn{
+		router: newRouter(config, func(endpoint string) *sharedConn {
+			return pool.acquire(endpoint, discovery, o.dialer, o.dialerKey, o.share)
+		}),
+		logger:         logger,
+		timeout:        config.Timeout,
+		methodTimeouts: methodTimeouts,
+	}
+	if !o.lazy {
+		// 只预先连接默认区域，其他区域在第一次调用时连接
+		if _, err := conn.ClientConn(context.Background()); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+		}
+		home := conn.router.home
+		logger.Infof("服务客户端连接成功: service=%s, region=%s, endpoint=%s, timeout=%v", config.ServiceName, home.region, home.endpoint, config.Timeout)
+	}
+	for region, rt := range conn.router.regions {
+		if rt != conn.router.home {
+			logger.Infof("服务客户端区域路由: service=%s, region=%s, endpoint=%s", config.ServiceName, region, rt.endpoint)
+		}
+	}
+
+	return &Client[T]{
+		config:  config,
+		conn:    conn,
+		logger:  logger,
+		service: factory(conn, logger, config),
+	}, nil
+}
+
+// NewWithDiscovery 使用服务发现创建服务客户端，discovery 不能为空
+func NewWithDiscovery[T any](factory Factory[T], config *common.ServiceConfig, discovery registry.Discovery, opts ...Option) (*Client[T], error) {
+	if discovery == nil {
+		return nil, ErrDiscoveryRequired
+	}
+	return New(factory, config, discovery, opts...)
+}
+
+// Service 返回服务客户端
+func (c *Client[T]) Service() T {
+	return c.service
+}
+
+// Conn 返回连接
+func (c *Client[T]) Conn() *Conn {
+	return c.conn
+}
+
+// Config 返回配置
+func (c *Client[T]) Config() *common.ServiceConfig {
+	return c.config
+}
+
+// Logger 返回日志
+func (c *Client[T]) Logger() *log.Helper {
+	return c.logger
+}
+
+// Health 使用 gRPC 健康检查协议检查服务状态，服务不是 SERVING 状态时返回错误
+func (c *Client[T]) Health(ctx context.Context) error {
+	resp, err := grpc_health_v1.NewHealthClient(c.conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return fmt.Errorf("服务状态异常: service=%s, status=%s", c.config.ServiceName, resp.GetStatus())
+	}
+	return nil
+}
+
+// Close 关闭客户端，重复调用无副作用
+func (c *Client[T]) Close() error {
+	if c == nil || c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
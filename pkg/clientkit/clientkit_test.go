@@ -0,0 +1,360 @@
+package clientkit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/heyinLab/common/pkg/common"
+	"github.com/heyinLab/common/pkg/middleware/auth"
+)
+
+const healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+// healthServer 可控制延迟与返回错误的健康检查服务
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	delay time.Duration
+	err   error
+	calls atomic.Int32
+}
+
+func (s *healthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.calls.Add(1)
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+// startServer 启动内存 gRPC 服务，任意端点都连接到该服务，返回拨号方法与拨号次数
+func startServer(t *testing.T, srv *healthServer) (Dialer, *atomic.Int32) {
+	t.Helper()
+	lis := listen(t, srv)
+	return newDialer(func(string) *bufconn.Listener { return lis })
+}
+
+// startServers 按端点启动多个内存 gRPC 服务
+func startServers(t *testing.T, servers map[string]*healthServer) (Dialer, *atomic.Int32) {
+	t.Helper()
+	listeners := make(map[string]*bufconn.Listener, len(servers))
+	for endpoint, srv := range servers {
+		listeners[endpoint] = listen(t, srv)
+	}
+	return newDialer(func(endpoint string) *bufconn.Listener { return listeners[endpoint] })
+}
+
+func listen(t *testing.T, srv *healthServer) *bufconn.Listener {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return lis
+}
+
+func newDialer(listener func(endpoint string) *bufconn.Listener) (Dialer, *atomic.Int32) {
+	var dials atomic.Int32
+	dialer := func(ctx context.Context, endpoint string, _ registry.Discovery) (*grpc.ClientConn, error) {
+		dials.Add(1)
+		lis := listener(endpoint)
+		if lis == nil {
+			return nil, fmt.Errorf("unknown endpoint %s", endpoint)
+		}
+		return grpc.NewClient("passthrough:///"+endpoint,
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+	}
+	return dialer, &dials
+}
+
+type healthService struct {
+	client grpc_health_v1.HealthClient
+	config *common.ServiceConfig
+}
+
+func newHealthService(conn grpc.ClientConnInterface, _ *log.Helper, config *common.ServiceConfig) *healthService {
+	return &healthService{client: grpc_health_v1.NewHealthClient(conn), config: config}
+}
+
+func testConfig(t *testing.T) *common.ServiceConfig {
+	return common.NewServiceConfig("test-server").WithEndpoint(t.Name()).WithTimeout(time.Second)
+}
+
+func TestNew(t *testing.T) {
+	dialer, dials := startServer(t, &healthServer{})
+
+	_, err := New(newHealthService, nil, nil)
+	require.ErrorIs(t, err, ErrNilConfig)
+	_, err = NewWithDiscovery(newHealthService, testConfig(t), nil)
+	require.ErrorIs(t, err, ErrDiscoveryRequired)
+
+	config := testConfig(t)
+	client, err := New(newHealthService, config, nil, WithDialer(dialer))
+	require.NoError(t, err)
+	require.Equal(t, int32(1), dials.Load())
+	require.Same(t, config, client.Service().config)
+	require.NoError(t, client.Health(context.Background()))
+
+	require.NoError(t, client.Close())
+	require.NoError(t, client.Close())
+	err = client.Health(context.Background())
+	require.Error(t, err)
+	require.Equal(t, ErrClosed.Error(), errors.FromError(err).Message)
+}
+
+func TestLazyDial(t *testing.T) {
+	dialer, dials := startServer(t, &healthServer{})
+
+	client, err := New(newHealthService, testConfig(t), nil, WithDialer(dialer), WithLazyDial())
+	require.NoError(t, err)
+	defer client.Close()
+	require.Equal(t, int32(0), dials.Load())
+
+	require.NoError(t, client.Health(context.Background()))
+	require.NoError(t, client.Health(context.Background()))
+	require.Equal(t, int32(1), dials.Load())
+}
+
+func TestSharedConn(t *testing.T) {
+	dialer, dials := startServer(t, &healthServer{})
+
+	a, err := New(newHealthService, testConfig(t), nil, WithSharedDialer("test", dialer))
+	require.NoError(t, err)
+	b, err := New(newHealthService, testConfig(t), nil, WithSharedDialer("test", dialer))
+	require.NoError(t, err)
+	c, err := New(newHealthService, testConfig(t), nil, WithSharedDialer("test", dialer), WithoutSharing())
+	require.NoError(t, err)
+	defer c.Close()
+	require.Equal(t, int32(2), dials.Load())
+
+	ccA, err := a.Conn().ClientConn(context.Background())
+	require.NoError(t, err)
+	ccB, err := b.Conn().ClientConn(context.Background())
+	require.NoError(t, err)
+	ccC, err := c.Conn().ClientConn(context.Background())
+	require.NoError(t, err)
+	require.Same(t, ccA, ccB)
+	require.NotSame(t, ccA, ccC)
+
+	// 还有客户端在使用时不关闭连接
+	require.NoError(t, a.Close())
+	require.NoError(t, b.Health(context.Background()))
+	require.NotEqual(t, connectivity.Shutdown, ccB.GetState())
+
+	require.NoError(t, b.Close())
+	require.Equal(t, connectivity.Shutdown, ccB.GetState())
+	require.NoError(t, c.Health(context.Background()))
+
+	// 全部关闭后重新创建会重新拨号
+	d, err := New(newHealthService, testConfig(t), nil, WithSharedDialer("test", dialer))
+	require.NoError(t, err)
+	defer d.Close()
+	require.Equal(t, int32(3), dials.Load())
+
+	// key 不同或没有 key 的自定义拨号方法不共享连接
+	e, err := New(newHealthService, testConfig(t), nil, WithSharedDialer("other", dialer))
+	require.NoError(t, err)
+	defer e.Close()
+	f, err := New(newHealthService, testConfig(t), nil, WithDialer(dialer))
+	require.NoError(t, err)
+	defer f.Close()
+	g, err := New(newHealthService, testConfig(t), nil, WithDialer(dialer))
+	require.NoError(t, err)
+	defer g.Close()
+	require.Equal(t, int32(6), dials.Load())
+
+	ccF, err := f.Conn().ClientConn(context.Background())
+	require.NoError(t, err)
+	ccG, err := g.Conn().ClientConn(context.Background())
+	require.NoError(t, err)
+	require.NotSame(t, ccF, ccG)
+}
+
+func TestMethodTimeout(t *testing.T) {
+	dialer, _ := startServer(t, &healthServer{delay: 100 * time.Millisecond})
+
+	config := testConfig(t).WithTimeout(20 * time.Millisecond)
+	client, err := New(newHealthService, config, nil, WithDialer(dialer))
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = client.Health(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 504, errors.Code(err))
+
+	config = testConfig(t).WithTimeout(20*time.Millisecond).WithMethodTimeout("Check", time.Second)
+	client, err = New(newHealthService, config, nil, WithDialer(dialer))
+	require.NoError(t, err)
+	defer client.Close()
+	require.Equal(t, time.Second, client.Conn().Timeout(healthCheckMethod))
+	require.NoError(t, client.Health(context.Background()))
+
+	client, err = New(newHealthService, testConfig(t), nil, WithDialer(dialer),
+		WithMethodTimeout(healthCheckMethod, 20*time.Millisecond))
+	require.NoError(t, err)
+	defer client.Close()
+	require.Equal(t, 20*time.Millisecond, client.Conn().Timeout(healthCheckMethod))
+	require.Equal(t, time.Second, client.Conn().Timeout("/grpc.health.v1.Health/Watch"))
+	require.Error(t, client.Health(context.Background()))
+}
+
+func TestErrorConversion(t *testing.T) {
+	dialer, _ := startServer(t, &healthServer{err: errors.NotFound("PLAN_NOT_FOUND", "套餐不存在")})
+
+	client, err := New(newHealthService, testConfig(t), nil, WithDialer(dialer))
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Service().client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.Error(t, err)
+	var kratosErr *errors.Error
+	require.ErrorAs(t, err, &kratosErr)
+	require.Equal(t, "PLAN_NOT_FOUND", kratosErr.Reason)
+	require.Equal(t, "套餐不存在", kratosErr.Message)
+	require.True(t, errors.IsNotFound(err))
+}
+
+func TestRegionRouting(t *testing.T) {
+	servers := map[string]*healthServer{
+		"sea.internal:9000": {},
+		"cn.internal:9000":  {},
+		"us.internal:9000":  {},
+	}
+	dialer, dials := startServers(t, servers)
+
+	config := common.NewServiceConfig("resource-server").
+		WithRegionEndpoint("sea", "sea.internal:9000").
+		WithRegionEndpoint("cn", "cn.internal:9000").
+		WithRegionEndpoint("us", "us.internal:9000").
+		WithRegionEndpoint("eu", "us.internal:9000").
+		WithHomeRegion("sea")
+	config.Endpoint = ""
+	client, err := New(newHealthService, config, nil, WithDialer(dialer))
+	require.NoError(t, err)
+	defer client.Close()
+	// 只预先连接默认区域
+	require.Equal(t, int32(1), dials.Load())
+
+	calls := func() map[string]int32 {
+		result := make(map[string]int32, len(servers))
+		for endpoint, srv := range servers {
+			result[endpoint] = srv.calls.Load()
+		}
+		return result
+	}
+
+	// 未携带区域
+	require.NoError(t, client.Health(context.Background()))
+	require.Equal(t, map[string]int32{"sea.internal:9000": 1, "cn.internal:9000": 0, "us.internal:9000": 0}, calls())
+
+	// Claims 中的区域
+	ctx := auth.NewContext(context.Background(), &auth.Claims{UserCode: "u1", TenantCode: "t1", RegionName: "CN"})
+	require.NoError(t, client.Health(ctx))
+	require.Equal(t, int32(1), servers["cn.internal:9000"].calls.Load())
+
+	// 显式指定的区域优先于 Claims
+	require.NoError(t, client.Health(NewRegionContext(ctx, "us")))
+	require.Equal(t, int32(1), servers["us.internal:9000"].calls.Load())
+	require.Equal(t, "us", RegionFromContext(NewRegionContext(ctx, "US")))
+
+	// 同一端点的区域共用连接
+	require.NoError(t, client.Health(NewRegionContext(ctx, "eu")))
+	require.Equal(t, int32(2), servers["us.internal:9000"].calls.Load())
+	require.Equal(t, int32(3), dials.Load())
+
+	// 未配置的区域回退到默认区域
+	require.NoError(t, client.Health(NewRegionContext(ctx, "mars")))
+	require.Equal(t, int32(2), servers["sea.internal:9000"].calls.Load())
+
+	home, err := client.Conn().ClientConn(context.Background())
+	require.NoError(t, err)
+	us, err := client.Conn().ClientConn(NewRegionContext(context.Background(), "us"))
+	require.NoError(t, err)
+	eu, err := client.Conn().ClientConn(NewRegionContext(context.Background(), "eu"))
+	require.NoError(t, err)
+	require.NotSame(t, home, us)
+	require.Same(t, us, eu)
+
+	require.NoError(t, client.Close())
+	require.Equal(t, connectivity.Shutdown, home.GetState())
+	require.Equal(t, connectivity.Shutdown, us.GetState())
+}
+
+func TestRegionConfig(t *testing.T) {
+	config := common.NewServiceConfig("resource-server").WithRegionEndpoint("cn", "")
+	require.Error(t, config.Validate())
+
+	config = common.NewServiceConfig("resource-server").WithRegionEndpoint("cn", "cn:9000").WithHomeRegion("cn")
+	config.Endpoint = ""
+	require.NoError(t, config.Validate())
+	copied := config.Copy()
+	copied.Regions["cn"] = "other:9000"
+	require.Equal(t, "cn:9000", config.Regions["cn"])
+	require.Equal(t, "cn", copied.HomeRegion)
+
+	config.HomeRegion = ""
+	require.Error(t, config.Validate())
+
+	// 区域名称不区分大小写
+	config = &common.ServiceConfig{Regions: map[string]string{"cn": "cn:9000", " SEA ": "sea:9000"}, HomeRegion: "CN"}
+	require.NoError(t, config.Validate())
+	// Validate 不修改配置
+	require.Equal(t, "CN", config.HomeRegion)
+	require.Equal(t, map[string]string{"cn": "cn:9000", " SEA ": "sea:9000"}, config.Regions)
+	require.Zero(t, config.Timeout)
+	config.Normalize()
+	require.Equal(t, "cn", config.HomeRegion)
+	require.Equal(t, map[string]string{"cn": "cn:9000", "sea": "sea:9000"}, config.Regions)
+	require.Equal(t, common.DefaultTimeout, config.Timeout)
+	require.Equal(t, "us:9000", common.NewServiceConfig("x").WithRegionEndpoint("US", "us:9000").Regions["us"])
+
+	config = &common.ServiceConfig{Endpoint: "x:9000", Regions: map[string]string{"cn": "a:9000", "CN": "b:9000"}}
+	require.Error(t, config.Validate())
+}
+
+// countLogger 按级别统计日志条数
+type countLogger struct {
+	counts map[log.Level]int
+}
+
+func (l *countLogger) Log(level log.Level, _ ...any) error {
+	l.counts[level]++
+	return nil
+}
+
+func TestRegionFallbackLog(t *testing.T) {
+	dialer, _ := startServer(t, &healthServer{})
+	logger := &countLogger{counts: make(map[log.Level]int)}
+	config := testConfig(t).WithRegionEndpoint("cn", t.Name()).WithHomeRegion("cn")
+	client, err := New(newHealthService, config, nil, WithDialer(dialer), WithLogger(logger))
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.Health(NewRegionContext(context.Background(), "mars")))
+	}
+	require.NoError(t, client.Health(NewRegionContext(context.Background(), "venus")))
+	require.Equal(t, 2, logger.counts[log.LevelWarn])
+}
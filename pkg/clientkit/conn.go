@@ -0,0 +1,308 @@
+package clientkit
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	"github.com/go-kratos/kratos/v2/registry"
+	kratosGrpc "github.com/go-kratos/kratos/v2/transport/grpc"
+	"google.golang.org/grpc"
+
+	middleware "github.com/heyinLab/common/pkg/middleware/grpc"
+)
+
+// Dialer 建立 gRPC 连接，测试时可替换
+type Dialer func(ctx context.Context, endpoint string, discovery registry.Discovery) (*grpc.ClientConn, error)
+
+// DefaultDialer 使用 kratos 建立非加密连接，携带 recovery 与 claims 透传中间件
+//
+// 连接本身不设置调用超时，超时由 Conn 按调用方配置与方法单独设置；
+// 使用服务发现时，创建 watcher 的等待时间受 ctx 控制。
+func DefaultDialer(ctx context.Context, endpoint string, discovery registry.Discovery) (*grpc.ClientConn, error) {
+	type result struct {
+		cc  *grpc.ClientConn
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		cc, err := dial(endpoint, discovery)
+		done <- result{cc: cc, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.cc, res.err
+	case <-ctx.Done():
+		// 拨号完成后关闭迟到的连接
+		go func() {
+			if res := <-done; res.cc != nil {
+				_ = res.cc.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+func dial(endpoint string, discovery registry.Discovery) (*grpc.ClientConn, error) {
+	opts := []kratosGrpc.ClientOption{
+		kratosGrpc.WithEndpoint(endpoint),
+		kratosGrpc.WithTimeout(0),
+		kratosGrpc.WithMiddleware(
+			recovery.Recovery(),
+			middleware.ForwardClaims(),
+		),
+	}
+	if discovery != nil {
+		opts = append(opts, kratosGrpc.WithDiscovery(discovery))
+	}
+	return kratosGrpc.DialInsecure(context.Background(), opts...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// poolKey 同一端点、同一服务发现实例、同一拨号方法的客户端共享连接
+type poolKey struct {
+	endpoint  string
+	discovery registry.Discovery
+	dialerKey string // DefaultDialer 为空，自定义拨号方法为 WithSharedDialer 的 key
+}
+
+// sharedConn 引用计数的共享连接，首次使用时建立
+type sharedConn struct {
+	key    poolKey
+	dialer Dialer
+	shared bool
+
+	mu   sync.Mutex
+	cc   *grpc.ClientConn
+	refs int
+}
+
+type connPool struct {
+	mu    sync.Mutex
+	conns map[poolKey]*sharedConn
+}
+
+var pool = &connPool{conns: make(map[poolKey]*sharedConn)}
+
+// acquire 获取共享连接并增加引用
+//
+// discovery 不可比较，或使用了没有 key 的自定义拨号方法时不共享。
+func (p *connPool) acquire(endpoint string, discovery registry.Discovery, dialer Dialer, dialerKey string, share bool) *sharedConn {
+	key := poolKey{endpoint: endpoint, discovery: discovery, dialerKey: dialerKey}
+	if discovery != nil && !reflect.TypeOf(discovery).Comparable() {
+		share = false
+	}
+	if dialerKey == "" && !isDefaultDialer(dialer) {
+		share = false
+	}
+	if !share {
+		return &sharedConn{key: key, dialer: dialer, refs: 1}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sc, ok := p.conns[key]
+	if !ok {
+		sc = &sharedConn{key: key, dialer: dialer, shared: true}
+		p.conns[key] = sc
+	}
+	sc.mu.Lock()
+	sc.refs++
+	sc.mu.Unlock()
+	return sc
+}
+
+// isDefaultDialer 是否为 DefaultDialer
+func isDefaultDialer(dialer Dialer) bool {
+	return reflect.ValueOf(dialer).Pointer() == reflect.ValueOf(DefaultDialer).Pointer()
+}
+
+// release 减少引用，最后一个引用释放时关闭连接
+func (p *connPool) release(sc *sharedConn) error {
+	if sc.shared {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.refs--
+	if sc.refs > 0 {
+		return nil
+	}
+	if sc.shared && p.conns[sc.key] == sc {
+		delete(p.conns, sc.key)
+	}
+	if sc.cc == nil {
+		return nil
+	}
+	err := sc.cc.Close()
+	sc.cc = nil
+	return err
+}
+
+// get 返回已建立的连接，未建立时拨号；拨号失败不缓存，下次调用重试
+func (sc *sharedConn) get(ctx context.Context, dialTimeout time.Duration) (*grpc.ClientConn, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.cc != nil {
+		return sc.cc, nil
+	}
+	if sc.refs <= 0 {
+		return nil, ErrClosed
+	}
+	if dialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dialTimeout)
+		defer cancel()
+	}
+	cc, err := sc.dialer(ctx, sc.key.endpoint, sc.key.discovery)
+	if err != nil {
+		return nil, err
+	}
+	sc.cc = cc
+	return cc, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Conn 客户端持有的连接句柄，实现 grpc.ClientConnInterface，可直接传给生成的 NewXxxClient
+//
+// 每次调用按方法设置超时（方法单独配置的超时优先于默认超时），并将错误统一转换为 kratos 错误，
+// 业务方可以通过 errors.Reason(err) 判断服务端返回的错误原因。
+type Conn struct {
+	router         *router
+	logger         *log.Helper
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
+	closed         atomic.Bool
+	// fallbackLogged 已记录过回退日志的区域，每个区域只记录一次
+	fallbackLogged sync.Map
+}
+
+var _ grpc.ClientConnInterface = (*Conn)(nil)
+
+// Invoke 实现 grpc.ClientConnInterface
+func (c *Conn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	cc, err := c.route(ctx, method)
+	if err != nil {
+		return convertError(err)
+	}
+	if timeout := c.Timeout(method); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return convertError(cc.Invoke(ctx, method, args, reply, opts...))
+}
+
+// NewStream 实现 grpc.ClientConnInterface，流式调用只使用方法单独配置的超时
+func (c *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cc, err := c.route(ctx, method)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	timeout, ok := c.methodTimeout(method)
+	if !ok || timeout <= 0 {
+		stream, err := cc.NewStream(ctx, desc, method, opts...)
+		return stream, convertError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	stream, err := cc.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		cancel()
+		return nil, convertError(err)
+	}
+	return &timeoutStream{ClientStream: stream, cancel: cancel}, nil
+}
+
+// timeoutStream 流结束时释放超时 ctx
+type timeoutStream struct {
+	grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+func (s *timeoutStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.cancel()
+		if err == io.EOF {
+			return err
+		}
+		return convertError(err)
+	}
+	return nil
+}
+
+// Timeout 返回方法的超时时间
+func (c *Conn) Timeout(method string) time.Duration {
+	if timeout, ok := c.methodTimeout(method); ok {
+		return timeout
+	}
+	return c.timeout
+}
+
+// methodTimeout 查找方法单独配置的超时，支持完整方法名（/pkg.Service/Method）与方法名
+func (c *Conn) methodTimeout(method string) (time.Duration, bool) {
+	if timeout, ok := c.methodTimeouts[method]; ok {
+		return timeout, true
+	}
+	if i := strings.LastIndexByte(method, '/'); i >= 0 {
+		timeout, ok := c.methodTimeouts[method[i+1:]]
+		return timeout, ok
+	}
+	return 0, false
+}
+
+// ClientConn 返回 ctx 中区域对应的底层连接，未建立时立即拨号
+func (c *Conn) ClientConn(ctx context.Context) (*grpc.ClientConn, error) {
+	return c.route(ctx, "")
+}
+
+// route 按 ctx 中的区域选择连接，区域未配置时回退到默认区域，每个区域只记录一次日志
+func (c *Conn) route(ctx context.Context, method string) (*grpc.ClientConn, error) {
+	if c.closed.Load() {
+		return nil, ErrClosed
+	}
+	rt, requested, fallback := c.router.pick(ctx)
+	if fallback {
+		if _, logged := c.fallbackLogged.LoadOrStore(requested, struct{}{}); !logged {
+			c.logger.WithContext(ctx).Warnf("区域未配置，使用默认区域: method=%s, region=%s, home=%s, endpoint=%s",
+				method, requested, rt.region, rt.endpoint)
+		}
+	}
+	rt.trace(ctx, method, requested, fallback)
+	return rt.shared.get(ctx, c.timeout)
+}
+
+// Close 释放连接引用，重复调用无副作用
+func (c *Conn) Close() error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	var firstErr error
+	for _, sc := range c.router.conns {
+		if err := pool.release(sc); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// convertError 将 gRPC 错误转换为 kratos 错误
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.FromError(err)
+}
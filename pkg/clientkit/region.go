@@ -0,0 +1,115 @@
+package clientkit
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/heyinLab/common/pkg/common"
+	"github.com/heyinLab/common/pkg/middleware/auth"
+)
+
+// regionKey context 中显式指定的区域
+type regionKey struct{}
+
+// NewRegionContext 指定调用的区域，优先于 Claims 中的 RegionName
+//
+// 示例:
+//
+//	// 将租户初始化请求发送到 us 区域的资源服务
+//	ctx = clientkit.NewRegionContext(ctx, "us")
+func NewRegionContext(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionKey{}, region)
+}
+
+// RegionFromContext 返回调用的区域：显式指定的区域优先，其次为 Claims 中的 RegionName
+func RegionFromContext(ctx context.Context) string {
+	if region, ok := ctx.Value(regionKey{}).(string); ok && region != "" {
+		return common.NormalizeRegion(region)
+	}
+	if claims, ok := auth.FromContext(ctx); ok && claims != nil {
+		return common.NormalizeRegion(claims.RegionName)
+	}
+	return ""
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// route 区域对应的连接
+type route struct {
+	region   string
+	endpoint string
+	shared   *sharedConn
+}
+
+// router 按区域选择连接，同一端点的区域共用一个连接
+type router struct {
+	home    *route
+	regions map[string]*route
+	conns   []*sharedConn
+}
+
+// newRouter 按配置为每个端点获取连接
+func newRouter(config *common.ServiceConfig, acquire func(endpoint string) *sharedConn) *router {
+	homeRegion := common.NormalizeRegion(config.HomeRegion)
+	r := &router{regions: make(map[string]*route, len(config.Regions))}
+	byEndpoint := make(map[string]*sharedConn)
+	get := func(endpoint string) *sharedConn {
+		sc, ok := byEndpoint[endpoint]
+		if !ok {
+			sc = acquire(endpoint)
+			byEndpoint[endpoint] = sc
+			r.conns = append(r.conns, sc)
+		}
+		return sc
+	}
+
+	homeEndpoint := config.Endpoint
+	for region, endpoint := range config.Regions {
+		region = common.NormalizeRegion(region)
+		if homeRegion != "" && region == homeRegion {
+			homeEndpoint = endpoint
+			continue
+		}
+		r.regions[region] = &route{region: region, endpoint: endpoint}
+	}
+	// 默认区域先获取，保证 conns[0] 为默认连接
+	r.home = &route{region: homeRegion, endpoint: homeEndpoint, shared: get(homeEndpoint)}
+	if homeRegion != "" {
+		r.regions[homeRegion] = r.home
+	}
+	for _, rt := range r.regions {
+		if rt.shared == nil {
+			rt.shared = get(rt.endpoint)
+		}
+	}
+	return r
+}
+
+// pick 返回调用应使用的路由，区域未配置时回退到默认区域
+func (r *router) pick(ctx context.Context) (rt *route, requested string, fallback bool) {
+	requested = RegionFromContext(ctx)
+	if requested == "" {
+		return r.home, requested, false
+	}
+	if rt, ok := r.regions[requested]; ok {
+		return rt, requested, false
+	}
+	return r.home, requested, true
+}
+
+// trace 在当前 span 上记录路由结果
+func (rt *route) trace(ctx context.Context, method, requested string, fallback bool) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.AddEvent("clientkit.route", trace.WithAttributes(
+		attribute.String("rpc.method", method),
+		attribute.String("region.requested", requested),
+		attribute.String("region.routed", rt.region),
+		attribute.String("region.endpoint", rt.endpoint),
+		attribute.Bool("region.fallback", fallback),
+	))
+}
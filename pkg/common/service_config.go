@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -22,6 +23,18 @@ type ServiceConfig struct {
 
 	// Timeout 请求超时时间
 	Timeout time.Duration
+
+	// MethodTimeouts 按方法单独设置的超时时间，优先于 Timeout
+	// key 为完整方法名（/api.product.v1.ProductInternalService/InternalGetPlan）或方法名（InternalGetPlan）
+	MethodTimeouts map[string]time.Duration
+
+	// Regions 按区域路由的服务端点，key 为区域（cn、sea、us、eu），value 格式同 Endpoint
+	// 调用时按 context 中的区域选择端点，未配置的区域使用 HomeRegion
+	Regions map[string]string
+
+	// HomeRegion 默认区域，请求未携带区域或区域未配置时使用
+	// 为空或未在 Regions 中配置时使用 Endpoint
+	HomeRegion string
 }
 
 // NewServiceConfig 创建新的服务配置
@@ -39,15 +52,59 @@ func NewServiceConfig(serviceName string) *ServiceConfig {
 	}
 }
 
-// Validate 验证配置
+// NormalizeRegion 统一区域名称的格式：去除首尾空白并转为小写
+func NormalizeRegion(region string) string {
+	return strings.ToLower(strings.TrimSpace(region))
+}
+
+// Validate 验证配置，不修改配置
+//
+// 区域名称按 NormalizeRegion 的格式比较，规范化后重复的区域返回错误。
 func (c *ServiceConfig) Validate() error {
-	if c.Endpoint == "" {
+	regions := make(map[string]struct{}, len(c.Regions))
+	for region, endpoint := range c.Regions {
+		if endpoint == "" {
+			return fmt.Errorf("区域服务端点不能为空: region=%s", region)
+		}
+		normalized := NormalizeRegion(region)
+		if _, ok := regions[normalized]; ok {
+			return fmt.Errorf("区域重复配置: region=%s", normalized)
+		}
+		regions[normalized] = struct{}{}
+	}
+	if c.Endpoint == "" && c.regionEndpoint(c.HomeRegion) == "" {
 		return fmt.Errorf("服务端点不能为空")
 	}
+	return nil
+}
+
+// Normalize 规范化配置：区域名称统一为 NormalizeRegion 的格式，超时时间未设置时使用 DefaultTimeout
+//
+// 应在 Validate 通过后调用，规范化后重复的区域只保留其中一个。
+func (c *ServiceConfig) Normalize() *ServiceConfig {
+	if len(c.Regions) > 0 {
+		regions := make(map[string]string, len(c.Regions))
+		for region, endpoint := range c.Regions {
+			regions[NormalizeRegion(region)] = endpoint
+		}
+		c.Regions = regions
+	}
+	c.HomeRegion = NormalizeRegion(c.HomeRegion)
 	if c.Timeout <= 0 {
 		c.Timeout = DefaultTimeout
 	}
-	return nil
+	return c
+}
+
+// regionEndpoint 按规范化后的区域名称查找服务端点
+func (c *ServiceConfig) regionEndpoint(region string) string {
+	region = NormalizeRegion(region)
+	for r, endpoint := range c.Regions {
+		if NormalizeRegion(r) == region {
+			return endpoint
+		}
+	}
+	return ""
 }
 
 // WithEndpoint 设置服务端点
@@ -76,11 +133,57 @@ func (c *ServiceConfig) WithTimeout(timeout time.Duration) *ServiceConfig {
 	return c
 }
 
+// WithMethodTimeout 设置单个方法的超时时间
+//
+// 参数:
+//   - method: 完整方法名或方法名，如 "InternalListSubscriptions"
+//   - timeout: 超时时间
+func (c *ServiceConfig) WithMethodTimeout(method string, timeout time.Duration) *ServiceConfig {
+	if c.MethodTimeouts == nil {
+		c.MethodTimeouts = make(map[string]time.Duration)
+	}
+	c.MethodTimeouts[method] = timeout
+	return c
+}
+
+// WithRegionEndpoint 设置区域的服务端点
+//
+// 参数:
+//   - region: 区域，如 "cn"、"sea"、"us"、"eu"
+//   - endpoint: 服务端点，格式同 WithEndpoint
+func (c *ServiceConfig) WithRegionEndpoint(region, endpoint string) *ServiceConfig {
+	if c.Regions == nil {
+		c.Regions = make(map[string]string)
+	}
+	c.Regions[NormalizeRegion(region)] = endpoint
+	return c
+}
+
+// WithHomeRegion 设置默认区域
+func (c *ServiceConfig) WithHomeRegion(region string) *ServiceConfig {
+	c.HomeRegion = NormalizeRegion(region)
+	return c
+}
+
 // Copy 创建配置的副本
 func (c *ServiceConfig) Copy() *ServiceConfig {
-	return &ServiceConfig{
+	copied := &ServiceConfig{
 		Endpoint:    c.Endpoint,
 		ServiceName: c.ServiceName,
 		Timeout:     c.Timeout,
+		HomeRegion:  c.HomeRegion,
+	}
+	if c.MethodTimeouts != nil {
+		copied.MethodTimeouts = make(map[string]time.Duration, len(c.MethodTimeouts))
+		for method, timeout := range c.MethodTimeouts {
+			copied.MethodTimeouts[method] = timeout
+		}
 	}
-}
\ No newline at end of file
+	if c.Regions != nil {
+		copied.Regions = make(map[string]string, len(c.Regions))
+		for region, endpoint := range c.Regions {
+			copied.Regions[region] = endpoint
+		}
+	}
+	return copied
+}
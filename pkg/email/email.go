@@ -3,7 +3,8 @@ package email
 import (
 	"context"
 	"fmt"
-	"time"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 // Service 邮件服务
@@ -61,7 +62,7 @@ func (s *Service) SendInvitationEmail(ctx context.Context, req *InvitationEmailR
 	}
 
 	// 设置默认邀请时间
-	inviteTime := time.Now().Format("2006-01-02 15:04:05")
+	inviteTime := clock.NowContext(ctx).Format("2006-01-02 15:04:05")
 	if req.InviteTime != "" {
 		inviteTime = req.InviteTime
 	}
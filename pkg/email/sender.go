@@ -5,7 +5,8 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/smtp"
-	"time"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 // Sender 邮件发送器
@@ -120,7 +121,7 @@ func (s *Sender) SendTenantActivationEmail(ctx context.Context, to, userName, te
 		"TenantName":     tenantName,
 		"ActivationLink": activationLink,
 		"ExpireTime":     expireTime,
-		"CurrentYear":    time.Now().Year(),
+		"CurrentYear":    clock.NowContext(ctx).Year(),
 	}
 
 	subject, body, err := tm.RenderTemplate(EmailTypeTenantActivation, data)
@@ -158,7 +159,7 @@ func (s *Sender) SendInvitationEmail(ctx context.Context, to, userName, tenantNa
 		"AcceptLink":     acceptLink,
 		"DeclineLink":    declineLink,
 		"ExpireTime":     expireTime,
-		"CurrentYear":    time.Now().Year(),
+		"CurrentYear":    clock.NowContext(ctx).Year(),
 	}
 
 	subject, body, err := tm.RenderTemplate(EmailTypeInvitation, data)
@@ -183,7 +184,7 @@ func (s *Sender) SendPasswordResetEmail(ctx context.Context, to, userName, reset
 		"UserName":    userName,
 		"ResetLink":   resetLink,
 		"ExpireTime":  expireTime,
-		"CurrentYear": time.Now().Year(),
+		"CurrentYear": clock.NowContext(ctx).Year(),
 	}
 
 	subject, body, err := tm.RenderTemplate(EmailTypePasswordReset, data)
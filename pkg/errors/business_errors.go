@@ -3,6 +3,8 @@ package errors
 import (
 	commonV1 "github.com/heyinLab/common/api/gen/go/common"
 	"strings"
+
+	kratosErrors "github.com/go-kratos/kratos/v2/errors"
 )
 
 // 业务错误类型
@@ -67,6 +69,13 @@ var (
 	ErrDataDuplicate  = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_DATA_DUPLICATE), Message: "数据重复", Type: "DATA_DUPLICATE", HttpCode: 409}
 	ErrDataConstraint = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_DATA_CONSTRAINT), Message: "数据约束错误", Type: "DATA_CONSTRAINT", HttpCode: 400}
 
+	// 订阅相关错误 (10600-10699)
+	ErrSubscriptionNotFound  = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_SUBSCRIPTION_NOT_FOUND), Message: "未订阅该产品", Type: "SUBSCRIPTION_NOT_FOUND", HttpCode: 403}
+	ErrSubscriptionExpired   = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_SUBSCRIPTION_EXPIRED), Message: "订阅已过期", Type: "SUBSCRIPTION_EXPIRED", HttpCode: 403}
+	ErrSubscriptionSuspended = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_SUBSCRIPTION_SUSPENDED), Message: "订阅已暂停", Type: "SUBSCRIPTION_SUSPENDED", HttpCode: 403}
+	ErrQuotaExceeded         = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_QUOTA_EXCEEDED), Message: "配额不足", Type: "QUOTA_EXCEEDED", HttpCode: 429}
+	ErrFeatureDisabled       = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_FEATURE_DISABLED), Message: "当前套餐未开通该功能", Type: "FEATURE_DISABLED", HttpCode: 403}
+
 	// 系统相关错误 (19900-19999)
 	ErrSystemError        = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_SYSTEM_ERROR), Message: "系统错误", Type: "SYSTEM_ERROR", HttpCode: 500}
 	ErrServiceUnavailable = &BusinessError{Code: convertToInt32(commonV1.ErrorCode_SERVICE_UNAVAILABLE), Message: "服务不可用", Type: "SERVICE_UNAVAILABLE", HttpCode: 503}
@@ -142,6 +151,14 @@ func WrapError(err error, message string) *BusinessError {
 	}
 }
 
+// 将业务错误转换为 kratos 错误，用于中间件直接返回给调用方，其他错误原样返回
+func ToKratosError(err error) error {
+	if be, ok := err.(*BusinessError); ok {
+		return kratosErrors.New(int(be.HttpCode), be.Type, be.Message)
+	}
+	return err
+}
+
 func convertToInt32(error commonV1.ErrorCode) int32 {
 	return int32(error)
 }
@@ -0,0 +1,156 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	// ErrMissingLocale 缺少必需的语言
+	ErrMissingLocale = errors.New("i18n: missing required locale")
+	// ErrNoMatch 没有匹配的语言
+	ErrNoMatch = errors.New("i18n: no matching locale")
+)
+
+// Map 多语言内容，key 为语言代码（如 zh-CN、en），value 为该语言下的内容
+//
+// 对应 proto 中的 google.protobuf.Struct 多语言字段，如 product_i18n、plan_i18n、
+// 定价规则的 i18n 与配额维度的 dimension_i18n:
+//
+//	{"zh-CN": {"name": "专业版", "description": "..."}, "en": {"name": "Professional"}}
+type Map[T any] map[string]T
+
+// Decode 将 Struct 解码为多语言内容，每种语言的内容按 JSON 规则解码为 T
+//
+// 示例:
+//
+//	type PlanI18n struct {
+//	    Name        string `json:"name"`
+//	    Description string `json:"description"`
+//	}
+//	plans, err := i18n.Decode[PlanI18n](subscription.GetPlanI18N())
+func Decode[T any](s *structpb.Struct) (Map[T], error) {
+	m := make(Map[T], len(s.GetFields()))
+	for locale, value := range s.GetFields() {
+		data, err := value.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("i18n: encode locale %q: %w", locale, err)
+		}
+
+		var v T
+		if err = json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("i18n: decode locale %q: %w", locale, err)
+		}
+		m[locale] = v
+	}
+	return m, nil
+}
+
+// MustDecode 解码失败时 panic，用于可信数据
+func MustDecode[T any](s *structpb.Struct) Map[T] {
+	m, err := Decode[T](s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Encode 将多语言内容编码为 Struct
+func (m Map[T]) Encode() (*structpb.Struct, error) {
+	s := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(m))}
+	for locale, v := range m {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("i18n: encode locale %q: %w", locale, err)
+		}
+
+		value := &structpb.Value{}
+		if err = value.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("i18n: encode locale %q: %w", locale, err)
+		}
+		s.Fields[locale] = value
+	}
+	return s, nil
+}
+
+// Locales 已有的语言，按字母排序
+func (m Map[T]) Locales() []string {
+	locales := make([]string, 0, len(m))
+	for locale := range m {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Get 精确查询语言，语言代码不区分大小写，下划线与连字符等价
+func (m Map[T]) Get(locale string) (T, bool) {
+	if v, ok := m[locale]; ok {
+		return v, true
+	}
+	key := normalizeKey(locale)
+	for l, v := range m {
+		if normalizeKey(l) == key {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Validate 校验必需的语言均存在且内容非零值
+func (m Map[T]) Validate(required ...string) error {
+	var missing []string
+	for _, locale := range required {
+		v, ok := m.Get(locale)
+		if !ok || isZero(v) {
+			missing = append(missing, locale)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingLocale, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ValidateStruct 校验 Struct 中必需的语言均存在且内容非空，不需要解码
+func ValidateStruct(s *structpb.Struct, required ...string) error {
+	m := make(Map[*structpb.Value], len(s.GetFields()))
+	for locale, value := range s.GetFields() {
+		if !isEmptyValue(value) {
+			m[locale] = value
+		}
+	}
+	return m.Validate(required...)
+}
+
+// normalizeKey 统一语言代码格式，如 zh_CN => zh-cn
+func normalizeKey(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func isZero(v any) bool {
+	rv := reflect.ValueOf(v)
+	return !rv.IsValid() || rv.IsZero()
+}
+
+// isEmptyValue 空字符串、空对象、空列表与 null 视为空
+func isEmptyValue(value *structpb.Value) bool {
+	switch kind := value.GetKind().(type) {
+	case nil, *structpb.Value_NullValue:
+		return true
+	case *structpb.Value_StringValue:
+		return kind.StringValue == ""
+	case *structpb.Value_StructValue:
+		return len(kind.StructValue.GetFields()) == 0
+	case *structpb.Value_ListValue:
+		return len(kind.ListValue.GetValues()) == 0
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type planI18n struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+func newPlanStruct(t *testing.T) *structpb.Struct {
+	s, err := structpb.NewStruct(map[string]interface{}{
+		"zh-CN": map[string]interface{}{"name": "专业版", "description": "适合团队"},
+		"zh-TW": map[string]interface{}{"name": "專業版"},
+		"en":    map[string]interface{}{"name": "Professional"},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func TestDecodeEncode(t *testing.T) {
+	plans, err := Decode[planI18n](newPlanStruct(t))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "zh-CN", "zh-TW"}, plans.Locales())
+	assert.Equal(t, planI18n{Name: "专业版", Description: "适合团队"}, plans["zh-CN"])
+
+	s, err := plans.Encode()
+	require.NoError(t, err)
+	again, err := Decode[planI18n](s)
+	require.NoError(t, err)
+	assert.Equal(t, plans, again)
+
+	empty, err := Decode[planI18n](nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	bad, err := structpb.NewStruct(map[string]interface{}{"en": "Professional"})
+	require.NoError(t, err)
+	_, err = Decode[planI18n](bad)
+	require.Error(t, err)
+	assert.Panics(t, func() { MustDecode[planI18n](bad) })
+}
+
+func TestGetAndValidate(t *testing.T) {
+	plans := MustDecode[planI18n](newPlanStruct(t))
+
+	v, ok := plans.Get("zh_cn")
+	require.True(t, ok)
+	assert.Equal(t, "专业版", v.Name)
+	_, ok = plans.Get("ja")
+	assert.False(t, ok)
+
+	require.NoError(t, plans.Validate("zh-CN", "en"))
+	plans["en"] = planI18n{}
+	err := plans.Validate("zh-CN", "en", "ja")
+	require.ErrorIs(t, err, ErrMissingLocale)
+	assert.Contains(t, err.Error(), "en, ja")
+
+	s, err := structpb.NewStruct(map[string]interface{}{
+		"zh-CN": map[string]interface{}{"name": "专业版"},
+		"en":    map[string]interface{}{},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ValidateStruct(s, "zh-CN"))
+	require.ErrorIs(t, ValidateStruct(s, "zh-CN", "en"), ErrMissingLocale)
+}
+
+func TestBest(t *testing.T) {
+	plans := MustDecode[planI18n](newPlanStruct(t))
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		fallbacks      []string
+		locale         string
+		ok             bool
+	}{
+		{name: "exact", acceptLanguage: "zh-CN", locale: "zh-CN", ok: true},
+		{name: "traditional chinese", acceptLanguage: "zh-HK,zh;q=0.9", locale: "zh-TW", ok: true},
+		{name: "english region", acceptLanguage: "en-GB,en;q=0.8", locale: "en", ok: true},
+		{name: "quality order", acceptLanguage: "ja;q=0.5,en;q=0.9", locale: "en", ok: true},
+		{name: "fallback", acceptLanguage: "ja", fallbacks: []string{"zh_CN"}, locale: "zh-CN", ok: true},
+		{name: "fallback match", acceptLanguage: "", fallbacks: []string{"en-US"}, locale: "en", ok: true},
+		{name: "no match", acceptLanguage: "ja", fallbacks: []string{"ko"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, locale, ok := plans.Best(tt.acceptLanguage, tt.fallbacks...)
+			require.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.locale, locale)
+			if ok {
+				assert.Equal(t, plans[tt.locale], v)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	v, err := Resolve[planI18n](newPlanStruct(t), "en-US")
+	require.NoError(t, err)
+	assert.Equal(t, "Professional", v.Name)
+
+	_, err = Resolve[planI18n](newPlanStruct(t), "ja")
+	require.ErrorIs(t, err, ErrNoMatch)
+}
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string      { return h[key] }
+func (h headerCarrier) Set(key, value string)      { h[key] = value }
+func (h headerCarrier) Add(key, value string)      { h[key] = value }
+func (h headerCarrier) Keys() []string             { return nil }
+func (h headerCarrier) Values(key string) []string { return []string{h[key]} }
+
+type testTransport struct {
+	transport.Transporter
+	header headerCarrier
+}
+
+func (t testTransport) RequestHeader() transport.Header { return t.header }
+
+func TestLocalize(t *testing.T) {
+	plans := MustDecode[planI18n](newPlanStruct(t))
+
+	ctx := transport.NewServerContext(context.Background(), testTransport{
+		header: headerCarrier{"Accept-Language": "zh-Hant"},
+	})
+	assert.Equal(t, "zh-Hant", AcceptLanguage(ctx))
+	v, locale, ok := Localize(ctx, plans, "en")
+	require.True(t, ok)
+	assert.Equal(t, "zh-TW", locale)
+	assert.Equal(t, "專業版", v.Name)
+
+	v, locale, ok = Localize(context.Background(), plans, "en")
+	require.True(t, ok)
+	assert.Equal(t, "en", locale)
+	assert.Equal(t, "Professional", v.Name)
+}
@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"golang.org/x/text/language"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/heyinLab/common/pkg/middleware/common"
+)
+
+// Match 按偏好语言选出最匹配的内容，返回内容与命中的语言代码
+//
+// 匹配遵循 BCP-47 回退规则（zh-HK => zh-Hant => zh-TW，en-GB => en），
+// 偏好语言都不匹配时依次尝试 fallbacks，仍然不匹配时返回 false。
+func (m Map[T]) Match(prefs []language.Tag, fallbacks ...string) (T, string, bool) {
+	var zero T
+	if len(m) == 0 {
+		return zero, "", false
+	}
+
+	locales, tags := m.tags()
+	var matcher language.Matcher
+	if len(tags) > 0 {
+		matcher = language.NewMatcher(tags)
+	}
+	match := func(prefs ...language.Tag) (string, bool) {
+		if matcher == nil || len(prefs) == 0 {
+			return "", false
+		}
+		_, idx, conf := matcher.Match(prefs...)
+		if conf == language.No {
+			return "", false
+		}
+		return locales[idx], true
+	}
+
+	// 按偏好顺序优先取高置信度的匹配，避免靠后的 zh 精确匹配 zh-CN 覆盖靠前的 zh-HK
+	for _, pref := range prefs {
+		if matcher == nil {
+			break
+		}
+		if _, idx, conf := matcher.Match(pref); conf >= language.High {
+			return m[locales[idx]], locales[idx], true
+		}
+	}
+	if locale, ok := match(prefs...); ok {
+		return m[locale], locale, true
+	}
+
+	for _, fallback := range fallbacks {
+		if v, ok := m[fallback]; ok {
+			return v, fallback, true
+		}
+		if tag, err := language.Parse(fallback); err == nil {
+			if locale, ok := match(tag); ok {
+				return m[locale], locale, true
+			}
+		}
+		key := normalizeKey(fallback)
+		for locale, v := range m {
+			if normalizeKey(locale) == key {
+				return v, locale, true
+			}
+		}
+	}
+	return zero, "", false
+}
+
+// Best 按 Accept-Language 请求头选出最匹配的内容
+//
+// 示例:
+//
+//	plan, locale, ok := plans.Best("zh-HK,zh;q=0.9,en;q=0.8", "en", "zh-CN")
+func (m Map[T]) Best(acceptLanguage string, fallbacks ...string) (T, string, bool) {
+	prefs, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	return m.Match(prefs, fallbacks...)
+}
+
+// tags 可解析的语言代码，按字母排序保证匹配结果稳定
+func (m Map[T]) tags() ([]string, []language.Tag) {
+	all := make([]string, 0, len(m))
+	for locale := range m {
+		all = append(all, locale)
+	}
+	sort.Strings(all)
+
+	locales := make([]string, 0, len(all))
+	tags := make([]language.Tag, 0, len(all))
+	for _, locale := range all {
+		tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+		if err != nil {
+			continue
+		}
+		locales = append(locales, locale)
+		tags = append(tags, tag)
+	}
+	return locales, tags
+}
+
+// AcceptLanguage 返回请求的 Accept-Language 请求头，gRPC 请求读取同名 metadata
+func AcceptLanguage(ctx context.Context) string {
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		return tr.RequestHeader().Get(common.ACCEPTLANGUAGE)
+	}
+	return ""
+}
+
+// Localize 按请求的 Accept-Language 选出最匹配的内容
+func Localize[T any](ctx context.Context, m Map[T], fallbacks ...string) (T, string, bool) {
+	return m.Best(AcceptLanguage(ctx), fallbacks...)
+}
+
+// Resolve 解码 Struct 并按 Accept-Language 选出最匹配的内容，没有匹配时返回 ErrNoMatch
+func Resolve[T any](s *structpb.Struct, acceptLanguage string, fallbacks ...string) (T, error) {
+	var zero T
+	m, err := Decode[T](s)
+	if err != nil {
+		return zero, err
+	}
+	v, _, ok := m.Best(acceptLanguage, fallbacks...)
+	if !ok {
+		return zero, ErrNoMatch
+	}
+	return v, nil
+}
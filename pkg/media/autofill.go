@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // dataHrefRegex 匹配任意标签的 data-href="xxx" src="..." 属性组合
@@ -33,6 +35,8 @@ type fieldInfo struct {
 	srcElem  reflect.Type
 	dstElem  reflect.Type
 	keyType  reflect.Type // map的key类型
+	// srcStruct 源字段为 *structpb.Struct（proto 多语言字段），映射前转为 map[string]interface{}
+	srcStruct bool
 }
 
 // fieldType 字段类型
@@ -48,6 +52,11 @@ const (
 	fieldTypeMap                       // Map类型，需要递归（如多语言 map[string]*Lang）
 )
 
+var (
+	structpbType  = reflect.TypeOf(structpb.Struct{})
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
 // typeCache 类型信息缓存
 var typeCache sync.Map // map[typePair]*typeInfo
 
@@ -303,6 +312,14 @@ func buildTypeInfo(srcType, dstType reflect.Type) *typeInfo {
 				fi.fieldType = fieldTypeSlice
 				fi.elemInfo = getTypeInfo(fi.srcElem, fi.dstElem)
 			}
+		case dstFieldType.Kind() == reflect.Map && deref(srcField.Type) == structpbType:
+			// proto 多语言字段（如 product_i18n）按 map[string]interface{} 处理
+			fi.fieldType = fieldTypeMap
+			fi.keyType = dstFieldType.Key()
+			fi.srcElem = interfaceType
+			fi.dstElem = dstFieldType.Elem()
+			fi.elemInfo = &typeInfo{}
+			fi.srcStruct = true
 		case dstFieldType.Kind() == reflect.Map:
 			fi.fieldType = fieldTypeMap
 			fi.keyType = dstFieldType.Key()
@@ -456,6 +473,14 @@ func mapStructAndCollect(srcField, dstField reflect.Value, fi fieldInfo, collect
 
 // mapMapAndCollect 映射map并收集ID（如多语言 map[string]*Lang）
 func mapMapAndCollect(srcField, dstField reflect.Value, fi fieldInfo, collector *idCollector) {
+	if fi.srcStruct {
+		s, _ := srcField.Interface().(*structpb.Struct)
+		if s == nil {
+			return
+		}
+		srcField = reflect.ValueOf(s.AsMap())
+	}
+
 	srcField = derefValue(srcField)
 	if !srcField.IsValid() || srcField.IsNil() || srcField.Len() == 0 {
 		return
@@ -526,11 +551,25 @@ func mapInterfaceToStruct(srcVal, dstVal reflect.Value, collector *idCollector)
 			continue
 		}
 
-		jsonTag := dstField.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			jsonTag = dstField.Name
-		} else if idx := strings.Index(jsonTag, ","); idx != -1 {
-			jsonTag = jsonTag[:idx]
+		jsonTag := jsonName(dstField)
+
+		// URL/URLs（双字段模式）从对应 ID 字段的 json key 获取文件ID
+		if dstField.Type == reflect.TypeOf(URL("")) || dstField.Type == reflect.TypeOf(URLs{}) {
+			key, ok := interfaceIDKey(dstType, dstField)
+			if !ok {
+				continue
+			}
+			ids := interfaceStrings(srcVal.MapIndex(reflect.ValueOf(key)))
+			if dstField.Type == reflect.TypeOf(URL("")) {
+				if len(ids) > 0 {
+					dstVal.Field(i).SetString(ids[0])
+					collector.add(ids[0])
+				}
+			} else if len(ids) > 0 {
+				dstVal.Field(i).Set(reflect.ValueOf(URLs(ids)))
+				collector.addAll(ids)
+			}
+			continue
 		}
 
 		srcMapVal := srcVal.MapIndex(reflect.ValueOf(jsonTag))
@@ -575,6 +614,10 @@ func mapInterfaceToStruct(srcVal, dstVal reflect.Value, collector *idCollector)
 				dstFieldVal.SetString(actualVal.String())
 				collector.add(actualVal.String())
 			}
+		case dstFieldType == reflect.TypeOf(FileIDs{}):
+			ids := interfaceStrings(actualVal)
+			dstFieldVal.Set(reflect.ValueOf(FileIDs(ids)))
+			collector.addAll(ids)
 		case dstFieldType.Kind() == reflect.Int, dstFieldType.Kind() == reflect.Int64:
 			switch actualVal.Kind() {
 			case reflect.Float64:
@@ -594,6 +637,65 @@ func mapInterfaceToStruct(srcVal, dstVal reflect.Value, collector *idCollector)
 	}
 }
 
+// jsonName 字段的 json key，未设置时使用字段名
+func jsonName(field reflect.StructField) string {
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "" || jsonTag == "-" {
+		return field.Name
+	}
+	if idx := strings.Index(jsonTag, ","); idx != -1 {
+		return jsonTag[:idx]
+	}
+	return jsonTag
+}
+
+// interfaceIDKey URL/URLs 字段对应的 ID 字段在源 map 中的 key
+//
+// 与结构体模式相同，通过 media tag 或去掉 URL 后缀找到 ID 字段，再使用该字段的 json key。
+func interfaceIDKey(dstType reflect.Type, urlField reflect.StructField) (string, bool) {
+	idFieldName := urlField.Tag.Get("media")
+	if idFieldName == "" {
+		idFieldName = strings.TrimSuffix(urlField.Name, "URL")
+	}
+	idField, ok := dstType.FieldByName(idFieldName)
+	if !ok {
+		return "", false
+	}
+	return jsonName(idField), true
+}
+
+// interfaceStrings 从 interface{} 值中读取字符串或字符串列表
+func interfaceStrings(v reflect.Value) []string {
+	v = derefValue(v)
+	if v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		if v.String() == "" {
+			return nil
+		}
+		return []string{v.String()}
+	case reflect.Slice:
+		ids := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Interface {
+				elem = elem.Elem()
+			}
+			if elem.IsValid() && elem.Kind() == reflect.String && elem.String() != "" {
+				ids = append(ids, elem.String())
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
 // fillURLs 填充URL
 func fillURLs(dstVal reflect.Value, info *typeInfo, resources map[string]*ResourceInfo) {
 	dstVal = derefValue(dstVal)
@@ -711,6 +813,16 @@ func fillInterfaceStructURLs(dstVal reflect.Value, resources map[string]*Resourc
 		fieldType := dstField.Type
 
 		switch {
+		case fieldType == reflect.TypeOf(URL("")):
+			if res, ok := resources[fieldVal.String()]; ok && res.Success {
+				fieldVal.SetString(res.URL)
+			}
+		case fieldType == reflect.TypeOf(URLs{}):
+			for j := 0; j < fieldVal.Len(); j++ {
+				if res, ok := resources[fieldVal.Index(j).String()]; ok && res.Success {
+					fieldVal.Index(j).SetString(res.URL)
+				}
+			}
 		case fieldType == reflect.TypeOf(RichText("")):
 			text := fieldVal.String()
 			newText := dataHrefRegex.ReplaceAllStringFunc(text, func(match string) string {
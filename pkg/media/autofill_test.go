@@ -3,6 +3,8 @@ package media
 import (
 	"context"
 	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // 模拟 Resolver
@@ -216,3 +218,78 @@ func TestAutoFillOne(t *testing.T) {
 
 	t.Log("AutoFillOne test passed!")
 }
+
+// ProductI18nDTO 源为 proto 多语言字段（google.protobuf.Struct）
+type ProductI18nDTO struct {
+	ID   uint32                     `json:"id"`
+	I18n map[string]*ProductLangDTO `json:"i18n"`
+}
+
+type ProductProto struct {
+	ID   uint32
+	I18n *structpb.Struct
+}
+
+func TestAutoFillStruct(t *testing.T) {
+	resolver := &autoFillMockResolver{
+		data: map[string]*ResourceInfo{
+			"cover_id":  {URL: "https://cdn.example.com/cover.jpg", Success: true},
+			"gallery_1": {URL: "https://cdn.example.com/g1.jpg", Success: true},
+			"gallery_2": {URL: "https://cdn.example.com/g2.jpg", Success: true},
+		},
+	}
+	filler := NewFiller(resolver)
+
+	i18n, err := structpb.NewStruct(map[string]interface{}{
+		"zh-CN": map[string]interface{}{
+			"name":    "商品A",
+			"cover":   "cover_id",
+			"gallery": []interface{}{"gallery_1", "gallery_2"},
+		},
+		"en": map[string]interface{}{
+			"name": "Product A",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewStruct error: %v", err)
+	}
+
+	var dst ProductI18nDTO
+	if err = AutoFillOne(context.Background(), filler, &ProductProto{ID: 3, I18n: i18n}, &dst); err != nil {
+		t.Fatalf("AutoFillOne error: %v", err)
+	}
+
+	zh := dst.I18n["zh-CN"]
+	if zh == nil {
+		t.Fatal("zh-CN language is nil")
+	}
+	if zh.Name != "商品A" {
+		t.Errorf("zh.Name: expected 商品A, got %s", zh.Name)
+	}
+	if string(zh.Cover) != "cover_id" {
+		t.Errorf("zh.Cover (ID): expected cover_id, got %s", zh.Cover)
+	}
+	if string(zh.CoverURL) != "https://cdn.example.com/cover.jpg" {
+		t.Errorf("zh.CoverURL: expected URL, got %s", zh.CoverURL)
+	}
+	if len(zh.Gallery) != 2 || zh.Gallery[1] != "gallery_2" {
+		t.Errorf("zh.Gallery (IDs): expected 2 items, got %v", zh.Gallery)
+	}
+	if len(zh.GalleryURL) != 2 || zh.GalleryURL[1] != "https://cdn.example.com/g2.jpg" {
+		t.Errorf("zh.GalleryURL: expected 2 URLs, got %v", zh.GalleryURL)
+	}
+
+	en := dst.I18n["en"]
+	if en == nil || en.Name != "Product A" || en.CoverURL != "" {
+		t.Errorf("en: unexpected %+v", en)
+	}
+
+	// nil Struct 不填充
+	var empty ProductI18nDTO
+	if err = AutoFillOne(context.Background(), filler, &ProductProto{ID: 4}, &empty); err != nil {
+		t.Fatalf("AutoFillOne error: %v", err)
+	}
+	if empty.I18n != nil {
+		t.Errorf("I18n: expected nil, got %v", empty.I18n)
+	}
+}
@@ -3,7 +3,10 @@ package platform
 import (
 	"context"
 	"fmt"
-	middleware "github.com/heyinLab/common/pkg/middleware/grpc"
+	"iter"
+
+	"github.com/heyinLab/common/pkg/clientkit"
+	"github.com/heyinLab/common/pkg/utils/pagination"
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/registry"
@@ -34,99 +37,61 @@ import (
 //	    Status: "GA",
 //	})
 type Client struct {
-	config *Config
-	conn   *grpc.ClientConn
-	logger *log.Helper
-
-	// 子服务客户端
-	iamClient *IAMClient
+	client *clientkit.Client[*IAMClient]
 }
 
-// NewClient 创建平台服务客户端（直连方式）
+// NewClient 创建商户服务客户端（直连方式）
 //
 // 参数:
 //   - config: 客户端配置，可以使用 DefaultConfig() 获取默认配置
+//   - opts: 连接选项（可选），如 clientkit.WithLazyDial()
 //
 // 返回:
 //   - *Client: 客户端实例
 //   - error: 创建失败时的错误信息
-func NewClient(config *Config) (*Client, error) {
-	if config == nil {
-		config = DefaultConfig()
-	}
-
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "platform-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, nil, logger)
-	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
-	}
-
-	return &Client{
-		config:    config,
-		conn:      conn,
-		logger:    logger,
-		iamClient: newIAMClient(conn, logger),
-	}, nil
+func NewClient(config *Config, opts ...clientkit.Option) (*Client, error) {
+	return newClient(config, nil, opts...)
 }
 
-// NewClientWithDiscovery 创建带服务发现的平台服务客户端
+// NewClientWithDiscovery 创建带服务发现的商户服务客户端
 //
 // 参数:
 //   - config: 客户端配置
 //   - discovery: 服务发现实例（如 Consul）
+//   - opts: 连接选项（可选）
 //
 // 返回:
 //   - *Client: 客户端实例
 //   - error: 创建失败时的错误信息
-func NewClientWithDiscovery(config *Config, discovery registry.Discovery) (*Client, error) {
-	if config == nil {
-		config = DefaultConfig()
-	}
-
+func NewClientWithDiscovery(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
 	if discovery == nil {
-		return nil, fmt.Errorf("服务发现实例不能为空")
+		return nil, clientkit.ErrDiscoveryRequired
 	}
+	return newClient(config, discovery, opts...)
+}
 
-	if err := config.Validate(); err != nil {
-		return nil, err
+func newClient(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
+	if config == nil {
+		config = DefaultConfig()
 	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "platform-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, discovery, logger)
+	opts = append([]clientkit.Option{clientkit.WithModule("merchant-client")}, opts...)
+	client, err := clientkit.New(newIAMClient, config, discovery, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+		return nil, err
 	}
-
-	logger.Infof("平台服务客户端连接成功 (服务发现): endpoint=%s, timeout=%v", config.Endpoint, config.Timeout)
-
-	return &Client{
-		config:    config,
-		conn:      conn,
-		logger:    logger,
-		iamClient: newIAMClient(conn, logger),
-	}, nil
+	return &Client{client: client}, nil
 }
 
 // Close 关闭客户端连接
 //
-// 释放 gRPC 连接资源，应该在程序退出前调用
+// 释放 gRPC 连接资源，应该在程序退出前调用；与其他客户端共享的连接在最后一个客户端关闭时释放
 func (c *Client) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
-	}
-	return nil
+	return c.client.Close()
+}
+
+// Health 检查商户服务状态
+func (c *Client) Health(ctx context.Context) error {
+	return c.client.Health(ctx)
 }
 
 // ========== 服务访问器 ==========
@@ -141,7 +106,7 @@ func (c *Client) Close() error {
 //	    Status: "GA",
 //	})
 func (c *Client) IAM() *IAMClient {
-	return c.iamClient
+	return c.client.Service()
 }
 
 // ========== IAM 客户端 ==========
@@ -155,7 +120,7 @@ type IAMClient struct {
 }
 
 // newIAMClient 创建 IAM 客户端
-func newIAMClient(conn *grpc.ClientConn, logger *log.Helper) *IAMClient {
+func newIAMClient(conn grpc.ClientConnInterface, logger *log.Helper, _ *Config) *IAMClient {
 	return &IAMClient{
 		client: v1.NewMerchantIamServiceClient(conn),
 		logger: logger,
@@ -251,6 +216,37 @@ func (c *IAMClient) ListPlatformUser(ctx context.Context, page, limit int32, opt
 	return resp, nil
 }
 
+// Tenants 遍历租户列表，自动翻页，每页最多 20 条
+//
+// 示例:
+//
+//	for tenant, err := range client.IAM().Tenants(ctx, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *IAMClient) Tenants(ctx context.Context, opt *ListTenantOptions, opts ...pagination.Option) iter.Seq2[*v1.InternalTenant, error] {
+	return pagination.Iterate(ctx, func(ctx context.Context, page, pageSize int32) (pagination.Page[*v1.InternalTenant], error) {
+		resp, err := c.ListTenant(ctx, page, pageSize, opt)
+		if err != nil {
+			return pagination.Page[*v1.InternalTenant]{}, err
+		}
+		return pagination.Page[*v1.InternalTenant]{Items: resp.GetItems(), Total: resp.GetTotal()}, nil
+	}, append([]pagination.Option{pagination.WithPageSize(20)}, opts...)...)
+}
+
+// PlatformUsers 遍历平台用户列表，自动翻页，每页最多 20 条
+func (c *IAMClient) PlatformUsers(ctx context.Context, opt *ListPlatformUserOptions, opts ...pagination.Option) iter.Seq2[*v1.InternalPlatformUser, error] {
+	return pagination.Iterate(ctx, func(ctx context.Context, page, pageSize int32) (pagination.Page[*v1.InternalPlatformUser], error) {
+		resp, err := c.ListPlatformUser(ctx, page, pageSize, opt)
+		if err != nil {
+			return pagination.Page[*v1.InternalPlatformUser]{}, err
+		}
+		return pagination.Page[*v1.InternalPlatformUser]{Items: resp.GetItems(), Total: resp.GetTotal()}, nil
+	}, append([]pagination.Option{pagination.WithPageSize(20)}, opts...)...)
+}
+
 // ========== 辅助函数 ==========
 
 // getStringValue 获取指针字符串的值
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/go-kratos/kratos/v2/errors"
@@ -40,22 +41,45 @@ func IsOpenAPIRequest(ctx context.Context) bool {
 	return GetAuthType(ctx) == common.AuthTypeOpenAPI
 }
 
+// 操作者类型
+const (
+	OperatorTypeUser    = "user"
+	OperatorTypeAPIKey  = "api_key"
+	OperatorTypeUnknown = "unknown"
+)
+
 // Operator 操作者信息（用于审计日志）
 type Operator struct {
 	Type string // "user" 或 "api_key"
-	ID   uint64
+	ID   uint64 // API Key ID（仅 api_key 有值）
+	Code string // 用户编码（仅 user 有值）
+}
+
+// Identity 返回可持久化的操作者标识：用户返回用户编码，API Key 返回 "api_key:<id>"，未知返回空字符串
+func (o Operator) Identity() string {
+	switch o.Type {
+	case OperatorTypeUser:
+		return o.Code
+	case OperatorTypeAPIKey:
+		if o.ID == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s:%d", OperatorTypeAPIKey, o.ID)
+	default:
+		return ""
+	}
 }
 
 // GetOperator 获取操作者信息
 func GetOperator(ctx context.Context) Operator {
 	if IsOpenAPIRequest(ctx) {
-		return Operator{Type: "api_key", ID: GetAPIKeyID(ctx)}
+		return Operator{Type: OperatorTypeAPIKey, ID: GetAPIKeyID(ctx)}
 	}
 	claims, ok := FromContext(ctx)
 	if ok && claims.UserCode != "" {
-		return Operator{Type: "user", ID: 0} // ID 不再使用，返回0
+		return Operator{Type: OperatorTypeUser, Code: claims.UserCode} // 用户不再有数值ID，使用用户编码
 	}
-	return Operator{Type: "unknown", ID: 0}
+	return Operator{Type: OperatorTypeUnknown}
 }
 
 // Server 统一认证中间件，支持 JWT Token 和 OpenAPI 两种认证方式
@@ -1,12 +1,32 @@
 package common
 
+import "context"
+
 // 常用 Header
 const (
 	USERCODE   string = "X-User-Code"
 	TENANTCODE string = "X-Tenant-Code"
 	REGIONNAME string = "X-Region-Name"
+
+	FORWARDEDFOR string = "X-Forwarded-For"
+	REALIP       string = "X-Real-IP"
+
+	ACCEPTLANGUAGE string = "Accept-Language"
 )
 
+type clientIPKey struct{}
+
+// NewClientIPContext 将客户端 IP 存入 context，由 geo.Server 写入
+func NewClientIPContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext 从 context 中获取客户端 IP，未写入时返回空字符串
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
 // OpenAPI 认证相关的 context key
 type openapiContextKey string
 
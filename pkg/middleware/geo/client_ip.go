@@ -0,0 +1,128 @@
+package geo
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"google.golang.org/grpc/peer"
+
+	"github.com/heyinLab/common/pkg/middleware/common"
+)
+
+// TrustedProxies 可信代理的地址段，只有来自可信代理的请求才读取 X-Forwarded-For / X-Real-IP
+type TrustedProxies []netip.Prefix
+
+// ParseTrustedProxies 解析 CIDR 或 IP 列表，如 "10.0.0.0/8"、"172.16.0.1"
+func ParseTrustedProxies(cidrs ...string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			addr, err := netip.ParseAddr(cidr)
+			if err != nil {
+				return nil, err
+			}
+			addr = addr.Unmap()
+			proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// Contains 地址是否为可信代理
+func (p TrustedProxies) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP 提取客户端 IP
+//
+// 直连地址不是可信代理时直接使用直连地址，防止客户端伪造请求头；
+// 否则从右向左遍历 X-Forwarded-For，跳过可信代理，第一个不可信的地址即客户端地址；
+// 没有 X-Forwarded-For 时使用 X-Real-IP。
+func clientIP(ctx context.Context, tr transport.Transporter, trusted TrustedProxies) string {
+	remote, ok := remoteAddr(ctx, tr)
+	if !ok {
+		return ""
+	}
+	if !trusted.Contains(remote) {
+		return remote.String()
+	}
+
+	header := tr.RequestHeader()
+	var forwarded []netip.Addr
+	for _, value := range header.Values(common.FORWARDEDFOR) {
+		for _, part := range strings.Split(value, ",") {
+			addr, err := parseAddr(part)
+			if err != nil {
+				// 格式错误之前的地址不可信
+				forwarded = forwarded[:0]
+				continue
+			}
+			forwarded = append(forwarded, addr)
+		}
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		if !trusted.Contains(forwarded[i]) {
+			return forwarded[i].String()
+		}
+	}
+	if len(forwarded) > 0 {
+		// 整条链路都是可信代理，使用最早的地址
+		return forwarded[0].String()
+	}
+
+	if addr, err := parseAddr(header.Get(common.REALIP)); err == nil {
+		return addr.String()
+	}
+	return remote.String()
+}
+
+// remoteAddr 直连地址，HTTP 使用 RemoteAddr，gRPC 使用 peer
+func remoteAddr(ctx context.Context, tr transport.Transporter) (netip.Addr, bool) {
+	var raw string
+	if ht, ok := tr.(http.Transporter); ok && ht.Request() != nil {
+		raw = ht.Request().RemoteAddr
+	} else if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		raw = p.Addr.String()
+	}
+	if raw == "" {
+		return netip.Addr{}, false
+	}
+	addr, err := parseAddr(raw)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr, true
+}
+
+// parseAddr 解析 IP 或 IP:port
+func parseAddr(raw string) (netip.Addr, error) {
+	raw = strings.TrimSpace(raw)
+	if host, _, err := net.SplitHostPort(raw); err == nil {
+		raw = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(raw, "[]"))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.WithZone("").Unmap(), nil
+}
@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"context"
+
+	systemV1 "github.com/heyinLab/common/api/gen/go/system/v1"
+	"github.com/heyinLab/common/pkg/utils/geoip"
+)
+
+// Location 请求来源的地理位置
+type Location struct {
+	IP               string   // 客户端 IP
+	Country          string   // 国家名称
+	CountryCode      string   // ISO 3166-1 alpha-2 国家代码
+	Province         string   // 省
+	City             string   // 城市
+	SubdivisionCodes []string // ISO 3166-2 行政区代码
+	TimeZone         string   // IANA 时区
+	Private          bool     // 内网或保留地址
+
+	// Region 国家所属区域，需要配置 WithCountryMapper
+	Region systemV1.InternalRegion
+	// LanguageID 国家默认语言 ID，需要配置 WithCountryMapper
+	LanguageID uint32
+}
+
+// fromResult 使用 GeoIP 查询结果填充位置
+func (l *Location) fromResult(res geoip.Result) {
+	l.Country = res.Country
+	l.CountryCode = res.CountryCode
+	l.Province = res.Province
+	l.City = res.City
+	l.SubdivisionCodes = res.SubdivisionCodes
+	l.TimeZone = res.TimeZone
+	l.Private = res.Private
+}
+
+// 定义用于在 context 中传递 Location 的 key
+type locationKey struct{}
+
+// NewContext 将 Location 存入 context
+func NewContext(ctx context.Context, loc *Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, loc)
+}
+
+// FromContext 从 context 中获取 Location
+func FromContext(ctx context.Context) (*Location, bool) {
+	loc, ok := ctx.Value(locationKey{}).(*Location)
+	return loc, ok
+}
+
+// ClientIP 返回请求的客户端 IP，未经过 Server 中间件时返回空字符串
+func ClientIP(ctx context.Context) string {
+	if loc, ok := FromContext(ctx); ok && loc != nil {
+		return loc.IP
+	}
+	return ""
+}
+
+// CountryCode 返回请求来源的国家代码，未知时返回空字符串
+func CountryCode(ctx context.Context) string {
+	if loc, ok := FromContext(ctx); ok && loc != nil {
+		return loc.CountryCode
+	}
+	return ""
+}
+
+// TimeZone 返回请求来源的时区，未知时返回空字符串
+func TimeZone(ctx context.Context) string {
+	if loc, ok := FromContext(ctx); ok && loc != nil {
+		return loc.TimeZone
+	}
+	return ""
+}
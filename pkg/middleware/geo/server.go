@@ -0,0 +1,139 @@
+package geo
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+
+	systemV1 "github.com/heyinLab/common/api/gen/go/system/v1"
+	"github.com/heyinLab/common/pkg/middleware/auth"
+	"github.com/heyinLab/common/pkg/middleware/common"
+	"github.com/heyinLab/common/pkg/utils/geoip"
+)
+
+// CountryMapper 返回国家所属区域与默认语言，(*country.Registry).Locale 满足该签名
+type CountryMapper func(countryCode string) (region systemV1.InternalRegion, languageID uint32, ok bool)
+
+// RegionNamer 根据位置推断 Claims.RegionName（如 cn、sea、us、eu），返回空字符串表示不推断
+type RegionNamer func(loc *Location) string
+
+type options struct {
+	proxies    []string
+	countries  CountryMapper
+	regionName RegionNamer
+	logger     *log.Helper
+}
+
+// Option 中间件配置项
+type Option func(*options)
+
+// WithTrustedProxies 设置可信代理（CIDR 或 IP），格式错误的条目记录日志后忽略
+//
+// 未设置时不信任任何代理，客户端 IP 始终为直连地址。
+func WithTrustedProxies(cidrs ...string) Option {
+	return func(o *options) { o.proxies = append(o.proxies, cidrs...) }
+}
+
+// WithCountryMapper 设置国家到区域与默认语言的映射
+//
+// 示例:
+//
+//	geo.Server(resolver, geo.WithCountryMapper(countryRegistry.Locale))
+func WithCountryMapper(mapper CountryMapper) Option {
+	return func(o *options) { o.countries = mapper }
+}
+
+// WithRegionName 请求头未携带 X-Region-Name 时，根据位置推断 Claims.RegionName
+//
+// 需要放在 auth.Server 之后，推断的区域会随 ForwardClaims 传递给下游服务。
+func WithRegionName(namer RegionNamer) Option {
+	return func(o *options) { o.regionName = namer }
+}
+
+// WithLogger 设置日志
+func WithLogger(logger log.Logger) Option {
+	return func(o *options) { o.logger = log.NewHelper(log.With(logger, "module", "middleware/geo")) }
+}
+
+// Server 解析请求来源的地理位置并存入 context
+//
+// 客户端 IP 依次取自 X-Forwarded-For、X-Real-IP（仅当直连地址为可信代理时）与直连地址，
+// 通过 resolver 查询国家、省市与时区，查询失败时 Location 只包含 IP，不影响请求。
+// 业务代码通过 FromContext、ClientIP、CountryCode、TimeZone 获取。
+func Server(resolver geoip.GeoIP, opts ...Option) middleware.Middleware {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.logger == nil {
+		o.logger = log.NewHelper(log.With(log.GetLogger(), "module", "middleware/geo"))
+	}
+	trusted := make(TrustedProxies, 0, len(o.proxies))
+	for _, cidr := range o.proxies {
+		proxies, err := ParseTrustedProxies(cidr)
+		if err != nil {
+			o.logger.Warnf("忽略格式错误的可信代理: cidr=%s, error=%v", cidr, err)
+			continue
+		}
+		trusted = append(trusted, proxies...)
+	}
+
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			loc := &Location{IP: clientIP(ctx, tr, trusted)}
+			if loc.IP != "" && resolver != nil {
+				if res, err := resolver.Query(loc.IP); err == nil {
+					loc.fromResult(res)
+				} else {
+					o.logger.WithContext(ctx).Debugf("查询 IP 归属地失败: ip=%s, error=%v", loc.IP, err)
+				}
+			}
+			if loc.CountryCode != "" && o.countries != nil {
+				if region, languageID, ok := o.countries(loc.CountryCode); ok {
+					loc.Region = region
+					loc.LanguageID = languageID
+				}
+			}
+			ctx = NewContext(ctx, loc)
+			ctx = common.NewClientIPContext(ctx, loc.IP)
+
+			if o.regionName != nil {
+				if claims, ok := auth.FromContext(ctx); ok && claims != nil && claims.RegionName == "" {
+					if regionName := o.regionName(loc); regionName != "" {
+						inferred := *claims
+						inferred.RegionName = regionName
+						ctx = auth.NewContext(ctx, &inferred)
+					}
+				}
+			}
+
+			return handler(ctx, req)
+		}
+	}
+}
+
+// RegionNameByInternalRegion 按国家所属大洲推断区域的 RegionNamer，需要配置 WithCountryMapper
+//
+// 中国大陆为 cn，亚洲与大洋洲其他国家为 sea，北美与南美为 us，欧洲与非洲为 eu。
+func RegionNameByInternalRegion(loc *Location) string {
+	if loc.CountryCode == "CN" {
+		return "cn"
+	}
+	switch loc.Region {
+	case systemV1.InternalRegion_INTERNAL_ASIA, systemV1.InternalRegion_INTERNAL_OCEANIA:
+		return "sea"
+	case systemV1.InternalRegion_INTERNAL_NORTH_AMERICA, systemV1.InternalRegion_INTERNAL_SOUTH_AMERICA:
+		return "us"
+	case systemV1.InternalRegion_INTERNAL_EUROPE, systemV1.InternalRegion_INTERNAL_AFRICA:
+		return "eu"
+	default:
+		return ""
+	}
+}
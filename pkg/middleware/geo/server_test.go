@@ -0,0 +1,192 @@
+package geo
+
+import (
+	"context"
+	"net"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	systemV1 "github.com/heyinLab/common/api/gen/go/system/v1"
+	"github.com/heyinLab/common/pkg/middleware/auth"
+	"github.com/heyinLab/common/pkg/middleware/common"
+	"github.com/heyinLab/common/pkg/utils/geoip"
+)
+
+type headerCarrier nethttp.Header
+
+func (h headerCarrier) Get(key string) string      { return nethttp.Header(h).Get(key) }
+func (h headerCarrier) Set(key, value string)      { nethttp.Header(h).Set(key, value) }
+func (h headerCarrier) Add(key, value string)      { nethttp.Header(h).Add(key, value) }
+func (h headerCarrier) Values(key string) []string { return nethttp.Header(h).Values(key) }
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+type httpTransport struct {
+	http.Transporter
+	request *nethttp.Request
+}
+
+func (t httpTransport) Request() *nethttp.Request        { return t.request }
+func (t httpTransport) RequestHeader() transport.Header  { return headerCarrier(t.request.Header) }
+func (t httpTransport) Kind() transport.Kind             { return transport.KindHTTP }
+func (t httpTransport) Operation() string                { return "/test" }
+func (t httpTransport) ReplyHeader() transport.Header    { return headerCarrier(nethttp.Header{}) }
+func (t httpTransport) Endpoint() string                 { return "" }
+func (t httpTransport) PathTemplate() string             { return "" }
+func (t httpTransport) Response() nethttp.ResponseWriter { return nil }
+
+type grpcTransport struct {
+	header metadata.MD
+}
+
+func (t grpcTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (t grpcTransport) Endpoint() string                { return "" }
+func (t grpcTransport) Operation() string               { return "/test" }
+func (t grpcTransport) RequestHeader() transport.Header { return mdCarrier(t.header) }
+func (t grpcTransport) ReplyHeader() transport.Header   { return mdCarrier(metadata.MD{}) }
+
+type mdCarrier metadata.MD
+
+func (m mdCarrier) Get(key string) string {
+	if vals := metadata.MD(m).Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+func (m mdCarrier) Set(key, value string)      { metadata.MD(m).Set(key, value) }
+func (m mdCarrier) Add(key, value string)      { metadata.MD(m).Append(key, value) }
+func (m mdCarrier) Values(key string) []string { return metadata.MD(m).Get(key) }
+func (m mdCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func httpContext(remoteAddr string, header map[string]string) context.Context {
+	req, _ := nethttp.NewRequest("GET", "/", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return transport.NewServerContext(context.Background(), httpTransport{request: req})
+}
+
+type fakeGeoIP map[string]geoip.Result
+
+func (f fakeGeoIP) Query(ip string) (geoip.Result, error) {
+	res, ok := f[ip]
+	if !ok {
+		return geoip.Result{IP: ip}, geoip.ErrNotFound
+	}
+	return res, nil
+}
+
+func run(t *testing.T, ctx context.Context, resolver geoip.GeoIP, opts ...Option) (*Location, context.Context) {
+	t.Helper()
+	var got context.Context
+	_, err := Server(resolver, opts...)(func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = ctx
+		return nil, nil
+	})(ctx, nil)
+	require.NoError(t, err)
+	loc, ok := FromContext(got)
+	require.True(t, ok)
+	return loc, got
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := WithTrustedProxies("10.0.0.0/8", "172.16.0.1")
+
+	// 不可信的直连地址忽略伪造的请求头
+	loc, _ := run(t, httpContext("8.8.8.8:5000", map[string]string{"X-Forwarded-For": "1.2.3.4"}), nil, trusted)
+	require.Equal(t, "8.8.8.8", loc.IP)
+
+	// 跳过链路中的可信代理
+	loc, got := run(t, httpContext("10.0.0.1:5000", map[string]string{"X-Forwarded-For": "5.6.7.8, 1.2.3.4, 172.16.0.1"}), nil, trusted)
+	require.Equal(t, "1.2.3.4", loc.IP)
+	require.Equal(t, "1.2.3.4", common.ClientIPFromContext(got))
+
+	// 格式错误之前的地址不可信
+	loc, _ = run(t, httpContext("10.0.0.1:5000", map[string]string{"X-Forwarded-For": "5.6.7.8, garbage, 10.0.0.9"}), nil, trusted)
+	require.Equal(t, "10.0.0.9", loc.IP)
+
+	loc, _ = run(t, httpContext("[::ffff:10.0.0.1]:5000", map[string]string{"X-Real-IP": "2001:4860::8888"}), nil, trusted)
+	require.Equal(t, "2001:4860::8888", loc.IP)
+
+	loc, _ = run(t, httpContext("10.0.0.1:5000", nil), nil, trusted)
+	require.Equal(t, "10.0.0.1", loc.IP)
+
+	// 未配置可信代理时不读取请求头
+	loc, _ = run(t, httpContext("10.0.0.1:5000", map[string]string{"X-Real-IP": "1.2.3.4"}), nil)
+	require.Equal(t, "10.0.0.1", loc.IP)
+
+	// gRPC 使用 peer 地址与 metadata
+	ctx := transport.NewServerContext(context.Background(), grpcTransport{header: metadata.Pairs("x-forwarded-for", "9.9.9.9")})
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.1.1.1"), Port: 9000}})
+	loc, _ = run(t, ctx, nil, trusted)
+	require.Equal(t, "9.9.9.9", loc.IP)
+
+	_, err := ParseTrustedProxies("10.0.0.0/33")
+	require.Error(t, err)
+
+	// 格式错误的可信代理被忽略，其余条目仍然生效
+	loc, _ = run(t, httpContext("10.0.0.1:5000", map[string]string{"X-Real-IP": "1.2.3.4"}), nil,
+		WithTrustedProxies("not-a-cidr", "10.0.0.0/8"))
+	require.Equal(t, "1.2.3.4", loc.IP)
+}
+
+func TestServer(t *testing.T) {
+	resolver := fakeGeoIP{
+		"47.108.149.89": {Country: "中国", CountryCode: "CN", Province: "四川省", City: "成都市", TimeZone: "Asia/Shanghai"},
+		"1.1.1.1":       {Country: "澳大利亚", CountryCode: "AU", TimeZone: "Australia/Sydney"},
+	}
+	countries := func(code string) (systemV1.InternalRegion, uint32, bool) {
+		switch code {
+		case "CN":
+			return systemV1.InternalRegion_INTERNAL_ASIA, 1, true
+		case "AU":
+			return systemV1.InternalRegion_INTERNAL_OCEANIA, 2, true
+		}
+		return systemV1.InternalRegion_INTERNAL_REGION_UNSPECIFIED, 0, false
+	}
+	opts := []Option{WithCountryMapper(countries), WithRegionName(RegionNameByInternalRegion)}
+
+	loc, ctx := run(t, httpContext("47.108.149.89:1234", nil), resolver, opts...)
+	require.Equal(t, "CN", loc.CountryCode)
+	require.Equal(t, "四川省", loc.Province)
+	require.Equal(t, "Asia/Shanghai", TimeZone(ctx))
+	require.Equal(t, systemV1.InternalRegion_INTERNAL_ASIA, loc.Region)
+	require.Equal(t, uint32(1), loc.LanguageID)
+	require.Equal(t, "47.108.149.89", ClientIP(ctx))
+
+	// 推断 Claims 的区域，已携带区域时不覆盖
+	claims := &auth.Claims{UserCode: "u1", TenantCode: "t1"}
+	_, ctx = run(t, auth.NewContext(httpContext("1.1.1.1:1234", nil), claims), resolver, opts...)
+	inferred, _ := auth.FromContext(ctx)
+	require.Equal(t, "sea", inferred.RegionName)
+	require.Equal(t, "", claims.RegionName)
+	require.Equal(t, "AU", CountryCode(ctx))
+
+	_, ctx = run(t, auth.NewContext(httpContext("1.1.1.1:1234", nil), &auth.Claims{UserCode: "u1", RegionName: "eu"}), resolver, opts...)
+	inferred, _ = auth.FromContext(ctx)
+	require.Equal(t, "eu", inferred.RegionName)
+
+	// 查询失败时只包含 IP
+	loc, _ = run(t, httpContext("8.8.8.8:1234", nil), resolver, opts...)
+	require.Equal(t, "8.8.8.8", loc.IP)
+	require.Empty(t, loc.CountryCode)
+	require.Equal(t, systemV1.InternalRegion_INTERNAL_REGION_UNSPECIFIED, loc.Region)
+}
@@ -10,7 +10,9 @@ import (
 	"google.golang.org/grpc"
 )
 
-// createGRPCConn 创建 gRPC 连接
+// CreateGRPCConn 创建 gRPC 连接
+//
+// Deprecated: 服务客户端请使用 clientkit.New，由其负责连接复用、超时与关闭。
 func CreateGRPCConn(config *common.ServiceConfig, discovery registry.Discovery, logger *log.Helper) (*grpc.ClientConn, error) {
 	opts := []kratosGrpc.ClientOption{
 		kratosGrpc.WithEndpoint(config.Endpoint),
@@ -23,6 +23,10 @@ func ForwardClaims() middleware.Middleware {
 					common.REGIONNAME, claims.RegionName,
 				)
 			}
+			// 3. 透传客户端 IP，下游服务的 geo.Server 将上游服务配置为可信代理后可取得原始客户端地址
+			if clientIP := common.ClientIPFromContext(ctx); clientIP != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, common.FORWARDEDFOR, clientIP)
+			}
 			return handler(ctx, req)
 		}
 	}
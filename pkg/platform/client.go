@@ -2,8 +2,7 @@ package platform
 
 import (
 	"context"
-	"fmt"
-	middleware "github.com/heyinLab/common/pkg/middleware/grpc"
+	"github.com/heyinLab/common/pkg/clientkit"
 	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
@@ -35,47 +34,20 @@ import (
 //	    Status: "GA",
 //	})
 type Client struct {
-	config *Config
-	conn   *grpc.ClientConn
-	logger *log.Helper
-
-	// 子服务客户端
-	iamClient *IAMClient
+	client *clientkit.Client[*IAMClient]
 }
 
 // NewClient 创建平台服务客户端（直连方式）
 //
 // 参数:
 //   - config: 客户端配置，可以使用 DefaultConfig() 获取默认配置
+//   - opts: 连接选项（可选），如 clientkit.WithLazyDial()
 //
 // 返回:
 //   - *Client: 客户端实例
 //   - error: 创建失败时的错误信息
-func NewClient(config *Config) (*Client, error) {
-	if config == nil {
-		config = DefaultConfig()
-	}
-
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "platform-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, nil, logger)
-	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
-	}
-
-	return &Client{
-		config:    config,
-		conn:      conn,
-		logger:    logger,
-		iamClient: newIAMClient(conn, logger),
-	}, nil
+func NewClient(config *Config, opts ...clientkit.Option) (*Client, error) {
+	return newClient(config, nil, opts...)
 }
 
 // NewClientWithDiscovery 创建带服务发现的平台服务客户端
@@ -83,51 +55,40 @@ func NewClient(config *Config) (*Client, error) {
 // 参数:
 //   - config: 客户端配置
 //   - discovery: 服务发现实例（如 Consul）
+//   - opts: 连接选项（可选）
 //
 // 返回:
 //   - *Client: 客户端实例
 //   - error: 创建失败时的错误信息
-func NewClientWithDiscovery(config *Config, discovery registry.Discovery) (*Client, error) {
-	if config == nil {
-		config = DefaultConfig()
-	}
-
+func NewClientWithDiscovery(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
 	if discovery == nil {
-		return nil, fmt.Errorf("服务发现实例不能为空")
+		return nil, clientkit.ErrDiscoveryRequired
 	}
+	return newClient(config, discovery, opts...)
+}
 
-	if err := config.Validate(); err != nil {
-		return nil, err
+func newClient(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
+	if config == nil {
+		config = DefaultConfig()
 	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "platform-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, discovery, logger)
+	opts = append([]clientkit.Option{clientkit.WithModule("platform-client")}, opts...)
+	client, err := clientkit.New(newIAMClient, config, discovery, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+		return nil, err
 	}
-
-	logger.Infof("平台服务客户端连接成功 (服务发现): endpoint=%s, timeout=%v", config.Endpoint, config.Timeout)
-
-	return &Client{
-		config:    config,
-		conn:      conn,
-		logger:    logger,
-		iamClient: newIAMClient(conn, logger),
-	}, nil
+	return &Client{client: client}, nil
 }
 
 // Close 关闭客户端连接
 //
-// 释放 gRPC 连接资源，应该在程序退出前调用
+// 释放 gRPC 连接资源，应该在程序退出前调用；与其他客户端共享的连接在最后一个客户端关闭时释放
 func (c *Client) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
-	}
-	return nil
+	return c.client.Close()
+}
+
+// Health 检查平台服务状态
+func (c *Client) Health(ctx context.Context) error {
+	return c.client.Health(ctx)
 }
 
 // ========== 服务访问器 ==========
@@ -142,7 +103,7 @@ func (c *Client) Close() error {
 //	    Status: "GA",
 //	})
 func (c *Client) IAM() *IAMClient {
-	return c.iamClient
+	return c.client.Service()
 }
 
 // ========== IAM 客户端 ==========
@@ -156,7 +117,7 @@ type IAMClient struct {
 }
 
 // newIAMClient 创建 IAM 客户端
-func newIAMClient(conn *grpc.ClientConn, logger *log.Helper) *IAMClient {
+func newIAMClient(conn grpc.ClientConnInterface, logger *log.Helper, _ *Config) *IAMClient {
 	return &IAMClient{
 		client: v1.NewPlatformIamServiceClient(conn),
 		logger: logger,
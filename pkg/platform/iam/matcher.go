@@ -0,0 +1,218 @@
+package iam
+
+import (
+	"sort"
+	"strings"
+
+	v1 "github.com/heyinLab/common/api/gen/go/platform/v1"
+)
+
+// Route 一条 API 权限路由
+type Route struct {
+	Code   string
+	Method string // 大写 HTTP 方法，空字符串或 * 匹配任意方法
+	Path   string // 路径模板，支持 {id}、:id 参数段与末尾的 * 通配
+}
+
+type segmentKind int
+
+const (
+	segmentStatic segmentKind = iota
+	segmentParam
+	segmentWildcard
+)
+
+type segment struct {
+	kind  segmentKind
+	value string
+}
+
+type compiledRoute struct {
+	Route
+	segments []segment
+	// score 越大越具体：静态段优先于参数段，参数段优先于通配
+	score []segmentKind
+}
+
+// APIMatcher 将 HTTP 方法与路径匹配到 API 权限编码
+//
+// 多条路由同时匹配时选择最具体的：逐段比较，静态段 > 参数段 > 通配，末尾通配匹配零段时不如精确路由；
+// 仍然相同时，指定了方法的路由优先于任意方法的路由。
+type APIMatcher struct {
+	routes []*compiledRoute
+}
+
+// NewAPIMatcher 创建匹配器
+func NewAPIMatcher(routes ...Route) *APIMatcher {
+	m := &APIMatcher{}
+	for _, r := range routes {
+		m.add(r)
+	}
+	sort.SliceStable(m.routes, func(i, j int) bool {
+		return m.routes[i].moreSpecific(m.routes[j])
+	})
+	return m
+}
+
+// NewAPIMatcherFromTree 从权限树的 api 类型节点创建匹配器
+//
+// 权限树节点没有 method 字段，path 可以写成 "GET /api/v1/users/{id}" 的形式指定方法，
+// 只写路径时匹配任意方法。
+func NewAPIMatcherFromTree(nodes []*Node) *APIMatcher {
+	var routes []Route
+	Walk(nodes, func(node *Node, _ *Node) bool {
+		if node.GetType() == TypeAPI && node.GetCode() != "" && node.GetPath() != "" {
+			method, path := splitMethod(node.GetPath())
+			routes = append(routes, Route{Code: node.GetCode(), Method: method, Path: path})
+		}
+		return true
+	})
+	return NewAPIMatcher(routes...)
+}
+
+// NewAPIMatcherFromPermissions 从权限定义创建匹配器，只使用启用的 api 类型权限
+func NewAPIMatcherFromPermissions(permissions []*v1.Permission) *APIMatcher {
+	var routes []Route
+	var walk func(permissions []*v1.Permission)
+	walk = func(permissions []*v1.Permission) {
+		for _, p := range permissions {
+			if p == nil {
+				continue
+			}
+			if p.GetType() == TypeAPI && p.GetCode() != "" && p.GetPath() != "" && (p.IsActive == nil || p.GetIsActive()) {
+				method, path := splitMethod(p.GetPath())
+				if p.GetMethod() != "" {
+					method = p.GetMethod()
+				}
+				routes = append(routes, Route{Code: p.GetCode(), Method: method, Path: path})
+			}
+			walk(p.GetChildren())
+		}
+	}
+	walk(permissions)
+	return NewAPIMatcher(routes...)
+}
+
+// splitMethod 拆分 "GET /path" 形式的路径
+func splitMethod(s string) (method, path string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); i > 0 && !strings.HasPrefix(s, "/") {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return "", s
+}
+
+func (m *APIMatcher) add(r Route) {
+	r.Method = strings.ToUpper(strings.TrimSpace(r.Method))
+	if r.Method == "*" {
+		r.Method = ""
+	}
+	c := &compiledRoute{Route: r}
+	for _, part := range splitPath(r.Path) {
+		var seg segment
+		switch {
+		case part == "*" || part == "**":
+			seg = segment{kind: segmentWildcard}
+		case strings.HasPrefix(part, ":"):
+			seg = segment{kind: segmentParam, value: part[1:]}
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			name, _, _ := strings.Cut(part[1:len(part)-1], "=")
+			seg = segment{kind: segmentParam, value: name}
+			if strings.HasSuffix(part, "=**}") {
+				seg.kind = segmentWildcard
+			}
+		default:
+			seg = segment{kind: segmentStatic, value: part}
+		}
+		c.segments = append(c.segments, seg)
+		c.score = append(c.score, seg.kind)
+		if seg.kind == segmentWildcard {
+			// 通配只能出现在末尾
+			break
+		}
+	}
+	m.routes = append(m.routes, c)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func (r *compiledRoute) moreSpecific(o *compiledRoute) bool {
+	for i := 0; i < len(r.score) && i < len(o.score); i++ {
+		if r.score[i] != o.score[i] {
+			return r.score[i] < o.score[i]
+		}
+	}
+	if len(r.score) != len(o.score) {
+		// 末尾通配可以匹配零段，比长度相同的精确路由更不具体
+		if len(r.score) > len(o.score) {
+			return r.score[len(o.score)] != segmentWildcard
+		}
+		return o.score[len(r.score)] == segmentWildcard
+	}
+	return r.Method != "" && o.Method == ""
+}
+
+// match 匹配路径，返回路径参数
+func (r *compiledRoute) match(parts []string) (map[string]string, bool) {
+	var params map[string]string
+	for i, seg := range r.segments {
+		if seg.kind == segmentWildcard {
+			return params, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		switch seg.kind {
+		case segmentStatic:
+			if seg.value != parts[i] {
+				return nil, false
+			}
+		case segmentParam:
+			if seg.value != "" {
+				if params == nil {
+					params = make(map[string]string)
+				}
+				params[seg.value] = parts[i]
+			}
+		}
+	}
+	return params, len(parts) == len(r.segments)
+}
+
+// Match 匹配 HTTP 方法与路径，返回权限编码
+func (m *APIMatcher) Match(method, path string) (string, bool) {
+	route, _, ok := m.MatchRoute(method, path)
+	if !ok {
+		return "", false
+	}
+	return route.Code, true
+}
+
+// MatchRoute 匹配 HTTP 方法与路径，返回命中的路由与路径参数
+func (m *APIMatcher) MatchRoute(method, path string) (Route, map[string]string, bool) {
+	method = strings.ToUpper(method)
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	parts := splitPath(path)
+	for _, r := range m.routes {
+		if r.Method != "" && r.Method != method {
+			continue
+		}
+		if params, ok := r.match(parts); ok {
+			return r.Route, params, true
+		}
+	}
+	return Route{}, nil, false
+}
+
+// Len 路由数量
+func (m *APIMatcher) Len() int {
+	return len(m.routes)
+}
@@ -0,0 +1,79 @@
+package iam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/heyinLab/common/api/gen/go/platform/v1"
+)
+
+func TestAPIMatcher(t *testing.T) {
+	m := NewAPIMatcher(
+		Route{Code: "user:list", Method: "GET", Path: "/api/v1/users"},
+		Route{Code: "user:get", Method: "get", Path: "/api/v1/users/{id}"},
+		Route{Code: "user:me", Method: "GET", Path: "/api/v1/users/me"},
+		Route{Code: "user:update", Method: "PUT", Path: "/api/v1/users/:id"},
+		Route{Code: "user:any", Path: "/api/v1/users/{id}/roles"},
+		Route{Code: "files", Method: "GET", Path: "/api/v1/files/*"},
+		Route{Code: "files:any", Path: "/api/v1/files/*"},
+		Route{Code: "files:list", Method: "GET", Path: "/api/v1/files"},
+		Route{Code: "docs", Method: "GET", Path: "/api/v1/docs/**"},
+	)
+
+	tests := []struct {
+		method, path, code string
+	}{
+		{"GET", "/api/v1/users", "user:list"},
+		{"GET", "/api/v1/users/", "user:list"},
+		{"GET", "/api/v1/users/42", "user:get"},
+		{"GET", "/api/v1/users/me", "user:me"}, // 静态段优先
+		{"PUT", "/api/v1/users/42?x=1", "user:update"},
+		{"POST", "/api/v1/users/42/roles", "user:any"},
+		{"GET", "/api/v1/files/a/b/c", "files"},
+		{"GET", "/api/v1/files", "files:list"}, // 精确路由优先于匹配零段的通配
+		{"POST", "/api/v1/files", "files:any"},
+		{"GET", "/api/v1/docs", "docs"},
+		{"DELETE", "/api/v1/users/42", ""},
+		{"GET", "/api/v1/users/42/unknown", ""},
+	}
+	for _, tt := range tests {
+		code, ok := m.Match(tt.method, tt.path)
+		require.Equal(t, tt.code != "", ok, "%s %s", tt.method, tt.path)
+		require.Equal(t, tt.code, code, "%s %s", tt.method, tt.path)
+	}
+
+	route, params, ok := m.MatchRoute("PUT", "/api/v1/users/42")
+	require.True(t, ok)
+	require.Equal(t, "/api/v1/users/:id", route.Path)
+	require.Equal(t, map[string]string{"id": "42"}, params)
+}
+
+func TestNewAPIMatcherFromTree(t *testing.T) {
+	m := NewAPIMatcherFromTree(testTree())
+	require.Equal(t, 2, m.Len())
+
+	code, ok := m.Match("GET", "/api/v1/users/7")
+	require.True(t, ok)
+	require.Equal(t, "system:user:get", code)
+
+	_, ok = m.Match("POST", "/api/v1/users")
+	require.False(t, ok)
+}
+
+func TestNewAPIMatcherFromPermissions(t *testing.T) {
+	str := func(s string) *string { return &s }
+	inactive := false
+	m := NewAPIMatcherFromPermissions([]*v1.Permission{{
+		Code: str("order"), Type: str(TypeMenu),
+		Children: []*v1.Permission{
+			{Code: str("order:create"), Type: str(TypeAPI), Method: str("POST"), Path: str("/api/v1/orders")},
+			{Code: str("order:delete"), Type: str(TypeAPI), Method: str("DELETE"), Path: str("/api/v1/orders/{id}"), IsActive: &inactive},
+		},
+	}})
+	require.Equal(t, 1, m.Len())
+
+	code, ok := m.Match("POST", "/api/v1/orders")
+	require.True(t, ok)
+	require.Equal(t, "order:create", code)
+}
@@ -0,0 +1,131 @@
+package iam
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+)
+
+// Requirement 接口需要的权限编码
+type Requirement struct {
+	Codes []string
+	// Any 为 true 时拥有任意一个编码即可，默认需要全部编码
+	Any bool
+}
+
+// Satisfied 判断已授权的编码是否满足要求
+func (r Requirement) Satisfied(granted CodeSet) bool {
+	if r.Any {
+		return granted.HasAny(r.Codes...)
+	}
+	return granted.HasAll(r.Codes...)
+}
+
+// Registry 按 operation 声明接口需要的权限
+type Registry map[string]Requirement
+
+// Require 声明 operation 需要全部 codes
+func (r Registry) Require(operation string, codes ...string) Registry {
+	r[operation] = Requirement{Codes: codes}
+	return r
+}
+
+// RequireAny 声明 operation 需要任意一个 codes
+func (r Registry) RequireAny(operation string, codes ...string) Registry {
+	r[operation] = Requirement{Codes: codes, Any: true}
+	return r
+}
+
+// GrantResolver 获取当前请求已授权的权限编码，通常根据 auth.FromContext 中的用户与租户查询
+type GrantResolver func(ctx context.Context) (CodeSet, error)
+
+type grantsKey struct{}
+
+// NewContext 将已授权的权限编码存入 context
+func NewContext(ctx context.Context, granted CodeSet) context.Context {
+	return context.WithValue(ctx, grantsKey{}, granted)
+}
+
+// FromContext 获取中间件解析的已授权权限编码
+func FromContext(ctx context.Context) (CodeSet, bool) {
+	granted, ok := ctx.Value(grantsKey{}).(CodeSet)
+	return granted, ok
+}
+
+// Can 判断当前请求是否拥有 code，用于接口内的细粒度（如按钮级）校验
+func Can(ctx context.Context, code string) bool {
+	granted, ok := FromContext(ctx)
+	return ok && granted.Has(code)
+}
+
+// MiddlewareOption 中间件配置项
+type MiddlewareOption func(*middlewareOptions)
+
+type middlewareOptions struct {
+	matcher     *APIMatcher
+	denyUnknown bool
+}
+
+// WithMatcher operation 未登记时，按 HTTP 方法与路径匹配 API 权限
+func WithMatcher(matcher *APIMatcher) MiddlewareOption {
+	return func(o *middlewareOptions) { o.matcher = matcher }
+}
+
+// WithDenyUnknown 既未登记也未匹配到 API 权限的请求直接拒绝，默认放行
+func WithDenyUnknown(deny bool) MiddlewareOption {
+	return func(o *middlewareOptions) { o.denyUnknown = deny }
+}
+
+// Server 权限校验中间件，需要放在 auth.Server() 之后
+//
+// 先按 operation 查找 registry，未登记时使用 WithMatcher 配置的 API 权限匹配。
+//
+// 示例:
+//
+//	iam.Server(resolver, iam.Registry{}.
+//		Require("/api.user.v1.UserService/DeleteUser", "system:user:delete"),
+//		iam.WithMatcher(iam.NewAPIMatcherFromTree(tree)),
+//	)
+func Server(resolver GrantResolver, registry Registry, opts ...MiddlewareOption) middleware.Middleware {
+	o := &middlewareOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			requirement, ok := registry[tr.Operation()]
+			if !ok && o.matcher != nil {
+				if ht, isHTTP := tr.(http.Transporter); isHTTP {
+					var code string
+					if code, ok = o.matcher.Match(ht.Request().Method, ht.Request().URL.Path); ok {
+						requirement = Requirement{Codes: []string{code}}
+					}
+				}
+			}
+			if !ok {
+				if o.denyUnknown {
+					return nil, businessErrors.ToKratosError(businessErrors.ErrPermissionDenied)
+				}
+				return handler(ctx, req)
+			}
+
+			granted, err := resolver(ctx)
+			if err != nil {
+				return nil, businessErrors.ToKratosError(err)
+			}
+			if !requirement.Satisfied(granted) {
+				return nil, businessErrors.ToKratosError(businessErrors.ErrPermissionDenied)
+			}
+			return handler(NewContext(ctx, granted), req)
+		}
+	}
+}
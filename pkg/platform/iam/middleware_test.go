@@ -0,0 +1,84 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/stretchr/testify/require"
+)
+
+type testTransport struct {
+	http.Transporter
+	operation string
+	request   *nethttp.Request
+}
+
+func (t testTransport) Operation() string         { return t.operation }
+func (t testTransport) Request() *nethttp.Request { return t.request }
+
+func serverContext(operation, method, path string) context.Context {
+	req, _ := nethttp.NewRequest(method, path, nil)
+	return transport.NewServerContext(context.Background(), testTransport{operation: operation, request: req})
+}
+
+func TestServer(t *testing.T) {
+	var resolved int
+	resolver := func(ctx context.Context) (CodeSet, error) {
+		resolved++
+		return NewCodeSet("system:user:list", "system:user:get"), nil
+	}
+	registry := Registry{}.
+		Require("/user/List", "system:user:list").
+		Require("/user/Delete", "system:user:delete").
+		RequireAny("/user/Get", "system:user:get", "system:user:admin")
+
+	var canList bool
+	handler := Server(resolver, registry, WithMatcher(NewAPIMatcherFromTree(testTree())))(
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			canList = Can(ctx, "system:user:list")
+			return "ok", nil
+		})
+
+	reply, err := handler(serverContext("/user/List", "GET", "/"), nil)
+	require.NoError(t, err)
+	require.Equal(t, "ok", reply)
+	require.True(t, canList)
+
+	_, err = handler(serverContext("/user/Get", "GET", "/"), nil)
+	require.NoError(t, err)
+
+	_, err = handler(serverContext("/user/Delete", "GET", "/"), nil)
+	require.Equal(t, 403, kerrors.Code(err))
+	require.Equal(t, "PERMISSION_DENIED", kerrors.Reason(err))
+
+	// 未登记的 operation 按 HTTP 路径匹配
+	_, err = handler(serverContext("/unknown", "GET", "/api/v1/users/1"), nil)
+	require.NoError(t, err)
+
+	// 未登记也未匹配时默认放行，且不查询授权
+	resolved = 0
+	canList = false
+	_, err = handler(serverContext("/health", "GET", "/health"), nil)
+	require.NoError(t, err)
+	require.Zero(t, resolved)
+	require.False(t, canList)
+}
+
+func TestServer_DenyUnknownAndResolverError(t *testing.T) {
+	boom := errors.New("boom")
+	handler := Server(func(ctx context.Context) (CodeSet, error) { return nil, boom },
+		Registry{}.Require("/user/List", "system:user:list"),
+		WithDenyUnknown(true),
+	)(func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil })
+
+	_, err := handler(serverContext("/health", "GET", "/health"), nil)
+	require.Equal(t, 403, kerrors.Code(err))
+
+	_, err = handler(serverContext("/user/List", "GET", "/"), nil)
+	require.ErrorIs(t, err, boom)
+}
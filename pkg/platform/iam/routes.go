@@ -0,0 +1,217 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	v1 "github.com/heyinLab/common/api/gen/go/platform/v1"
+)
+
+// ErrDuplicateRoutePath 路由树中存在重复的完整路径
+var ErrDuplicateRoutePath = errors.New("iam: duplicate route path")
+
+// RouteRecord 前端路由，字段与管理后台的路由配置一致
+type RouteRecord struct {
+	Name      string         `json:"name"`
+	Path      string         `json:"path"`
+	Component string         `json:"component,omitempty"`
+	Redirect  string         `json:"redirect,omitempty"`
+	Meta      *v1.RouteMeta  `json:"meta,omitempty"`
+	Children  []*RouteRecord `json:"children,omitempty"`
+
+	// fullPath 拼接父路由后的完整路径
+	fullPath string
+}
+
+// FullPath 返回拼接父路由后的完整路径
+func (r *RouteRecord) FullPath() string {
+	return r.fullPath
+}
+
+// Localizer 将菜单标题（通常是多语言 key）翻译为当前语言，返回空字符串时保留原值
+type Localizer func(key string) string
+
+// RouteOption 路由构建配置项
+type RouteOption func(*RouteBuilder)
+
+// WithLocalizer 设置菜单标题的翻译函数
+func WithLocalizer(localizer Localizer) RouteOption {
+	return func(b *RouteBuilder) { b.localizer = localizer }
+}
+
+// WithRouteStatuses 只输出指定发布状态的菜单，默认不过滤
+func WithRouteStatuses(statuses ...string) RouteOption {
+	return func(b *RouteBuilder) { b.statuses = statuses }
+}
+
+// RouteBuilder 根据权限树与用户已授权的权限编码生成前端路由
+//
+// 只有 menu 类型的节点会生成路由，api/button 节点用于授权判断。规则：
+//   - 菜单被授权或 meta.ignoreAccess 为 true 时可访问
+//   - 有子菜单的目录，子菜单全部不可访问时整个目录被移除
+//   - 目录的 redirect 为空或指向不可访问的路由时，重定向到第一个可访问的子路由
+//   - 同级按 meta.order、sort_order 排序
+//   - 完整路径重复时返回 ErrDuplicateRoutePath
+type RouteBuilder struct {
+	localizer Localizer
+	statuses  []string
+}
+
+// NewRouteBuilder 创建路由构建器
+func NewRouteBuilder(opts ...RouteOption) *RouteBuilder {
+	b := &RouteBuilder{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Build 生成用户可访问的路由树
+func (b *RouteBuilder) Build(nodes []*Node, granted CodeSet) ([]*RouteRecord, error) {
+	routes := b.build(nodes, granted, "/")
+	if err := ValidateRoutes(routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+func (b *RouteBuilder) build(nodes []*Node, granted CodeSet, parentPath string) []*RouteRecord {
+	menus := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil || node.GetType() != TypeMenu {
+			continue
+		}
+		if len(b.statuses) > 0 && !slices.Contains(b.statuses, node.GetStatus()) {
+			continue
+		}
+		menus = append(menus, node)
+	}
+	sort.SliceStable(menus, func(i, j int) bool {
+		return menuOrder(menus[i]) < menuOrder(menus[j])
+	})
+
+	var routes []*RouteRecord
+	for _, node := range menus {
+		fullPath := joinRoutePath(parentPath, node.GetPath())
+		children := b.build(node.GetChildren(), granted, fullPath)
+		if hasMenuChildren(node) {
+			// 目录本身不可直接访问，没有可访问的子菜单时整体移除
+			if len(children) == 0 {
+				continue
+			}
+		} else if !granted.Has(node.GetCode()) && !node.GetMeta().GetIgnoreAccess() {
+			continue
+		}
+
+		route := &RouteRecord{
+			Name:      node.GetCode(),
+			Path:      node.GetPath(),
+			Component: node.GetComponent(),
+			Meta:      b.meta(node),
+			Children:  children,
+			fullPath:  fullPath,
+		}
+		if route.Name == "" {
+			route.Name = node.GetName()
+		}
+		route.Redirect = resolveRedirect(node.GetRedirect(), children)
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+func (b *RouteBuilder) meta(node *Node) *v1.RouteMeta {
+	meta := &v1.RouteMeta{}
+	if node.GetMeta() != nil {
+		meta = proto.Clone(node.GetMeta()).(*v1.RouteMeta)
+	}
+	title := meta.GetTitle()
+	if title == "" {
+		title = node.GetName()
+	}
+	if b.localizer != nil {
+		if localized := b.localizer(title); localized != "" {
+			title = localized
+		}
+	}
+	meta.Title = &title
+	return meta
+}
+
+// menuOrder 排序值，meta.order 优先于 sort_order
+func menuOrder(node *Node) int32 {
+	if node.GetMeta() != nil && node.GetMeta().Order != nil {
+		return node.GetMeta().GetOrder()
+	}
+	return node.GetSortOrder()
+}
+
+func hasMenuChildren(node *Node) bool {
+	for _, child := range node.GetChildren() {
+		if child.GetType() == TypeMenu {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRedirect 保留指向可访问子路由或外部链接的 redirect，否则重定向到第一个在菜单中显示的子路由
+func resolveRedirect(redirect string, children []*RouteRecord) string {
+	if len(children) == 0 {
+		return redirect
+	}
+	if strings.Contains(redirect, "://") {
+		return redirect
+	}
+	for _, child := range children {
+		if redirect != "" && (child.fullPath == redirect || child.Path == redirect) {
+			return redirect
+		}
+	}
+	for _, child := range children {
+		if !child.Meta.GetHideInMenu() {
+			return child.fullPath
+		}
+	}
+	return children[0].fullPath
+}
+
+// joinRoutePath 拼接路由路径，子路由以 / 开头时视为绝对路径
+func joinRoutePath(parent, p string) string {
+	if strings.HasPrefix(p, "/") {
+		return path.Clean(p)
+	}
+	return path.Join(parent, p)
+}
+
+// ValidateRoutes 校验路由树中不存在重复的完整路径
+func ValidateRoutes(routes []*RouteRecord) error {
+	seen := make(map[string]string)
+	var duplicates []string
+	var walk func(routes []*RouteRecord, parent string)
+	walk = func(routes []*RouteRecord, parent string) {
+		for _, r := range routes {
+			fullPath := r.fullPath
+			if fullPath == "" {
+				fullPath = joinRoutePath(parent, r.Path)
+			}
+			if name, ok := seen[fullPath]; ok {
+				duplicates = append(duplicates, fmt.Sprintf("%s (%s, %s)", fullPath, name, r.Name))
+			} else {
+				seen[fullPath] = r.Name
+			}
+			walk(r.Children, fullPath)
+		}
+	}
+	walk(routes, "/")
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateRoutePath, strings.Join(duplicates, "; "))
+	}
+	return nil
+}
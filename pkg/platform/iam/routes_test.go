@@ -0,0 +1,124 @@
+package iam
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/heyinLab/common/api/gen/go/platform/v1"
+)
+
+func menu(id uint32, code, path string, order int32, children ...*Node) *Node {
+	n := withPath(node(id, code, TypeMenu, StatusGA, children...), path)
+	title := "menu." + code
+	n.Meta = &v1.RouteMeta{Title: &title}
+	n.SortOrder = order
+	return n
+}
+
+func menuTree() []*Node {
+	hidden := true
+	detail := menu(14, "system:user:detail", "detail/:id", 3)
+	detail.Meta.HideInMenu = &hidden
+
+	users := menu(11, "system:user", "user", 2,
+		node(15, "system:user:delete", TypeButton, StatusGA),
+	)
+	users.Component = new(string)
+	*users.Component = "/system/user/index"
+
+	return []*Node{
+		menu(20, "report", "/report", 2),
+		menu(10, "system", "/system", 1,
+			users,
+			menu(12, "system:role", "role", 1),
+			menu(13, "system:audit", "audit", 0),
+			detail,
+		),
+		menu(30, "empty", "/empty", 3,
+			menu(31, "empty:child", "child", 1),
+		),
+	}
+}
+
+func TestRouteBuilder(t *testing.T) {
+	localizer := func(key string) string {
+		return map[string]string{"menu.system": "系统管理", "menu.report": "报表"}[key]
+	}
+	routes, err := NewRouteBuilder(WithLocalizer(localizer)).Build(menuTree(),
+		NewCodeSet("system:user", "system:role", "system:user:detail", "report"))
+	require.NoError(t, err)
+
+	// 没有可访问子菜单的目录被移除，同级按排序值排列
+	require.Len(t, routes, 2)
+	system, report := routes[0], routes[1]
+	require.Equal(t, "system", system.Name)
+	require.Equal(t, "系统管理", system.Meta.GetTitle())
+	require.Equal(t, "报表", report.Meta.GetTitle())
+
+	var names []string
+	for _, child := range system.Children {
+		names = append(names, child.Name)
+	}
+	require.Equal(t, []string{"system:role", "system:user", "system:user:detail"}, names)
+	require.Equal(t, "menu.system:role", system.Children[0].Meta.GetTitle())
+	require.Equal(t, "/system/user", system.Children[1].FullPath())
+	require.Equal(t, "/system/user/index", system.Children[1].Component)
+	require.Empty(t, system.Children[1].Children)
+
+	// 没有配置 redirect 时重定向到第一个可访问的子路由
+	require.Equal(t, "/system/role", system.Redirect)
+
+	data, err := json.Marshal(system.Children[1])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"system:user","path":"user","component":"/system/user/index","meta":{"title":"menu.system:user"}}`, string(data))
+}
+
+func TestRouteBuilder_Redirect(t *testing.T) {
+	tree := menuTree()
+	redirect := "/system/audit"
+	tree[1].Redirect = &redirect
+
+	granted := NewCodeSet("system:role", "system:audit")
+	routes, err := NewRouteBuilder().Build(tree, granted)
+	require.NoError(t, err)
+	require.Equal(t, "/system/audit", routes[0].Redirect)
+
+	// redirect 指向的子路由不可访问时回退到第一个可访问的子路由
+	delete(granted, "system:audit")
+	routes, err = NewRouteBuilder().Build(tree, granted)
+	require.NoError(t, err)
+	require.Equal(t, "/system/role", routes[0].Redirect)
+
+	// 在菜单中隐藏的子路由不作为重定向目标
+	routes, err = NewRouteBuilder().Build(tree, NewCodeSet("system:user:detail", "system:role"))
+	require.NoError(t, err)
+	require.Equal(t, "/system/role", routes[0].Redirect)
+}
+
+func TestRouteBuilder_StatusAndIgnoreAccess(t *testing.T) {
+	tree := menuTree()
+	tree[0].Status = StatusBeta
+	ignore := true
+	tree[0].Meta.IgnoreAccess = &ignore
+
+	routes, err := NewRouteBuilder().Build(tree, NewCodeSet())
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, "report", routes[0].Name)
+
+	routes, err = NewRouteBuilder(WithRouteStatuses(StatusGA)).Build(tree, NewCodeSet())
+	require.NoError(t, err)
+	require.Empty(t, routes)
+}
+
+func TestRouteBuilder_DuplicatePath(t *testing.T) {
+	tree := menuTree()
+	tree[1].Children[1].Path = new(string)
+	*tree[1].Children[1].Path = "/system/user"
+
+	_, err := NewRouteBuilder().Build(tree, NewCodeSet("system:user", "system:role"))
+	require.ErrorIs(t, err, ErrDuplicateRoutePath)
+	require.Contains(t, err.Error(), "/system/user (system:role, system:user)")
+}
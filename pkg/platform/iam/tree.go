@@ -0,0 +1,339 @@
+package iam
+
+import (
+	"slices"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	v1 "github.com/heyinLab/common/api/gen/go/platform/v1"
+)
+
+// Node 权限树节点
+type Node = v1.TenantPermissionTreeNode
+
+// 权限类型
+const (
+	TypeMenu   = "menu"
+	TypeAPI    = "api"
+	TypeButton = "button"
+)
+
+// 权限发布状态
+const (
+	StatusDev  = "DEV"
+	StatusBeta = "BETA"
+	StatusGA   = "GA"
+)
+
+// CodeSet 权限编码集合
+type CodeSet map[string]struct{}
+
+// NewCodeSet 创建权限编码集合，忽略空编码
+func NewCodeSet(codes ...string) CodeSet {
+	set := make(CodeSet, len(codes))
+	for _, code := range codes {
+		if code != "" {
+			set[code] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Has 是否包含 code
+func (s CodeSet) Has(code string) bool {
+	_, ok := s[code]
+	return ok
+}
+
+// HasAll 是否包含全部 codes，codes 为空时返回 true
+func (s CodeSet) HasAll(codes ...string) bool {
+	for _, code := range codes {
+		if !s.Has(code) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAny 是否包含任意一个 codes，codes 为空时返回 true
+func (s CodeSet) HasAny(codes ...string) bool {
+	if len(codes) == 0 {
+		return true
+	}
+	for _, code := range codes {
+		if s.Has(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Codes 返回排序后的编码列表
+func (s CodeSet) Codes() []string {
+	codes := make([]string, 0, len(s))
+	for code := range s {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Walk 深度优先（先序）遍历权限树，fn 返回 false 时不再遍历该节点的子节点
+func Walk(nodes []*Node, fn func(node *Node, parent *Node) bool) {
+	var walk func(nodes []*Node, parent *Node)
+	walk = func(nodes []*Node, parent *Node) {
+		for _, node := range nodes {
+			if node == nil {
+				continue
+			}
+			if fn(node, parent) {
+				walk(node.GetChildren(), node)
+			}
+		}
+	}
+	walk(nodes, nil)
+}
+
+// Flatten 按先序展开权限树，返回的节点仍保留 Children
+func Flatten(nodes []*Node) []*Node {
+	var result []*Node
+	Walk(nodes, func(node *Node, _ *Node) bool {
+		result = append(result, node)
+		return true
+	})
+	return result
+}
+
+// Codes 返回树中所有节点的权限编码
+func Codes(nodes []*Node) CodeSet {
+	set := make(CodeSet)
+	Walk(nodes, func(node *Node, _ *Node) bool {
+		if code := node.GetCode(); code != "" {
+			set[code] = struct{}{}
+		}
+		return true
+	})
+	return set
+}
+
+// Filter 复制权限树，只保留 keep 返回 true 的节点
+//
+// 节点被过滤时其整个子树一并移除；返回的是新树，不修改原始节点。
+func Filter(nodes []*Node, keep func(node *Node) bool) []*Node {
+	var result []*Node
+	for _, node := range nodes {
+		if node == nil || !keep(node) {
+			continue
+		}
+		clone := shallowClone(node)
+		clone.Children = Filter(node.GetChildren(), keep)
+		result = append(result, clone)
+	}
+	return result
+}
+
+// FilterByStatus 只保留指定发布状态（DEV/BETA/GA）的节点
+//
+// 例如生产环境只展示 GA，灰度环境展示 BETA 与 GA。
+func FilterByStatus(nodes []*Node, statuses ...string) []*Node {
+	return Filter(nodes, func(node *Node) bool {
+		return slices.Contains(statuses, node.GetStatus())
+	})
+}
+
+// Prune 按已授权的权限编码裁剪权限树
+//
+// 节点本身被授权或存在被授权的子孙节点时保留，父节点仅为保持树结构而保留。
+// 授权了父节点不代表授权了子节点，子节点需要单独授权。
+func Prune(nodes []*Node, granted CodeSet) []*Node {
+	var result []*Node
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		children := Prune(node.GetChildren(), granted)
+		if len(children) == 0 && !granted.Has(node.GetCode()) {
+			continue
+		}
+		clone := shallowClone(node)
+		clone.Children = children
+		result = append(result, clone)
+	}
+	return result
+}
+
+// shallowClone 复制节点自身字段，不复制子节点
+func shallowClone(node *Node) *Node {
+	clone := &Node{
+		Id:          node.Id,
+		Name:        node.Name,
+		Code:        node.Code,
+		Type:        node.Type,
+		ParentId:    node.ParentId,
+		ParentCode:  node.ParentCode,
+		Path:        node.Path,
+		Redirect:    node.Redirect,
+		Component:   node.Component,
+		Status:      node.Status,
+		ProductCode: node.ProductCode,
+		SortOrder:   node.SortOrder,
+	}
+	if node.Meta != nil {
+		clone.Meta = proto.Clone(node.Meta).(*v1.RouteMeta)
+	}
+	return clone
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Index 权限树索引，按编码查找节点及其祖先
+//
+// Index 构建后只读，可并发使用；原始树不应再被修改。
+type Index struct {
+	roots  []*Node
+	nodes  []*Node
+	byCode map[string]*Node
+	byID   map[uint32]*Node
+	parent map[*Node]*Node
+}
+
+// NewIndex 为权限树建立索引
+func NewIndex(nodes []*Node) *Index {
+	idx := &Index{
+		roots:  nodes,
+		byCode: make(map[string]*Node),
+		byID:   make(map[uint32]*Node),
+		parent: make(map[*Node]*Node),
+	}
+	Walk(nodes, func(node *Node, parent *Node) bool {
+		idx.nodes = append(idx.nodes, node)
+		if code := node.GetCode(); code != "" {
+			idx.byCode[code] = node
+		}
+		if node.GetId() != 0 {
+			idx.byID[node.GetId()] = node
+		}
+		if parent != nil {
+			idx.parent[node] = parent
+		}
+		return true
+	})
+	return idx
+}
+
+// Roots 返回根节点
+func (idx *Index) Roots() []*Node {
+	return idx.roots
+}
+
+// Nodes 返回先序展开的全部节点
+func (idx *Index) Nodes() []*Node {
+	return idx.nodes
+}
+
+// Lookup 按编码查找节点
+func (idx *Index) Lookup(code string) (*Node, bool) {
+	node, ok := idx.byCode[code]
+	return node, ok
+}
+
+// LookupID 按 ID 查找节点
+func (idx *Index) LookupID(id uint32) (*Node, bool) {
+	node, ok := idx.byID[id]
+	return node, ok
+}
+
+// Parent 返回节点的父节点，根节点返回 nil
+func (idx *Index) Parent(node *Node) *Node {
+	return idx.parent[node]
+}
+
+// Ancestors 返回节点的祖先，从根节点到直接父节点
+func (idx *Index) Ancestors(code string) []*Node {
+	node, ok := idx.byCode[code]
+	if !ok {
+		return nil
+	}
+	var ancestors []*Node
+	for p := idx.parent[node]; p != nil; p = idx.parent[p] {
+		ancestors = append(ancestors, p)
+	}
+	slices.Reverse(ancestors)
+	return ancestors
+}
+
+// Codes 返回所有权限编码
+func (idx *Index) Codes() CodeSet {
+	set := make(CodeSet, len(idx.byCode))
+	for code := range idx.byCode {
+		set[code] = struct{}{}
+	}
+	return set
+}
+
+// ByType 返回指定类型的节点（menu/api/button）
+func (idx *Index) ByType(typ string) []*Node {
+	var result []*Node
+	for _, node := range idx.nodes {
+		if node.GetType() == typ {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Changes 两个版本权限树的差异，节点按编码对应，没有编码的节点被忽略
+type Changes struct {
+	Added    []*Node
+	Removed  []*Node
+	Modified []Modification
+}
+
+// Modification 编码不变但内容（含父节点、状态、路由元数据等）发生变化的节点
+type Modification struct {
+	Old *Node
+	New *Node
+}
+
+// Empty 是否没有差异
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// Diff 比较两个版本的权限树，结果按新旧树的先序排列
+//
+// 只比较节点自身字段，子节点的变化单独体现在子节点上。
+func Diff(old, current []*Node) *Changes {
+	oldIdx, newIdx := NewIndex(old), NewIndex(current)
+	changes := &Changes{}
+
+	for _, node := range newIdx.nodes {
+		code := node.GetCode()
+		if code == "" {
+			continue
+		}
+		prev, ok := oldIdx.byCode[code]
+		if !ok {
+			changes.Added = append(changes.Added, node)
+			continue
+		}
+		if !proto.Equal(shallowClone(prev), shallowClone(node)) {
+			changes.Modified = append(changes.Modified, Modification{Old: prev, New: node})
+		}
+	}
+	for _, node := range oldIdx.nodes {
+		code := node.GetCode()
+		if code == "" {
+			continue
+		}
+		if _, ok := newIdx.byCode[code]; !ok {
+			changes.Removed = append(changes.Removed, node)
+		}
+	}
+	return changes
+}
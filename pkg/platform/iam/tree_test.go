@@ -0,0 +1,133 @@
+package iam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/heyinLab/common/api/gen/go/platform/v1"
+)
+
+func node(id uint32, code, typ, status string, children ...*Node) *Node {
+	n := &Node{Id: id, Name: code, Code: &code, Type: &typ, Status: status, Children: children}
+	for _, child := range children {
+		child.ParentId = &n.Id
+		child.ParentCode = n.Code
+	}
+	return n
+}
+
+func withPath(n *Node, path string) *Node {
+	n.Path = &path
+	return n
+}
+
+func testTree() []*Node {
+	return []*Node{
+		node(1, "system", TypeMenu, StatusGA,
+			node(2, "system:user", TypeMenu, StatusGA,
+				withPath(node(3, "system:user:list", TypeAPI, StatusGA), "GET /api/v1/users"),
+				withPath(node(4, "system:user:get", TypeAPI, StatusGA), "GET /api/v1/users/{id}"),
+				node(5, "system:user:delete", TypeButton, StatusBeta),
+			),
+			node(6, "system:audit", TypeMenu, StatusDev,
+				node(7, "system:audit:list", TypeAPI, StatusGA),
+			),
+		),
+		node(8, "report", TypeMenu, StatusGA),
+	}
+}
+
+func codesOf(nodes []*Node) []string {
+	var codes []string
+	for _, n := range Flatten(nodes) {
+		codes = append(codes, n.GetCode())
+	}
+	return codes
+}
+
+func TestFlattenAndIndex(t *testing.T) {
+	tree := testTree()
+	require.Equal(t, []string{
+		"system", "system:user", "system:user:list", "system:user:get", "system:user:delete",
+		"system:audit", "system:audit:list", "report",
+	}, codesOf(tree))
+
+	idx := NewIndex(tree)
+	n, ok := idx.Lookup("system:user:get")
+	require.True(t, ok)
+	require.Equal(t, uint32(4), n.GetId())
+	require.Equal(t, "system:user", idx.Parent(n).GetCode())
+
+	var ancestors []string
+	for _, a := range idx.Ancestors("system:user:get") {
+		ancestors = append(ancestors, a.GetCode())
+	}
+	require.Equal(t, []string{"system", "system:user"}, ancestors)
+	require.Len(t, idx.ByType(TypeAPI), 3)
+	require.Len(t, idx.Codes(), 8)
+
+	_, ok = idx.LookupID(100)
+	require.False(t, ok)
+}
+
+func TestFilterByStatus(t *testing.T) {
+	tree := testTree()
+
+	ga := FilterByStatus(tree, StatusGA)
+	// DEV 的菜单被过滤时，其 GA 子节点一并移除
+	require.Equal(t, []string{"system", "system:user", "system:user:list", "system:user:get", "report"}, codesOf(ga))
+
+	beta := FilterByStatus(tree, StatusBeta, StatusGA)
+	require.Contains(t, codesOf(beta), "system:user:delete")
+
+	// 原始树不受影响
+	require.Len(t, Flatten(tree), 8)
+}
+
+func TestPrune(t *testing.T) {
+	tree := testTree()
+	pruned := Prune(tree, NewCodeSet("system:user:get", "report"))
+	require.Equal(t, []string{"system", "system:user", "system:user:get", "report"}, codesOf(pruned))
+
+	// 授权父节点不代表授权子节点
+	pruned = Prune(tree, NewCodeSet("system:user"))
+	require.Equal(t, []string{"system", "system:user"}, codesOf(pruned))
+	require.Empty(t, pruned[0].GetChildren()[0].GetChildren())
+
+	require.Empty(t, Prune(tree, NewCodeSet()))
+}
+
+func TestDiff(t *testing.T) {
+	old := testTree()
+	current := testTree()
+
+	require.True(t, Diff(old, current).Empty())
+
+	// 修改状态、新增、删除节点
+	user := current[0].Children[0]
+	user.Children[2].Status = StatusGA
+	user.Children = append(user.Children, node(9, "system:user:create", TypeButton, StatusGA))
+	current = current[:1]
+	current[0].Meta = &v1.RouteMeta{}
+
+	changes := Diff(old, current)
+	require.Len(t, changes.Added, 1)
+	require.Equal(t, "system:user:create", changes.Added[0].GetCode())
+	require.Len(t, changes.Removed, 1)
+	require.Equal(t, "report", changes.Removed[0].GetCode())
+	require.Len(t, changes.Modified, 2)
+	require.Equal(t, "system", changes.Modified[0].New.GetCode())
+	require.Equal(t, "system:user:delete", changes.Modified[1].New.GetCode())
+	require.Equal(t, StatusBeta, changes.Modified[1].Old.GetStatus())
+}
+
+func TestCodeSet(t *testing.T) {
+	set := NewCodeSet("a", "b", "")
+	require.Equal(t, []string{"a", "b"}, set.Codes())
+	require.True(t, set.HasAll("a", "b"))
+	require.False(t, set.HasAll("a", "c"))
+	require.True(t, set.HasAny("c", "b"))
+	require.False(t, set.HasAny("c"))
+	require.True(t, set.HasAny())
+}
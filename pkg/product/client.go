@@ -2,87 +2,54 @@ package product
 
 import (
 	"context"
-	"fmt"
+	"iter"
+
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/registry"
 	v1 "github.com/heyinLab/common/api/gen/go/product/v1"
-	middleware "github.com/heyinLab/common/pkg/middleware/grpc"
+	"github.com/heyinLab/common/pkg/clientkit"
+	"github.com/heyinLab/common/pkg/utils/pagination"
 	"google.golang.org/grpc"
 )
 
 type Client struct {
-	config        *Config
-	conn          *grpc.ClientConn
-	logger        *log.Helper
-	productClient *ProductClient
+	client *clientkit.Client[*ProductClient]
 }
 
-func NewClient(config *Config) (*Client, error) {
-	if config == nil {
-		config = DefaultConfig()
-	}
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "product-client",
-	))
+func NewClient(config *Config, opts ...clientkit.Option) (*Client, error) {
+	return newClient(config, nil, opts...)
+}
 
-	conn, err := middleware.CreateGRPCConn(config, nil, logger)
-	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+func NewClientWithDiscovery(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
+	if discovery == nil {
+		return nil, clientkit.ErrDiscoveryRequired
 	}
-	return &Client{
-		config:        config,
-		conn:          conn,
-		logger:        logger,
-		productClient: newProductClient(conn, logger, config),
-	}, nil
+	return newClient(config, discovery, opts...)
 }
 
-func NewClientWithDiscovery(config *Config, discovery registry.Discovery) (*Client, error) {
+func newClient(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
-
-	if discovery == nil {
-		return nil, fmt.Errorf("服务发现实例不能为空")
-	}
-
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "product-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, discovery, logger)
+	opts = append([]clientkit.Option{clientkit.WithModule("product-client")}, opts...)
+	client, err := clientkit.New(newProductClient, config, discovery, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+		return nil, err
 	}
-
-	logger.Infof("平台服务客户端连接成功 (服务发现): endpoint=%s, timeout=%v", config.Endpoint, config.Timeout)
-
-	return &Client{
-		config:        config,
-		conn:          conn,
-		logger:        logger,
-		productClient: newProductClient(conn, logger, config),
-	}, nil
+	return &Client{client: client}, nil
 }
 
 func (c *Client) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
-	}
-	return nil
+	return c.client.Close()
+}
+
+// Health 检查产品服务状态
+func (c *Client) Health(ctx context.Context) error {
+	return c.client.Health(ctx)
 }
 
 func (c *Client) ProductClient() *ProductClient {
-	return c.productClient
+	return c.client.Service()
 }
 
 type ProductClient struct {
@@ -91,7 +58,7 @@ type ProductClient struct {
 	config *Config
 }
 
-func newProductClient(conn *grpc.ClientConn, logger *log.Helper, config *Config) *ProductClient {
+func newProductClient(conn grpc.ClientConnInterface, logger *log.Helper, config *Config) *ProductClient {
 	return &ProductClient{
 		client: v1.NewProductInternalServiceClient(conn),
 		logger: logger,
@@ -115,9 +82,6 @@ func (c *ProductClient) GetPlan(ctx context.Context, planCode string, opt *GetPl
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalGetPlan(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("获取套餐信息失败:plan_ode=%s,error=%v", planCode, err)
@@ -143,9 +107,6 @@ func (c *ProductClient) MerchantGetPlan(ctx context.Context, planCode string, op
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalMerchantGetPlan(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("商户获取套餐信息失败:plan_ode=%s,error=%v", planCode, err)
@@ -171,9 +132,6 @@ func (c *ProductClient) GetProduct(ctx context.Context, productCode string, opt
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalGetProduct(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("获取产品信息失败:product_code=%s,error=%v", productCode, err)
@@ -199,9 +157,6 @@ func (c *ProductClient) MerchantGetProduct(ctx context.Context, productCode stri
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalMerchantGetProduct(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("商户获取产品信息失败:product_code=%s,error=%v", productCode, err)
@@ -252,9 +207,6 @@ func (c *ProductClient) ListPricingRules(ctx context.Context, opt *ListPricingRu
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalListPricingRules(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("获取定价规则列表失败:error=%v", err)
@@ -263,3 +215,20 @@ func (c *ProductClient) ListPricingRules(ctx context.Context, opt *ListPricingRu
 
 	return resp, nil
 }
+
+// PricingRules 遍历定价规则，自动翻页，opt 中的 Page/PageSize 会被忽略
+func (c *ProductClient) PricingRules(ctx context.Context, opt *ListPricingRulesOption, opts ...pagination.Option) iter.Seq2[*v1.InternalPricingRuleInfo, error] {
+	return pagination.Iterate(ctx, func(ctx context.Context, page, pageSize int32) (pagination.Page[*v1.InternalPricingRuleInfo], error) {
+		pageOpt := &ListPricingRulesOption{}
+		if opt != nil {
+			*pageOpt = *opt
+		}
+		pageOpt.Page, pageOpt.PageSize = &page, &pageSize
+
+		resp, err := c.ListPricingRules(ctx, pageOpt)
+		if err != nil {
+			return pagination.Page[*v1.InternalPricingRuleInfo]{}, err
+		}
+		return pagination.Page[*v1.InternalPricingRuleInfo]{Items: resp.GetRules(), Total: int64(resp.GetTotal())}, nil
+	}, opts...)
+}
@@ -0,0 +1,333 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/heyinLab/common/api/gen/go/product/v1"
+	"github.com/heyinLab/common/pkg/utils/timeutil"
+)
+
+var (
+	ErrRuleNotFound          = errors.New("pricing: rule not found")
+	ErrInvalidValue          = errors.New("pricing: invalid rule value")
+	ErrUnlimitedNotAllowed   = errors.New("pricing: rule does not allow unlimited")
+	ErrUnsupportedResetCycle = errors.New("pricing: unsupported reset period")
+)
+
+// Grant 一个订阅授予的套餐参数
+type Grant struct {
+	SubscriptionCode string
+	Parameters       []*v1.InternalPlanParameter
+	// Quantity 购买份数，可叠加（is_stackable）的参数按份数累加，0 视为 1
+	Quantity int32
+}
+
+// Ledger 用量台账
+type Ledger interface {
+	// Sum 统计 ruleKey 在 [from, to) 内的用量，from/to 为零值表示不限起始/结束时间
+	Sum(ruleKey string, from, to time.Time) float64
+}
+
+// UsageEntry 一条用量记录，Amount 为负数表示释放
+type UsageEntry struct {
+	RuleKey string
+	Amount  float64
+	At      time.Time
+}
+
+// MemoryLedger 内存台账
+type MemoryLedger []UsageEntry
+
+// Sum 实现 Ledger 接口
+func (l MemoryLedger) Sum(ruleKey string, from, to time.Time) float64 {
+	var total float64
+	for _, e := range l {
+		if e.RuleKey != ruleKey || e.At.Before(from) || (!to.IsZero() && !e.At.Before(to)) {
+			continue
+		}
+		total += e.Amount
+	}
+	return total
+}
+
+// Input 评估输入
+type Input struct {
+	Grants []Grant
+	Ledger Ledger
+	Now    time.Time
+	// Location 租户时区，用于计算重置周期；nil 时使用 timeutil.GetDefaultTimeLocation()
+	Location *time.Location
+}
+
+func (in Input) location() *time.Location {
+	if in.Location != nil {
+		return in.Location
+	}
+	return timeutil.GetDefaultTimeLocation()
+}
+
+// Limit 规则在当前输入下的限额
+type Limit struct {
+	RuleKey   string
+	RuleType  v1.InternalRuleType
+	Unit      string
+	Unlimited bool
+	Enabled   bool    // 开关型规则是否开启；数值型规则上限大于 0 或不限时为 true
+	Value     float64 // 上限，不限时为 -1
+	Text      string  // 字符串型参数的值
+	Used      float64 // 当前周期内的用量
+	Remaining float64 // 剩余量，不限时为 -1
+	// PeriodStart/ResetAt 当前用量周期的起止时间，不重置的规则均为零值
+	PeriodStart time.Time
+	ResetAt     time.Time
+}
+
+// Allows 判断能否再使用 amount
+func (l *Limit) Allows(amount float64) bool {
+	switch {
+	case l.RuleType == v1.InternalRuleType_INTERNAL_SWITCH:
+		return l.Enabled
+	case l.Unlimited:
+		return true
+	default:
+		return l.Used+amount <= l.Value
+	}
+}
+
+// Violation 未通过的规则
+type Violation struct {
+	Limit  *Limit
+	Amount float64
+}
+
+func (v Violation) Error() string {
+	if v.Limit.RuleType == v1.InternalRuleType_INTERNAL_SWITCH {
+		return fmt.Sprintf("%s is disabled", v.Limit.RuleKey)
+	}
+	return fmt.Sprintf("%s exceeds limit: used %g + %g > %g", v.Limit.RuleKey, v.Limit.Used, v.Amount, v.Limit.Value)
+}
+
+// Decision 检查点的校验结果
+type Decision struct {
+	Checkpoint string
+	Limits     []*Limit
+	Violations []Violation
+}
+
+// Allowed 是否全部通过
+func (d *Decision) Allowed() bool {
+	return len(d.Violations) == 0
+}
+
+// Err 返回第一个未通过的规则，全部通过时返回 nil
+func (d *Decision) Err() error {
+	if d.Allowed() {
+		return nil
+	}
+	return d.Violations[0]
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Evaluator 定价规则评估器
+//
+// 规则定义来自 ProductClient.ListPricingRules，参数值来自套餐的 InternalPlanParameter。
+// 同一产品有多个订阅时：累加规则（is_accumulative）的上限相加，其他规则取最大值。
+type Evaluator struct {
+	rules        map[string]*v1.InternalPricingRuleInfo
+	byCheckpoint map[string][]*v1.InternalPricingRuleInfo
+}
+
+// NewEvaluator 创建评估器，禁用的规则会被忽略
+func NewEvaluator(rules []*v1.InternalPricingRuleInfo) *Evaluator {
+	e := &Evaluator{
+		rules:        make(map[string]*v1.InternalPricingRuleInfo, len(rules)),
+		byCheckpoint: make(map[string][]*v1.InternalPricingRuleInfo),
+	}
+	for _, rule := range rules {
+		if rule.GetStatus() == v1.InternalRuleStatus_INTERNAL_RULE_INACTIVE {
+			continue
+		}
+		e.rules[rule.GetRuleKey()] = rule
+		for _, cp := range rule.GetCheckpoints() {
+			e.byCheckpoint[cp] = append(e.byCheckpoint[cp], rule)
+		}
+	}
+	for _, rules := range e.byCheckpoint {
+		slices.SortStableFunc(rules, func(a, b *v1.InternalPricingRuleInfo) int {
+			return int(a.GetSortOrder()) - int(b.GetSortOrder())
+		})
+	}
+	return e
+}
+
+// Rule 获取规则定义
+func (e *Evaluator) Rule(ruleKey string) (*v1.InternalPricingRuleInfo, bool) {
+	rule, ok := e.rules[ruleKey]
+	return rule, ok
+}
+
+// Check 校验检查点上的所有规则，amount 为本次操作在数值型规则上的用量
+func (e *Evaluator) Check(in Input, checkpoint string, amount float64) (*Decision, error) {
+	d := &Decision{Checkpoint: checkpoint}
+	for _, rule := range e.byCheckpoint[checkpoint] {
+		limit, err := e.limit(rule, in)
+		if err != nil {
+			return nil, err
+		}
+		d.Limits = append(d.Limits, limit)
+		if !limit.Allows(amount) {
+			d.Violations = append(d.Violations, Violation{Limit: limit, Amount: amount})
+		}
+	}
+	return d, nil
+}
+
+// Limit 计算单个规则的限额与用量
+func (e *Evaluator) Limit(in Input, ruleKey string) (*Limit, error) {
+	rule, ok := e.rules[ruleKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRuleNotFound, ruleKey)
+	}
+	return e.limit(rule, in)
+}
+
+func (e *Evaluator) limit(rule *v1.InternalPricingRuleInfo, in Input) (*Limit, error) {
+	limit := &Limit{RuleKey: rule.GetRuleKey(), RuleType: rule.GetRuleType(), Unit: rule.GetUnit()}
+
+	var found bool
+	for _, grant := range in.Grants {
+		for _, p := range grant.Parameters {
+			if p.GetRuleKey() != rule.GetRuleKey() {
+				continue
+			}
+			if err := limit.merge(rule, p, grant.Quantity, found); err != nil {
+				return nil, err
+			}
+			found = true
+		}
+	}
+
+	if limit.Unlimited {
+		limit.Value, limit.Remaining = -1, -1
+		limit.Enabled = true
+	} else if rule.GetRuleType() != v1.InternalRuleType_INTERNAL_SWITCH {
+		limit.Enabled = limit.Value > 0
+	}
+	if rule.GetRuleType() == v1.InternalRuleType_INTERNAL_SWITCH || in.Ledger == nil {
+		limit.Remaining = remaining(limit)
+		return limit, nil
+	}
+
+	if rule.GetIsResetPeriodically() {
+		start, next, err := ResetWindow(rule.GetResetPeriod(), in.Now, in.location())
+		if err != nil {
+			return nil, err
+		}
+		limit.PeriodStart, limit.ResetAt = start, next
+	}
+	// 不重置的规则统计全部历史
+	limit.Used = in.Ledger.Sum(rule.GetRuleKey(), limit.PeriodStart, limit.ResetAt)
+	limit.Remaining = remaining(limit)
+	return limit, nil
+}
+
+// merge 合并一个订阅的参数
+func (l *Limit) merge(rule *v1.InternalPricingRuleInfo, p *v1.InternalPlanParameter, quantity int32, accumulate bool) error {
+	if quantity <= 0 {
+		quantity = 1
+	}
+	if !p.GetIsStackable() {
+		quantity = 1
+	}
+
+	if p.GetIsUnlimited() || strings.TrimSpace(p.GetRuleValue()) == "-1" {
+		if !rule.GetAllowUnlimited() {
+			return fmt.Errorf("%w: %s", ErrUnlimitedNotAllowed, rule.GetRuleKey())
+		}
+		l.Unlimited = true
+		return nil
+	}
+
+	raw := strings.TrimSpace(p.GetRuleValue())
+	switch p.GetValueType() {
+	case v1.InternalValueType_INTERNAL_VALUE_TYPE_BOOLEAN:
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%w: %s=%q", ErrInvalidValue, rule.GetRuleKey(), raw)
+		}
+		l.Enabled = l.Enabled || enabled
+		if enabled {
+			l.Value = max(l.Value, 1)
+		}
+		return nil
+	case v1.InternalValueType_INTERNAL_VALUE_TYPE_STRING:
+		if rule.GetRuleType() == v1.InternalRuleType_INTERNAL_SWITCH || raw == "" {
+			enabled, err := strconv.ParseBool(raw)
+			if err != nil {
+				enabled = raw != ""
+			}
+			l.Text = raw
+			l.Enabled = l.Enabled || enabled
+			return nil
+		}
+		// 数值型规则的字符串值按数字解析
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return fmt.Errorf("%w: %s=%q", ErrInvalidValue, rule.GetRuleKey(), raw)
+	}
+	value *= float64(quantity)
+
+	if rule.GetRuleType() == v1.InternalRuleType_INTERNAL_SWITCH {
+		l.Enabled = l.Enabled || value > 0
+		l.Value = max(l.Value, value)
+		return nil
+	}
+	if accumulate && rule.GetIsAccumulative() {
+		l.Value += value
+		return nil
+	}
+	l.Value = max(l.Value, value)
+	return nil
+}
+
+func remaining(l *Limit) float64 {
+	if l.Unlimited {
+		return -1
+	}
+	return max(l.Value-l.Used, 0)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ResetWindow 计算 now 所在重置周期的起止时间 [start, next)，按 loc 时区的自然日/周/月/年划分
+//
+// 周以周一为起始。不重置（NONE/UNSPECIFIED）返回零值。
+func ResetWindow(period v1.InternalResetPeriod, now time.Time, loc *time.Location) (start, next time.Time, err error) {
+	var p timeutil.Period
+	switch period {
+	case v1.InternalResetPeriod_INTERNAL_RESET_PERIOD_UNSPECIFIED, v1.InternalResetPeriod_INTERNAL_NONE:
+		return time.Time{}, time.Time{}, nil
+	case v1.InternalResetPeriod_INTERNAL_DAILY:
+		p = timeutil.Daily
+	case v1.InternalResetPeriod_INTERNAL_WEEKLY:
+		p = timeutil.Weekly
+	case v1.InternalResetPeriod_INTERNAL_MONTHLY:
+		p = timeutil.Monthly
+	case v1.InternalResetPeriod_INTERNAL_YEARLY:
+		p = timeutil.Yearly
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", ErrUnsupportedResetCycle, period)
+	}
+
+	w := timeutil.NewPeriodEngine(timeutil.WithLocation(loc)).At(p, now)
+	return w.Start, w.End, nil
+}
@@ -0,0 +1,135 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/heyinLab/common/api/gen/go/product/v1"
+)
+
+func testRules() []*v1.InternalPricingRuleInfo {
+	return []*v1.InternalPricingRuleInfo{
+		{RuleKey: "store_count", RuleType: v1.InternalRuleType_INTERNAL_NUMERIC, Checkpoints: []string{"store.create"}, IsAccumulative: true, Status: v1.InternalRuleStatus_INTERNAL_RULE_ACTIVE},
+		{RuleKey: "sms_daily", RuleType: v1.InternalRuleType_INTERNAL_USAGE, Checkpoints: []string{"sms.send"}, IsResetPeriodically: true, ResetPeriod: v1.InternalResetPeriod_INTERNAL_DAILY, AllowUnlimited: true, Status: v1.InternalRuleStatus_INTERNAL_RULE_ACTIVE},
+		{RuleKey: "custom_domain", RuleType: v1.InternalRuleType_INTERNAL_SWITCH, Checkpoints: []string{"store.create", "domain.bind"}, SortOrder: -1, Status: v1.InternalRuleStatus_INTERNAL_RULE_ACTIVE},
+		{RuleKey: "max_products", RuleType: v1.InternalRuleType_INTERNAL_NUMERIC, Checkpoints: []string{"product.create"}, Status: v1.InternalRuleStatus_INTERNAL_RULE_ACTIVE},
+		{RuleKey: "legacy", RuleType: v1.InternalRuleType_INTERNAL_NUMERIC, Checkpoints: []string{"store.create"}, Status: v1.InternalRuleStatus_INTERNAL_RULE_INACTIVE},
+	}
+}
+
+func param(key, value string, typ v1.InternalValueType) *v1.InternalPlanParameter {
+	return &v1.InternalPlanParameter{RuleKey: key, RuleValue: value, ValueType: typ}
+}
+
+func TestEvaluator_Check(t *testing.T) {
+	e := NewEvaluator(testRules())
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC) // 本地时间 09:00
+
+	base := Grant{SubscriptionCode: "s1", Parameters: []*v1.InternalPlanParameter{
+		param("store_count", "2", v1.InternalValueType_INTERNAL_VALUE_TYPE_NUMBER),
+		param("sms_daily", "100", v1.InternalValueType_INTERNAL_VALUE_TYPE_NUMBER),
+		param("custom_domain", "true", v1.InternalValueType_INTERNAL_VALUE_TYPE_BOOLEAN),
+		param("max_products", "50", v1.InternalValueType_INTERNAL_VALUE_TYPE_NUMBER),
+	}}
+	addon := Grant{SubscriptionCode: "s2", Quantity: 3, Parameters: []*v1.InternalPlanParameter{
+		{RuleKey: "store_count", RuleValue: "1", ValueType: v1.InternalValueType_INTERNAL_VALUE_TYPE_NUMBER, IsStackable: true},
+		param("max_products", "20", v1.InternalValueType_INTERNAL_VALUE_TYPE_NUMBER),
+	}}
+	ledger := MemoryLedger{
+		{RuleKey: "store_count", Amount: 1, At: now.AddDate(0, -2, 0)},
+		{RuleKey: "store_count", Amount: 1, At: now.AddDate(0, -1, 0)},
+		{RuleKey: "sms_daily", Amount: 80, At: now.Add(-10 * time.Hour)}, // 前一天 23:00（本地）
+		{RuleKey: "sms_daily", Amount: 95, At: now.Add(-30 * time.Minute)},
+	}
+	in := Input{Grants: []Grant{base}, Ledger: ledger, Now: now, Location: loc}
+
+	d, err := e.Check(in, "store.create", 1)
+	require.NoError(t, err)
+	require.False(t, d.Allowed())
+	require.Equal(t, "custom_domain", d.Limits[0].RuleKey)
+	require.Len(t, d.Limits, 2)
+	require.Equal(t, "store_count", d.Violations[0].Limit.RuleKey)
+	require.EqualError(t, d.Err(), "store_count exceeds limit: used 2 + 1 > 2")
+
+	// 累加规则跨订阅相加，可叠加参数按份数计算；非累加规则取最大值
+	in.Grants = []Grant{base, addon}
+	d, err = e.Check(in, "store.create", 1)
+	require.NoError(t, err)
+	require.True(t, d.Allowed())
+	limit, err := e.Limit(in, "store_count")
+	require.NoError(t, err)
+	require.Equal(t, float64(5), limit.Value)
+	require.Equal(t, float64(3), limit.Remaining)
+	limit, err = e.Limit(in, "max_products")
+	require.NoError(t, err)
+	require.Equal(t, float64(50), limit.Value)
+
+	// 按租户时区的自然日重置
+	limit, err = e.Limit(in, "sms_daily")
+	require.NoError(t, err)
+	require.Equal(t, float64(95), limit.Used)
+	require.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, loc), limit.PeriodStart)
+	require.Equal(t, time.Date(2026, 3, 11, 0, 0, 0, 0, loc), limit.ResetAt)
+	d, err = e.Check(in, "sms.send", 10)
+	require.NoError(t, err)
+	require.False(t, d.Allowed())
+
+	// 不限量
+	in.Grants = []Grant{{Parameters: []*v1.InternalPlanParameter{{RuleKey: "sms_daily", IsUnlimited: true}}}}
+	d, err = e.Check(in, "sms.send", 1e9)
+	require.NoError(t, err)
+	require.True(t, d.Allowed())
+	require.True(t, d.Limits[0].Unlimited)
+
+	// 未开通的开关与未配置的数值规则
+	in.Grants = nil
+	d, err = e.Check(in, "domain.bind", 0)
+	require.NoError(t, err)
+	require.EqualError(t, d.Err(), "custom_domain is disabled")
+
+	_, err = e.Limit(in, "legacy")
+	require.ErrorIs(t, err, ErrRuleNotFound)
+
+	in.Grants = []Grant{{Parameters: []*v1.InternalPlanParameter{{RuleKey: "store_count", IsUnlimited: true}}}}
+	_, err = e.Limit(in, "store_count")
+	require.ErrorIs(t, err, ErrUnlimitedNotAllowed)
+
+	in.Grants = []Grant{{Parameters: []*v1.InternalPlanParameter{param("store_count", "abc", v1.InternalValueType_INTERNAL_VALUE_TYPE_NUMBER)}}}
+	_, err = e.Limit(in, "store_count")
+	require.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestResetWindow(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2026, 2, 28, 17, 0, 0, 0, time.UTC) // 本地 2026-03-01 01:00，周日
+
+	start, next, err := ResetWindow(v1.InternalResetPeriod_INTERNAL_WEEKLY, now, loc)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 2, 23, 0, 0, 0, 0, loc), start)
+	require.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, loc), next)
+
+	start, next, err = ResetWindow(v1.InternalResetPeriod_INTERNAL_MONTHLY, now, loc)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, loc), start)
+	require.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, loc), next)
+
+	// UTC 时区下仍是 2 月
+	start, _, err = ResetWindow(v1.InternalResetPeriod_INTERNAL_MONTHLY, now, time.UTC)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), start)
+
+	start, next, err = ResetWindow(v1.InternalResetPeriod_INTERNAL_YEARLY, now, loc)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, loc), start)
+	require.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, loc), next)
+
+	start, next, err = ResetWindow(v1.InternalResetPeriod_INTERNAL_NONE, now, loc)
+	require.NoError(t, err)
+	require.True(t, start.IsZero() && next.IsZero())
+
+	_, _, err = ResetWindow(v1.InternalResetPeriod(99), now, loc)
+	require.ErrorIs(t, err, ErrUnsupportedResetCycle)
+}
@@ -3,7 +3,7 @@ package resource
 import (
 	"context"
 	"fmt"
-	middleware "github.com/heyinLab/common/pkg/middleware/grpc"
+	"github.com/heyinLab/common/pkg/clientkit"
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/registry"
@@ -30,7 +30,7 @@ import (
 //	file, err := client.GetFile(ctx, tenantCode, fileID)
 type ResourceClient struct {
 	config *InternalConfig
-	conn   *grpc.ClientConn
+	kit    *clientkit.Client[*ResourceClient]
 	client v1.ResourceInternalServiceClient
 	logger *log.Helper
 }
@@ -39,6 +39,7 @@ type ResourceClient struct {
 //
 // 参数:
 //   - config: 客户端配置，可以使用 DefaultInternalConfig() 获取默认配置
+//   - opts: 连接选项（可选），如 clientkit.WithLazyDial()
 //
 // 返回:
 //   - *ResourceClient: 客户端实例
@@ -49,31 +50,8 @@ type ResourceClient struct {
 //	config := resource.DefaultInternalConfig().
 //	    WithEndpoint("localhost:9000")
 //	client, err := resource.NewResourceClient(config)
-func NewResourceClient(config *InternalConfig) (*ResourceClient, error) {
-	if config == nil {
-		config = DefaultInternalConfig()
-	}
-
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "resource-internal-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, nil, logger)
-	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
-	}
-
-	return &ResourceClient{
-		config: config,
-		conn:   conn,
-		client: v1.NewResourceInternalServiceClient(conn),
-		logger: logger,
-	}, nil
+func NewResourceClient(config *InternalConfig, opts ...clientkit.Option) (*ResourceClient, error) {
+	return newClient(config, nil, opts...)
 }
 
 // NewResourceClientWithDiscovery 创建带服务发现的资源服务内部客户端
@@ -81,6 +59,7 @@ func NewResourceClient(config *InternalConfig) (*ResourceClient, error) {
 // 参数:
 //   - config: 客户端配置
 //   - discovery: 服务发现实例（如 Consul）
+//   - opts: 连接选项（可选）
 //
 // 返回:
 //   - *ResourceClient: 客户端实例
@@ -93,45 +72,43 @@ func NewResourceClient(config *InternalConfig) (*ResourceClient, error) {
 //
 //	config := resource.DefaultInternalConfig()
 //	client, err := resource.NewResourceClientWithDiscovery(config, consulClient)
-func NewResourceClientWithDiscovery(config *InternalConfig, discovery registry.Discovery) (*ResourceClient, error) {
-	if config == nil {
-		config = DefaultInternalConfig()
-	}
-
+func NewResourceClientWithDiscovery(config *InternalConfig, discovery registry.Discovery, opts ...clientkit.Option) (*ResourceClient, error) {
 	if discovery == nil {
-		return nil, fmt.Errorf("服务发现实例不能为空")
+		return nil, clientkit.ErrDiscoveryRequired
 	}
+	return newClient(config, discovery, opts...)
+}
 
-	if err := config.Validate(); err != nil {
-		return nil, err
+func newClient(config *InternalConfig, discovery registry.Discovery, opts ...clientkit.Option) (*ResourceClient, error) {
+	if config == nil {
+		config = DefaultInternalConfig()
 	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "resource-internal-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, discovery, logger)
+	opts = append([]clientkit.Option{clientkit.WithModule("resource-internal-client")}, opts...)
+	kit, err := clientkit.New(newResourceClient, config, discovery, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+		return nil, err
 	}
+	client := kit.Service()
+	client.kit = kit
+	return client, nil
+}
 
-	logger.Infof("资源内部服务客户端连接成功 (服务发现): endpoint=%s, timeout=%v", config.Endpoint, config.Timeout)
-
+func newResourceClient(conn grpc.ClientConnInterface, logger *log.Helper, config *InternalConfig) *ResourceClient {
 	return &ResourceClient{
 		config: config,
-		conn:   conn,
 		client: v1.NewResourceInternalServiceClient(conn),
 		logger: logger,
-	}, nil
+	}
 }
 
 // Close 关闭客户端连接
 func (c *ResourceClient) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
-	}
-	return nil
+	return c.kit.Close()
+}
+
+// Health 检查资源服务状态
+func (c *ResourceClient) Health(ctx context.Context) error {
+	return c.kit.Health(ctx)
 }
 
 // ========== 文件相关接口 ==========
@@ -147,9 +124,6 @@ func (c *ResourceClient) Close() error {
 //   - *v1.InternalFileInfo: 文件信息
 //   - error: 错误信息
 func (c *ResourceClient) GetFile(ctx context.Context, tenantCode string, fileID string) (*v1.InternalFileInfo, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalGetFile(ctx, &v1.InternalGetFileRequest{
 		TenantCode: tenantCode,
 		FileId:     fileID,
@@ -182,9 +156,6 @@ func (c *ResourceClient) GetFiles(ctx context.Context, tenantCode string, fileID
 		return nil, nil, fmt.Errorf("文件ID数量不能超过100个，当前: %d", len(fileIDs))
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalGetFiles(ctx, &v1.InternalGetFilesRequest{
 		TenantCode: tenantCode,
 		FileIds:    fileIDs,
@@ -228,9 +199,6 @@ func (c *ResourceClient) GetFileUrls(ctx context.Context, fileIDs []string, opts
 		return nil, fmt.Errorf("文件ID数量不能超过100个，当前: %d", len(fileIDs))
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	req := &v1.InternalGetFileUrlsRequest{
 		FileIds: fileIDs,
 	}
@@ -306,9 +274,6 @@ func (c *ResourceClient) GetDownloadUrls(ctx context.Context, tenantCode string,
 		return nil, fmt.Errorf("文件数量不能超过50个，当前: %d", len(files))
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	// 转换请求
 	protoFiles := make([]*v1.InternalFileDownloadRequest, len(files))
 	for i, f := range files {
@@ -373,9 +338,6 @@ func (c *ResourceClient) GetDownloadUrl(ctx context.Context, tenantCode string,
 //   - *v1.InternalFileInfo: 已存在的文件信息（如果存在）
 //   - error: 错误信息
 func (c *ResourceClient) CheckFileExists(ctx context.Context, tenantCode string, checksumSHA256 string, size int64) (bool, *v1.InternalFileInfo, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalCheckFileExists(ctx, &v1.InternalCheckFileExistsRequest{
 		TenantCode:     tenantCode,
 		ChecksumSha256: checksumSHA256,
@@ -401,9 +363,6 @@ func (c *ResourceClient) CheckFileExists(ctx context.Context, tenantCode string,
 //   - *v1.InternalQuotaInfo: 配额信息
 //   - error: 错误信息
 func (c *ResourceClient) GetQuota(ctx context.Context, tenantCode string) (*v1.InternalQuotaInfo, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalGetQuota(ctx, &v1.InternalGetQuotaRequest{
 		TenantCode: tenantCode,
 	})
@@ -446,9 +405,6 @@ type CheckQuotaResult struct {
 //   - *CheckQuotaResult: 检查结果
 //   - error: 错误信息
 func (c *ResourceClient) CheckQuota(ctx context.Context, tenantCode string, checkType CheckQuotaType, size int64) (*CheckQuotaResult, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalCheckQuota(ctx, &v1.InternalCheckQuotaRequest{
 		TenantCode: tenantCode,
 		CheckType:  string(checkType),
@@ -507,10 +463,11 @@ type InitTenantResult struct {
 // 注意:
 //   - 一个租户只能初始化一次
 //   - 重复调用会返回错误
+//   - 配置了区域端点（InternalConfig.Regions）时，请求发送到 region 对应的资源服务
 func (c *ResourceClient) InitTenant(ctx context.Context, tenantCode string, region string) (*InitTenantResult, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
+	if region != "" {
+		ctx = clientkit.NewRegionContext(ctx, region)
+	}
 	resp, err := c.client.InternalInitTenant(ctx, &v1.InternalInitTenantRequest{
 		TenantCode: tenantCode,
 		Region:     region,
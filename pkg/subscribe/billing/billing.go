@@ -0,0 +1,341 @@
+package billing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	"github.com/heyinLab/common/pkg/utils/timeutil"
+)
+
+// Cycle 计费周期
+type Cycle = v1.InternalBillingCycle
+
+const (
+	CycleMonthly  = v1.InternalBillingCycle_INTERNAL_BILLING_CYCLE_MONTHLY
+	CycleYearly   = v1.InternalBillingCycle_INTERNAL_BILLING_CYCLE_YEARLY
+	CycleLifetime = v1.InternalBillingCycle_INTERNAL_BILLING_CYCLE_LIFETIME
+)
+
+var (
+	ErrUnsupportedCycle     = errors.New("billing: unsupported billing cycle")
+	ErrInvalidPeriod        = errors.New("billing: time is outside of the billing period")
+	ErrInvalidAmount        = errors.New("billing: amount must not be negative")
+	ErrCurrencyMismatch     = errors.New("billing: currency mismatch")
+	ErrCouponNotApplicable  = errors.New("billing: coupon is not applicable")
+	ErrLifetimeNotProrated  = errors.New("billing: lifetime subscription can not be prorated")
+	ErrLifetimeNotRenewable = errors.New("billing: lifetime subscription can not be renewed")
+	ErrInvalidCycleQuantity = errors.New("billing: cycle quantity must be positive")
+)
+
+// Period 计费周期区间 [Start, End)，End 为零值表示终身
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsLifetime 是否为终身周期
+func (p Period) IsLifetime() bool {
+	return p.End.IsZero()
+}
+
+// Contains 判断 t 是否在周期内
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && (p.IsLifetime() || t.Before(p.End))
+}
+
+// PeriodEnd 计算从 start 开始 count 个计费周期后的结束时间
+//
+// 月付、年付按自然月/年顺延，月末日期取目标月份的最后一天；终身返回零值。
+func PeriodEnd(start time.Time, cycle Cycle, count int) (time.Time, error) {
+	if count <= 0 {
+		return time.Time{}, ErrInvalidCycleQuantity
+	}
+	switch cycle {
+	case CycleMonthly:
+		return timeutil.AddMonths(start, count), nil
+	case CycleYearly:
+		return timeutil.AddYears(start, count), nil
+	case CycleLifetime:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: %s", ErrUnsupportedCycle, cycle)
+	}
+}
+
+// NewPeriod 创建从 start 开始 count 个计费周期的区间
+func NewPeriod(start time.Time, cycle Cycle, count int) (Period, error) {
+	end, err := PeriodEnd(start, cycle, count)
+	if err != nil {
+		return Period{}, err
+	}
+	return Period{Start: start, End: end}, nil
+}
+
+// Renew 续费 count 个周期
+//
+// 未过期时从原结束时间顺延；已过期（含宽限期内）从 now 重新开始。
+func Renew(current Period, cycle Cycle, count int, now time.Time) (Period, error) {
+	if current.IsLifetime() {
+		return Period{}, ErrLifetimeNotRenewable
+	}
+	start := current.End
+	if now.After(start) {
+		start = now
+	}
+	return NewPeriod(start, cycle, count)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Price 套餐价格，金额均为最小货币单位（如分）
+type Price struct {
+	PlanCode string
+	Cycle    Cycle
+	Amount   int64
+	Currency string
+}
+
+// Coupon 优惠券，PercentOff 与 AmountOff 同时设置时先打折再立减
+type Coupon struct {
+	Code       string
+	PercentOff int32 // 折扣百分比，20 表示减免 20%（即八折）
+	AmountOff  int64 // 立减金额
+	MinAmount  int64 // 使用门槛，原价低于门槛时不可用
+}
+
+// Quote 报价
+type Quote struct {
+	OriginalPrice  int64
+	DiscountAmount int64
+	FinalPrice     int64
+	Currency       string
+	CouponCode     string
+}
+
+// ApplyCoupons 依次应用优惠券，优惠后金额不低于 0
+//
+// 百分比折扣按四舍五入计算到最小货币单位；门槛以原价判断。
+func ApplyCoupons(price Price, count int, coupons ...Coupon) (*Quote, error) {
+	if price.Amount < 0 {
+		return nil, ErrInvalidAmount
+	}
+	if count <= 0 {
+		return nil, ErrInvalidCycleQuantity
+	}
+	original := price.Amount * int64(count)
+	final := original
+
+	var codes string
+	for _, c := range coupons {
+		if c.PercentOff < 0 || c.PercentOff > 100 || c.AmountOff < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrCouponNotApplicable, c.Code)
+		}
+		if original < c.MinAmount {
+			return nil, fmt.Errorf("%w: %s requires %d", ErrCouponNotApplicable, c.Code, c.MinAmount)
+		}
+		if c.PercentOff > 0 {
+			final -= mulDivRound(final, int64(c.PercentOff), 100)
+		}
+		final = max(final-c.AmountOff, 0)
+
+		if c.Code != "" {
+			if codes != "" {
+				codes += ","
+			}
+			codes += c.Code
+		}
+	}
+
+	return &Quote{
+		OriginalPrice:  original,
+		DiscountAmount: original - final,
+		FinalPrice:     final,
+		Currency:       price.Currency,
+		CouponCode:     codes,
+	}, nil
+}
+
+// Order 根据报价生成订单信息，订单状态为待支付
+func (q *Quote) Order(orderNo string, orderType v1.InternalOrderType, cycle Cycle, period Period) *v1.InternalSubscriptionOrderInfo {
+	order := &v1.InternalSubscriptionOrderInfo{
+		OrderNo:          orderNo,
+		OrderType:        orderType,
+		BillingCycle:     cycle,
+		OriginalPrice:    q.OriginalPrice,
+		DiscountAmount:   q.DiscountAmount,
+		FinalPrice:       q.FinalPrice,
+		Currency:         q.Currency,
+		Status:           v1.InternalOrderStatus_INTERNAL_ORDER_STATUS_PENDING,
+		ServiceStartDate: timeutil.TimeToTimestamppb(&period.Start),
+	}
+	if q.CouponCode != "" {
+		order.CouponCode = &q.CouponCode
+	}
+	if !period.IsLifetime() {
+		order.ServiceEndDate = timeutil.TimeToTimestamppb(&period.End)
+	}
+	return order
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// DowngradePolicy 降级生效策略
+type DowngradePolicy int
+
+const (
+	// DowngradeAtPeriodEnd 当前周期结束后生效，不退差价
+	DowngradeAtPeriodEnd DowngradePolicy = iota
+	// DowngradeImmediate 立即生效，未使用部分折算为余额
+	DowngradeImmediate
+)
+
+// Proration 套餐变更的差价
+type Proration struct {
+	Credit        int64     // 当前套餐未使用部分的价值（向下取整）
+	Charge        int64     // 新套餐需要支付的金额（向上取整）
+	AmountDue     int64     // 需要补缴的金额
+	CreditBalance int64     // 折算为余额的金额
+	EffectiveAt   time.Time // 变更生效时间
+	Period        Period    // 变更后的计费周期
+}
+
+// Upgrade 计算在 at 时刻从 current 升级到 target 的差价，升级立即生效
+//
+//   - 计费周期相同：保持当前周期，按剩余时间折算新套餐价格
+//   - 计费周期不同：从 at 开始新周期，支付新套餐全价
+//
+// 两种情况都会抵扣当前套餐未使用部分的价值。
+func Upgrade(current Price, currentPeriod Period, target Price, at time.Time) (*Proration, error) {
+	return change(current, currentPeriod, target, at)
+}
+
+// Downgrade 计算降级的差价
+func Downgrade(current Price, currentPeriod Period, target Price, at time.Time, policy DowngradePolicy) (*Proration, error) {
+	if policy == DowngradeImmediate {
+		return change(current, currentPeriod, target, at)
+	}
+
+	if err := validateChange(current, currentPeriod, target, at); err != nil {
+		return nil, err
+	}
+	period, err := NewPeriod(currentPeriod.End, target.Cycle, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &Proration{
+		Charge:      target.Amount,
+		AmountDue:   target.Amount,
+		EffectiveAt: currentPeriod.End,
+		Period:      period,
+	}, nil
+}
+
+func change(current Price, currentPeriod Period, target Price, at time.Time) (*Proration, error) {
+	if err := validateChange(current, currentPeriod, target, at); err != nil {
+		return nil, err
+	}
+
+	remaining := int64(currentPeriod.End.Sub(at) / time.Second)
+	total := int64(currentPeriod.End.Sub(currentPeriod.Start) / time.Second)
+	credit := mulDivFloor(current.Amount, remaining, total)
+
+	p := &Proration{Credit: credit, EffectiveAt: at}
+	if target.Cycle == current.Cycle {
+		p.Period = currentPeriod
+		p.Charge = mulDivCeil(target.Amount, remaining, total)
+	} else {
+		period, err := NewPeriod(at, target.Cycle, 1)
+		if err != nil {
+			return nil, err
+		}
+		p.Period = period
+		p.Charge = target.Amount
+	}
+
+	p.AmountDue = max(p.Charge-p.Credit, 0)
+	p.CreditBalance = max(p.Credit-p.Charge, 0)
+	return p, nil
+}
+
+func validateChange(current Price, currentPeriod Period, target Price, at time.Time) error {
+	if current.Amount < 0 || target.Amount < 0 {
+		return ErrInvalidAmount
+	}
+	if current.Currency != target.Currency {
+		return fmt.Errorf("%w: %s != %s", ErrCurrencyMismatch, current.Currency, target.Currency)
+	}
+	if currentPeriod.IsLifetime() {
+		return ErrLifetimeNotProrated
+	}
+	if !currentPeriod.Contains(at) || !currentPeriod.End.After(currentPeriod.Start) {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Status 订阅状态，与 subscribe.Status 相同
+type Status = v1.InternalSubscriptionStatus
+
+// InGracePeriod 判断订阅是否已到期但仍在宽限期内
+func InGracePeriod(end time.Time, grace time.Duration, now time.Time) bool {
+	return !end.IsZero() && !now.Before(end) && now.Before(end.Add(grace))
+}
+
+// EffectiveStatus 按当前时间与宽限期计算订阅的实际状态
+//
+// 正式订阅到期后在宽限期内仍视为 ACTIVE；试用订阅没有宽限期。
+func EffectiveStatus(status Status, end time.Time, grace time.Duration, now time.Time) Status {
+	if end.IsZero() || now.Before(end) {
+		return status
+	}
+	switch status {
+	case v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE:
+		if InGracePeriod(end, grace, now) {
+			return status
+		}
+		return v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_EXPIRED
+	case v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL:
+		return v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_EXPIRED
+	default:
+		return status
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func mulDiv(a, b, c int64) (*big.Int, *big.Int) {
+	return new(big.Int).QuoRem(
+		new(big.Int).Mul(big.NewInt(a), big.NewInt(b)),
+		big.NewInt(c),
+		new(big.Int),
+	)
+}
+
+// mulDivFloor 计算 a*b/c 并向下取整，a、b、c 均为非负数
+func mulDivFloor(a, b, c int64) int64 {
+	q, _ := mulDiv(a, b, c)
+	return q.Int64()
+}
+
+// mulDivCeil 计算 a*b/c 并向上取整，a、b、c 均为非负数
+func mulDivCeil(a, b, c int64) int64 {
+	q, r := mulDiv(a, b, c)
+	if r.Sign() > 0 {
+		return q.Int64() + 1
+	}
+	return q.Int64()
+}
+
+// mulDivRound 计算 a*b/c 并四舍五入，a、b、c 均为非负数
+func mulDivRound(a, b, c int64) int64 {
+	q, r := mulDiv(a, b, c)
+	if new(big.Int).Mul(r, big.NewInt(2)).Cmp(big.NewInt(c)) >= 0 {
+		return q.Int64() + 1
+	}
+	return q.Int64()
+}
@@ -0,0 +1,151 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriodEnd(t *testing.T) {
+	end, err := PeriodEnd(date(2025, 1, 31), CycleMonthly, 1)
+	require.NoError(t, err)
+	require.Equal(t, date(2025, 2, 28), end)
+
+	end, err = PeriodEnd(date(2025, 1, 31), CycleMonthly, 3)
+	require.NoError(t, err)
+	require.Equal(t, date(2025, 4, 30), end)
+
+	end, err = PeriodEnd(date(2024, 2, 29), CycleYearly, 1)
+	require.NoError(t, err)
+	require.Equal(t, date(2025, 2, 28), end)
+
+	period, err := NewPeriod(date(2025, 1, 1), CycleLifetime, 1)
+	require.NoError(t, err)
+	require.True(t, period.IsLifetime())
+	require.True(t, period.Contains(date(2099, 1, 1)))
+
+	_, err = PeriodEnd(date(2025, 1, 1), v1.InternalBillingCycle_INTERNAL_BILLING_CYCLE_UNSPECIFIED, 1)
+	require.ErrorIs(t, err, ErrUnsupportedCycle)
+	_, err = PeriodEnd(date(2025, 1, 1), CycleMonthly, 0)
+	require.ErrorIs(t, err, ErrInvalidCycleQuantity)
+
+	// 未过期从原结束时间顺延，过期后从当前时间开始
+	renewed, err := Renew(Period{Start: date(2025, 1, 1), End: date(2025, 2, 1)}, CycleMonthly, 1, date(2025, 1, 20))
+	require.NoError(t, err)
+	require.Equal(t, Period{Start: date(2025, 2, 1), End: date(2025, 3, 1)}, renewed)
+	renewed, err = Renew(Period{Start: date(2025, 1, 1), End: date(2025, 2, 1)}, CycleMonthly, 1, date(2025, 2, 10))
+	require.NoError(t, err)
+	require.Equal(t, Period{Start: date(2025, 2, 10), End: date(2025, 3, 10)}, renewed)
+
+	_, err = Renew(Period{Start: date(2025, 1, 1)}, CycleMonthly, 1, date(2025, 2, 10))
+	require.ErrorIs(t, err, ErrLifetimeNotRenewable)
+}
+
+func TestApplyCoupons(t *testing.T) {
+	price := Price{PlanCode: "pro", Cycle: CycleMonthly, Amount: 9999, Currency: "CNY"}
+
+	quote, err := ApplyCoupons(price, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(9999), quote.FinalPrice)
+
+	// 八折后 7999.2 -> 7999，再立减 1000
+	quote, err = ApplyCoupons(price, 1, Coupon{Code: "P20", PercentOff: 20}, Coupon{Code: "M10", AmountOff: 1000, MinAmount: 5000})
+	require.NoError(t, err)
+	require.Equal(t, int64(6999), quote.FinalPrice)
+	require.Equal(t, int64(3000), quote.DiscountAmount)
+	require.Equal(t, "P20,M10", quote.CouponCode)
+
+	quote, err = ApplyCoupons(price, 1, Coupon{AmountOff: 20000})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), quote.FinalPrice)
+
+	_, err = ApplyCoupons(price, 1, Coupon{Code: "BIG", AmountOff: 100, MinAmount: 10000})
+	require.ErrorIs(t, err, ErrCouponNotApplicable)
+
+	quote, err = ApplyCoupons(price, 12, Coupon{Code: "BIG", AmountOff: 100, MinAmount: 10000})
+	require.NoError(t, err)
+	require.Equal(t, int64(119888), quote.FinalPrice)
+
+	period, err := NewPeriod(date(2025, 1, 1), CycleMonthly, 12)
+	require.NoError(t, err)
+	order := quote.Order("NO1", v1.InternalOrderType_INTERNAL_ORDER_TYPE_NEW, CycleMonthly, period)
+	require.Equal(t, int64(119988), order.OriginalPrice)
+	require.Equal(t, "BIG", order.GetCouponCode())
+	require.Equal(t, date(2026, 1, 1), order.GetServiceEndDate().AsTime())
+	require.Equal(t, v1.InternalOrderStatus_INTERNAL_ORDER_STATUS_PENDING, order.Status)
+}
+
+func TestUpgradeDowngrade(t *testing.T) {
+	basic := Price{PlanCode: "basic", Cycle: CycleMonthly, Amount: 3000, Currency: "CNY"}
+	pro := Price{PlanCode: "pro", Cycle: CycleMonthly, Amount: 9000, Currency: "CNY"}
+	proYearly := Price{PlanCode: "pro", Cycle: CycleYearly, Amount: 90000, Currency: "CNY"}
+	period := Period{Start: date(2025, 4, 1), End: date(2025, 5, 1)} // 30 天
+
+	// 剩余 1/3：抵扣 1000，补缴 3000-1000
+	p, err := Upgrade(basic, period, pro, date(2025, 4, 21))
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), p.Credit)
+	require.Equal(t, int64(3000), p.Charge)
+	require.Equal(t, int64(2000), p.AmountDue)
+	require.Equal(t, period, p.Period)
+
+	// 剩余 7/30：抵扣 700（3000*7/30），新套餐 2100
+	p, err = Upgrade(basic, period, pro, date(2025, 4, 24))
+	require.NoError(t, err)
+	require.Equal(t, int64(700), p.Credit)
+	require.Equal(t, int64(2100), p.Charge)
+
+	// 取整：抵扣向下取整，收费向上取整
+	p, err = Upgrade(Price{Cycle: CycleMonthly, Amount: 100, Currency: "CNY"}, period, Price{Cycle: CycleMonthly, Amount: 200, Currency: "CNY"}, date(2025, 4, 30))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), p.Credit)
+	require.Equal(t, int64(7), p.Charge)
+
+	// 切换为年付：从当前时间开始新周期，支付全价
+	p, err = Upgrade(basic, period, proYearly, date(2025, 4, 21))
+	require.NoError(t, err)
+	require.Equal(t, int64(89000), p.AmountDue)
+	require.Equal(t, Period{Start: date(2025, 4, 21), End: date(2026, 4, 21)}, p.Period)
+
+	// 立即降级：差价转为余额
+	p, err = Downgrade(pro, period, basic, date(2025, 4, 11), DowngradeImmediate)
+	require.NoError(t, err)
+	require.Equal(t, int64(6000), p.Credit)
+	require.Equal(t, int64(2000), p.Charge)
+	require.Equal(t, int64(0), p.AmountDue)
+	require.Equal(t, int64(4000), p.CreditBalance)
+
+	// 周期结束后降级
+	p, err = Downgrade(pro, period, basic, date(2025, 4, 11), DowngradeAtPeriodEnd)
+	require.NoError(t, err)
+	require.Equal(t, date(2025, 5, 1), p.EffectiveAt)
+	require.Equal(t, Period{Start: date(2025, 5, 1), End: date(2025, 6, 1)}, p.Period)
+	require.Equal(t, int64(3000), p.AmountDue)
+
+	_, err = Upgrade(basic, period, pro, date(2025, 5, 1))
+	require.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = Upgrade(basic, Period{Start: date(2025, 4, 1)}, pro, date(2025, 5, 1))
+	require.ErrorIs(t, err, ErrLifetimeNotProrated)
+	_, err = Upgrade(basic, period, Price{Cycle: CycleMonthly, Amount: 1, Currency: "USD"}, date(2025, 4, 2))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestEffectiveStatus(t *testing.T) {
+	end := date(2025, 5, 1)
+	grace := 72 * time.Hour
+
+	require.Equal(t, v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE, EffectiveStatus(v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE, end, grace, date(2025, 4, 30)))
+	require.Equal(t, v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE, EffectiveStatus(v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE, end, grace, date(2025, 5, 3)))
+	require.True(t, InGracePeriod(end, grace, date(2025, 5, 3)))
+	require.Equal(t, v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_EXPIRED, EffectiveStatus(v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE, end, grace, date(2025, 5, 4)))
+	require.Equal(t, v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_EXPIRED, EffectiveStatus(v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL, end, grace, date(2025, 5, 1)))
+	require.Equal(t, v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_SUSPENDED, EffectiveStatus(v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_SUSPENDED, end, grace, date(2025, 6, 1)))
+	require.Equal(t, v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE, EffectiveStatus(v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE, time.Time{}, 0, date(2099, 1, 1)))
+}
@@ -2,89 +2,57 @@ package subscribe
 
 import (
 	"context"
-	"fmt"
+	"iter"
+
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/registry"
 	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
-	middleware "github.com/heyinLab/common/pkg/middleware/grpc"
+	"github.com/heyinLab/common/pkg/clientkit"
+	"github.com/heyinLab/common/pkg/utils/pagination"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Client struct {
-	config          *Config
-	conn            *grpc.ClientConn
-	logger          *log.Helper
-	subscribeClient *SubscribeClient
+	client *clientkit.Client[*SubscribeClient]
 }
 
-func NewClient(config *Config) (*Client, error) {
-	if config == nil {
-		config = DefaultConfig()
-	}
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "subscribe-client",
-	))
+func NewClient(config *Config, opts ...clientkit.Option) (*Client, error) {
+	return newClient(config, nil, opts...)
+}
 
-	conn, err := middleware.CreateGRPCConn(config, nil, logger)
-	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+func NewClientWithDiscovery(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
+	if discovery == nil {
+		return nil, clientkit.ErrDiscoveryRequired
 	}
-	return &Client{
-		config:          config,
-		conn:            conn,
-		logger:          logger,
-		subscribeClient: newSubscribeClient(conn, logger, config),
-	}, nil
+	return newClient(config, discovery, opts...)
 }
 
-func NewClientWithDiscovery(config *Config, discovery registry.Discovery) (*Client, error) {
+func newClient(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
-
-	if discovery == nil {
-		return nil, fmt.Errorf("服务发现实例不能为空")
-	}
-
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "subscribe-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, discovery, logger)
+	opts = append([]clientkit.Option{clientkit.WithModule("subscribe-client")}, opts...)
+	client, err := clientkit.New(newSubscribeClient, config, discovery, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+		return nil, err
 	}
-
-	logger.Infof("平台服务客户端连接成功 (服务发现): endpoint=%s, timeout=%v", config.Endpoint, config.Timeout)
-
-	return &Client{
-		config:          config,
-		conn:            conn,
-		logger:          logger,
-		subscribeClient: newSubscribeClient(conn, logger, config),
-	}, nil
+	return &Client{client: client}, nil
 }
 
 func (c *Client) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
-	}
-	return nil
+	return c.client.Close()
+}
+
+// Health 检查订阅服务状态
+func (c *Client) Health(ctx context.Context) error {
+	return c.client.Health(ctx)
 }
 
 func (c *Client) SubscribeClient() *SubscribeClient {
-	return c.subscribeClient
+	return c.client.Service()
 }
 
 type SubscribeClient struct {
@@ -93,7 +61,7 @@ type SubscribeClient struct {
 	config *Config
 }
 
-func newSubscribeClient(conn *grpc.ClientConn, logger *log.Helper, config *Config) *SubscribeClient {
+func newSubscribeClient(conn grpc.ClientConnInterface, logger *log.Helper, config *Config) *SubscribeClient {
 	return &SubscribeClient{
 		client: v1.NewSubscriptionInternalServiceClient(conn),
 		logger: logger,
@@ -103,9 +71,6 @@ func newSubscribeClient(conn *grpc.ClientConn, logger *log.Helper, config *Confi
 
 // GetTenantSubscriptions 获取商家指定产品订阅列表
 func (c *SubscribeClient) GetTenantSubscriptions(ctx context.Context, tenantCode string, productCode string) ([]*v1.InternalSubscriptionInfo, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalListSubscriptions(ctx, &v1.InternalListSubscriptionsRequest{
 		TenantCode:  &tenantCode,
 		ProductCode: &productCode,
@@ -118,6 +83,40 @@ func (c *SubscribeClient) GetTenantSubscriptions(ctx context.Context, tenantCode
 	return resp.Subscriptions, nil
 }
 
+// ListSubscriptions 分页获取订阅列表
+func (c *SubscribeClient) ListSubscriptions(ctx context.Context, req *v1.InternalListSubscriptionsRequest) (*v1.InternalListSubscriptionsResponse, error) {
+	resp, err := c.client.InternalListSubscriptions(ctx, req)
+	if err != nil {
+		c.logger.WithContext(ctx).Errorf("获取订阅列表失败:page=%d page_size=%d error=%v", req.GetPage(), req.GetPageSize(), err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Subscriptions 遍历订阅列表，自动翻页，req 中的 Page/PageSize 会被忽略
+//
+// 未指定排序时按 create_time 升序，保证翻页过程中顺序稳定。
+func (c *SubscribeClient) Subscriptions(ctx context.Context, req *v1.InternalListSubscriptionsRequest, opts ...pagination.Option) iter.Seq2[*v1.InternalSubscriptionInfo, error] {
+	if req == nil {
+		req = &v1.InternalListSubscriptionsRequest{}
+	}
+	return pagination.Iterate(ctx, func(ctx context.Context, page, pageSize int32) (pagination.Page[*v1.InternalSubscriptionInfo], error) {
+		pageReq := proto.Clone(req).(*v1.InternalListSubscriptionsRequest)
+		pageReq.Page, pageReq.PageSize = &page, &pageSize
+		if pageReq.SortBy == nil {
+			sortBy, sortOrder := "create_time", "asc"
+			pageReq.SortBy, pageReq.SortOrder = &sortBy, &sortOrder
+		}
+
+		resp, err := c.ListSubscriptions(ctx, pageReq)
+		if err != nil {
+			return pagination.Page[*v1.InternalSubscriptionInfo]{}, err
+		}
+		return pagination.Page[*v1.InternalSubscriptionInfo]{Items: resp.GetSubscriptions(), Total: int64(resp.GetTotal())}, nil
+	}, opts...)
+}
+
 type CreateSubscriptionOptions struct {
 	// 订阅开始时间
 	StartDate *timestamppb.Timestamp
@@ -151,9 +150,6 @@ func (c *SubscribeClient) CreateSubscription(ctx context.Context, productCode st
 		req.AutomaticRenewal = opts.AutomaticRenewal
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalCreateSubscription(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("创建订阅失败:product_code=%s plan_code=:%s err=%v", productCode, planCode, err)
@@ -171,9 +167,6 @@ func (c *SubscribeClient) ReNewSubscription(ctx context.Context, productCode str
 		Order:       order,
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalReNewSubscription(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("续订订阅失败:product_code=%s plan_code=:%s renew_time=:%s err=%v", productCode, planCode, reNewTime.String(), err)
@@ -208,9 +201,6 @@ func (c *SubscribeClient) UpgradeSubscription(ctx context.Context, productCode s
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalUpgradeSubscription(ctx, req)
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("升级订阅失败:product_code=%s plan_code=:%s err=%v", productCode, planCode, err)
@@ -222,9 +212,6 @@ func (c *SubscribeClient) UpgradeSubscription(ctx context.Context, productCode s
 
 // 获取商户订阅状态
 func (c *SubscribeClient) InternalGetSubscriptionStats(ctx context.Context, tenantCode string) (*v1.InternalGetSubscriptionStatsResponse, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
 	resp, err := c.client.InternalGetSubscriptionStats(ctx, &v1.InternalGetSubscriptionStatsRequest{TenantCode: tenantCode})
 	if err != nil {
 		c.logger.WithContext(ctx).Errorf("获取商户订阅状态失败:tenant_code=%serr=%v", err)
@@ -233,3 +220,37 @@ func (c *SubscribeClient) InternalGetSubscriptionStats(ctx context.Context, tena
 
 	return resp, nil
 }
+
+// ConsumeQuota 消耗配额，requestID 为幂等键，重试时需保持不变
+func (c *SubscribeClient) ConsumeQuota(ctx context.Context, tenantCode string, productCode string, dimensionKey string, amount int32, requestID string) (*v1.InternalQuotaUsageInfo, error) {
+	resp, err := c.client.InternalConsumeQuota(ctx, &v1.InternalConsumeQuotaRequest{
+		TenantCode:   tenantCode,
+		ProductCode:  productCode,
+		DimensionKey: dimensionKey,
+		Amount:       amount,
+		RequestId:    requestID,
+	})
+	if err != nil {
+		c.logger.WithContext(ctx).Errorf("消耗配额失败:tenant_code=%s product_code=%s dimension_key=%s amount=%d request_id=%s err=%v", tenantCode, productCode, dimensionKey, amount, requestID, err)
+		return nil, err
+	}
+
+	return resp.QuotaUsage, nil
+}
+
+// ReleaseQuota 释放配额，requestID 为幂等键，重试时需保持不变
+func (c *SubscribeClient) ReleaseQuota(ctx context.Context, tenantCode string, productCode string, dimensionKey string, amount int32, requestID string) (*v1.InternalQuotaUsageInfo, error) {
+	resp, err := c.client.InternalReleaseQuota(ctx, &v1.InternalReleaseQuotaRequest{
+		TenantCode:   tenantCode,
+		ProductCode:  productCode,
+		DimensionKey: dimensionKey,
+		Amount:       amount,
+		RequestId:    requestID,
+	})
+	if err != nil {
+		c.logger.WithContext(ctx).Errorf("释放配额失败:tenant_code=%s product_code=%s dimension_key=%s amount=%d request_id=%s err=%v", tenantCode, productCode, dimensionKey, amount, requestID, err)
+		return nil, err
+	}
+
+	return resp.QuotaUsage, nil
+}
@@ -0,0 +1,471 @@
+package entitlement
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+	"github.com/heyinLab/common/pkg/middleware/auth"
+	"github.com/heyinLab/common/pkg/subscribe"
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+const (
+	// Unlimited 配额上限为 -1 表示不限量
+	Unlimited int32 = -1
+
+	// DefaultCacheTTL 订阅缓存的默认有效期
+	DefaultCacheTTL = time.Minute
+)
+
+// Source 订阅数据来源，*subscribe.SubscribeClient 实现了该接口
+type Source interface {
+	GetTenantSubscriptions(ctx context.Context, tenantCode string, productCode string) ([]*v1.InternalSubscriptionInfo, error)
+	ConsumeQuota(ctx context.Context, tenantCode string, productCode string, dimensionKey string, amount int32, requestID string) (*v1.InternalQuotaUsageInfo, error)
+	ReleaseQuota(ctx context.Context, tenantCode string, productCode string, dimensionKey string, amount int32, requestID string) (*v1.InternalQuotaUsageInfo, error)
+}
+
+var _ Source = (*subscribe.SubscribeClient)(nil)
+
+// Entitlement 租户在某个产品维度上的权益
+type Entitlement struct {
+	TenantCode       string
+	ProductCode      string
+	DimensionKey     string
+	SubscriptionCode string                        // 提供该维度的订阅编号
+	Status           v1.InternalSubscriptionStatus // 按当前时间计算后的订阅状态
+	QuotaType        v1.InternalQuotaType          // 配额类型
+	Unlimited        bool                          // 是否不限量
+	Limit            int32                         // 配额上限，不限量时为 -1
+	Used             int32                         // 已使用配额
+	Remaining        int32                         // 剩余配额，不限量时为 -1
+	ExpiresAt        time.Time                     // 权益到期时间，零值表示永不过期
+}
+
+// IsTrial 是否处于试用期
+func (e *Entitlement) IsTrial() bool {
+	return e.Status == v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL
+}
+
+// Option Enforcer 配置项
+type Option func(*Enforcer)
+
+// WithCacheTTL 设置订阅缓存的最长有效期，小于等于 0 表示不缓存
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(e *Enforcer) { e.ttl = ttl }
+}
+
+// WithClock 设置时钟，默认为 clock.Default()，测试中可注入 clock.NewFake
+func WithClock(c clock.Clock) Option {
+	return func(e *Enforcer) {
+		if c != nil {
+			e.clock = c
+		}
+	}
+}
+
+// WithLogger 设置日志
+func WithLogger(logger log.Logger) Option {
+	return func(e *Enforcer) {
+		e.logger = log.NewHelper(log.With(logger, "module", "entitlement"))
+	}
+}
+
+type cacheKey struct {
+	tenantCode  string
+	productCode string
+}
+
+type cacheEntry struct {
+	subscriptions []*v1.InternalSubscriptionInfo
+	expiresAt     time.Time
+}
+
+// Enforcer 权益与配额校验
+//
+// 订阅按 租户+产品 缓存，缓存到期时间不会晚于任一订阅的开始、试用结束或结束时间，
+// 因此订阅状态切换后的第一次校验一定会重新拉取订阅。
+type Enforcer struct {
+	source Source
+	ttl    time.Duration
+	clock  clock.Clock
+	logger *log.Helper
+
+	mu    sync.RWMutex
+	cache map[cacheKey]*cacheEntry
+	group singleflight.Group
+}
+
+// NewEnforcer 创建权益校验器
+func NewEnforcer(source Source, opts ...Option) *Enforcer {
+	e := &Enforcer{
+		source: source,
+		ttl:    DefaultCacheTTL,
+		clock:  clock.Default(),
+		cache:  make(map[cacheKey]*cacheEntry),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.logger == nil {
+		e.logger = log.NewHelper(log.With(log.GetLogger(), "module", "entitlement"))
+	}
+	return e
+}
+
+// Check 校验当前租户能否在 productCode 的 dimensionKey 维度上使用 amount 个配额
+//
+// amount 为 0 时只校验权益是否开通。校验不通过时返回的错误为
+// ErrSubscriptionNotFound、ErrSubscriptionExpired、ErrSubscriptionSuspended、
+// ErrFeatureDisabled 或 ErrQuotaExceeded 包装后的业务错误。
+func (e *Enforcer) Check(ctx context.Context, productCode, dimensionKey string, amount int32) (*Entitlement, error) {
+	tenantCode, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := e.subscriptions(ctx, tenantCode, productCode)
+	if err != nil {
+		return nil, err
+	}
+
+	ent, err := evaluate(subscriptions, tenantCode, productCode, dimensionKey, e.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err = ent.allow(amount); err != nil {
+		return ent, err
+	}
+	return ent, nil
+}
+
+// Consume 校验并消耗配额，requestID 为幂等键
+//
+// 本地校验不通过时不会请求订阅服务；开关型配额不产生消耗。
+func (e *Enforcer) Consume(ctx context.Context, productCode, dimensionKey string, amount int32, requestID string) (*Entitlement, error) {
+	if amount <= 0 || requestID == "" {
+		return nil, businessErrors.WrapError(businessErrors.ErrInvalidParameter, "消耗数量必须大于0且 request_id 不能为空")
+	}
+
+	ent, err := e.Check(ctx, productCode, dimensionKey, amount)
+	if err != nil {
+		return ent, err
+	}
+	if ent.QuotaType == v1.InternalQuotaType_INTERNAL_QUOTA_TYPE_SWITCH {
+		return ent, nil
+	}
+
+	usage, err := e.source.ConsumeQuota(ctx, ent.TenantCode, productCode, dimensionKey, amount, requestID)
+	if err != nil {
+		// 服务端的用量可能已经变化，丢弃缓存
+		e.Invalidate(ent.TenantCode, productCode)
+		return nil, err
+	}
+	return e.applyUsage(ent, usage), nil
+}
+
+// Release 释放已消耗的配额，requestID 为幂等键
+func (e *Enforcer) Release(ctx context.Context, productCode, dimensionKey string, amount int32, requestID string) (*Entitlement, error) {
+	if amount <= 0 || requestID == "" {
+		return nil, businessErrors.WrapError(businessErrors.ErrInvalidParameter, "释放数量必须大于0且 request_id 不能为空")
+	}
+	tenantCode, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := e.source.ReleaseQuota(ctx, tenantCode, productCode, dimensionKey, amount, requestID)
+	if err != nil {
+		e.Invalidate(tenantCode, productCode)
+		return nil, err
+	}
+	ent := &Entitlement{TenantCode: tenantCode, ProductCode: productCode, DimensionKey: dimensionKey}
+	return e.applyUsage(ent, usage), nil
+}
+
+// Invalidate 丢弃租户在某个产品上的订阅缓存，订阅变更（续费、升级）后调用
+func (e *Enforcer) Invalidate(tenantCode, productCode string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.cache, cacheKey{tenantCode: tenantCode, productCode: productCode})
+}
+
+// subscriptions 获取租户订阅，优先使用缓存，并发请求合并为一次调用
+func (e *Enforcer) subscriptions(ctx context.Context, tenantCode, productCode string) ([]*v1.InternalSubscriptionInfo, error) {
+	key := cacheKey{tenantCode: tenantCode, productCode: productCode}
+	now := e.clock.Now()
+
+	e.mu.RLock()
+	entry, ok := e.cache[key]
+	e.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.subscriptions, nil
+	}
+
+	v, err, _ := e.group.Do(tenantCode+"\x00"+productCode, func() (interface{}, error) {
+		subscriptions, err := e.source.GetTenantSubscriptions(ctx, tenantCode, productCode)
+		if err != nil {
+			e.logger.WithContext(ctx).Errorf("获取租户订阅失败: tenant_code=%s, product_code=%s, error=%v", tenantCode, productCode, err)
+			return nil, err
+		}
+		if e.ttl > 0 {
+			e.mu.Lock()
+			e.cache[key] = &cacheEntry{subscriptions: subscriptions, expiresAt: cacheExpiry(subscriptions, now, e.ttl)}
+			e.mu.Unlock()
+		}
+		return subscriptions, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*v1.InternalSubscriptionInfo), nil
+}
+
+// applyUsage 用订阅服务返回的最新用量更新缓存与权益
+func (e *Enforcer) applyUsage(ent *Entitlement, usage *v1.InternalQuotaUsageInfo) *Entitlement {
+	if usage == nil {
+		e.Invalidate(ent.TenantCode, ent.ProductCode)
+		return ent
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if entry, ok := e.cache[cacheKey{tenantCode: ent.TenantCode, productCode: ent.ProductCode}]; ok {
+		entry.subscriptions = replaceUsage(entry.subscriptions, usage)
+		// 多个订阅提供同一维度时，重新汇总
+		if merged, err := evaluate(entry.subscriptions, ent.TenantCode, ent.ProductCode, ent.DimensionKey, e.clock.Now()); err == nil {
+			return merged
+		}
+	}
+
+	if usage.SubscriptionCode != "" {
+		ent.SubscriptionCode = usage.SubscriptionCode
+	}
+	ent.QuotaType = usage.QuotaType
+	ent.setQuota(usage.QuotaLimit, usage.IsUnlimited, usage.QuotaUsed)
+	return ent
+}
+
+// replaceUsage 返回替换了同一维度用量记录的订阅列表
+//
+// 缓存中的订阅按不可变数据处理，被修改的订阅会先复制，不影响已经读取到旧列表的调用方。
+func replaceUsage(subscriptions []*v1.InternalSubscriptionInfo, usage *v1.InternalQuotaUsageInfo) []*v1.InternalSubscriptionInfo {
+	for i, sub := range subscriptions {
+		if sub.GetSubscriptionCode() != usage.GetSubscriptionCode() {
+			continue
+		}
+		sub = proto.Clone(sub).(*v1.InternalSubscriptionInfo)
+		replaced := false
+		for j, u := range sub.QuotaUsages {
+			if u.GetDimensionKey() == usage.GetDimensionKey() {
+				sub.QuotaUsages[j] = usage
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			sub.QuotaUsages = append(sub.QuotaUsages, usage)
+		}
+		subscriptions = slices.Clone(subscriptions)
+		subscriptions[i] = sub
+		return subscriptions
+	}
+	return subscriptions
+}
+
+// cacheExpiry 计算缓存到期时间：不晚于 now+ttl，也不晚于下一个订阅状态切换的时间点
+func cacheExpiry(subscriptions []*v1.InternalSubscriptionInfo, now time.Time, ttl time.Duration) time.Time {
+	expiresAt := now.Add(ttl)
+	for _, sub := range subscriptions {
+		for _, ts := range []*time.Time{timeOf(sub.GetStartDate()), timeOf(sub.GetTrialEndDate()), timeOf(sub.GetEndDate())} {
+			if ts != nil && ts.After(now) && ts.Before(expiresAt) {
+				expiresAt = *ts
+			}
+		}
+	}
+	return expiresAt
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// effectiveStatus 按当前时间计算订阅的实际状态，未生效的订阅返回 UNSPECIFIED
+func effectiveStatus(sub *v1.InternalSubscriptionInfo, now time.Time) (v1.InternalSubscriptionStatus, time.Time) {
+	status := sub.GetStatus()
+	if start := timeOf(sub.GetStartDate()); start != nil && now.Before(*start) {
+		return v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_UNSPECIFIED, time.Time{}
+	}
+
+	var end time.Time
+	if t := timeOf(sub.GetEndDate()); t != nil {
+		end = *t
+	}
+	if status == v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL || sub.GetIsTrial() {
+		if t := timeOf(sub.GetTrialEndDate()); t != nil {
+			end = *t
+		}
+		if status == v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE {
+			status = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL
+		}
+	}
+
+	switch status {
+	case v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE,
+		v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL:
+		if !end.IsZero() && !now.Before(end) {
+			return v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_EXPIRED, end
+		}
+	}
+	return status, end
+}
+
+// evaluate 汇总所有生效订阅在 dimensionKey 上的权益
+//
+// 多个生效订阅（如基础套餐+加购包）提供同一维度时，上限与用量累加，任一不限量则不限量。
+func evaluate(subscriptions []*v1.InternalSubscriptionInfo, tenantCode, productCode, dimensionKey string, now time.Time) (*Entitlement, error) {
+	var (
+		ent       *Entitlement
+		suspended bool
+		expired   bool
+	)
+	for _, sub := range subscriptions {
+		status, end := effectiveStatus(sub, now)
+		switch status {
+		case v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE,
+			v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL:
+		case v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_SUSPENDED:
+			suspended = true
+			continue
+		case v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_EXPIRED,
+			v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_CANCELLED:
+			expired = true
+			continue
+		default:
+			continue
+		}
+
+		quotaType, limit, unlimited, used, ok := dimensionOf(sub, dimensionKey)
+		if ent == nil {
+			ent = &Entitlement{
+				TenantCode:   tenantCode,
+				ProductCode:  productCode,
+				DimensionKey: dimensionKey,
+				Status:       status,
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		if ent.SubscriptionCode == "" {
+			ent.SubscriptionCode = sub.GetSubscriptionCode()
+			ent.Status = status
+			ent.QuotaType = quotaType
+			ent.ExpiresAt = end
+			ent.setQuota(limit, unlimited, used)
+			continue
+		}
+		// 正式订阅优先于试用订阅，到期时间取最晚的一个
+		if status == v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE {
+			ent.Status = status
+		}
+		if !ent.ExpiresAt.IsZero() && (end.IsZero() || end.After(ent.ExpiresAt)) {
+			ent.ExpiresAt = end
+		}
+		ent.mergeQuota(limit, unlimited, used)
+	}
+
+	switch {
+	case ent == nil && suspended:
+		return nil, businessErrors.WrapError(businessErrors.ErrSubscriptionSuspended, productCode)
+	case ent == nil && expired:
+		return nil, businessErrors.WrapError(businessErrors.ErrSubscriptionExpired, productCode)
+	case ent == nil:
+		return nil, businessErrors.WrapError(businessErrors.ErrSubscriptionNotFound, productCode)
+	case ent.SubscriptionCode == "":
+		return nil, businessErrors.WrapError(businessErrors.ErrFeatureDisabled, dimensionKey)
+	}
+	return ent, nil
+}
+
+// dimensionOf 读取订阅在 dimensionKey 上的配额，优先使用用量记录，其次使用配额快照
+func dimensionOf(sub *v1.InternalSubscriptionInfo, dimensionKey string) (quotaType v1.InternalQuotaType, limit int32, unlimited bool, used int32, ok bool) {
+	for _, u := range sub.GetQuotaUsages() {
+		if u.GetDimensionKey() == dimensionKey {
+			return u.GetQuotaType(), u.GetQuotaLimit(), u.GetIsUnlimited(), u.GetQuotaUsed(), true
+		}
+	}
+
+	v, found := sub.GetQuotaSnapshot().GetFields()[dimensionKey]
+	if !found {
+		return
+	}
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_BoolValue:
+		if kind.BoolValue {
+			limit = 1
+		}
+		return v1.InternalQuotaType_INTERNAL_QUOTA_TYPE_SWITCH, limit, false, 0, true
+	case *structpb.Value_NumberValue:
+		return v1.InternalQuotaType_INTERNAL_QUOTA_TYPE_NUMERIC, int32(kind.NumberValue), false, 0, true
+	}
+	return
+}
+
+func (e *Entitlement) setQuota(limit int32, unlimited bool, used int32) {
+	e.Unlimited = unlimited || limit == Unlimited
+	e.Used = used
+	if e.Unlimited {
+		e.Limit, e.Remaining = Unlimited, Unlimited
+		return
+	}
+	e.Limit = limit
+	e.Remaining = max(limit-used, 0)
+}
+
+func (e *Entitlement) mergeQuota(limit int32, unlimited bool, used int32) {
+	if e.Unlimited || unlimited || limit == Unlimited {
+		e.setQuota(Unlimited, true, e.Used+used)
+		return
+	}
+	e.setQuota(e.Limit+limit, false, e.Used+used)
+}
+
+// allow 判断权益能否满足 amount 的用量
+func (e *Entitlement) allow(amount int32) error {
+	if e.QuotaType == v1.InternalQuotaType_INTERNAL_QUOTA_TYPE_SWITCH {
+		if e.Unlimited || e.Limit > 0 {
+			return nil
+		}
+		return businessErrors.WrapError(businessErrors.ErrFeatureDisabled, e.DimensionKey)
+	}
+	if e.Unlimited || amount <= e.Remaining {
+		return nil
+	}
+	return businessErrors.WrapError(businessErrors.ErrQuotaExceeded,
+		fmt.Sprintf("%s 剩余 %d，需要 %d", e.DimensionKey, e.Remaining, amount))
+}
+
+func tenantFromContext(ctx context.Context) (string, error) {
+	claims, ok := auth.FromContext(ctx)
+	if !ok || claims == nil || claims.TenantCode == "" {
+		return "", businessErrors.ErrTenantMissing
+	}
+	return claims.TenantCode, nil
+}
+
+func timeOf(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
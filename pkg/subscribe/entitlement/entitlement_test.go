@@ -0,0 +1,256 @@
+package entitlement
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+	"github.com/heyinLab/common/pkg/middleware/auth"
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+type fakeSource struct {
+	subscriptions []*v1.InternalSubscriptionInfo
+	listCalls     int
+	consumed      map[string]int32
+}
+
+func (s *fakeSource) GetTenantSubscriptions(_ context.Context, _ string, _ string) ([]*v1.InternalSubscriptionInfo, error) {
+	s.listCalls++
+	return s.subscriptions, nil
+}
+
+func (s *fakeSource) ConsumeQuota(_ context.Context, _ string, _ string, dimensionKey string, amount int32, requestID string) (*v1.InternalQuotaUsageInfo, error) {
+	if s.consumed == nil {
+		s.consumed = make(map[string]int32)
+	}
+	if _, ok := s.consumed[requestID]; !ok {
+		s.consumed[requestID] = amount
+	}
+	return s.usage(dimensionKey), nil
+}
+
+func (s *fakeSource) ReleaseQuota(_ context.Context, _ string, _ string, dimensionKey string, _ int32, requestID string) (*v1.InternalQuotaUsageInfo, error) {
+	delete(s.consumed, requestID)
+	return s.usage(dimensionKey), nil
+}
+
+// usage 模拟服务端返回扣减后的用量
+func (s *fakeSource) usage(dimensionKey string) *v1.InternalQuotaUsageInfo {
+	for _, sub := range s.subscriptions {
+		for _, u := range sub.QuotaUsages {
+			if u.DimensionKey != dimensionKey {
+				continue
+			}
+			var consumed int32
+			for _, v := range s.consumed {
+				consumed += v
+			}
+			return &v1.InternalQuotaUsageInfo{
+				SubscriptionCode: u.SubscriptionCode,
+				DimensionKey:     u.DimensionKey,
+				QuotaLimit:       u.QuotaLimit,
+				QuotaUsed:        u.QuotaUsed + consumed,
+				QuotaType:        u.QuotaType,
+			}
+		}
+	}
+	return nil
+}
+
+var testNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func tenantContext() context.Context {
+	return auth.NewContext(context.Background(), &auth.Claims{UserCode: "u1", TenantCode: "t1"})
+}
+
+func activeSubscription() *v1.InternalSubscriptionInfo {
+	return &v1.InternalSubscriptionInfo{
+		SubscriptionCode: "sub-1",
+		TenantCode:       "t1",
+		ProductCode:      "mall",
+		Status:           v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE,
+		StartDate:        timestamppb.New(testNow.AddDate(0, -1, 0)),
+		EndDate:          timestamppb.New(testNow.Add(30 * time.Second)),
+		QuotaUsages: []*v1.InternalQuotaUsageInfo{
+			{SubscriptionCode: "sub-1", DimensionKey: "store_count", QuotaLimit: 3, QuotaUsed: 2, QuotaType: v1.InternalQuotaType_INTERNAL_QUOTA_TYPE_NUMERIC},
+			{SubscriptionCode: "sub-1", DimensionKey: "api_calls", QuotaLimit: -1, QuotaUsed: 100, QuotaType: v1.InternalQuotaType_INTERNAL_QUOTA_TYPE_USAGE},
+			{SubscriptionCode: "sub-1", DimensionKey: "custom_domain", QuotaLimit: 0, QuotaType: v1.InternalQuotaType_INTERNAL_QUOTA_TYPE_SWITCH},
+		},
+	}
+}
+
+func errorType(t *testing.T, err error) string {
+	t.Helper()
+	be, ok := err.(*businessErrors.BusinessError)
+	require.True(t, ok, "unexpected error: %v", err)
+	return be.Type
+}
+
+func TestEnforcer_Check(t *testing.T) {
+	source := &fakeSource{subscriptions: []*v1.InternalSubscriptionInfo{activeSubscription()}}
+	enforcer := NewEnforcer(source, WithClock(clock.Fixed(testNow)))
+	ctx := tenantContext()
+
+	ent, err := enforcer.Check(ctx, "mall", "store_count", 1)
+	require.NoError(t, err)
+	require.Equal(t, "sub-1", ent.SubscriptionCode)
+	require.Equal(t, int32(1), ent.Remaining)
+	require.False(t, ent.IsTrial())
+
+	ent, err = enforcer.Check(ctx, "mall", "store_count", 2)
+	require.Equal(t, "QUOTA_EXCEEDED", errorType(t, err))
+	require.NotNil(t, ent)
+
+	ent, err = enforcer.Check(ctx, "mall", "api_calls", 1000000)
+	require.NoError(t, err)
+	require.True(t, ent.Unlimited)
+	require.Equal(t, Unlimited, ent.Remaining)
+
+	_, err = enforcer.Check(ctx, "mall", "custom_domain", 0)
+	require.Equal(t, "FEATURE_DISABLED", errorType(t, err))
+
+	_, err = enforcer.Check(ctx, "mall", "unknown", 0)
+	require.Equal(t, "FEATURE_DISABLED", errorType(t, err))
+
+	_, err = enforcer.Check(context.Background(), "mall", "store_count", 1)
+	require.Equal(t, "TENANT_MISSING", errorType(t, err))
+
+	require.Equal(t, 1, source.listCalls)
+}
+
+func TestEnforcer_Status(t *testing.T) {
+	fake := clock.NewFake(testNow)
+
+	trial := activeSubscription()
+	trial.Status = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL
+	trial.IsTrial = true
+	trial.TrialEndDate = timestamppb.New(testNow.Add(time.Hour))
+	trial.EndDate = timestamppb.New(testNow.AddDate(0, 1, 0))
+
+	source := &fakeSource{subscriptions: []*v1.InternalSubscriptionInfo{trial}}
+	enforcer := NewEnforcer(source, WithClock(fake), WithCacheTTL(24*time.Hour))
+	ctx := tenantContext()
+
+	ent, err := enforcer.Check(ctx, "mall", "store_count", 1)
+	require.NoError(t, err)
+	require.True(t, ent.IsTrial())
+	require.Equal(t, testNow.Add(time.Hour), ent.ExpiresAt)
+
+	// 缓存在试用结束时失效
+	fake.Advance(time.Hour)
+	_, err = enforcer.Check(ctx, "mall", "store_count", 1)
+	require.Equal(t, "SUBSCRIPTION_EXPIRED", errorType(t, err))
+	require.Equal(t, 2, source.listCalls)
+
+	suspended := activeSubscription()
+	suspended.Status = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_SUSPENDED
+	source.subscriptions = []*v1.InternalSubscriptionInfo{trial, suspended}
+	enforcer.Invalidate("t1", "mall")
+	_, err = enforcer.Check(ctx, "mall", "store_count", 1)
+	require.Equal(t, "SUBSCRIPTION_SUSPENDED", errorType(t, err))
+
+	source.subscriptions = nil
+	enforcer.Invalidate("t1", "mall")
+	_, err = enforcer.Check(ctx, "mall", "store_count", 1)
+	require.Equal(t, "SUBSCRIPTION_NOT_FOUND", errorType(t, err))
+}
+
+func TestEnforcer_MergeAndSnapshot(t *testing.T) {
+	addon := &v1.InternalSubscriptionInfo{
+		SubscriptionCode: "sub-2",
+		Status:           v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE,
+		QuotaSnapshot: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"store_count":   structpb.NewNumberValue(5),
+			"custom_domain": structpb.NewBoolValue(true),
+		}},
+	}
+	source := &fakeSource{subscriptions: []*v1.InternalSubscriptionInfo{activeSubscription(), addon}}
+	enforcer := NewEnforcer(source, WithClock(clock.Fixed(testNow)))
+	ctx := tenantContext()
+
+	ent, err := enforcer.Check(ctx, "mall", "store_count", 6)
+	require.NoError(t, err)
+	require.Equal(t, int32(8), ent.Limit)
+	require.Equal(t, int32(6), ent.Remaining)
+	require.True(t, ent.ExpiresAt.IsZero())
+
+	_, err = enforcer.Check(ctx, "mall", "custom_domain", 0)
+	require.NoError(t, err)
+}
+
+func TestEnforcer_ConsumeRelease(t *testing.T) {
+	source := &fakeSource{subscriptions: []*v1.InternalSubscriptionInfo{activeSubscription()}}
+	enforcer := NewEnforcer(source, WithClock(clock.Fixed(testNow)))
+	ctx := tenantContext()
+
+	_, err := enforcer.Consume(ctx, "mall", "store_count", 1, "")
+	require.Equal(t, "INVALID_PARAMETER", errorType(t, err))
+
+	ent, err := enforcer.Consume(ctx, "mall", "store_count", 1, "req-1")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), ent.Used)
+	require.Equal(t, int32(0), ent.Remaining)
+
+	// 本地缓存已更新，无需再次请求订阅服务即可拒绝
+	_, err = enforcer.Consume(ctx, "mall", "store_count", 1, "req-2")
+	require.Equal(t, "QUOTA_EXCEEDED", errorType(t, err))
+	require.Len(t, source.consumed, 1)
+
+	ent, err = enforcer.Release(ctx, "mall", "store_count", 1, "req-1")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), ent.Remaining)
+
+	_, err = enforcer.Check(ctx, "mall", "store_count", 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, source.listCalls)
+}
+
+type testTransport struct {
+	transport.Transporter
+	operation string
+}
+
+func (t testTransport) Operation() string { return t.operation }
+
+func TestServer(t *testing.T) {
+	source := &fakeSource{subscriptions: []*v1.InternalSubscriptionInfo{activeSubscription()}}
+	enforcer := NewEnforcer(source, WithClock(clock.Fixed(testNow)))
+	mw := Server(enforcer, map[string]Requirement{
+		"/store/Create": {ProductCode: "mall", DimensionKey: "store_count", Amount: 1},
+		"/store/Batch": {ProductCode: "mall", DimensionKey: "store_count", AmountFunc: func(req interface{}) int32 {
+			return int32(len(req.([]string)))
+		}},
+	})
+
+	var got *Entitlement
+	handler := mw(func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = FromContext(ctx)
+		return "ok", nil
+	})
+
+	ctx := transport.NewServerContext(tenantContext(), testTransport{operation: "/store/Create"})
+	reply, err := handler(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, "ok", reply)
+	require.Equal(t, "store_count", got.DimensionKey)
+
+	ctx = transport.NewServerContext(tenantContext(), testTransport{operation: "/store/Batch"})
+	_, err = handler(ctx, []string{"a", "b"})
+	require.Equal(t, 429, errors.Code(err))
+	require.Equal(t, "QUOTA_EXCEEDED", errors.Reason(err))
+
+	got = nil
+	ctx = transport.NewServerContext(tenantContext(), testTransport{operation: "/store/List"})
+	_, err = handler(ctx, nil)
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
@@ -0,0 +1,69 @@
+package entitlement
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+)
+
+// Requirement 接口声明需要的权益
+type Requirement struct {
+	ProductCode  string
+	DimensionKey string
+	// Amount 需要的配额数量，0 表示只校验权益是否开通
+	Amount int32
+	// AmountFunc 根据请求计算需要的配额数量，设置后忽略 Amount
+	AmountFunc func(req interface{}) int32
+}
+
+type entitlementKey struct{}
+
+// NewContext 将校验通过的权益存入 context
+func NewContext(ctx context.Context, ent *Entitlement) context.Context {
+	return context.WithValue(ctx, entitlementKey{}, ent)
+}
+
+// FromContext 获取中间件校验通过的权益
+func FromContext(ctx context.Context) (*Entitlement, bool) {
+	ent, ok := ctx.Value(entitlementKey{}).(*Entitlement)
+	return ent, ok
+}
+
+// Server 权益校验中间件，按 operation 匹配 requirements 中声明的权益
+//
+// 需要放在 auth.Server() 之后。中间件只做校验，不消耗配额；
+// 业务成功后由调用方通过 Enforcer.Consume 扣减。
+//
+// 示例:
+//
+//	entitlement.Server(enforcer, map[string]entitlement.Requirement{
+//		"/api.store.v1.StoreService/CreateStore": {ProductCode: "mall", DimensionKey: "store_count", Amount: 1},
+//	})
+func Server(enforcer *Enforcer, requirements map[string]Requirement) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			requirement, ok := requirements[tr.Operation()]
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			amount := requirement.Amount
+			if requirement.AmountFunc != nil {
+				amount = requirement.AmountFunc(req)
+			}
+
+			ent, err := enforcer.Check(ctx, requirement.ProductCode, requirement.DimensionKey, amount)
+			if err != nil {
+				return nil, businessErrors.ToKratosError(err)
+			}
+			return handler(NewContext(ctx, ent), req)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package subscribe
+
+import (
+	"fmt"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+)
+
+// Status 订阅状态
+type Status = v1.InternalSubscriptionStatus
+
+const (
+	StatusActive    = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_ACTIVE
+	StatusTrial     = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_TRIAL
+	StatusSuspended = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_SUSPENDED
+	StatusExpired   = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_EXPIRED
+	StatusCancelled = v1.InternalSubscriptionStatus_INTERNAL_SUBSCRIPTION_STATUS_CANCELLED
+)
+
+// Event 订阅生命周期事件
+type Event string
+
+const (
+	EventConvert   Event = "convert"   // 试用转正式
+	EventRenew     Event = "renew"     // 续费（过期后续费即重新激活）
+	EventUpgrade   Event = "upgrade"   // 升级套餐
+	EventDowngrade Event = "downgrade" // 降级套餐
+	EventSuspend   Event = "suspend"   // 暂停（欠费、违规）
+	EventResume    Event = "resume"    // 恢复
+	EventExpire    Event = "expire"    // 到期
+	EventCancel    Event = "cancel"    // 取消
+)
+
+// transitions 合法的状态迁移，CANCELLED 为终态
+var transitions = map[Status]map[Event]Status{
+	StatusTrial: {
+		EventConvert: StatusActive,
+		EventUpgrade: StatusActive,
+		EventSuspend: StatusSuspended,
+		EventExpire:  StatusExpired,
+		EventCancel:  StatusCancelled,
+	},
+	StatusActive: {
+		EventRenew:     StatusActive,
+		EventUpgrade:   StatusActive,
+		EventDowngrade: StatusActive,
+		EventSuspend:   StatusSuspended,
+		EventExpire:    StatusExpired,
+		EventCancel:    StatusCancelled,
+	},
+	StatusSuspended: {
+		EventResume: StatusActive,
+		EventExpire: StatusExpired,
+		EventCancel: StatusCancelled,
+	},
+	StatusExpired: {
+		EventRenew:  StatusActive,
+		EventCancel: StatusCancelled,
+	},
+}
+
+// TransitionError 非法的状态迁移
+type TransitionError struct {
+	From  Status
+	Event Event
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("subscribe: illegal transition %s on %s", e.Event, e.From)
+}
+
+// Unwrap 使 errors.Is(err, businessErrors.ErrDataConflict) 成立
+func (e *TransitionError) Unwrap() error {
+	return businessErrors.ErrDataConflict
+}
+
+// InitialStatus 新建订阅的初始状态
+func InitialStatus(isTrial bool) Status {
+	if isTrial {
+		return StatusTrial
+	}
+	return StatusActive
+}
+
+// NextStatus 计算 from 状态在 event 作用下的下一个状态
+func NextStatus(from Status, event Event) (Status, error) {
+	if to, ok := transitions[from][event]; ok {
+		return to, nil
+	}
+	return from, &TransitionError{From: from, Event: event}
+}
+
+// CanTransition 判断 from 是否可以通过某个事件迁移到 to
+func CanTransition(from, to Status) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedEvents 返回 from 状态下允许的事件
+func AllowedEvents(from Status) []Event {
+	events := make([]Event, 0, len(transitions[from]))
+	for _, event := range []Event{EventConvert, EventRenew, EventUpgrade, EventDowngrade, EventSuspend, EventResume, EventExpire, EventCancel} {
+		if _, ok := transitions[from][event]; ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// IsTerminal 判断状态是否为终态
+func IsTerminal(status Status) bool {
+	return len(transitions[status]) == 0
+}
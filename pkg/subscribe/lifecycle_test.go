@@ -0,0 +1,48 @@
+package subscribe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+)
+
+func TestNextStatus(t *testing.T) {
+	tests := []struct {
+		from  Status
+		event Event
+		to    Status
+		ok    bool
+	}{
+		{StatusTrial, EventConvert, StatusActive, true},
+		{StatusTrial, EventExpire, StatusExpired, true},
+		{StatusTrial, EventRenew, StatusTrial, false},
+		{StatusActive, EventRenew, StatusActive, true},
+		{StatusActive, EventSuspend, StatusSuspended, true},
+		{StatusActive, EventResume, StatusActive, false},
+		{StatusSuspended, EventResume, StatusActive, true},
+		{StatusSuspended, EventUpgrade, StatusSuspended, false},
+		{StatusExpired, EventRenew, StatusActive, true},
+		{StatusExpired, EventSuspend, StatusExpired, false},
+		{StatusCancelled, EventRenew, StatusCancelled, false},
+	}
+	for _, tt := range tests {
+		to, err := NextStatus(tt.from, tt.event)
+		require.Equal(t, tt.to, to, "%s on %s", tt.event, tt.from)
+		if tt.ok {
+			require.NoError(t, err)
+			continue
+		}
+		var te *TransitionError
+		require.True(t, errors.As(err, &te))
+		require.ErrorIs(t, err, businessErrors.ErrDataConflict)
+	}
+
+	require.Equal(t, StatusTrial, InitialStatus(true))
+	require.True(t, CanTransition(StatusSuspended, StatusActive))
+	require.False(t, CanTransition(StatusCancelled, StatusActive))
+	require.True(t, IsTerminal(StatusCancelled))
+	require.Equal(t, []Event{EventRenew, EventCancel}, AllowedEvents(StatusExpired))
+}
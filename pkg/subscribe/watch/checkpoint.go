@@ -0,0 +1,62 @@
+package watch
+
+import (
+	"context"
+	"sync"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// CheckpointStore 位点存储
+type CheckpointStore interface {
+	// Load 读取位点，不存在时返回 nil
+	Load(ctx context.Context, key string) ([]byte, error)
+	Save(ctx context.Context, key string, data []byte) error
+}
+
+// memoryCheckpointStore 进程内位点存储，仅适用于单实例或测试
+type memoryCheckpointStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemoryCheckpointStore 创建进程内位点存储
+func NewMemoryCheckpointStore() CheckpointStore {
+	return &memoryCheckpointStore{data: make(map[string][]byte)}
+}
+
+func (s *memoryCheckpointStore) Load(_ context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data[key], nil
+}
+
+func (s *memoryCheckpointStore) Save(_ context.Context, key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// consulCheckpointStore 基于 Consul KV 的位点存储
+type consulCheckpointStore struct {
+	kv *consulapi.KV
+}
+
+// NewConsulCheckpointStore 创建基于 Consul KV 的位点存储，多实例共享
+func NewConsulCheckpointStore(client *consulapi.Client) CheckpointStore {
+	return &consulCheckpointStore{kv: client.KV()}
+}
+
+func (s *consulCheckpointStore) Load(ctx context.Context, key string) ([]byte, error) {
+	pair, _, err := s.kv.Get(key, (&consulapi.QueryOptions{}).WithContext(ctx))
+	if err != nil || pair == nil {
+		return nil, err
+	}
+	return pair.Value, nil
+}
+
+func (s *consulCheckpointStore) Save(ctx context.Context, key string, data []byte) error {
+	_, err := s.kv.Put(&consulapi.KVPair{Key: key, Value: data}, (&consulapi.WriteOptions{}).WithContext(ctx))
+	return err
+}
@@ -0,0 +1,87 @@
+package watch
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	consulapi "github.com/hashicorp/consul/api"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// Elector 选主，Watcher 与 Scheduler 只在主节点上工作
+type Elector interface {
+	IsLeader() bool
+}
+
+type alwaysLeader struct{}
+
+func (alwaysLeader) IsLeader() bool { return true }
+
+// AlwaysLeader 单实例部署时使用，始终为主节点
+func AlwaysLeader() Elector {
+	return alwaysLeader{}
+}
+
+// ConsulElector 基于 Consul 分布式锁的选主
+type ConsulElector struct {
+	client *consulapi.Client
+	key    string
+	logger *log.Helper
+	leader atomic.Bool
+}
+
+// NewConsulElector 创建基于 Consul 分布式锁的选主，需要调用 Run 参与竞选
+func NewConsulElector(client *consulapi.Client, key string) *ConsulElector {
+	return &ConsulElector{
+		client: client,
+		key:    key,
+		logger: log.NewHelper(log.With(log.GetLogger(), "module", "subscribe-watch")),
+	}
+}
+
+// IsLeader 当前实例是否持有锁
+func (e *ConsulElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Run 持续参与竞选，失去锁后重新竞选，阻塞直到 ctx 结束
+func (e *ConsulElector) Run(ctx context.Context) error {
+	for {
+		if err := e.campaign(ctx); err != nil {
+			e.logger.WithContext(ctx).Errorf("竞选主节点失败: key=%s, error=%v", e.key, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.FromContext(ctx).After(time.Second):
+		}
+	}
+}
+
+func (e *ConsulElector) campaign(ctx context.Context) error {
+	lock, err := e.client.LockKey(e.key)
+	if err != nil {
+		return err
+	}
+	lost, err := lock.Lock(ctx.Done())
+	if err != nil || lost == nil {
+		return err
+	}
+
+	e.leader.Store(true)
+	e.logger.WithContext(ctx).Infof("成为主节点: key=%s", e.key)
+	defer func() {
+		e.leader.Store(false)
+		_ = lock.Unlock()
+		e.logger.WithContext(ctx).Infof("失去主节点: key=%s", e.key)
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-lost:
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	"github.com/heyinLab/common/pkg/subscribe"
+)
+
+const (
+	// firedRetention 已触发记录在截止时间之后的保留时长
+	firedRetention = 30 * 24 * time.Hour
+	// schedulerKeySuffix 调度器位点 key 的后缀
+	schedulerKeySuffix = "/scheduler"
+)
+
+// Deadline 提醒参照的截止时间
+type Deadline string
+
+const (
+	DeadlineEnd      Deadline = "end_date"       // 订阅结束时间
+	DeadlineTrialEnd Deadline = "trial_end_date" // 试用结束时间
+)
+
+// Rule 提醒规则，在截止时间之前 Before 触发
+type Rule struct {
+	Name     string
+	Deadline Deadline
+	Before   time.Duration
+	// MaxDelay 允许的最大延迟，超过后不再触发（如服务长时间停机），0 表示不限制
+	MaxDelay time.Duration
+}
+
+// Due 到期的提醒
+type Due struct {
+	// ID 提醒唯一标识，截止时间变化（如续费）后会产生新的 ID
+	ID           string
+	Rule         Rule
+	Subscription *v1.InternalSubscriptionInfo
+	Deadline     time.Time
+	FireAt       time.Time
+}
+
+// DueHandler 提醒处理函数，返回错误时下一轮重试
+type DueHandler func(ctx context.Context, due Due) error
+
+// Scheduler 在订阅 end_date / trial_end_date 之前触发回调，用于续费提醒与自动续费
+//
+// 已触发的提醒记录在 CheckpointStore 中，同一提醒只会成功触发一次；只有主节点会触发。
+// 通常使用 Watch 从订阅列表同步需要调度的订阅，可以与 Watcher 共用同一组选项：
+//
+//	scheduler := watch.NewScheduler(handler, rules, opts...)
+//	go scheduler.Run(ctx)
+//	scheduler.Watch(ctx, client, nil, opts...)
+type Scheduler struct {
+	handler DueHandler
+	rules   []Rule
+	opts    *options
+
+	mu            sync.Mutex
+	subscriptions map[string]*v1.InternalSubscriptionInfo
+	fired         map[string]int64
+	loaded        bool
+	wake          chan struct{}
+}
+
+// NewScheduler 创建提醒调度器
+//
+// 已触发记录保存在 WithCheckpoint 的 key 加上 /scheduler 后缀下（默认 subscribe/watch/scheduler），
+// 与 Watcher 的快照互不覆盖。
+func NewScheduler(handler DueHandler, rules []Rule, opts ...Option) *Scheduler {
+	o := newOptions(opts)
+	o.checkpointKey += schedulerKeySuffix
+	return &Scheduler{
+		handler:       handler,
+		rules:         rules,
+		opts:          o,
+		subscriptions: make(map[string]*v1.InternalSubscriptionInfo),
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Track 添加或更新需要调度的订阅
+func (s *Scheduler) Track(sub *v1.InternalSubscriptionInfo) {
+	s.mu.Lock()
+	s.subscriptions[sub.GetSubscriptionCode()] = sub
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Untrack 移除订阅
+func (s *Scheduler) Untrack(subscriptionCode string) {
+	s.mu.Lock()
+	delete(s.subscriptions, subscriptionCode)
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Sync 使用全量订阅替换需要调度的订阅，实现 SnapshotHandler
+func (s *Scheduler) Sync(_ context.Context, subscriptions map[string]*v1.InternalSubscriptionInfo) {
+	tracked := make(map[string]*v1.InternalSubscriptionInfo, len(subscriptions))
+	for code, sub := range subscriptions {
+		tracked[code] = sub
+	}
+	s.mu.Lock()
+	s.subscriptions = tracked
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Watch 监听订阅列表并同步到调度器，阻塞直到 ctx 结束
+//
+// 每轮的全量订阅通过 Sync 同步，因此首轮与重启后已存在的订阅同样会被调度；
+// handler 不为 nil 时同时接收订阅变更事件。
+func (s *Scheduler) Watch(ctx context.Context, lister Lister, handler Handler, opts ...Option) error {
+	opts = append(opts[:len(opts):len(opts)], WithSnapshot(s.Sync))
+	if handler == nil {
+		handler = func(context.Context, Event) error { return nil }
+	}
+	return WatchSubscriptions(ctx, lister, handler, opts...)
+}
+
+// HandleEvent 实现 Handler，将 Watcher 的变更同步到调度器
+func (s *Scheduler) HandleEvent(_ context.Context, event Event) error {
+	if event.Type == EventRemoved || event.Subscription == nil {
+		s.Untrack(event.SubscriptionCode)
+		return nil
+	}
+	s.Track(event.Subscription)
+	return nil
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run 调度循环，阻塞直到 ctx 结束
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		next, err := s.Fire(ctx)
+		if err != nil {
+			s.opts.logger.WithContext(ctx).Errorf("触发订阅提醒失败: %v", err)
+		}
+
+		wait := s.opts.interval
+		if !next.IsZero() {
+			wait = min(wait, max(next.Sub(s.opts.clock.Now()), 0))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wake:
+		case <-s.opts.clock.After(wait):
+		}
+	}
+}
+
+// Fire 触发所有已到期的提醒，返回下一个提醒的触发时间（没有则为零值）
+func (s *Scheduler) Fire(ctx context.Context) (time.Time, error) {
+	if !s.opts.elector.IsLeader() {
+		s.mu.Lock()
+		s.loaded = false
+		s.mu.Unlock()
+		return time.Time{}, nil
+	}
+	if err := s.load(ctx); err != nil {
+		return time.Time{}, err
+	}
+
+	now := s.opts.clock.Now()
+	dues, next := s.collect(now)
+
+	var failed int
+	for _, due := range dues {
+		if err := s.handler(ctx, due); err != nil {
+			s.opts.logger.WithContext(ctx).Errorf("处理订阅提醒失败: id=%s, error=%v", due.ID, err)
+			failed++
+			continue
+		}
+		s.mu.Lock()
+		s.fired[due.ID] = due.Deadline.Unix()
+		s.mu.Unlock()
+	}
+
+	if err := s.save(ctx, now); err != nil {
+		return next, err
+	}
+	if failed > 0 {
+		return next, fmt.Errorf("watch: %d reminders failed", failed)
+	}
+	return next, nil
+}
+
+// collect 计算到期的提醒（按触发时间排序）与下一个提醒的触发时间
+func (s *Scheduler) collect(now time.Time) ([]Due, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		dues []Due
+		next time.Time
+	)
+	for code, sub := range s.subscriptions {
+		switch sub.GetStatus() {
+		case subscribe.StatusExpired, subscribe.StatusCancelled:
+			continue
+		}
+		for _, rule := range s.rules {
+			deadline := deadlineOf(sub, rule.Deadline)
+			if deadline.IsZero() {
+				continue
+			}
+			due := Due{
+				ID:           fmt.Sprintf("%s:%s:%d", code, rule.Name, deadline.Unix()),
+				Rule:         rule,
+				Subscription: sub,
+				Deadline:     deadline,
+				FireAt:       deadline.Add(-rule.Before),
+			}
+			if _, ok := s.fired[due.ID]; ok {
+				continue
+			}
+			if now.Before(due.FireAt) {
+				if next.IsZero() || due.FireAt.Before(next) {
+					next = due.FireAt
+				}
+				continue
+			}
+			if rule.MaxDelay > 0 && now.Sub(due.FireAt) > rule.MaxDelay {
+				s.fired[due.ID] = deadline.Unix()
+				continue
+			}
+			dues = append(dues, due)
+		}
+	}
+	sort.Slice(dues, func(i, j int) bool {
+		if dues[i].FireAt.Equal(dues[j].FireAt) {
+			return dues[i].ID < dues[j].ID
+		}
+		return dues[i].FireAt.Before(dues[j].FireAt)
+	})
+	return dues, next
+}
+
+func deadlineOf(sub *v1.InternalSubscriptionInfo, deadline Deadline) time.Time {
+	switch deadline {
+	case DeadlineEnd:
+		if sub.GetEndDate() != nil {
+			return sub.GetEndDate().AsTime()
+		}
+	case DeadlineTrialEnd:
+		if sub.GetIsTrial() && sub.GetTrialEndDate() != nil {
+			return sub.GetTrialEndDate().AsTime()
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Scheduler) load(ctx context.Context) error {
+	s.mu.Lock()
+	loaded := s.loaded
+	s.mu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	data, err := s.opts.checkpoint.Load(ctx, s.opts.checkpointKey)
+	if err != nil {
+		return err
+	}
+	fired := make(map[string]int64)
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &fired); err != nil {
+			return fmt.Errorf("watch: invalid checkpoint %s: %w", s.opts.checkpointKey, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.fired = fired
+	s.loaded = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Scheduler) save(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	for id, deadline := range s.fired {
+		if now.Sub(time.Unix(deadline, 0)) > firedRetention {
+			delete(s.fired, id)
+		}
+	}
+	data, err := json.Marshal(s.fired)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.opts.checkpoint.Save(ctx, s.opts.checkpointKey, data)
+}
@@ -0,0 +1,139 @@
+package watch
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	"github.com/heyinLab/common/pkg/subscribe"
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(testNow)
+	store := NewMemoryCheckpointStore()
+	rules := []Rule{
+		{Name: "remind", Deadline: DeadlineEnd, Before: 72 * time.Hour},
+		{Name: "renew", Deadline: DeadlineEnd, Before: time.Hour},
+		{Name: "trial", Deadline: DeadlineTrialEnd, Before: 24 * time.Hour, MaxDelay: time.Hour},
+	}
+
+	var fired []string
+	handler := func(_ context.Context, due Due) error {
+		fired = append(fired, due.ID)
+		return nil
+	}
+	s := NewScheduler(handler, rules, WithClock(fake), WithCheckpoint(store, "scheduler"))
+
+	s1 := newSubscription("s1", "basic", subscribe.StatusActive, testNow.Add(96*time.Hour))
+	s.Track(s1)
+	trial := newSubscription("s2", "basic", subscribe.StatusTrial, testNow.AddDate(0, 1, 0))
+	trial.IsTrial = true
+	trial.TrialEndDate = timestamppb.New(testNow.Add(12 * time.Hour))
+	s.Track(trial)
+	s.Track(newSubscription("s3", "basic", subscribe.StatusCancelled, testNow.Add(time.Hour)))
+
+	// 试用提醒已延迟 12 小时，超过 MaxDelay，跳过
+	next, err := s.Fire(ctx)
+	require.NoError(t, err)
+	require.Empty(t, fired)
+	require.Equal(t, testNow.Add(24*time.Hour), next)
+
+	fake.Advance(24 * time.Hour)
+	next, err = s.Fire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"s1:remind:" + itoa(testNow.Add(96*time.Hour))}, fired)
+	require.Equal(t, testNow.Add(95*time.Hour), next)
+
+	// 不重复触发
+	_, err = s.Fire(ctx)
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+
+	// 续费后截止时间变化，产生新的提醒
+	fake.Advance(72 * time.Hour)
+	renewed := newSubscription("s1", "basic", subscribe.StatusActive, testNow.Add(96*time.Hour).AddDate(0, 1, 0))
+	require.NoError(t, s.HandleEvent(ctx, Event{Type: EventRenewed, SubscriptionCode: "s1", Subscription: renewed}))
+	_, err = s.Fire(ctx)
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+
+	// 新的调度器从位点恢复，已触发的不再触发
+	s2 := NewScheduler(handler, rules, WithClock(fake), WithCheckpoint(store, "scheduler"))
+	s2.Track(s1)
+	_, err = s2.Fire(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"s1:remind:" + itoa(testNow.Add(96*time.Hour)), "s1:renew:" + itoa(testNow.Add(96*time.Hour))}, fired)
+}
+
+func TestScheduler_Run(t *testing.T) {
+	fake := clock.NewFake(testNow)
+	fired := make(chan Due, 1)
+	s := NewScheduler(func(_ context.Context, due Due) error {
+		fired <- due
+		return nil
+	}, []Rule{{Name: "renew", Deadline: DeadlineEnd}}, WithClock(fake))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Run(ctx) }()
+
+	s.Track(newSubscription("s1", "basic", subscribe.StatusActive, testNow.Add(10*time.Second)))
+	require.Eventually(t, func() bool {
+		for _, at := range fake.Timers() {
+			if at.Equal(testNow.Add(10 * time.Second)) {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+
+	fake.Advance(10 * time.Second)
+	due := <-fired
+	require.Equal(t, "s1", due.Subscription.GetSubscriptionCode())
+	require.Equal(t, testNow.Add(10*time.Second), due.FireAt)
+}
+
+func TestScheduler_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake := clock.NewFake(testNow)
+	store := NewMemoryCheckpointStore()
+	opts := []Option{WithClock(fake), WithCheckpoint(store, "subscriptions")}
+	lister := &fakeLister{subscriptions: []*v1.InternalSubscriptionInfo{
+		newSubscription("s1", "basic", subscribe.StatusActive, testNow.Add(time.Hour)),
+	}}
+
+	// 重启前的实例已经记录了快照，s1 不会再产生变更事件
+	require.NoError(t, NewWatcher(lister, func(context.Context, Event) error { return nil }, opts...).Poll(ctx))
+
+	var fired []string
+	s := NewScheduler(func(_ context.Context, due Due) error {
+		fired = append(fired, due.ID)
+		return nil
+	}, []Rule{{Name: "renew", Deadline: DeadlineEnd, Before: 2 * time.Hour}}, opts...)
+	go func() { _ = s.Watch(ctx, lister, nil, opts...) }()
+
+	require.Eventually(t, func() bool {
+		_, err := s.Fire(ctx)
+		return err == nil && len(fired) == 1
+	}, time.Second, time.Millisecond)
+	require.Equal(t, "s1:renew:"+itoa(testNow.Add(time.Hour)), fired[0])
+
+	// Watcher 与 Scheduler 共用选项时位点互不覆盖
+	watcher := NewWatcher(lister, func(context.Context, Event) error { return nil }, opts...)
+	require.NoError(t, watcher.Poll(ctx))
+	restarted := NewScheduler(func(context.Context, Due) error { return nil }, nil, opts...)
+	_, err := restarted.Fire(ctx)
+	require.NoError(t, err)
+}
+
+func itoa(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,368 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	"github.com/heyinLab/common/pkg/subscribe"
+	"github.com/heyinLab/common/pkg/utils/clock"
+	"github.com/heyinLab/common/pkg/utils/pagination"
+)
+
+const (
+	// DefaultInterval 默认轮询间隔
+	DefaultInterval = time.Minute
+	// DefaultPageSize 默认分页大小
+	DefaultPageSize = 200
+)
+
+// Lister 订阅列表来源，*subscribe.SubscribeClient 实现了该接口
+type Lister interface {
+	ListSubscriptions(ctx context.Context, req *v1.InternalListSubscriptionsRequest) (*v1.InternalListSubscriptionsResponse, error)
+}
+
+var _ Lister = (*subscribe.SubscribeClient)(nil)
+
+// EventType 订阅变更类型
+type EventType string
+
+const (
+	EventCreated       EventType = "created"        // 新订阅
+	EventPlanChanged   EventType = "plan_changed"   // 升级/降级
+	EventRenewed       EventType = "renewed"        // 续费（结束时间延后）
+	EventStatusChanged EventType = "status_changed" // 状态变化（暂停、过期、取消、试用转正式等）
+	EventUpdated       EventType = "updated"        // 其他变化（如试用结束时间调整）
+	EventRemoved       EventType = "removed"        // 不再出现在列表中
+)
+
+// State 用于比较变更的订阅快照
+type State struct {
+	Status       subscribe.Status `json:"status"`
+	PlanCode     string           `json:"plan_code"`
+	EndDate      int64            `json:"end_date,omitempty"`
+	TrialEndDate int64            `json:"trial_end_date,omitempty"`
+}
+
+// StateOf 提取订阅快照
+func StateOf(sub *v1.InternalSubscriptionInfo) State {
+	s := State{Status: sub.GetStatus(), PlanCode: sub.GetPlanCode()}
+	if sub.GetEndDate() != nil {
+		s.EndDate = sub.GetEndDate().AsTime().Unix()
+	}
+	if sub.GetTrialEndDate() != nil {
+		s.TrialEndDate = sub.GetTrialEndDate().AsTime().Unix()
+	}
+	return s
+}
+
+func (s State) fingerprint() string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d|%s|%d|%d", s.Status, s.PlanCode, s.EndDate, s.TrialEndDate)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Event 订阅变更事件
+type Event struct {
+	// ID 事件唯一标识，同一订阅的同一状态只会产生一个 ID，可用于下游幂等
+	ID               string
+	Type             EventType
+	SubscriptionCode string
+	// Subscription 最新的订阅信息，EventRemoved 时为 nil
+	Subscription *v1.InternalSubscriptionInfo
+	// Previous 变更前的快照，EventCreated 时为 nil
+	Previous *State
+}
+
+// Handler 事件处理函数，返回错误时该订阅的变更会在下一轮重新投递
+type Handler func(ctx context.Context, event Event) error
+
+// SnapshotHandler 全量订阅处理函数，key 为订阅编码
+type SnapshotHandler func(ctx context.Context, subscriptions map[string]*v1.InternalSubscriptionInfo)
+
+// diff 比较快照，返回事件；没有变化返回 nil
+func diff(code string, prev *State, sub *v1.InternalSubscriptionInfo) *Event {
+	if sub == nil {
+		return &Event{ID: code + ":" + string(EventRemoved) + ":" + prev.fingerprint(), Type: EventRemoved, SubscriptionCode: code, Previous: prev}
+	}
+
+	cur := StateOf(sub)
+	var typ EventType
+	switch {
+	case prev == nil:
+		typ = EventCreated
+	case *prev == cur:
+		return nil
+	case prev.PlanCode != cur.PlanCode:
+		typ = EventPlanChanged
+	case cur.EndDate > prev.EndDate:
+		typ = EventRenewed
+	case prev.Status != cur.Status:
+		typ = EventStatusChanged
+	default:
+		typ = EventUpdated
+	}
+	return &Event{ID: code + ":" + cur.fingerprint(), Type: typ, SubscriptionCode: code, Subscription: sub, Previous: prev}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Option Watcher 配置项
+type Option func(*options)
+
+type options struct {
+	interval      time.Duration
+	pageSize      int32
+	tenantCode    string
+	productCode   string
+	checkpoint    CheckpointStore
+	checkpointKey string
+	elector       Elector
+	clock         clock.Clock
+	logger        *log.Helper
+	emitInitial   bool
+	snapshot      SnapshotHandler
+}
+
+// WithInterval 设置轮询间隔
+func WithInterval(d time.Duration) Option {
+	return func(o *options) { o.interval = d }
+}
+
+// WithPageSize 设置分页大小
+func WithPageSize(size int32) Option {
+	return func(o *options) { o.pageSize = size }
+}
+
+// WithFilter 只监听指定租户或产品，空字符串表示不过滤
+func WithFilter(tenantCode, productCode string) Option {
+	return func(o *options) {
+		o.tenantCode = tenantCode
+		o.productCode = productCode
+	}
+}
+
+// WithCheckpoint 设置位点存储，多实例部署时应使用共享存储
+func WithCheckpoint(store CheckpointStore, key string) Option {
+	return func(o *options) {
+		o.checkpoint = store
+		o.checkpointKey = key
+	}
+}
+
+// WithElector 设置选主，只有主节点会轮询与投递事件
+func WithElector(elector Elector) Option {
+	return func(o *options) { o.elector = elector }
+}
+
+// WithClock 设置时钟，默认为 clock.Default()，测试中可注入 clock.NewFake
+func WithClock(c clock.Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+// WithLogger 设置日志
+func WithLogger(logger log.Logger) Option {
+	return func(o *options) { o.logger = log.NewHelper(log.With(logger, "module", "subscribe-watch")) }
+}
+
+// WithEmitInitial 没有位点时，首轮为已存在的订阅投递 EventCreated；默认只记录快照不投递
+func WithEmitInitial(emit bool) Option {
+	return func(o *options) { o.emitInitial = emit }
+}
+
+// WithSnapshot 每轮拉取全量订阅后回调，用于重建下游的完整状态
+//
+// 与事件不同，快照不受位点影响：首轮与重启后从位点恢复时，未变化的订阅同样会出现在快照中。
+func WithSnapshot(fn SnapshotHandler) Option {
+	return func(o *options) { o.snapshot = fn }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		interval:      DefaultInterval,
+		pageSize:      DefaultPageSize,
+		checkpoint:    NewMemoryCheckpointStore(),
+		checkpointKey: "subscribe/watch",
+		elector:       AlwaysLeader(),
+		clock:         clock.Default(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.logger == nil {
+		o.logger = log.NewHelper(log.With(log.GetLogger(), "module", "subscribe-watch"))
+	}
+	return o
+}
+
+// Watcher 轮询订阅列表并投递变更事件
+//
+// 每轮拉取全量订阅与上一轮快照比较，快照持久化在 CheckpointStore 中，
+// 因此同一变更只会投递一次（处理失败的除外），重启或切主后从位点继续。
+type Watcher struct {
+	lister  Lister
+	handler Handler
+	opts    *options
+
+	states map[string]State
+	loaded bool
+}
+
+// NewWatcher 创建订阅监听器
+func NewWatcher(lister Lister, handler Handler, opts ...Option) *Watcher {
+	return &Watcher{lister: lister, handler: handler, opts: newOptions(opts)}
+}
+
+// WatchSubscriptions 监听订阅变更，阻塞直到 ctx 结束
+func WatchSubscriptions(ctx context.Context, lister Lister, handler Handler, opts ...Option) error {
+	return NewWatcher(lister, handler, opts...).Run(ctx)
+}
+
+// Run 按间隔轮询，阻塞直到 ctx 结束
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		if err := w.Poll(ctx); err != nil {
+			w.opts.logger.WithContext(ctx).Errorf("轮询订阅变更失败: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.opts.clock.After(w.opts.interval):
+		}
+	}
+}
+
+// Poll 执行一轮轮询，非主节点直接返回
+func (w *Watcher) Poll(ctx context.Context) error {
+	if !w.opts.elector.IsLeader() {
+		// 失去主节点后快照可能被新的主节点推进，重新成为主节点时需要重新加载
+		w.loaded = false
+		return nil
+	}
+	if !w.loaded {
+		if err := w.load(ctx); err != nil {
+			return err
+		}
+	}
+
+	subscriptions, err := w.list(ctx)
+	if err != nil {
+		return err
+	}
+	if w.opts.snapshot != nil {
+		w.opts.snapshot(ctx, subscriptions)
+	}
+
+	baseline := w.states == nil
+	if baseline {
+		w.states = make(map[string]State, len(subscriptions))
+	}
+
+	var failed int
+	for code, sub := range subscriptions {
+		var prev *State
+		if s, ok := w.states[code]; ok {
+			prev = &s
+		}
+		event := diff(code, prev, sub)
+		if event == nil {
+			continue
+		}
+		if baseline && !w.opts.emitInitial {
+			w.states[code] = StateOf(sub)
+			continue
+		}
+		if err = w.handler(ctx, *event); err != nil {
+			w.opts.logger.WithContext(ctx).Errorf("处理订阅变更失败: id=%s, type=%s, error=%v", event.ID, event.Type, err)
+			failed++
+			continue
+		}
+		w.states[code] = StateOf(sub)
+	}
+	for code, s := range w.states {
+		if _, ok := subscriptions[code]; ok {
+			continue
+		}
+		prev := s
+		event := diff(code, &prev, nil)
+		if err = w.handler(ctx, *event); err != nil {
+			w.opts.logger.WithContext(ctx).Errorf("处理订阅变更失败: id=%s, type=%s, error=%v", event.ID, event.Type, err)
+			failed++
+			continue
+		}
+		delete(w.states, code)
+	}
+
+	if err = w.save(ctx); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("watch: %d subscription events failed", failed)
+	}
+	return nil
+}
+
+func (w *Watcher) list(ctx context.Context) (map[string]*v1.InternalSubscriptionInfo, error) {
+	// 翻页过程中总数变化时放弃本轮，避免数据错位被误判为删除
+	seq := pagination.Iterate(ctx, func(ctx context.Context, page, pageSize int32) (pagination.Page[*v1.InternalSubscriptionInfo], error) {
+		sortBy, sortOrder := "create_time", "asc"
+		req := &v1.InternalListSubscriptionsRequest{
+			Page:      &page,
+			PageSize:  &pageSize,
+			SortBy:    &sortBy,
+			SortOrder: &sortOrder,
+		}
+		if w.opts.tenantCode != "" {
+			req.TenantCode = &w.opts.tenantCode
+		}
+		if w.opts.productCode != "" {
+			req.ProductCode = &w.opts.productCode
+		}
+
+		resp, err := w.lister.ListSubscriptions(ctx, req)
+		if err != nil {
+			return pagination.Page[*v1.InternalSubscriptionInfo]{}, err
+		}
+		return pagination.Page[*v1.InternalSubscriptionInfo]{Items: resp.GetSubscriptions(), Total: int64(resp.GetTotal())}, nil
+	}, pagination.WithPageSize(w.opts.pageSize))
+
+	result := make(map[string]*v1.InternalSubscriptionInfo)
+	for sub, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		result[sub.GetSubscriptionCode()] = sub
+	}
+	return result, nil
+}
+
+func (w *Watcher) load(ctx context.Context) error {
+	data, err := w.opts.checkpoint.Load(ctx, w.opts.checkpointKey)
+	if err != nil {
+		return err
+	}
+	w.states = nil
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &w.states); err != nil {
+			return fmt.Errorf("watch: invalid checkpoint %s: %w", w.opts.checkpointKey, err)
+		}
+		if w.states == nil {
+			w.states = make(map[string]State)
+		}
+	}
+	w.loaded = true
+	return nil
+}
+
+func (w *Watcher) save(ctx context.Context) error {
+	data, err := json.Marshal(w.states)
+	if err != nil {
+		return err
+	}
+	return w.opts.checkpoint.Save(ctx, w.opts.checkpointKey, data)
+}
@@ -0,0 +1,148 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/heyinLab/common/api/gen/go/subscribe/v1"
+	"github.com/heyinLab/common/pkg/subscribe"
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+type fakeLister struct {
+	subscriptions []*v1.InternalSubscriptionInfo
+	pages         int
+}
+
+func (l *fakeLister) ListSubscriptions(_ context.Context, req *v1.InternalListSubscriptionsRequest) (*v1.InternalListSubscriptionsResponse, error) {
+	l.pages++
+	start := int((req.GetPage() - 1) * req.GetPageSize())
+	end := min(start+int(req.GetPageSize()), len(l.subscriptions))
+	if start >= len(l.subscriptions) {
+		return &v1.InternalListSubscriptionsResponse{Total: int32(len(l.subscriptions))}, nil
+	}
+	return &v1.InternalListSubscriptionsResponse{Subscriptions: l.subscriptions[start:end], Total: int32(len(l.subscriptions))}, nil
+}
+
+type leaderFlag struct{ leader bool }
+
+func (f *leaderFlag) IsLeader() bool { return f.leader }
+
+var testNow = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func newSubscription(code, plan string, status subscribe.Status, end time.Time) *v1.InternalSubscriptionInfo {
+	return &v1.InternalSubscriptionInfo{
+		SubscriptionCode: code,
+		PlanCode:         plan,
+		Status:           status,
+		EndDate:          timestamppb.New(end),
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+	lister := &fakeLister{subscriptions: []*v1.InternalSubscriptionInfo{
+		newSubscription("s1", "basic", subscribe.StatusActive, testNow.AddDate(0, 1, 0)),
+		newSubscription("s2", "basic", subscribe.StatusTrial, testNow.AddDate(0, 0, 7)),
+		newSubscription("s3", "pro", subscribe.StatusActive, testNow.AddDate(1, 0, 0)),
+	}}
+	store := NewMemoryCheckpointStore()
+
+	var (
+		events []Event
+		fail   bool
+	)
+	handler := func(_ context.Context, e Event) error {
+		if fail {
+			return errors.New("boom")
+		}
+		events = append(events, e)
+		return nil
+	}
+	w := NewWatcher(lister, handler, WithCheckpoint(store, "watch"), WithPageSize(2))
+
+	// 首轮只记录快照
+	require.NoError(t, w.Poll(ctx))
+	require.Empty(t, events)
+	require.Equal(t, 2, lister.pages)
+
+	lister.subscriptions = []*v1.InternalSubscriptionInfo{
+		newSubscription("s1", "basic", subscribe.StatusActive, testNow.AddDate(0, 2, 0)),
+		newSubscription("s2", "basic", subscribe.StatusExpired, testNow.AddDate(0, 0, 7)),
+		newSubscription("s4", "basic", subscribe.StatusActive, testNow.AddDate(0, 1, 0)),
+	}
+	require.NoError(t, w.Poll(ctx))
+	types := map[string]EventType{}
+	for _, e := range events {
+		types[e.SubscriptionCode] = e.Type
+	}
+	require.Equal(t, map[string]EventType{"s1": EventRenewed, "s2": EventStatusChanged, "s3": EventRemoved, "s4": EventCreated}, types)
+
+	// 没有变化不重复投递
+	events = nil
+	require.NoError(t, w.Poll(ctx))
+	require.Empty(t, events)
+
+	// 处理失败的变更下一轮重新投递
+	lister.subscriptions[0] = newSubscription("s1", "pro", subscribe.StatusActive, testNow.AddDate(0, 2, 0))
+	fail = true
+	require.Error(t, w.Poll(ctx))
+	fail = false
+
+	// 新实例从位点继续
+	w2 := NewWatcher(lister, handler, WithCheckpoint(store, "watch"))
+	require.NoError(t, w2.Poll(ctx))
+	require.Len(t, events, 1)
+	require.Equal(t, EventPlanChanged, events[0].Type)
+	require.Equal(t, "basic", events[0].Previous.PlanCode)
+}
+
+func TestWatcher_Leader(t *testing.T) {
+	ctx := context.Background()
+	lister := &fakeLister{subscriptions: []*v1.InternalSubscriptionInfo{
+		newSubscription("s1", "basic", subscribe.StatusActive, testNow),
+	}}
+	elector := &leaderFlag{}
+	var events []Event
+	w := NewWatcher(lister, func(_ context.Context, e Event) error {
+		events = append(events, e)
+		return nil
+	}, WithElector(elector), WithEmitInitial(true))
+
+	require.NoError(t, w.Poll(ctx))
+	require.Zero(t, lister.pages)
+
+	elector.leader = true
+	require.NoError(t, w.Poll(ctx))
+	require.Len(t, events, 1)
+	require.Equal(t, EventCreated, events[0].Type)
+}
+
+func TestWatchSubscriptions(t *testing.T) {
+	fake := clock.NewFake(testNow)
+	lister := &fakeLister{}
+	events := make(chan Event, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchSubscriptions(ctx, lister, func(_ context.Context, e Event) error {
+			events <- e
+			return nil
+		}, WithClock(fake), WithInterval(time.Minute))
+	}()
+
+	fake.BlockUntil(1)
+
+	lister.subscriptions = []*v1.InternalSubscriptionInfo{newSubscription("s1", "basic", subscribe.StatusActive, testNow)}
+	fake.Advance(time.Minute)
+	require.Equal(t, "s1", (<-events).SubscriptionCode)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
@@ -2,88 +2,52 @@ package system
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/registry"
 	v1 "github.com/heyinLab/common/api/gen/go/system/v1"
-	middleware "github.com/heyinLab/common/pkg/middleware/grpc"
+	"github.com/heyinLab/common/pkg/clientkit"
 	"google.golang.org/grpc"
 )
 
 type Client struct {
-	config       *Config
-	conn         *grpc.ClientConn
-	logger       *log.Helper
-	systemClient *SystemClient
+	client *clientkit.Client[*SystemClient]
 }
 
-func NewClient(config *Config) (*Client, error) {
-	if config == nil {
-		config = DefaultConfig()
-	}
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "system-client",
-	))
+func NewClient(config *Config, opts ...clientkit.Option) (*Client, error) {
+	return newClient(config, nil, opts...)
+}
 
-	conn, err := middleware.CreateGRPCConn(config, nil, logger)
-	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+func NewClientWithDiscovery(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
+	if discovery == nil {
+		return nil, clientkit.ErrDiscoveryRequired
 	}
-	return &Client{
-		config:       config,
-		conn:         conn,
-		logger:       logger,
-		systemClient: newSystemClient(conn, logger, config),
-	}, nil
+	return newClient(config, discovery, opts...)
 }
 
-func NewClientWithDiscovery(config *Config, discovery registry.Discovery) (*Client, error) {
+func newClient(config *Config, discovery registry.Discovery, opts ...clientkit.Option) (*Client, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
-
-	if discovery == nil {
-		return nil, fmt.Errorf("服务发现实例不能为空")
-	}
-
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
-
-	logger := log.NewHelper(log.With(
-		log.GetLogger(),
-		"module", "system-client",
-	))
-
-	conn, err := middleware.CreateGRPCConn(config, discovery, logger)
+	opts = append([]clientkit.Option{clientkit.WithModule("system-client")}, opts...)
+	client, err := clientkit.New(newSystemClient, config, discovery, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("创建 gRPC 连接失败: %w", err)
+		return nil, err
 	}
-
-	logger.Infof("平台服务客户端连接成功 (服务发现): endpoint=%s, timeout=%v", config.Endpoint, config.Timeout)
-
-	return &Client{
-		config:       config,
-		conn:         conn,
-		logger:       logger,
-		systemClient: newSystemClient(conn, logger, config),
-	}, nil
+	return &Client{client: client}, nil
 }
 
 func (c *Client) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
-	}
-	return nil
+	return c.client.Close()
+}
+
+// Health 检查系统服务状态
+func (c *Client) Health(ctx context.Context) error {
+	return c.client.Health(ctx)
 }
 
 func (c *Client) SystemClient() *SystemClient {
-	return c.systemClient
+	return c.client.Service()
 }
 
 type SystemClient struct {
@@ -92,7 +56,7 @@ type SystemClient struct {
 	config *Config
 }
 
-func newSystemClient(conn *grpc.ClientConn, logger *log.Helper, config *Config) *SystemClient {
+func newSystemClient(conn grpc.ClientConnInterface, logger *log.Helper, config *Config) *SystemClient {
 	return &SystemClient{
 		client: v1.NewSystemInternalServiceClient(conn),
 		logger: logger,
@@ -101,9 +65,6 @@ func newSystemClient(conn *grpc.ClientConn, logger *log.Helper, config *Config)
 }
 
 func (s *SystemClient) GetCountryInfo(ctx context.Context, countryCode string) (*v1.InternalCountry, error) {
-	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
-	defer cancel()
-
 	resp, err := s.client.InternalGetCountryInfo(ctx, &v1.InternalGetCountryInfoRequest{
 		CountryCode: &countryCode,
 	})
@@ -115,3 +76,14 @@ func (s *SystemClient) GetCountryInfo(ctx context.Context, countryCode string) (
 
 	return resp.Country, nil
 }
+
+// ListCountries 获取全部国家
+func (s *SystemClient) ListCountries(ctx context.Context, req *v1.InternalListCountriesRequest) ([]*v1.InternalCountry, error) {
+	resp, err := s.client.InternalListCountries(ctx, req)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("获取国家列表失败:error=%v", err)
+		return nil, err
+	}
+
+	return resp.Countries, nil
+}
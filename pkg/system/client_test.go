@@ -34,7 +34,7 @@ func TestGetCountryInfo(t *testing.T) {
 
 	// 测试获取订阅列表
 	ctx := context.Background()
-	country, err := client.systemClient.GetCountryInfo(ctx, "CN")
+	country, err := client.SystemClient().GetCountryInfo(ctx, "CN")
 	if err != nil {
 		t.Logf("获取国家失败（可能服务未启动）: %v", err)
 		t.Skip("跳过测试，服务可能未启动")
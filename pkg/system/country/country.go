@@ -0,0 +1,213 @@
+package country
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "github.com/heyinLab/common/api/gen/go/system/v1"
+)
+
+//go:embed countries.csv
+var countriesCSV []byte
+
+// maxE164Digits E.164 号码（国家码 + 国内号码）的最大位数
+const maxE164Digits = 15
+
+// Region 所属区域
+type Region = v1.InternalRegion
+
+const (
+	RegionAsia         = v1.InternalRegion_INTERNAL_ASIA
+	RegionEurope       = v1.InternalRegion_INTERNAL_EUROPE
+	RegionNorthAmerica = v1.InternalRegion_INTERNAL_NORTH_AMERICA
+	RegionSouthAmerica = v1.InternalRegion_INTERNAL_SOUTH_AMERICA
+	RegionOceania      = v1.InternalRegion_INTERNAL_OCEANIA
+	RegionAfrica       = v1.InternalRegion_INTERNAL_AFRICA
+	RegionAntarctica   = v1.InternalRegion_INTERNAL_Antarctica
+)
+
+var regionCodes = map[string]Region{
+	"AS": RegionAsia,
+	"EU": RegionEurope,
+	"NA": RegionNorthAmerica,
+	"SA": RegionSouthAmerica,
+	"OC": RegionOceania,
+	"AF": RegionAfrica,
+	"AN": RegionAntarctica,
+}
+
+// Country 国家元数据
+//
+// 名称、电话前缀、货币、区域、是否启用以系统服务为准，号码规则（国内前缀、号码长度）来自内置数据。
+type Country struct {
+	ID                uint32
+	Code              string // ISO 3166-1 alpha-2
+	Alpha3            string // ISO 3166-1 alpha-3
+	Numeric           string // ISO 3166-1 numeric
+	Name              string
+	Flag              string
+	CallingCode       string // 国际电话区号，不含 +
+	TrunkPrefix       string // 国内长途前缀，如中国为 0、美国为 1
+	Currency          string // ISO 4217 货币代码
+	Region            Region
+	DefaultLanguageID uint32
+	IsDefault         bool
+	IsActive          bool
+	Sort              int32
+	// MinLength/MaxLength 国内有效号码（不含国内前缀）的位数范围
+	MinLength int
+	MaxLength int
+}
+
+// PhonePrefix 返回 +86 形式的电话前缀
+func (c *Country) PhonePrefix() string {
+	if c.CallingCode == "" {
+		return ""
+	}
+	return "+" + c.CallingCode
+}
+
+// Proto 转换为系统服务的国家信息
+func (c *Country) Proto() *v1.InternalCountry {
+	country := &v1.InternalCountry{
+		Id:                c.ID,
+		Code:              c.Code,
+		Name:              c.Name,
+		DefaultLanguageId: c.DefaultLanguageID,
+		IsDefault:         c.IsDefault,
+		Sort:              c.Sort,
+		Region:            c.Region,
+		IsActive:          c.IsActive,
+	}
+	if c.Flag != "" {
+		country.Flag = &c.Flag
+	}
+	if prefix := c.PhonePrefix(); prefix != "" {
+		country.PhonePrefix = &prefix
+	}
+	if c.Currency != "" {
+		country.Currency = &c.Currency
+	}
+	return country
+}
+
+// lengthRange 国内号码位数范围，未配置时只受 E.164 总长度限制
+func (c *Country) lengthRange() (int, int) {
+	minLen, maxLen := c.MinLength, c.MaxLength
+	if minLen <= 0 {
+		minLen = 4
+	}
+	if maxLen <= 0 {
+		maxLen = maxE164Digits - len(c.CallingCode)
+	}
+	return minLen, maxLen
+}
+
+// merge 用系统服务的数据覆盖内置数据
+func (c *Country) merge(remote *v1.InternalCountry) {
+	c.ID = remote.GetId()
+	if remote.GetName() != "" {
+		c.Name = remote.GetName()
+	}
+	if remote.GetFlag() != "" {
+		c.Flag = remote.GetFlag()
+	}
+	if prefix := normalizeCallingCode(remote.GetPhonePrefix()); prefix != "" {
+		c.CallingCode = prefix
+	}
+	if remote.GetCurrency() != "" {
+		c.Currency = strings.ToUpper(remote.GetCurrency())
+	}
+	if remote.GetRegion() != v1.InternalRegion_INTERNAL_REGION_UNSPECIFIED {
+		c.Region = remote.GetRegion()
+	}
+	c.DefaultLanguageID = remote.GetDefaultLanguageId()
+	c.IsDefault = remote.GetIsDefault()
+	c.IsActive = remote.GetIsActive()
+	c.Sort = remote.GetSort()
+}
+
+// fromProto 系统服务返回了内置数据中没有的国家
+func fromProto(remote *v1.InternalCountry) *Country {
+	c := &Country{Code: strings.ToUpper(remote.GetCode()), Flag: flagOf(remote.GetCode())}
+	c.merge(remote)
+	return c
+}
+
+func normalizeCallingCode(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	prefix = strings.TrimPrefix(prefix, "+")
+	prefix = strings.TrimPrefix(prefix, "00")
+	return strings.ReplaceAll(prefix, "-", "")
+}
+
+// flagOf 根据 alpha-2 代码生成国旗 emoji
+func flagOf(code string) string {
+	if len(code) != 2 {
+		return ""
+	}
+	code = strings.ToUpper(code)
+	var b strings.Builder
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return ""
+		}
+		b.WriteRune(0x1F1E6 + r - 'A')
+	}
+	return b.String()
+}
+
+// Defaults 返回内置的国家数据（ISO 3166-1 全部国家和地区），默认均为启用状态
+func Defaults() []*Country {
+	countries, err := parseCSV(countriesCSV)
+	if err != nil {
+		panic(err)
+	}
+	return countries
+}
+
+func parseCSV(data []byte) ([]*Country, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comment = '#'
+	r.FieldsPerRecord = 10
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("country: invalid embedded data: %w", err)
+	}
+
+	countries := make([]*Country, 0, len(records))
+	for i, rec := range records {
+		region, ok := regionCodes[rec[6]]
+		if !ok {
+			return nil, fmt.Errorf("country: invalid region %q of %s", rec[6], rec[0])
+		}
+		minLen, err := strconv.Atoi(rec[7])
+		if err != nil {
+			return nil, fmt.Errorf("country: invalid min length of %s: %w", rec[0], err)
+		}
+		maxLen, err := strconv.Atoi(rec[8])
+		if err != nil {
+			return nil, fmt.Errorf("country: invalid max length of %s: %w", rec[0], err)
+		}
+		countries = append(countries, &Country{
+			Code:        rec[0],
+			Alpha3:      rec[1],
+			Numeric:     rec[2],
+			CallingCode: rec[3],
+			TrunkPrefix: rec[4],
+			Currency:    rec[5],
+			Region:      region,
+			MinLength:   minLen,
+			MaxLength:   maxLen,
+			Name:        rec[9],
+			Flag:        flagOf(rec[0]),
+			IsActive:    true,
+			Sort:        int32(i),
+		})
+	}
+	return countries, nil
+}
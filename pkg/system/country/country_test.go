@@ -0,0 +1,173 @@
+package country
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/heyinLab/common/api/gen/go/system/v1"
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+)
+
+type fakeSource struct {
+	countries []*v1.InternalCountry
+	err       error
+}
+
+func (s *fakeSource) ListCountries(ctx context.Context, req *v1.InternalListCountriesRequest) ([]*v1.InternalCountry, error) {
+	return s.countries, s.err
+}
+
+func TestDefaults(t *testing.T) {
+	countries := Defaults()
+	require.Len(t, countries, 250)
+
+	codes := make(map[string]bool)
+	for _, c := range countries {
+		require.Len(t, c.Code, 2)
+		require.False(t, codes[c.Code], c.Code)
+		codes[c.Code] = true
+		require.NotEmpty(t, c.CallingCode, c.Code)
+		if c.Currency != "" {
+			_, err := MinorUnits(c.Currency)
+			require.NoError(t, err, c.Code)
+		}
+	}
+
+	r := NewRegistry(nil)
+	cn, ok := r.Get("chn")
+	require.True(t, ok)
+	require.Equal(t, "CN", cn.Code)
+	require.Equal(t, "🇨🇳", cn.Flag)
+	require.Equal(t, "+86", cn.PhonePrefix())
+	require.Equal(t, RegionAsia, cn.Region)
+	require.Equal(t, "CNY", cn.Proto().GetCurrency())
+
+	require.Equal(t, "US", r.ByCallingCode("+1")[0].Code)
+	require.Equal(t, "RU", r.ByCallingCode("7")[0].Code)
+	require.NotEmpty(t, r.ByRegion(RegionOceania))
+	require.True(t, r.LoadedAt().IsZero())
+}
+
+func TestRegistry_Load(t *testing.T) {
+	flag := "🇨🇳"
+	prefix, currency := "+86", "cny"
+	source := &fakeSource{countries: []*v1.InternalCountry{
+		{Id: 1, Code: "cn", Name: "中国", Flag: &flag, PhonePrefix: &prefix, Currency: &currency, Region: RegionAsia, IsActive: true, IsDefault: true},
+		{Id: 2, Code: "US", Name: "美国", IsActive: false},
+		{Id: 3, Code: "ZZ", Name: "测试", IsActive: true},
+	}}
+	r := NewRegistry(source)
+	require.NoError(t, r.Load(context.Background()))
+	require.False(t, r.LoadedAt().IsZero())
+
+	cn, _ := r.Get("CN")
+	require.Equal(t, uint32(1), cn.ID)
+	require.Equal(t, "中国", cn.Name)
+	require.Equal(t, "CNY", cn.Currency)
+	// 号码规则仍来自内置数据
+	require.Equal(t, 11, cn.MaxLength)
+
+	us, _ := r.Get("US")
+	require.False(t, us.IsActive)
+	require.Equal(t, "1", us.CallingCode)
+
+	zz, ok := r.Get("ZZ")
+	require.True(t, ok)
+	require.Equal(t, "测试", zz.Name)
+
+	// 系统服务未维护的国家视为未启用
+	jp, _ := r.Get("JP")
+	require.False(t, jp.IsActive)
+	require.Len(t, r.Active(), 2)
+
+	// 拉取失败时保留上一次的数据
+	source.err = errors.New("unavailable")
+	require.Error(t, r.Load(context.Background()))
+	cn, _ = r.Get("CN")
+	require.Equal(t, "中国", cn.Name)
+}
+
+func TestParsePhone(t *testing.T) {
+	r := NewRegistry(nil)
+
+	tests := []struct {
+		raw, country string
+		e164         string
+		code         string
+	}{
+		{"138 0013 8000", "CN", "+8613800138000", "CN"},
+		{"+86 138-0013-8000", "", "+8613800138000", "CN"},
+		{"0086 13800138000", "", "+8613800138000", "CN"},
+		{"010 1234 5678", "CN", "+861012345678", "CN"},
+		{"(202) 555-0123", "US", "+12025550123", "US"},
+		{"1 202 555 0123", "US", "+12025550123", "US"},
+		{"+1 416 555 0123", "", "+14165550123", "US"},
+		{"+1 416 555 0123", "CA", "+14165550123", "CA"},
+		{"+44 (0)20 7946 0958", "", "+442079460958", "GB"},
+		{"020 7946 0958", "GB", "+442079460958", "GB"},
+		{"06 12 34 56 78", "FR", "+33612345678", "FR"},
+		{"+39 06 1234 5678", "", "+390612345678", "IT"},
+		{"+852 9123 4567", "", "+85291234567", "HK"},
+		{"8 (912) 345-67-89", "RU", "+79123456789", "RU"},
+	}
+	for _, tt := range tests {
+		phone, err := r.ParsePhone(tt.raw, tt.country)
+		require.NoError(t, err, tt.raw)
+		require.Equal(t, tt.e164, phone.E164(), tt.raw)
+		require.Equal(t, tt.code, phone.Country, tt.raw)
+	}
+
+	for _, raw := range []string{"", "abc", "+86 138", "13800138000", "+86 1380013800012", "+999 1234567", "+8613800138000x"} {
+		_, err := r.ParsePhone(raw, "")
+		require.Equal(t, businessErrors.ErrInvalidPhone, err, raw)
+	}
+
+	require.NoError(t, r.ValidatePhone("+8613800138000", "CN"))
+	require.Equal(t, businessErrors.ErrInvalidPhone, r.ValidatePhone("+14165550123", "CN"))
+	require.Equal(t, businessErrors.ErrInvalidPhone, r.ValidatePhone("138001380", "CN"))
+
+	e164, err := r.FormatE164("138 0013 8000", "CN")
+	require.NoError(t, err)
+	require.Equal(t, "+8613800138000", e164)
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		locale   string
+		want     string
+	}{
+		{123450, "CNY", "zh-CN", "￥1,234.50"},
+		{123450, "VES", "es-VE", "1.234,50\u00a0VES"},
+		{123450, "USD", "en-US", "$1,234.50"},
+		{123456789, "EUR", "de-DE", "1.234.567,89\u00a0€"},
+		{123450, "EUR", "fr-FR", "1\u00a0234,50\u00a0€"},
+		{123450, "BRL", "pt-BR", "R$1.234,50"},
+		{1234, "JPY", "ja-JP", "￥1,234"},
+		{1234567, "KWD", "en", "KWD\u00a01,234.567"},
+		{-5, "USD", "en-US", "-$0.05"},
+		{123450, "CHF", "en", "CHF\u00a01,234.50"},
+		{100, "USD", "invalid locale", "$1.00"},
+	}
+	for _, tt := range tests {
+		got, err := FormatMoney(tt.amount, tt.currency, tt.locale)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, "%d %s %s", tt.amount, tt.currency, tt.locale)
+	}
+
+	_, err := FormatMoney(100, "XXXX", "en")
+	require.Error(t, err)
+
+	scale, err := MinorUnits("JPY")
+	require.NoError(t, err)
+	require.Equal(t, 0, scale)
+
+	r := NewRegistry(nil)
+	got, err := r.FormatMoney(99900, "CN", "zh-CN")
+	require.NoError(t, err)
+	require.Equal(t, "￥999.00", got)
+}
@@ -0,0 +1,145 @@
+package country
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// suffixSymbolLanguages 货币符号放在金额之后的语言，如 1.234,50 €
+var suffixSymbolLanguages = map[string]bool{
+	"de": true, "fr": true, "es": true, "it": true, "ru": true, "uk": true, "be": true,
+	"pl": true, "cs": true, "sk": true, "sl": true, "hr": true, "sr": true, "bg": true, "ro": true,
+	"hu": true, "sv": true, "fi": true, "nb": true, "no": true, "da": true, "is": true, "et": true,
+	"lv": true, "lt": true, "el": true, "vi": true, "kk": true,
+}
+
+// newerCurrencies golang.org/x/text 尚未收录的 ISO 4217 货币及其小数位数
+var newerCurrencies = map[string]int{
+	"VES": 2, // 委内瑞拉主权玻利瓦尔
+	"SLE": 2, // 塞拉利昂新利昂
+	"MRU": 2, // 毛里塔尼亚新乌吉亚
+	"ZWG": 2, // 津巴布韦金
+}
+
+// lookupCurrency 解析货币代码，x/text 未收录的货币 unit 为 nil
+func lookupCurrency(code string) (*currency.Unit, int, error) {
+	unit, err := currency.ParseISO(code)
+	if err == nil {
+		scale, _ := currency.Standard.Rounding(unit)
+		return &unit, scale, nil
+	}
+	if scale, ok := newerCurrencies[strings.ToUpper(code)]; ok {
+		return nil, scale, nil
+	}
+	return nil, 0, fmt.Errorf("country: unknown currency %q: %w", code, err)
+}
+
+// MinorUnits 返回货币的小数位数（ISO 4217），如 CNY 为 2、JPY 为 0、KWD 为 3
+func MinorUnits(code string) (int, error) {
+	_, scale, err := lookupCurrency(code)
+	return scale, err
+}
+
+// FormatMoney 按 locale 格式化以最小货币单位表示的金额
+//
+// 金额与后置或字母形式的货币符号之间使用不换行空格（U+00A0）。
+// 例如 FormatMoney(123450, "CNY", "zh-CN") 返回 ￥1,234.50，
+// FormatMoney(123450, "EUR", "de-DE") 返回 1.234,50 €，
+// FormatMoney(1234, "JPY", "ja-JP") 返回 ￥1,234。
+func FormatMoney(amount int64, currencyCode, locale string) (string, error) {
+	unit, scale, err := lookupCurrency(currencyCode)
+	if err != nil {
+		return "", err
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		tag = language.English
+	}
+	p := message.NewPrinter(tag)
+
+	negative := amount < 0
+	// 转为无符号避免 math.MinInt64 取负溢出
+	abs := uint64(amount)
+	if negative {
+		abs = -abs
+	}
+	var pow uint64 = 1
+	for range scale {
+		pow *= 10
+	}
+	major, minor := abs/pow, abs%pow
+
+	// 整数部分按 locale 分组，小数部分单独拼接以保证精度
+	text := p.Sprint(number.Decimal(major))
+	if scale > 0 {
+		text += decimalSeparator(p) + fmt.Sprintf("%0*d", scale, minor)
+	}
+
+	symbol := strings.ToUpper(currencyCode)
+	if unit != nil {
+		symbol = p.Sprint(currency.Symbol(*unit))
+	}
+	if isSuffixLocale(tag) {
+		text = text + "\u00a0" + symbol
+	} else if lastRune(symbol) != 0 && unicode.IsLetter(lastRune(symbol)) {
+		// CHF 1,234.50
+		text = symbol + "\u00a0" + text
+	} else {
+		text = symbol + text
+	}
+	if negative {
+		text = "-" + text
+	}
+	return text, nil
+}
+
+// FormatMoney 使用国家的默认货币格式化金额
+func (c *Country) FormatMoney(amount int64, locale string) (string, error) {
+	return FormatMoney(amount, c.Currency, locale)
+}
+
+// FormatMoney 使用 country 的默认货币格式化金额，currencyOrCountry 可以是货币代码或国家代码
+func (r *Registry) FormatMoney(amount int64, currencyOrCountry, locale string) (string, error) {
+	if len(currencyOrCountry) == 2 {
+		if c, ok := r.Get(currencyOrCountry); ok {
+			return c.FormatMoney(amount, locale)
+		}
+	}
+	return FormatMoney(amount, currencyOrCountry, locale)
+}
+
+func decimalSeparator(p *message.Printer) string {
+	s := p.Sprint(number.Decimal(0.5, number.Scale(1)))
+	sep := strings.Trim(s, "0123456789")
+	if sep == "" {
+		return "."
+	}
+	return sep
+}
+
+func isSuffixLocale(tag language.Tag) bool {
+	if suffixSymbolLanguages[tag.String()] {
+		return true
+	}
+	base, _ := tag.Base()
+	if base.String() == "pt" {
+		// 葡萄牙语只有葡萄牙本土使用后置符号
+		region, _ := tag.Region()
+		return region.String() == "PT"
+	}
+	return suffixSymbolLanguages[base.String()]
+}
+
+func lastRune(s string) rune {
+	r := []rune(s)
+	if len(r) == 0 {
+		return 0
+	}
+	return r[len(r)-1]
+}
@@ -0,0 +1,139 @@
+package country
+
+import (
+	"strings"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+)
+
+// PhoneNumber 解析后的电话号码
+type PhoneNumber struct {
+	Country     string // alpha-2 国家代码
+	CallingCode string // 国际区号，不含 +
+	National    string // 国内有效号码，不含国内前缀
+}
+
+// E164 返回 +8613800138000 形式的号码
+func (p PhoneNumber) E164() string {
+	return "+" + p.CallingCode + p.National
+}
+
+// String 同 E164
+func (p PhoneNumber) String() string {
+	return p.E164()
+}
+
+// ParsePhone 解析电话号码
+//
+// 支持 +86 138 0013 8000、0086-13800138000 等国际格式，以及 defaultCountry 的国内格式（会去掉国内前缀）。
+// 多个国家共用国际区号时（如 +1、+7、+44），优先使用 defaultCountry，否则使用主要国家。
+// 号码格式或位数不符合国家规则时返回 ErrInvalidPhone。
+func (r *Registry) ParsePhone(raw, defaultCountry string) (PhoneNumber, error) {
+	digits, international, ok := normalizePhone(raw)
+	if !ok {
+		return PhoneNumber{}, businessErrors.ErrInvalidPhone
+	}
+
+	var def *Country
+	if defaultCountry != "" {
+		def, _ = r.Get(defaultCountry)
+	}
+
+	if !international {
+		if def == nil || def.CallingCode == "" {
+			return PhoneNumber{}, businessErrors.ErrInvalidPhone
+		}
+		national := digits
+		if def.TrunkPrefix != "" && strings.HasPrefix(national, def.TrunkPrefix) {
+			trimmed := national[len(def.TrunkPrefix):]
+			// 去除后位数不足时，开头的数字属于号码本身
+			if minLen, _ := def.lengthRange(); len(trimmed) >= minLen {
+				national = trimmed
+			}
+		}
+		return validate(def, national)
+	}
+
+	// 国际区号为 1~3 位，按最长匹配
+	for n := min(3, len(digits)); n >= 1; n-- {
+		candidates := r.ByCallingCode(digits[:n])
+		if len(candidates) == 0 {
+			continue
+		}
+		c := candidates[0]
+		if def != nil && def.CallingCode == digits[:n] {
+			c = def
+		}
+		national := digits[n:]
+		// 部分地区国际格式中仍会带上国内前缀 0，如 +44 (0)20
+		if c.TrunkPrefix == "0" && strings.HasPrefix(national, "0") {
+			if _, maxLen := c.lengthRange(); len(national) > maxLen {
+				national = national[1:]
+			}
+		}
+		return validate(c, national)
+	}
+	return PhoneNumber{}, businessErrors.ErrInvalidPhone
+}
+
+// ValidatePhone 校验号码是否为 country 的有效号码
+func (r *Registry) ValidatePhone(raw, country string) error {
+	phone, err := r.ParsePhone(raw, country)
+	if err != nil {
+		return err
+	}
+	c, ok := r.Get(country)
+	if !ok || c.Code != phone.Country {
+		return businessErrors.ErrInvalidPhone
+	}
+	return nil
+}
+
+// FormatE164 将号码规范化为 E.164 格式
+func (r *Registry) FormatE164(raw, defaultCountry string) (string, error) {
+	phone, err := r.ParsePhone(raw, defaultCountry)
+	if err != nil {
+		return "", err
+	}
+	return phone.E164(), nil
+}
+
+func validate(c *Country, national string) (PhoneNumber, error) {
+	minLen, maxLen := c.lengthRange()
+	if len(national) < minLen || len(national) > maxLen || len(c.CallingCode)+len(national) > maxE164Digits {
+		return PhoneNumber{}, businessErrors.ErrInvalidPhone
+	}
+	return PhoneNumber{Country: c.Code, CallingCode: c.CallingCode, National: national}, nil
+}
+
+// normalizePhone 去除分隔符，返回纯数字与是否为国际格式
+func normalizePhone(raw string) (digits string, international bool, ok bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", false, false
+	}
+	if strings.HasPrefix(raw, "+") {
+		international = true
+		raw = raw[1:]
+	}
+
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '\u00a0':
+		default:
+			return "", false, false
+		}
+	}
+	digits = b.String()
+	if !international && strings.HasPrefix(digits, "00") {
+		international = true
+		digits = digits[2:]
+	}
+	if digits == "" || len(digits) > maxE164Digits+1 {
+		return "", false, false
+	}
+	return digits, international, true
+}
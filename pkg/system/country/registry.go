@@ -0,0 +1,215 @@
+package country
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	v1 "github.com/heyinLab/common/api/gen/go/system/v1"
+	"github.com/heyinLab/common/pkg/system"
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// DefaultRefreshInterval 默认刷新间隔
+const DefaultRefreshInterval = time.Hour
+
+// Source 国家数据来源，*system.SystemClient 实现了该接口
+type Source interface {
+	ListCountries(ctx context.Context, req *v1.InternalListCountriesRequest) ([]*v1.InternalCountry, error)
+}
+
+var _ Source = (*system.SystemClient)(nil)
+
+// Option Registry 配置项
+type Option func(*Registry)
+
+// WithRefreshInterval 设置 Run 的刷新间隔
+func WithRefreshInterval(d time.Duration) Option {
+	return func(r *Registry) { r.interval = d }
+}
+
+// WithLogger 设置日志
+func WithLogger(logger log.Logger) Option {
+	return func(r *Registry) { r.logger = log.NewHelper(log.With(logger, "module", "country-registry")) }
+}
+
+// WithClock 设置时钟，默认为 clock.Default()
+func WithClock(c clock.Clock) Option {
+	return func(r *Registry) { r.clock = c }
+}
+
+// snapshot 一份只读的国家数据，刷新时整体替换
+type snapshot struct {
+	countries []*Country
+	byCode    map[string]*Country
+	byCalling map[string][]*Country
+	loadedAt  time.Time
+}
+
+func newSnapshot(countries []*Country, loadedAt time.Time) *snapshot {
+	s := &snapshot{
+		countries: countries,
+		byCode:    make(map[string]*Country, len(countries)*2),
+		byCalling: make(map[string][]*Country),
+		loadedAt:  loadedAt,
+	}
+	for _, c := range countries {
+		s.byCode[c.Code] = c
+		if c.Alpha3 != "" {
+			s.byCode[c.Alpha3] = c
+		}
+		if c.CallingCode != "" {
+			s.byCalling[c.CallingCode] = append(s.byCalling[c.CallingCode], c)
+		}
+	}
+	return s
+}
+
+// Registry 国家注册表
+//
+// 创建后即可使用内置数据，Load/Run 从系统服务拉取全部国家覆盖内置数据；
+// 拉取失败时保留上一次的数据。所有查询方法可并发调用。
+//
+// 示例:
+//
+//	registry := country.NewRegistry(systemClient.SystemClient())
+//	go registry.Run(ctx)
+//
+//	phone, err := registry.ParsePhone("138 0013 8000", "CN")
+//	price, err := registry.FormatMoney(order.FinalPrice, order.Currency, "zh-CN")
+type Registry struct {
+	source   Source
+	interval time.Duration
+	logger   *log.Helper
+	clock    clock.Clock
+	data     atomic.Pointer[snapshot]
+}
+
+// NewRegistry 创建国家注册表，source 为 nil 时只使用内置数据
+func NewRegistry(source Source, opts ...Option) *Registry {
+	r := &Registry{source: source, interval: DefaultRefreshInterval}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.logger == nil {
+		r.logger = log.NewHelper(log.With(log.GetLogger(), "module", "country-registry"))
+	}
+	if r.clock == nil {
+		r.clock = clock.Default()
+	}
+	r.data.Store(newSnapshot(Defaults(), time.Time{}))
+	return r
+}
+
+// Load 从系统服务拉取全部国家
+func (r *Registry) Load(ctx context.Context) error {
+	if r.source == nil {
+		return nil
+	}
+	remote, err := r.source.ListCountries(ctx, &v1.InternalListCountriesRequest{})
+	if err != nil {
+		return err
+	}
+
+	byCode := make(map[string]*Country)
+	countries := make([]*Country, 0, len(remote))
+	for _, c := range Defaults() {
+		byCode[c.Code] = c
+	}
+	seen := make(map[string]bool, len(remote))
+	for _, rc := range remote {
+		code := strings.ToUpper(rc.GetCode())
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		c, ok := byCode[code]
+		if ok {
+			c.merge(rc)
+		} else {
+			c = fromProto(rc)
+		}
+		countries = append(countries, c)
+	}
+	// 系统服务未维护的国家保留内置数据，但视为未启用
+	for _, c := range Defaults() {
+		if !seen[c.Code] {
+			c.IsActive = false
+			countries = append(countries, c)
+		}
+	}
+
+	r.data.Store(newSnapshot(countries, r.clock.Now()))
+	return nil
+}
+
+// Run 定期刷新，阻塞直到 ctx 结束
+func (r *Registry) Run(ctx context.Context) error {
+	ticker := r.clock.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Load(ctx); err != nil {
+			r.logger.WithContext(ctx).Errorf("刷新国家数据失败: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// LoadedAt 最近一次从系统服务加载成功的时间，只使用内置数据时为零值
+func (r *Registry) LoadedAt() time.Time {
+	return r.data.Load().loadedAt
+}
+
+// Get 按 alpha-2 或 alpha-3 代码查找国家，不区分大小写
+func (r *Registry) Get(code string) (*Country, bool) {
+	c, ok := r.data.Load().byCode[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// All 返回全部国家（含未启用的）
+func (r *Registry) All() []*Country {
+	return r.data.Load().countries
+}
+
+// Active 返回启用的国家
+func (r *Registry) Active() []*Country {
+	var result []*Country
+	for _, c := range r.data.Load().countries {
+		if c.IsActive {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ByRegion 返回区域内启用的国家
+func (r *Registry) ByRegion(region Region) []*Country {
+	var result []*Country
+	for _, c := range r.data.Load().countries {
+		if c.IsActive && c.Region == region {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ByCallingCode 返回使用该国际区号的国家，第一个为主要国家（如 +1 为美国）
+func (r *Registry) ByCallingCode(callingCode string) []*Country {
+	return r.data.Load().byCalling[normalizeCallingCode(callingCode)]
+}
+
+// Locale 返回国家所属区域与默认语言 ID，未找到国家时 ok 为 false
+func (r *Registry) Locale(code string) (region Region, languageID uint32, ok bool) {
+	c, ok := r.Get(code)
+	if !ok {
+		return v1.InternalRegion_INTERNAL_REGION_UNSPECIFIED, 0, false
+	}
+	return c.Region, c.DefaultLanguageID, true
+}
@@ -1,10 +1,6 @@
 package bank_card
 
-import (
-	"strconv"
-	"strings"
-)
-
+// BANKBIN 内置的银行卡号段，与 BANKNAME 一一对应，数据库未收录的号段由 Engine 合并使用
 var BANKBIN = []string{
 	"621098", "622150", "622151",
 	"622181", "622188", "955100", "621095", "620062", "621285",
@@ -241,6 +237,7 @@ var BANKBIN = []string{
 	"621481", "621310", "621396", "623251", "628351",
 }
 
+// BANKNAME 内置号段对应的 "银行·卡种" 名称
 var BANKNAME = []string{
 	"邮储银行·绿卡通", "邮储银行·绿卡银联标准卡",
 	"邮储银行·绿卡银联标准卡", "邮储银行·绿卡专用卡", "邮储银行·绿卡银联标准卡", "邮储银行·绿卡(银联卡)",
@@ -632,44 +629,21 @@ var BANKNAME = []string{
 	"玉溪市商业银行·红塔卡",
 }
 
-// GetNameOfBank 通过银行卡 的前六位确定 判断银行开户行及卡种
+// GetNameOfBank 通过银行卡号判断开户行及卡种，如 "中国工商银行·预付卡"，未找到时返回空字符串
 // @see https://github.com/AbrahamCaiJin/CommonUtilLibrary/blob/master/CommonUtil/src/main/java/com/jingewenku/abrahamcaijin/commonutil/BankCheck.java
 func GetNameOfBank(cardNo string) string {
-	if len(cardNo) < 6 {
-		return ""
-	}
-
-	// 通过银行卡前6位确定
-	cardBin := cardNo[0:6]
-
-	index := -1
-	for i := 0; i < len(BANKBIN); i++ {
-		if strings.Compare(BANKBIN[i], cardBin) == 0 {
-			index = i
-		}
-	}
-	if index == -1 {
+	bankCard, err := defaultEngine.Lookup(cardNo)
+	if err != nil {
 		return ""
 	}
-
-	return BANKNAME[index]
+	return bankCard.DisplayName()
 }
 
-var defaultDatabase *Database = NewDatabase(false)
-
+// QueryBankByCardNumber 通过银行卡号查询银行卡信息，未找到时返回 nil
 func QueryBankByCardNumber(cardNo string) *BankCard {
-	if len(cardNo) < 6 {
+	bankCard, err := defaultEngine.Lookup(cardNo)
+	if err != nil {
 		return nil
 	}
-
-	for i := 8; i >= 6; i-- {
-		cardBin := cardNo[0:i]
-		bin, _ := strconv.Atoi(cardBin)
-		bank := defaultDatabase.queryBankCard(uint32(bin))
-		if bank != nil {
-			return bank
-		}
-	}
-
-	return nil
+	return bankCard
 }
@@ -0,0 +1,90 @@
+package bank_card
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadBanksCSV 读取银行信息 CSV，每行格式为 bank_code,bank_name，参见 assets/name.csv
+func ReadBanksCSV(r io.Reader) ([]*Bank, error) {
+	var banks []*Bank
+	err := readCSV(r, 2, func(line int, record []string) error {
+		if record[0] == "" || record[1] == "" {
+			return fmt.Errorf("line %d: bank code and name are required", line)
+		}
+		banks = append(banks, &Bank{BankCode: record[0], BankName: record[1]})
+		return nil
+	})
+	return banks, err
+}
+
+// ReadBankCardsCSV 读取银行卡 BIN CSV，参见 assets/bin.csv
+//
+// 每行格式为 bin,bank_code,card_type,card_length[,card_name]，card_type 取 DC、CC、SCC、PC。
+func ReadBankCardsCSV(r io.Reader) ([]*BankCard, error) {
+	var bankCards []*BankCard
+	err := readCSV(r, 4, func(line int, record []string) error {
+		bin, err := strconv.ParseUint(record[0], 10, 64)
+		if err != nil || record[0] == "" {
+			return fmt.Errorf("line %d: invalid bin %q", line, record[0])
+		}
+		if record[1] == "" {
+			return fmt.Errorf("line %d: bank code is required", line)
+		}
+
+		var cardLength uint64
+		if record[3] != "" {
+			if cardLength, err = strconv.ParseUint(record[3], 10, 32); err != nil {
+				return fmt.Errorf("line %d: invalid card length %q", line, record[3])
+			}
+		}
+
+		bankCard := &BankCard{
+			BIN:        bin,
+			BankCode:   record[1],
+			CardType:   record[2],
+			CardLength: uint32(cardLength),
+		}
+		if len(record) > 4 {
+			bankCard.CardName = record[4]
+		}
+		bankCards = append(bankCards, bankCard)
+		return nil
+	})
+	return bankCards, err
+}
+
+// readCSV 逐行读取 CSV，忽略空行，字段去除首尾空白
+func readCSV(r io.Reader, minFields int, fn func(line int, record []string) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		if len(record) == 1 && record[0] == "" {
+			continue
+		}
+		if len(record) < minFields {
+			return fmt.Errorf("line %d: expected at least %d fields, got %d", line, minFields, len(record))
+		}
+		if err = fn(line, record); err != nil {
+			return err
+		}
+	}
+}
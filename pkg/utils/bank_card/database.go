@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"log"
 
 	"github.com/mattn/go-sqlite3"
@@ -17,6 +16,7 @@ type Database struct {
 	db *sql.DB
 }
 
+// NewDatabase 打开数据库，openFile 为 true 时打开 assets/bank_card.db 文件，否则使用内嵌数据库
 func NewDatabase(openFile bool) *Database {
 	db := &Database{}
 
@@ -29,6 +29,31 @@ func NewDatabase(openFile bool) *Database {
 	return db
 }
 
+// OpenDatabase 打开指定路径的数据库文件，文件不存在时创建空表
+func OpenDatabase(path string) (*Database, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	d := &Database{db: db}
+	d.initTables()
+	return d, nil
+}
+
+// OpenEmbeddedDatabase 打开内嵌的只读数据库
+func OpenEmbeddedDatabase() (*Database, error) {
+	d := &Database{}
+	if err := d.openFromEmbed(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
 // openFromFile 从文件打开数据库
 func (d *Database) openFromFile() {
 	db, err := sql.Open("sqlite3", "assets/bank_card.db")
@@ -45,13 +70,14 @@ func (d *Database) openFromFile() {
 func (d *Database) openFromEmbed() error {
 	db, err := sql.Open("sqlite3", "file::memory:?mode=ro&cache=shared")
 	if err != nil {
-		panic("failed to connect database")
 		return err
 	}
+	// 内存数据库随连接关闭而释放，只保留一个连接
+	db.SetMaxOpenConns(1)
 
 	conn, err := db.Conn(context.Background())
 	if err != nil {
-		log.Fatal(err)
+		_ = db.Close()
 		return err
 	}
 	defer conn.Close()
@@ -59,7 +85,7 @@ func (d *Database) openFromEmbed() error {
 	if err = conn.Raw(func(raw interface{}) error {
 		return raw.(*sqlite3.SQLiteConn).Deserialize(assets.BankCardDatabase, "")
 	}); err != nil {
-		log.Fatal(err)
+		_ = db.Close()
 		return err
 	}
 	conn.Close()
@@ -101,34 +127,90 @@ CREATE TABLE IF NOT EXISTS banks
 }
 
 func (d *Database) insertDataToBankTable(data *Bank) {
-	strSql := fmt.Sprintf("INSERT INTO banks (bank_code, bank_name) VALUES ('%s', '%s');", data.BankCode, data.BankName)
-	_, err := d.db.Exec(strSql)
+	_, err := d.db.Exec("INSERT INTO banks (bank_code, bank_name) VALUES (?, ?);", data.BankCode, data.BankName)
 	if err != nil {
 		log.Println(err)
 	}
 }
 
 func (d *Database) insertDataToBankCardTable(data *BankCard) {
-	strSql := fmt.Sprintf("INSERT INTO bank_cards (bin, bank_code, card_name, card_type, card_length) VALUES (%d, '%s', '%s', '%s', %d);",
+	_, err := d.db.Exec("INSERT INTO bank_cards (bin, bank_code, card_name, card_type, card_length) VALUES (?, ?, ?, ?, ?);",
 		data.BIN, data.BankCode, data.CardName, data.CardType, data.CardLength)
-	_, err := d.db.Exec(strSql)
 	if err != nil {
 		log.Println(err)
 	}
 }
 
-func (d *Database) UpdateBankCardTableCardName(bin uint32, cardName string) {
-	strSql := fmt.Sprintf("UPDATE bank_cards SET card_name = '%s' WHERE bin = '%d';", cardName, bin)
-	_, err := d.db.Exec(strSql)
+func (d *Database) UpdateBankCardTableCardName(bin uint64, cardName string) {
+	_, err := d.db.Exec("UPDATE bank_cards SET card_name = ? WHERE bin = ?;", cardName, bin)
 	if err != nil {
 		log.Println(err)
 	}
 }
 
+// upsertBank 新增或更新银行信息
+func (d *Database) upsertBank(data *Bank) error {
+	res, err := d.db.Exec("UPDATE banks SET bank_name = ? WHERE bank_code = ?;", data.BankName, data.BankCode)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+	_, err = d.db.Exec("INSERT INTO banks (bank_code, bank_name) VALUES (?, ?);", data.BankCode, data.BankName)
+	return err
+}
+
+// upsertBankCard 新增或更新银行卡信息
+func (d *Database) upsertBankCard(data *BankCard) error {
+	_, err := d.db.Exec(`INSERT INTO bank_cards (bin, bank_code, card_name, card_type, card_length) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(bin) DO UPDATE SET bank_code = excluded.bank_code, card_name = excluded.card_name,
+card_type = excluded.card_type, card_length = excluded.card_length;`,
+		data.BIN, data.BankCode, data.CardName, data.CardType, data.CardLength)
+	return err
+}
+
+// queryBanks 查询全部银行信息
+func (d *Database) queryBanks() ([]*Bank, error) {
+	rows, err := d.db.Query("SELECT id, COALESCE(bank_code, ''), COALESCE(bank_name, '') FROM banks ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banks []*Bank
+	for rows.Next() {
+		var bank Bank
+		if err = rows.Scan(&bank.Id, &bank.BankCode, &bank.BankName); err != nil {
+			return nil, err
+		}
+		banks = append(banks, &bank)
+	}
+	return banks, rows.Err()
+}
+
+// queryBankCards 查询全部银行卡信息
+func (d *Database) queryBankCards() ([]*BankCard, error) {
+	rows, err := d.db.Query("SELECT bin, COALESCE(bank_code, ''), COALESCE(card_name, ''), COALESCE(card_type, ''), COALESCE(card_length, 0) FROM bank_cards;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bankCards []*BankCard
+	for rows.Next() {
+		var bankCard BankCard
+		if err = rows.Scan(&bankCard.BIN, &bankCard.BankCode, &bankCard.CardName, &bankCard.CardType, &bankCard.CardLength); err != nil {
+			return nil, err
+		}
+		bankCards = append(bankCards, &bankCard)
+	}
+	return bankCards, rows.Err()
+}
+
 // queryBank 查询银行信息
 func (d *Database) queryBank(bankCode string) *Bank {
-	strSql := fmt.Sprintf("SELECT id, bank_code, bank_name FROM banks WHERE bank_code = '%s' LIMIT 1;", bankCode)
-	row := d.db.QueryRow(strSql)
+	row := d.db.QueryRow("SELECT id, bank_code, bank_name FROM banks WHERE bank_code = ? LIMIT 1;", bankCode)
 	if row == nil {
 		return nil
 	}
@@ -138,7 +220,7 @@ func (d *Database) queryBank(bankCode string) *Bank {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
 		}
-		log.Fatal("scan bank failed:", err)
+		log.Println("scan bank failed:", err)
 		return nil
 	}
 
@@ -148,9 +230,8 @@ func (d *Database) queryBank(bankCode string) *Bank {
 }
 
 // queryBankCard 查询银行卡信息
-func (d *Database) queryBankCard(bin uint32) *BankCard {
-	strSql := fmt.Sprintf("SELECT bin, bank_code, card_name, card_type, card_length FROM bank_cards WHERE bin = '%d' LIMIT 1;", bin)
-	row := d.db.QueryRow(strSql)
+func (d *Database) queryBankCard(bin uint64) *BankCard {
+	row := d.db.QueryRow("SELECT bin, COALESCE(bank_code, ''), COALESCE(card_name, ''), COALESCE(card_type, ''), COALESCE(card_length, 0) FROM bank_cards WHERE bin = ? LIMIT 1;", bin)
 	if row == nil {
 		return nil
 	}
@@ -160,7 +241,7 @@ func (d *Database) queryBankCard(bin uint32) *BankCard {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
 		}
-		log.Fatal("scan bank card failed:", err)
+		log.Println("scan bank card failed:", err)
 		return nil
 	}
 
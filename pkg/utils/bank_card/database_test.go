@@ -1,130 +1,85 @@
 package bank_card
 
 import (
-	"encoding/csv"
 	"fmt"
-	"github.com/stretchr/testify/assert"
-	"io"
-	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
-)
 
-func TestImportBankName(t *testing.T) {
-	db := NewDatabase(true)
-	defer db.Close()
+	"github.com/stretchr/testify/assert"
 
-	//db.openFromFile()
+	"github.com/heyinLab/common/pkg/utils/bank_card/assets"
+)
 
-	file, err := os.Open("assets/name.csv")
-	if err != nil {
-		fmt.Println("Read file err, err =", err)
-		return
-	}
-	defer file.Close()
+// newTestDatabase 复制内嵌数据库到临时目录，避免测试修改 assets/bank_card.db
+func newTestDatabase(t *testing.T) (*Database, string) {
+	path := filepath.Join(t.TempDir(), "bank_card.db")
+	assert.Nil(t, os.WriteFile(path, assets.BankCardDatabase, 0o600))
 
-	fileinfo, err := file.Stat()
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
+	db, err := OpenDatabase(path)
+	assert.Nil(t, err)
+	return db, path
+}
 
-	filesize := fileinfo.Size()
-	buffer := make([]byte, filesize)
+func TestImportBankName(t *testing.T) {
+	db, path := newTestDatabase(t)
+	db.Close()
 
-	_, err = file.Read(buffer)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
+	engine := NewEngine(WithDatabaseFile(path))
+	defer engine.Close()
 
-	r := csv.NewReader(strings.NewReader(string(buffer)))
+	file, err := os.Open("assets/name.csv")
+	assert.Nil(t, err)
+	defer file.Close()
 
-	for {
-		record, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Fatal(err)
-		}
+	count, err := engine.ImportBanksCSV(file)
+	assert.Nil(t, err)
+	assert.True(t, count > 0)
 
-		if len(record) == 2 && len(record[0]) > 0 && len(record[1]) > 0 {
-			db.insertDataToBankTable(&Bank{BankCode: record[0], BankName: record[1]})
-		}
-
-		fmt.Println(record)
-	}
+	bank, err := engine.Bank("ABC")
+	assert.Nil(t, err)
+	assert.Equal(t, "中国农业银行", bank.BankName)
 }
 
 func TestImportBankCard(t *testing.T) {
-	db := NewDatabase(true)
-	defer db.Close()
-
-	//db.openFromFile()
+	db, path := newTestDatabase(t)
+	db.Close()
 
+	engine := NewEngine(WithDatabaseFile(path))
 	file, err := os.Open("assets/bin.csv")
-	if err != nil {
-		fmt.Println("Read file err, err =", err)
-		return
-	}
+	assert.Nil(t, err)
 	defer file.Close()
 
-	fileInfo, err := file.Stat()
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-
-	filesize := fileInfo.Size()
-	buffer := make([]byte, filesize)
-
-	_, err = file.Read(buffer)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-
-	r := csv.NewReader(strings.NewReader(string(buffer)))
-
-	for {
-		record, err := r.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Fatal(err)
-		}
+	count, err := engine.ImportBankCardsCSV(file)
+	assert.Nil(t, err)
+	assert.True(t, count > 0)
+	engine.Close()
 
-		if len(record) == 4 && len(record[0]) > 0 && len(record[1]) > 0 && len(record[2]) > 0 && len(record[3]) > 0 {
-			bin, _ := strconv.Atoi(record[0])
-			cardLength, _ := strconv.Atoi(record[3])
-			db.insertDataToBankCardTable(&BankCard{
-				BIN:        uint32(bin),
-				BankCode:   record[1],
-				CardType:   record[2],
-				CardLength: uint32(cardLength),
-			})
-		}
+	// 重新打开文件，导入的数据已持久化且保留原有卡名
+	db, err = OpenDatabase(path)
+	assert.Nil(t, err)
+	defer db.Close()
 
-		fmt.Println(record)
-	}
+	bankCard := db.queryBankCard(620114)
+	assert.NotNil(t, bankCard)
+	assert.Equal(t, "ICBC", bankCard.BankCode)
+	assert.Equal(t, "预付卡", bankCard.CardName)
 }
 
 func TestImportBankCardSingle(t *testing.T) {
-	db := NewDatabase(true)
+	db, _ := newTestDatabase(t)
 	defer db.Close()
 
 	//db.openFromFile()
 
 	binStr := "620114|620187|620046"
 	strs := strings.Split(binStr, "|")
-	var bins []uint32
+	var bins []uint64
 	for _, str := range strs {
 		bin, _ := strconv.Atoi(str)
-		bins = append(bins, uint32(bin))
+		bins = append(bins, uint64(bin))
 	}
 
 	bankCode := "ABC"
@@ -143,7 +98,7 @@ func TestImportBankCardSingle(t *testing.T) {
 }
 
 func TestImportBankCardName(t *testing.T) {
-	db := NewDatabase(true)
+	db, _ := newTestDatabase(t)
 	defer db.Close()
 
 	//db.openFromFile()
@@ -2932,7 +2887,7 @@ func TestImportBankCardName(t *testing.T) {
 		bin, _ := strconv.Atoi(k)
 		strs := strings.Split(v, "-")
 		if len(strs) == 3 {
-			db.UpdateBankCardTableCardName(uint32(bin), strs[1])
+			db.UpdateBankCardTableCardName(uint64(bin), strs[1])
 		}
 	}
 }
@@ -0,0 +1,306 @@
+package bank_card
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrBINNotFound 未找到卡号对应的 BIN
+	ErrBINNotFound = errors.New("bank card bin not found")
+	// ErrBankNotFound 未找到银行
+	ErrBankNotFound = errors.New("bank not found")
+)
+
+// Engine BIN 查询引擎
+//
+// 首次查询时才加载数据：默认读取内嵌数据库，并合并 BANKBIN/BANKNAME 中数据库未收录的号段，
+// 之后的查询全部在内存中按最长前缀匹配完成，可并发使用。
+// 通过 WithDatabaseFile 打开数据库文件时，Upsert 与 Import 的数据会同时写入文件，
+// 否则只更新内存，进程重启后失效。
+type Engine struct {
+	path string
+
+	once    sync.Once
+	loadErr error
+
+	mu     sync.RWMutex
+	db     *Database
+	banks  map[string]*Bank
+	cards  map[string]*BankCard
+	minLen int
+	maxLen int
+}
+
+// EngineOption 引擎配置项
+type EngineOption func(*Engine)
+
+// WithDatabaseFile 使用数据库文件代替内嵌数据库，写入的数据会持久化到该文件
+func WithDatabaseFile(path string) EngineOption {
+	return func(e *Engine) { e.path = path }
+}
+
+// NewEngine 创建 BIN 查询引擎，数据在首次使用时加载
+func NewEngine(opts ...EngineOption) *Engine {
+	e := &Engine{}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+var defaultEngine = NewEngine()
+
+// DefaultEngine 返回使用内嵌数据库的默认引擎
+func DefaultEngine() *Engine {
+	return defaultEngine
+}
+
+// load 加载数据库与内置号段
+func (e *Engine) load() error {
+	e.once.Do(func() {
+		var db *Database
+		if e.path != "" {
+			db, e.loadErr = OpenDatabase(e.path)
+		} else {
+			db, e.loadErr = OpenEmbeddedDatabase()
+		}
+		if e.loadErr != nil {
+			return
+		}
+
+		banks, err := db.queryBanks()
+		if err != nil {
+			db.Close()
+			e.loadErr = err
+			return
+		}
+		bankCards, err := db.queryBankCards()
+		if err != nil {
+			db.Close()
+			e.loadErr = err
+			return
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		e.banks = make(map[string]*Bank, len(banks))
+		e.cards = make(map[string]*BankCard, len(bankCards)+len(BANKBIN))
+		for _, bank := range banks {
+			e.banks[bank.BankCode] = bank
+		}
+		for _, bankCard := range bankCards {
+			e.putLocked(bankCard)
+		}
+		e.mergeLegacyLocked()
+
+		if e.path != "" {
+			e.db = db
+		} else {
+			// 数据已全部加载到内存，内嵌数据库不再需要
+			db.Close()
+		}
+	})
+	return e.loadErr
+}
+
+// mergeLegacyLocked 合并 BANKBIN/BANKNAME 中数据库未收录的号段
+func (e *Engine) mergeLegacyLocked() {
+	for i := 0; i < len(BANKBIN) && i < len(BANKNAME); i++ {
+		if _, ok := e.cards[BANKBIN[i]]; ok {
+			continue
+		}
+		bin, err := strconv.ParseUint(BANKBIN[i], 10, 64)
+		if err != nil {
+			continue
+		}
+		bankName, cardName, _ := strings.Cut(BANKNAME[i], "·")
+		e.cards[BANKBIN[i]] = &BankCard{BIN: bin, BankName: bankName, CardName: cardName}
+		e.updateLengthLocked(len(BANKBIN[i]))
+	}
+}
+
+// putLocked 写入内存索引
+func (e *Engine) putLocked(bankCard *BankCard) {
+	key := strconv.FormatUint(bankCard.BIN, 10)
+	e.cards[key] = bankCard
+	e.updateLengthLocked(len(key))
+}
+
+func (e *Engine) updateLengthLocked(length int) {
+	if e.minLen == 0 || length < e.minLen {
+		e.minLen = length
+	}
+	if length > e.maxLen {
+		e.maxLen = length
+	}
+}
+
+// Lookup 按卡号查询发卡行与卡种
+//
+// 按最长前缀匹配 BIN，存在多个匹配时优先选择卡号长度一致的号段。
+// 卡号可以只包含前若干位（至少 6 位），返回结果为副本，可以随意修改。
+func (e *Engine) Lookup(cardNo string) (*BankCard, error) {
+	cardNo = Normalize(cardNo)
+	if len(cardNo) < 6 {
+		return nil, ErrInvalidLength
+	}
+	if !isNumberString(cardNo) {
+		return nil, ErrInvalidCardNo
+	}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var found *BankCard
+	for i := min(e.maxLen, len(cardNo)); i >= e.minLen; i-- {
+		bankCard, ok := e.cards[cardNo[:i]]
+		if !ok {
+			continue
+		}
+		if bankCard.CardLength == 0 || int(bankCard.CardLength) == len(cardNo) {
+			found = bankCard
+			break
+		}
+		if found == nil {
+			found = bankCard
+		}
+	}
+	if found == nil {
+		return nil, ErrBINNotFound
+	}
+
+	result := *found
+	if bank, ok := e.banks[result.BankCode]; ok && result.BankCode != "" {
+		result.BankName = bank.BankName
+	}
+	result.Network = DetectNetwork(cardNo)
+	return &result, nil
+}
+
+// Bank 按银行代码查询银行
+func (e *Engine) Bank(bankCode string) (*Bank, error) {
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	bank, ok := e.banks[bankCode]
+	if !ok {
+		return nil, ErrBankNotFound
+	}
+	result := *bank
+	return &result, nil
+}
+
+// Len 已加载的 BIN 数量
+func (e *Engine) Len() int {
+	if err := e.load(); err != nil {
+		return 0
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return len(e.cards)
+}
+
+// UpsertBank 新增或更新银行
+func (e *Engine) UpsertBank(bank *Bank) error {
+	if err := e.load(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.db != nil {
+		if err := e.db.upsertBank(bank); err != nil {
+			return err
+		}
+	}
+	copied := *bank
+	e.banks[bank.BankCode] = &copied
+	return nil
+}
+
+// UpsertBankCard 新增或更新 BIN
+func (e *Engine) UpsertBankCard(bankCard *BankCard) error {
+	if err := e.load(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.db != nil {
+		if err := e.db.upsertBankCard(bankCard); err != nil {
+			return err
+		}
+	}
+	copied := *bankCard
+	e.putLocked(&copied)
+	return nil
+}
+
+// ImportBanksCSV 从 CSV 导入银行，返回导入数量，格式参见 ReadBanksCSV
+func (e *Engine) ImportBanksCSV(r io.Reader) (int, error) {
+	banks, err := ReadBanksCSV(r)
+	if err != nil {
+		return 0, err
+	}
+	for i, bank := range banks {
+		if err = e.UpsertBank(bank); err != nil {
+			return i, err
+		}
+	}
+	return len(banks), nil
+}
+
+// ImportBankCardsCSV 从 CSV 导入 BIN，返回导入数量，格式参见 ReadBankCardsCSV
+//
+// 已存在的 BIN 会被覆盖，CSV 中未填写卡名时保留原有卡名。
+func (e *Engine) ImportBankCardsCSV(r io.Reader) (int, error) {
+	bankCards, err := ReadBankCardsCSV(r)
+	if err != nil {
+		return 0, err
+	}
+	for i, bankCard := range bankCards {
+		if bankCard.CardName == "" {
+			if existing := e.get(bankCard.BIN); existing != nil {
+				bankCard.CardName = existing.CardName
+			}
+		}
+		if err = e.UpsertBankCard(bankCard); err != nil {
+			return i, err
+		}
+	}
+	return len(bankCards), nil
+}
+
+func (e *Engine) get(bin uint64) *BankCard {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.cards[strconv.FormatUint(bin, 10)]
+}
+
+// Close 关闭数据库文件
+func (e *Engine) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.db != nil {
+		e.db.Close()
+		e.db = nil
+	}
+}
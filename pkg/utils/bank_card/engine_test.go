@@ -0,0 +1,74 @@
+package bank_card
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEngineLookup(t *testing.T) {
+	engine := NewEngine()
+	defer engine.Close()
+
+	bankCard, err := engine.Lookup("6201140000000000000")
+	assert.Nil(t, err)
+	assert.Equal(t, "ICBC", bankCard.BankCode)
+	assert.Equal(t, "中国工商银行", bankCard.BankName)
+	assert.Equal(t, NetworkUnionPay, bankCard.Network)
+	assert.Equal(t, "中国工商银行·预付卡", bankCard.DisplayName())
+
+	// 超过 uint32 范围的 10 位 BIN
+	bankCard, err = engine.Lookup("6229756114000000000")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(6229756114), bankCard.BIN)
+	assert.Equal(t, "QDRCB", bankCard.BankCode)
+
+	// 数据库未收录、仅在 BANKBIN 中的号段
+	bankCard, err = engine.Lookup("621661280000447287")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, bankCard.DisplayName())
+
+	_, err = engine.Lookup("7777770000000000")
+	assert.ErrorIs(t, err, ErrBINNotFound)
+	_, err = engine.Lookup("62220")
+	assert.ErrorIs(t, err, ErrInvalidLength)
+	_, err = engine.Lookup("6222ab")
+	assert.ErrorIs(t, err, ErrInvalidCardNo)
+
+	// 返回副本，修改不影响引擎
+	bankCard, _ = engine.Lookup("6201140000000000000")
+	bankCard.BankName = "changed"
+	bankCard, _ = engine.Lookup("6201140000000000000")
+	assert.Equal(t, "中国工商银行", bankCard.BankName)
+}
+
+func TestEngineImport(t *testing.T) {
+	engine := NewEngine()
+	defer engine.Close()
+
+	count, err := engine.ImportBanksCSV(strings.NewReader("TEST,测试银行\n\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = engine.ImportBankCardsCSV(strings.NewReader("99999901,TEST,CC,16,测试信用卡\n999999,TEST,DC,19\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	// 最长前缀优先
+	bankCard, err := engine.Lookup("9999990100000000")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(99999901), bankCard.BIN)
+	assert.Equal(t, "测试银行·测试信用卡", bankCard.DisplayName())
+	assert.Equal(t, "信用卡", bankCard.CardTypeName())
+
+	// 长度不一致时选择长度匹配的较短号段
+	bankCard, err = engine.Lookup("9999990100000000000")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(999999), bankCard.BIN)
+
+	_, err = engine.ImportBankCardsCSV(strings.NewReader("abc,TEST,DC,19\n"))
+	assert.NotNil(t, err)
+	_, err = engine.ImportBanksCSV(strings.NewReader("TEST\n"))
+	assert.NotNil(t, err)
+}
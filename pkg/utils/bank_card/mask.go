@@ -0,0 +1,139 @@
+package bank_card
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// MaskChar 脱敏字符
+const MaskChar = '*'
+
+// Mask 按 PCI DSS 规则脱敏卡号，最多保留前 6 位与后 4 位
+//
+// 示例: Mask("6222081106004039591") => "622208*********9591"
+//
+// 卡号不足 13 位时只保留后 4 位，不足 5 位时全部脱敏。
+func Mask(cardNo string) string {
+	cardNo = Normalize(cardNo)
+	length := len(cardNo)
+
+	head, tail := 6, 4
+	if length < 13 {
+		head = 0
+	}
+	if length <= tail {
+		tail = 0
+	}
+
+	var sb strings.Builder
+	sb.Grow(length)
+	sb.WriteString(cardNo[:head])
+	for i := head; i < length-tail; i++ {
+		sb.WriteByte(MaskChar)
+	}
+	sb.WriteString(cardNo[length-tail:])
+	return sb.String()
+}
+
+// Last4 返回卡号后 4 位，常用于展示“尾号”
+func Last4(cardNo string) string {
+	cardNo = Normalize(cardNo)
+	if len(cardNo) <= 4 {
+		return cardNo
+	}
+	return cardNo[len(cardNo)-4:]
+}
+
+// Format 按卡组织习惯分组展示卡号，美国运通为 4-6-5，其他每 4 位一组
+//
+// 可与 Mask 组合使用: Format(Mask(cardNo)) => "6222 08** **** ***9 591"
+func Format(cardNo string) string {
+	cardNo = Normalize(cardNo)
+
+	groups := []int{4}
+	if len(cardNo) == 15 && DetectNetwork(cardNo) == NetworkAmex {
+		groups = []int{4, 6, 5}
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(cardNo) + len(cardNo)/4)
+	for i, g := 0, 0; i < len(cardNo); g++ {
+		size := groups[len(groups)-1]
+		if g < len(groups) {
+			size = groups[g]
+		}
+		end := i + size
+		if end > len(cardNo) {
+			end = len(cardNo)
+		}
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(cardNo[i:end])
+		i = end
+	}
+	return sb.String()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// ErrEmptyTokenKey 令牌密钥为空
+var ErrEmptyTokenKey = errors.New("token key is empty")
+
+// Tokenizer 卡号令牌化，用于在不保存明文卡号的情况下去重、关联与展示
+//
+// 相同密钥下同一卡号的令牌与指纹固定不变，密钥需妥善保管并与数据分开存储。
+type Tokenizer struct {
+	key []byte
+}
+
+// NewTokenizer 创建令牌化器
+func NewTokenizer(key []byte) (*Tokenizer, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyTokenKey
+	}
+	return &Tokenizer{key: append([]byte(nil), key...)}, nil
+}
+
+// Fingerprint 卡号指纹（HMAC-SHA256 十六进制），用于判断两张卡是否相同
+func (t *Tokenizer) Fingerprint(cardNo string) string {
+	return hex.EncodeToString(t.sum(Normalize(cardNo)))
+}
+
+// Token 生成保留格式的卡号令牌
+//
+// 令牌与卡号等长，保留前 6 位与后 4 位，中间位由 HMAC 派生，
+// 并保证令牌不能通过 Luhn 校验，避免被误当作真实卡号使用。
+func (t *Tokenizer) Token(cardNo string) (string, error) {
+	cardNo = Normalize(cardNo)
+	if cardNo == "" {
+		return "", ErrEmptyCardNo
+	}
+	if !isNumberString(cardNo) {
+		return "", ErrInvalidCardNo
+	}
+	if len(cardNo) < 13 || len(cardNo) > 19 {
+		return "", ErrInvalidLength
+	}
+
+	sum := t.sum(cardNo)
+	token := []byte(cardNo)
+	last := len(token) - 5
+	for i := 6; i <= last; i++ {
+		token[i] = '0' + sum[i]%10
+	}
+	if IsValidLuhn(string(token)) {
+		// 改变任一位数字都会使 Luhn 校验失败
+		token[last] = '0' + (token[last]-'0'+1)%10
+	}
+	return string(token), nil
+}
+
+func (t *Tokenizer) sum(cardNo string) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(cardNo))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,55 @@
+package bank_card
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "622208*********9591", Mask("6222081106004039591"))
+	assert.Equal(t, "411111******1111", Mask("4111-1111-1111-1111"))
+	assert.Equal(t, "********9013", Mask("123456789013"))
+	assert.Equal(t, "****", Mask("1234"))
+	assert.Equal(t, "", Mask(""))
+
+	assert.Equal(t, "9591", Last4("6222081106004039591"))
+	assert.Equal(t, "6222 08** **** ***9 591", Format(Mask("6222081106004039591")))
+	assert.Equal(t, "3782 822463 10005", Format("378282246310005"))
+}
+
+func TestTokenizer(t *testing.T) {
+	_, err := NewTokenizer(nil)
+	assert.ErrorIs(t, err, ErrEmptyTokenKey)
+
+	tokenizer, err := NewTokenizer([]byte("secret"))
+	assert.Nil(t, err)
+
+	var cards = []string{
+		"6222081106004039591",
+		"4111111111111111",
+		"378282246310005",
+		"6228480402564890018",
+	}
+	for _, cardNo := range cards {
+		t.Run("tokenize: "+cardNo, func(t *testing.T) {
+			token, err := tokenizer.Token(cardNo)
+			assert.Nil(t, err)
+			assert.Len(t, token, len(cardNo))
+			assert.Equal(t, cardNo[:6], token[:6])
+			assert.Equal(t, cardNo[len(cardNo)-4:], token[len(token)-4:])
+			assert.NotEqual(t, cardNo, token)
+			assert.False(t, IsValidLuhn(token))
+
+			again, _ := tokenizer.Token(cardNo)
+			assert.Equal(t, token, again)
+		})
+	}
+
+	other, _ := NewTokenizer([]byte("other"))
+	assert.Equal(t, tokenizer.Fingerprint("4111 1111 1111 1111"), tokenizer.Fingerprint("4111111111111111"))
+	assert.NotEqual(t, tokenizer.Fingerprint("4111111111111111"), other.Fingerprint("4111111111111111"))
+
+	_, err = tokenizer.Token("411111")
+	assert.ErrorIs(t, err, ErrInvalidLength)
+}
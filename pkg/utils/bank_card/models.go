@@ -18,12 +18,24 @@ type Bank struct {
 
 // BankCard 银行卡信息
 type BankCard struct {
-	BIN        uint32 `gorm:"primarykey,column:bin"` // 银行识别码
-	BankCode   string `gorm:"column:bank_code"`      // 银行代码
-	BankName   string // 银行名称
-	CardType   string `gorm:"column:card_type"`   // 银行卡类型
-	CardName   string `gorm:"column:card_name"`   // 银行卡名称
-	CardLength uint32 `gorm:"column:card_length"` // 银行卡号长度
+	BIN        uint64  `gorm:"primarykey,column:bin"` // 银行识别码
+	BankCode   string  `gorm:"column:bank_code"`      // 银行代码
+	BankName   string  // 银行名称
+	CardType   string  `gorm:"column:card_type"`   // 银行卡类型
+	CardName   string  `gorm:"column:card_name"`   // 银行卡名称
+	CardLength uint32  `gorm:"column:card_length"` // 银行卡号长度
+	Network    Network `gorm:"-"`                  // 卡组织
+}
+
+// DisplayName 展示名称，如 "中国工商银行·预付卡"
+func (b *BankCard) DisplayName() string {
+	if b.CardName == "" {
+		return b.BankName
+	}
+	if b.BankName == "" {
+		return b.CardName
+	}
+	return b.BankName + "·" + b.CardName
 }
 
 // CardTypeName 将卡类型转为类型名
@@ -0,0 +1,101 @@
+package bank_card
+
+// Network 卡组织
+type Network string
+
+const (
+	NetworkUnknown    Network = ""
+	NetworkUnionPay   Network = "UnionPay"   // 中国银联
+	NetworkVisa       Network = "Visa"       // 维萨
+	NetworkMastercard Network = "Mastercard" // 万事达
+	NetworkJCB        Network = "JCB"        // JCB
+	NetworkAmex       Network = "Amex"       // 美国运通
+)
+
+// Name 卡组织中文名
+func (n Network) Name() string {
+	switch n {
+	case NetworkUnionPay:
+		return "银联"
+	case NetworkVisa:
+		return "维萨"
+	case NetworkMastercard:
+		return "万事达"
+	case NetworkJCB:
+		return "JCB"
+	case NetworkAmex:
+		return "美国运通"
+	}
+	return ""
+}
+
+// ValidLength 卡号长度是否符合卡组织规则，未知卡组织按 12~19 位校验
+func (n Network) ValidLength(length int) bool {
+	for _, rule := range networkRules {
+		if rule.network != n {
+			continue
+		}
+		return length >= rule.minLength && length <= rule.maxLength &&
+			(rule.lengths == nil || containsInt(rule.lengths, length))
+	}
+	return length >= 12 && length <= 19
+}
+
+// networkRule 卡组织号段规则，prefix 取卡号前 digits 位，落在 [low, high] 区间内即匹配
+type networkRule struct {
+	network   Network
+	digits    int
+	low       int
+	high      int
+	minLength int
+	maxLength int
+	lengths   []int // 非连续的合法长度，为空时取 [minLength, maxLength]
+}
+
+// networkRules 按号段从长到短排列，先匹配更精确的号段
+// @see https://en.wikipedia.org/wiki/Payment_card_number#Issuer_identification_number_(IIN)
+var networkRules = []networkRule{
+	{network: NetworkMastercard, digits: 4, low: 2221, high: 2720, minLength: 16, maxLength: 16},
+	{network: NetworkJCB, digits: 4, low: 3528, high: 3589, minLength: 16, maxLength: 19},
+	{network: NetworkAmex, digits: 2, low: 34, high: 34, minLength: 15, maxLength: 15},
+	{network: NetworkAmex, digits: 2, low: 37, high: 37, minLength: 15, maxLength: 15},
+	{network: NetworkMastercard, digits: 2, low: 51, high: 55, minLength: 16, maxLength: 16},
+	{network: NetworkUnionPay, digits: 2, low: 62, high: 62, minLength: 16, maxLength: 19},
+	{network: NetworkVisa, digits: 1, low: 4, high: 4, minLength: 13, maxLength: 19, lengths: []int{13, 16, 19}},
+}
+
+// DetectNetwork 根据卡号（或 BIN）的号段识别卡组织，不校验长度与 Luhn
+func DetectNetwork(cardNo string) Network {
+	cardNo = Normalize(cardNo)
+	if !isNumberString(cardNo) {
+		return NetworkUnknown
+	}
+
+	for _, rule := range networkRules {
+		if len(cardNo) < rule.digits {
+			continue
+		}
+		prefix := atoi(cardNo[:rule.digits])
+		if prefix >= rule.low && prefix <= rule.high {
+			return rule.network
+		}
+	}
+	return NetworkUnknown
+}
+
+func atoi(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	return n
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package bank_card
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectNetwork(t *testing.T) {
+	var cases = map[string]Network{
+		"6222081106004039591": NetworkUnionPay,
+		"6226 0957 1198 9751": NetworkUnionPay,
+		"4111111111111111":    NetworkVisa,
+		"5555555555554444":    NetworkMastercard,
+		"2223003122003222":    NetworkMastercard,
+		"3530111333300000":    NetworkJCB,
+		"378282246310005":     NetworkAmex,
+		"341111111111111":     NetworkAmex,
+		"6011111111111117":    NetworkUnknown,
+		"abc":                 NetworkUnknown,
+	}
+
+	for cardNo, network := range cases {
+		t.Run("detect network: "+cardNo, func(t *testing.T) {
+			assert.Equal(t, network, DetectNetwork(cardNo))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	network, err := Validate("4111 1111 1111 1111")
+	assert.Nil(t, err)
+	assert.Equal(t, NetworkVisa, network)
+
+	network, err = Validate("378282246310005")
+	assert.Nil(t, err)
+	assert.Equal(t, NetworkAmex, network)
+	assert.Equal(t, "美国运通", network.Name())
+
+	_, err = Validate("")
+	assert.ErrorIs(t, err, ErrEmptyCardNo)
+
+	_, err = Validate("4111a11111111111")
+	assert.ErrorIs(t, err, ErrInvalidCardNo)
+
+	// Visa 不允许 15 位，美国运通只允许 15 位
+	_, err = Validate("411111111111116")
+	assert.ErrorIs(t, err, ErrInvalidLength)
+	_, err = Validate("3782822463100050")
+	assert.ErrorIs(t, err, ErrInvalidLength)
+
+	_, err = Validate("4111111111111112")
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+
+	// 未知卡组织按 12~19 位校验
+	network, err = Validate("1234567812345670")
+	assert.Nil(t, err)
+	assert.Equal(t, NetworkUnknown, network)
+}
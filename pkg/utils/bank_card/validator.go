@@ -1,5 +1,21 @@
 package bank_card
 
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrEmptyCardNo 卡号为空
+	ErrEmptyCardNo = errors.New("card number is empty")
+	// ErrInvalidCardNo 卡号包含非数字字符
+	ErrInvalidCardNo = errors.New("card number must contain only digits")
+	// ErrInvalidLength 卡号长度不符合卡组织规则
+	ErrInvalidLength = errors.New("invalid card number length")
+	// ErrInvalidChecksum 卡号未通过 Luhn 校验
+	ErrInvalidChecksum = errors.New("invalid card number checksum")
+)
+
 // IsValidLuhn 使用Luhn算法校验银行卡号码
 // @see https://en.wikipedia.org/wiki/Luhn_algorithm
 // @see https://www.geeksforgeeks.org/luhn-algorithm/
@@ -42,6 +58,42 @@ func IsValidBankCardNo(cardNo string) bool {
 	return IsValidLuhn(cardNo)
 }
 
+// Validate 完整校验银行卡号，返回识别到的卡组织
+//
+// 依次校验：非空、纯数字、卡组织长度规则（未知卡组织按 12~19 位）、Luhn。
+// 卡号中的空格与连字符会被忽略。注意部分早期银联借记卡不满足 Luhn 校验。
+func Validate(cardNo string) (Network, error) {
+	cardNo = Normalize(cardNo)
+	if cardNo == "" {
+		return NetworkUnknown, ErrEmptyCardNo
+	}
+	if !isNumberString(cardNo) {
+		return NetworkUnknown, ErrInvalidCardNo
+	}
+
+	network := DetectNetwork(cardNo)
+	if !network.ValidLength(len(cardNo)) {
+		return network, ErrInvalidLength
+	}
+	if !IsValidLuhn(cardNo) {
+		return network, ErrInvalidChecksum
+	}
+	return network, nil
+}
+
+// Normalize 去除卡号中的空格与连字符
+func Normalize(cardNo string) string {
+	if !strings.ContainsAny(cardNo, " -\t") {
+		return cardNo
+	}
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '\t' {
+			return -1
+		}
+		return r
+	}, cardNo)
+}
+
 // isNumberString 验证字符是数字
 func isNumberString(s string) bool {
 	length := len(s)
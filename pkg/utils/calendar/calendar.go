@@ -0,0 +1,246 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/heyinLab/common/pkg/utils/timeutil"
+)
+
+// Option 日历配置项
+type Option func(*Calendar)
+
+// WithLocation 设置时区，判断工作日时按该时区的日期，默认为 timeutil.GetDefaultTimeLocation
+func WithLocation(loc *time.Location) Option {
+	return func(c *Calendar) {
+		if loc != nil {
+			c.loc = loc
+		}
+	}
+}
+
+// WithWeekend 设置周末，默认为周六、周日；七天都是周末时忽略
+func WithWeekend(weekend Weekend) Option {
+	return func(c *Calendar) {
+		if weekend != NewWeekend(time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday) {
+			c.weekend = weekend
+		}
+	}
+}
+
+// WithHolidays 设置节假日数据，多个数据合并，相同日期以后面的为准
+func WithHolidays(sets ...*HolidaySet) Option {
+	return func(c *Calendar) {
+		for _, set := range sets {
+			c.holidays.Merge(set)
+		}
+	}
+}
+
+// WithWorkHours 设置每个工作日的工作时段，start、end 为距当天零点的本地时间，
+// 如 9*time.Hour、18*time.Hour；默认为全天，仅影响 BusinessDuration
+func WithWorkHours(start, end time.Duration) Option {
+	return func(c *Calendar) {
+		if start >= 0 && end > start && end <= 24*time.Hour {
+			c.workStart, c.workEnd = start, end
+		}
+	}
+}
+
+// Calendar 工作日历，按周末与节假日（含调休上班日）判断工作日
+//
+// 示例:
+//
+//	cal, err := calendar.ForCountry("CN", calendar.WithWorkHours(9*time.Hour, 18*time.Hour))
+//	due, err := cal.AddBusinessDays(invoice.CreatedAt, 5)
+//	elapsed, err := cal.BusinessDuration(ticket.CreatedAt, time.Now())
+//
+// 配置了节假日数据时，只能计算数据包含的年份，其他年份返回 ErrYearNotCovered，
+// 需要更新节假日数据（见 Register、LoadDir），或明确用 New 创建只按周末计算的日历。
+type Calendar struct {
+	loc       *time.Location
+	weekend   Weekend
+	holidays  *HolidaySet
+	workStart time.Duration
+	workEnd   time.Duration
+}
+
+// New 创建工作日历，默认只有周六、周日休息
+func New(opts ...Option) *Calendar {
+	c := &Calendar{
+		weekend:  SaturdaySunday,
+		holidays: NewHolidaySet(""),
+		workEnd:  24 * time.Hour,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.loc == nil {
+		c.loc = timeutil.GetDefaultTimeLocation()
+	}
+	if c.loc == nil {
+		c.loc = time.Local
+	}
+	return c
+}
+
+// ForCountry 按国家创建工作日历，使用该国的周末与节假日数据，opts 可覆盖默认配置
+//
+// 没有该国节假日数据时返回 ErrHolidaysNotFound，此时可用 New(WithWeekend(WeekendOf(code))) 只按周末计算。
+// 内置数据只包含已公布安排的年份，可用 HolidaySet.Years 查看。
+func ForCountry(code string, opts ...Option) (*Calendar, error) {
+	holidays, err := Holidays(code)
+	if err != nil {
+		return nil, err
+	}
+	return New(append([]Option{WithWeekend(WeekendOf(code)), WithHolidays(holidays)}, opts...)...), nil
+}
+
+// Location 日历使用的时区
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+// Weekend 日历使用的周末
+func (c *Calendar) Weekend() Weekend {
+	return c.weekend
+}
+
+// Holiday 查询 t 所在日期的节假日或调休上班日
+func (c *Calendar) Holiday(t time.Time) (Holiday, bool) {
+	return c.holidays.Lookup(t.In(c.loc))
+}
+
+// IsHoliday t 所在日期是否为节假日（不含普通周末）
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	h, ok := c.Holiday(t)
+	return ok && !h.Workday
+}
+
+// Covers 日历是否能判断 t 所在年份的工作日
+//
+// 没有配置节假日数据时只按周末判断，任何年份都可以判断；配置了节假日数据时只能判断数据包含的年份。
+func (c *Calendar) Covers(t time.Time) bool {
+	return c.holidays.Len() == 0 || c.holidays.Covers(t.In(c.loc).Year())
+}
+
+// IsBusinessDay t 所在日期是否为工作日
+//
+// 调休上班日为工作日，节假日与周末不是工作日。节假日数据不包含该年份时返回 ErrYearNotCovered，
+// 避免把未公布安排的节假日当作工作日。
+func (c *Calendar) IsBusinessDay(t time.Time) (bool, error) {
+	t = t.In(c.loc)
+	if !c.Covers(t) {
+		return false, fmt.Errorf("%w: %s %d", ErrYearNotCovered, c.holidays.Code, t.Year())
+	}
+	if h, ok := c.holidays.Lookup(t); ok {
+		return h.Workday, nil
+	}
+	return !c.weekend.Contains(t.Weekday()), nil
+}
+
+// AddBusinessDays 返回 n 个工作日之后（n 为负数时为之前）的同一时刻
+//
+// 从 t 的下一天开始计数，t 本身是否为工作日不影响结果，如周五加 1 个工作日为下周一。
+// n 为 0 时原样返回。计数经过节假日数据不包含的年份时返回 ErrYearNotCovered。
+func (c *Calendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	t = t.In(c.loc)
+	step := 1
+	if n < 0 {
+		step = -1
+	}
+	for n != 0 {
+		t = c.addDays(t, step)
+		ok, err := c.IsBusinessDay(t)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			n -= step
+		}
+	}
+	return t, nil
+}
+
+// NextBusinessDay 返回 t 当天（是工作日时）或之后第一个工作日的零点
+func (c *Calendar) NextBusinessDay(t time.Time) (time.Time, error) {
+	day := c.startOfDay(t)
+	for {
+		ok, err := c.IsBusinessDay(day)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			return day, nil
+		}
+		day = c.addDays(day, 1)
+	}
+}
+
+// BusinessDaysBetween 统计 [from, to) 日期范围内的工作日天数，to 早于 from 时为负数
+func (c *Calendar) BusinessDaysBetween(from, to time.Time) (int, error) {
+	if to.Before(from) {
+		count, err := c.BusinessDaysBetween(to, from)
+		return -count, err
+	}
+
+	count := 0
+	end := c.startOfDay(to)
+	for day := c.startOfDay(from); day.Before(end); day = c.addDays(day, 1) {
+		ok, err := c.IsBusinessDay(day)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// BusinessDuration 统计 [from, to) 内处于工作日工作时段的时长，to 早于 from 时为负数
+//
+// 用于 SLA 计时，如周五 17:00 到下周一 10:00，工作时段为 9:00-18:00 时为 2 小时。
+func (c *Calendar) BusinessDuration(from, to time.Time) (time.Duration, error) {
+	if to.Before(from) {
+		total, err := c.BusinessDuration(to, from)
+		return -total, err
+	}
+
+	var total time.Duration
+	for day := c.startOfDay(from); day.Before(to); day = c.addDays(day, 1) {
+		ok, err := c.IsBusinessDay(day)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+		year, month, d := day.Date()
+		start := time.Date(year, month, d, 0, 0, 0, int(c.workStart), c.loc)
+		end := time.Date(year, month, d, 0, 0, 0, int(c.workEnd), c.loc)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total, nil
+}
+
+// startOfDay t 所在日期的零点
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	year, month, day := t.In(c.loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, c.loc)
+}
+
+// addDays 按日历增加天数，保持本地时间不变
+func (c *Calendar) addDays(t time.Time, days int) time.Time {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	return time.Date(year, month, day+days, hour, minute, sec, t.Nanosecond(), c.loc)
+}
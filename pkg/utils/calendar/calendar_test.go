@@ -0,0 +1,225 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newChinaCalendar(t *testing.T, opts ...Option) *Calendar {
+	cal, err := ForCountry("cn", opts...)
+	require.NoError(t, err)
+	return cal
+}
+
+func isBusinessDay(t *testing.T, cal *Calendar, day time.Time) bool {
+	ok, err := cal.IsBusinessDay(day)
+	require.NoError(t, err)
+	return ok
+}
+
+func addBusinessDays(t *testing.T, cal *Calendar, day time.Time, n int) time.Time {
+	got, err := cal.AddBusinessDays(day, n)
+	require.NoError(t, err)
+	return got
+}
+
+func date(loc *time.Location, year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, loc)
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	cal := newChinaCalendar(t)
+	loc := cal.Location()
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{"weekday", date(loc, 2025, 3, 12, 10), true},
+		{"weekend", date(loc, 2025, 3, 15, 10), false},
+		{"spring festival", date(loc, 2025, 1, 29, 10), false},
+		{"make-up workday on sunday", date(loc, 2025, 1, 26, 10), true},
+		{"make-up workday on saturday", date(loc, 2025, 2, 8, 10), true},
+		{"national day", date(loc, 2025, 10, 8, 10), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isBusinessDay(t, cal, tt.day))
+		})
+	}
+
+	assert.True(t, cal.IsHoliday(date(loc, 2025, 1, 29, 10)))
+	assert.False(t, cal.IsHoliday(date(loc, 2025, 1, 26, 10)))
+	h, ok := cal.Holiday(date(loc, 2025, 1, 26, 10))
+	require.True(t, ok)
+	assert.True(t, h.Workday)
+	assert.Equal(t, "春节", h.Name)
+
+	// 按日历时区的日期判断：UTC 1月27日 20:00 为北京时间 1月28日（春节）
+	assert.False(t, isBusinessDay(t, cal, time.Date(2025, 1, 27, 20, 0, 0, 0, time.UTC)))
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	cal := newChinaCalendar(t)
+	loc := cal.Location()
+
+	// 周五加 1 个工作日为下周一
+	assert.Equal(t, date(loc, 2025, 3, 17, 15), addBusinessDays(t, cal, date(loc, 2025, 3, 14, 15), 1))
+	// 春节前：1月24日（周五）+ 2 = 1月26日（周日调休）之后的 1月27日
+	assert.Equal(t, date(loc, 2025, 1, 27, 9), addBusinessDays(t, cal, date(loc, 2025, 1, 24, 9), 2))
+	// 跨越春节假期：1月27日 + 1 = 2月5日
+	assert.Equal(t, date(loc, 2025, 2, 5, 9), addBusinessDays(t, cal, date(loc, 2025, 1, 27, 9), 1))
+	// 向前
+	assert.Equal(t, date(loc, 2025, 1, 27, 9), addBusinessDays(t, cal, date(loc, 2025, 2, 5, 9), -1))
+	assert.Equal(t, date(loc, 2025, 2, 5, 9), addBusinessDays(t, cal, date(loc, 2025, 2, 5, 9), 0))
+
+	next := func(day time.Time) time.Time {
+		got, err := cal.NextBusinessDay(day)
+		require.NoError(t, err)
+		return got
+	}
+	assert.Equal(t, date(loc, 2025, 2, 5, 0), next(date(loc, 2025, 1, 30, 12)))
+	assert.Equal(t, date(loc, 2025, 2, 5, 0), next(date(loc, 2025, 2, 5, 12)))
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	cal := newChinaCalendar(t)
+	loc := cal.Location()
+	between := func(from, to time.Time) int {
+		n, err := cal.BusinessDaysBetween(from, to)
+		require.NoError(t, err)
+		return n
+	}
+
+	// 2025年10月：18 个工作日（国庆 1-8 日放假，10月11日周六调休上班）
+	assert.Equal(t, 18, between(date(loc, 2025, 10, 1, 0), date(loc, 2025, 11, 1, 0)))
+	assert.Equal(t, -18, between(date(loc, 2025, 11, 1, 0), date(loc, 2025, 10, 1, 0)))
+	assert.Equal(t, 0, between(date(loc, 2025, 3, 12, 8), date(loc, 2025, 3, 12, 20)))
+}
+
+func TestBusinessDuration(t *testing.T) {
+	cal := newChinaCalendar(t, WithWorkHours(9*time.Hour, 18*time.Hour))
+	loc := cal.Location()
+	duration := func(cal *Calendar, from, to time.Time) time.Duration {
+		d, err := cal.BusinessDuration(from, to)
+		require.NoError(t, err)
+		return d
+	}
+
+	// 周五 17:00 到下周一 10:00
+	assert.Equal(t, 2*time.Hour, duration(cal, date(loc, 2025, 3, 14, 17), date(loc, 2025, 3, 17, 10)))
+	// 同一天工作时段外
+	assert.Equal(t, time.Duration(0), duration(cal, date(loc, 2025, 3, 12, 19), date(loc, 2025, 3, 12, 23)))
+	// 跨越春节假期
+	assert.Equal(t, 2*time.Hour, duration(cal, date(loc, 2025, 1, 27, 17), date(loc, 2025, 2, 5, 10)))
+	assert.Equal(t, -2*time.Hour, duration(cal, date(loc, 2025, 3, 17, 10), date(loc, 2025, 3, 14, 17)))
+
+	// 默认全天
+	allDay := New(WithLocation(loc))
+	assert.Equal(t, 24*time.Hour+10*time.Hour, duration(allDay, date(loc, 2025, 3, 14, 0), date(loc, 2025, 3, 17, 10)))
+}
+
+func TestYearNotCovered(t *testing.T) {
+	cal := newChinaCalendar(t)
+	loc := cal.Location()
+
+	cn, err := Holidays("CN")
+	require.NoError(t, err)
+	assert.Equal(t, []int{2024, 2025, 2026}, cn.Years())
+	assert.True(t, cal.Covers(date(loc, 2026, 12, 31, 10)))
+	assert.False(t, cal.Covers(date(loc, 2027, 1, 4, 10)))
+
+	// 2027 年的节假日安排尚未加载，不能只按周末判断
+	_, err = cal.IsBusinessDay(date(loc, 2027, 1, 4, 10))
+	assert.ErrorIs(t, err, ErrYearNotCovered)
+	_, err = cal.AddBusinessDays(date(loc, 2026, 12, 30, 10), 5)
+	assert.ErrorIs(t, err, ErrYearNotCovered)
+	_, err = cal.NextBusinessDay(date(loc, 2027, 1, 1, 10))
+	assert.ErrorIs(t, err, ErrYearNotCovered)
+	_, err = cal.BusinessDaysBetween(date(loc, 2026, 12, 1, 0), date(loc, 2027, 2, 1, 0))
+	assert.ErrorIs(t, err, ErrYearNotCovered)
+	_, err = cal.BusinessDuration(date(loc, 2027, 2, 1, 0), date(loc, 2026, 12, 1, 0))
+	assert.ErrorIs(t, err, ErrYearNotCovered)
+
+	// 加载新年份的数据后可以计算
+	next := NewHolidaySet("CN").AddHoliday(date(time.UTC, 2027, 1, 1, 0), "元旦")
+	cal = New(WithLocation(loc), WithHolidays(cn, next))
+	assert.True(t, isBusinessDay(t, cal, date(loc, 2027, 1, 4, 10)))
+	assert.False(t, isBusinessDay(t, cal, date(loc, 2027, 1, 1, 10)))
+
+	// 没有节假日数据时只按周末计算，不限年份
+	weekendOnly := New(WithLocation(loc))
+	assert.True(t, weekendOnly.Covers(date(loc, 2030, 1, 1, 10)))
+	assert.True(t, isBusinessDay(t, weekendOnly, date(loc, 2030, 1, 1, 10)))
+}
+
+func TestWeekend(t *testing.T) {
+	assert.Equal(t, SaturdaySunday, WeekendOf("CN"))
+	assert.Equal(t, FridaySaturday, WeekendOf("sa"))
+	assert.Equal(t, []time.Weekday{time.Friday, time.Saturday}, FridaySaturday.Days())
+
+	loc := time.UTC
+	cal := New(WithLocation(loc), WithWeekend(WeekendOf("SA")))
+	assert.False(t, isBusinessDay(t, cal, date(loc, 2025, 3, 14, 10))) // 周五
+	assert.True(t, isBusinessDay(t, cal, date(loc, 2025, 3, 16, 10)))  // 周日
+	assert.Equal(t, date(loc, 2025, 3, 16, 10), addBusinessDays(t, cal, date(loc, 2025, 3, 13, 10), 1))
+
+	// 七天都是周末时忽略
+	all := New(WithWeekend(NewWeekend(0, 1, 2, 3, 4, 5, 6)))
+	assert.Equal(t, SaturdaySunday, all.Weekend())
+}
+
+func TestParseHolidays(t *testing.T) {
+	set, err := ParseHolidays("us", strings.NewReader(`# date,end,type,name
+2025-01-01,,holiday,New Year's Day
+2025-12-24,2025-12-26,holiday,Christmas
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "US", set.Code)
+	assert.Equal(t, 4, set.Len())
+	assert.Equal(t, []int{2025}, set.Years())
+	assert.True(t, set.Covers(2025))
+	assert.False(t, set.Covers(2026))
+	holidays := set.Between(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	require.Len(t, holidays, 3)
+	assert.Equal(t, "Christmas", holidays[0].Name)
+
+	for _, data := range []string{
+		"2025-13-01,,holiday,x",
+		"2025-01-02,2025-01-01,holiday,x",
+		"2025-01-01,,vacation,x",
+		"2025-01-01",
+	} {
+		_, err = ParseHolidays("US", strings.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalidHolidays, data)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	_, err := Holidays("ZZ")
+	require.ErrorIs(t, err, ErrHolidaysNotFound)
+	_, err = ForCountry("ZZ")
+	require.ErrorIs(t, err, ErrHolidaysNotFound)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ZZ.csv"), []byte("2025-03-12,,holiday,Test Day\n"), 0o644))
+	sets, err := LoadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, sets, 1)
+
+	cal, err := ForCountry("zz", WithLocation(time.UTC))
+	require.NoError(t, err)
+	assert.False(t, isBusinessDay(t, cal, date(time.UTC, 2025, 3, 12, 10)))
+	assert.True(t, isBusinessDay(t, cal, date(time.UTC, 2025, 3, 13, 10)))
+
+	cn, err := Holidays("CN")
+	require.NoError(t, err)
+	assert.Positive(t, cn.Len())
+}
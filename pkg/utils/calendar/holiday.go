@@ -0,0 +1,206 @@
+package calendar
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/heyinLab/common/pkg/utils/timeutil"
+)
+
+var (
+	// ErrInvalidHolidays 节假日数据格式错误
+	ErrInvalidHolidays = errors.New("calendar: invalid holiday data")
+	// ErrHolidaysNotFound 没有该国家的节假日数据
+	ErrHolidaysNotFound = errors.New("calendar: holidays not found")
+	// ErrYearNotCovered 节假日数据不包含该年份，无法判断工作日
+	ErrYearNotCovered = errors.New("calendar: year not covered by holiday data")
+)
+
+// Holiday 节假日或调休上班日
+type Holiday struct {
+	Date    time.Time // 日期，UTC 零点
+	Name    string
+	Workday bool // 调休上班日（如春节前后的周末）
+}
+
+// dateKey 日期的整数表示，如 20240101
+type dateKey int
+
+func keyOf(t time.Time) dateKey {
+	year, month, day := t.Date()
+	return dateKey(year*10000 + int(month)*100 + day)
+}
+
+// HolidaySet 一个国家或地区的节假日数据
+//
+// 节假日（Workday 为 false）当天休息，调休上班日（Workday 为 true）即使是周末也需要上班。
+type HolidaySet struct {
+	Code  string // ISO 3166-1 alpha-2
+	days  map[dateKey]Holiday
+	years map[int]struct{}
+}
+
+// NewHolidaySet 创建空的节假日数据
+func NewHolidaySet(code string) *HolidaySet {
+	return &HolidaySet{Code: strings.ToUpper(code), days: make(map[dateKey]Holiday), years: make(map[int]struct{})}
+}
+
+// AddHoliday 添加节假日，日期按 date 所在时区的年月日
+func (s *HolidaySet) AddHoliday(date time.Time, name string) *HolidaySet {
+	return s.add(date, name, false)
+}
+
+// AddWorkday 添加调休上班日
+func (s *HolidaySet) AddWorkday(date time.Time, name string) *HolidaySet {
+	return s.add(date, name, true)
+}
+
+func (s *HolidaySet) add(date time.Time, name string, workday bool) *HolidaySet {
+	year, month, day := date.Date()
+	s.days[keyOf(date)] = Holiday{
+		Date:    time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		Name:    name,
+		Workday: workday,
+	}
+	s.years[year] = struct{}{}
+	return s
+}
+
+// Lookup 查询 t 所在日期（按 t 的时区）是否为节假日或调休上班日
+func (s *HolidaySet) Lookup(t time.Time) (Holiday, bool) {
+	if s == nil {
+		return Holiday{}, false
+	}
+	h, ok := s.days[keyOf(t)]
+	return h, ok
+}
+
+// Len 数据条数（按天计）
+func (s *HolidaySet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.days)
+}
+
+// Covers 是否包含 year 年的数据，数据中出现过该年份的日期即视为包含
+func (s *HolidaySet) Covers(year int) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.years[year]
+	return ok
+}
+
+// Years 数据包含的年份，升序
+func (s *HolidaySet) Years() []int {
+	if s == nil {
+		return nil
+	}
+	years := make([]int, 0, len(s.years))
+	for year := range s.years {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// Between 返回 [from, to] 日期范围内的节假日与调休上班日，按日期排序
+func (s *HolidaySet) Between(from, to time.Time) []Holiday {
+	if s == nil {
+		return nil
+	}
+	lo, hi := keyOf(from), keyOf(to)
+	var holidays []Holiday
+	for key, h := range s.days {
+		if key >= lo && key <= hi {
+			holidays = append(holidays, h)
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+	return holidays
+}
+
+// Merge 合并其他数据，相同日期以 other 为准
+func (s *HolidaySet) Merge(other *HolidaySet) *HolidaySet {
+	if other == nil {
+		return s
+	}
+	if s.Code == "" {
+		s.Code = other.Code
+	}
+	for key, h := range other.days {
+		s.days[key] = h
+	}
+	for year := range other.years {
+		s.years[year] = struct{}{}
+	}
+	return s
+}
+
+// ParseHolidays 解析 CSV 格式的节假日数据
+//
+// 每行格式为 date,end,type,name，以 # 开头的行为注释:
+//
+//	2024-02-10,2024-02-17,holiday,春节
+//	2024-02-04,,workday,春节
+//
+// end 为空表示单日，type 为 holiday（放假）或 workday（调休上班）。
+func ParseHolidays(code string, r io.Reader) (*HolidaySet, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	set := NewHolidaySet(code)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHolidays, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if len(record) < 3 {
+			return nil, fmt.Errorf("%w: line %d: expected date,end,type[,name]", ErrInvalidHolidays, line)
+		}
+		start, err := time.Parse(timeutil.DateLayout, strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidHolidays, line, err)
+		}
+		end := start
+		if v := strings.TrimSpace(record[1]); v != "" {
+			if end, err = time.Parse(timeutil.DateLayout, v); err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidHolidays, line, err)
+			}
+			if end.Before(start) {
+				return nil, fmt.Errorf("%w: line %d: end before date", ErrInvalidHolidays, line)
+			}
+		}
+
+		var workday bool
+		switch strings.ToLower(strings.TrimSpace(record[2])) {
+		case "holiday":
+		case "workday":
+			workday = true
+		default:
+			return nil, fmt.Errorf("%w: line %d: unknown type %q", ErrInvalidHolidays, line, record[2])
+		}
+
+		var name string
+		if len(record) > 3 {
+			name = strings.TrimSpace(record[3])
+		}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			set.add(day, name, workday)
+		}
+	}
+	return set, nil
+}
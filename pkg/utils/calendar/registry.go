@@ -0,0 +1,99 @@
+package calendar
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 内置节假日数据，文件名为 ISO 3166-1 alpha-2 国家代码
+//
+//go:embed holidays/*.csv
+var embeddedHolidays embed.FS
+
+var registry = struct {
+	sync.RWMutex
+	sets map[string]*HolidaySet
+}{sets: make(map[string]*HolidaySet)}
+
+// Register 注册节假日数据，已有相同国家的数据时覆盖
+//
+// 用于加载内置数据以外的国家，或以最新发布的安排替换内置数据。
+func Register(set *HolidaySet) {
+	if set == nil || set.Code == "" {
+		return
+	}
+	registry.Lock()
+	registry.sets[set.Code] = set
+	registry.Unlock()
+}
+
+// Holidays 按国家代码获取节假日数据，优先返回 Register 注册的数据，其次为内置数据
+func Holidays(code string) (*HolidaySet, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	registry.RLock()
+	set, ok := registry.sets[code]
+	registry.RUnlock()
+	if ok {
+		return set, nil
+	}
+
+	data, err := embeddedHolidays.ReadFile("holidays/" + code + ".csv")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolidaysNotFound, code)
+	}
+	if set, err = ParseHolidays(code, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("parse embedded holidays %s: %w", code, err)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if existing, ok := registry.sets[code]; ok {
+		return existing, nil
+	}
+	registry.sets[code] = set
+	return set, nil
+}
+
+// LoadFile 从 CSV 文件加载节假日数据并注册，code 为空时使用文件名（如 US.csv）
+func LoadFile(code, path string) (*HolidaySet, error) {
+	if code == "" {
+		code = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	set, err := ParseHolidays(code, f)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	Register(set)
+	return set, nil
+}
+
+// LoadDir 加载目录下所有 <国家代码>.csv 文件并注册
+func LoadDir(dir string) ([]*HolidaySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]*HolidaySet, 0, len(paths))
+	for _, path := range paths {
+		set, err := LoadFile("", path)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
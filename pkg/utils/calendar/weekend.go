@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+)
+
+// Weekend 周末，按 time.Weekday 的位表示
+type Weekend uint8
+
+// 常用周末
+var (
+	SaturdaySunday = NewWeekend(time.Saturday, time.Sunday)
+	FridaySaturday = NewWeekend(time.Friday, time.Saturday)
+	ThursdayFriday = NewWeekend(time.Thursday, time.Friday)
+	FridayOnly     = NewWeekend(time.Friday)
+	SaturdayOnly   = NewWeekend(time.Saturday)
+)
+
+// countryWeekends 周末不是周六、周日的国家
+var countryWeekends = map[string]Weekend{
+	"AF": ThursdayFriday,
+	"BH": FridaySaturday,
+	"BD": FridaySaturday,
+	"DZ": FridaySaturday,
+	"EG": FridaySaturday,
+	"IL": FridaySaturday,
+	"IQ": FridaySaturday,
+	"IR": FridayOnly,
+	"JO": FridaySaturday,
+	"KW": FridaySaturday,
+	"LY": FridaySaturday,
+	"NP": SaturdayOnly,
+	"OM": FridaySaturday,
+	"QA": FridaySaturday,
+	"SA": FridaySaturday,
+	"SD": FridaySaturday,
+	"SY": FridaySaturday,
+	"YE": FridaySaturday,
+}
+
+// NewWeekend 创建周末
+func NewWeekend(days ...time.Weekday) Weekend {
+	var w Weekend
+	for _, day := range days {
+		w |= 1 << uint(day%7)
+	}
+	return w
+}
+
+// WeekendOf 国家的周末，未知国家为周六、周日
+func WeekendOf(code string) Weekend {
+	if w, ok := countryWeekends[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return w
+	}
+	return SaturdaySunday
+}
+
+// Contains day 是否为周末
+func (w Weekend) Contains(day time.Weekday) bool {
+	return w&(1<<uint(day%7)) != 0
+}
+
+// Days 周末包含的日期，从周日开始
+func (w Weekend) Days() []time.Weekday {
+	var days []time.Weekday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if w.Contains(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
@@ -0,0 +1,149 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock 时间源
+//
+// 业务代码通过 Clock 读取时间与创建定时器，测试中注入 Fake 即可控制时间，不需要 sleep。
+type Clock interface {
+	// Now 当前时间
+	Now() time.Time
+	// Since 自 t 以来经过的时间
+	Since(t time.Time) time.Duration
+	// After 经过 d 之后向返回的通道发送当前时间
+	After(d time.Duration) <-chan time.Time
+	// Sleep 阻塞 d
+	Sleep(d time.Duration)
+	// NewTimer 创建定时器
+	NewTimer(d time.Duration) Timer
+	// NewTicker 创建周期定时器，d 必须大于 0
+	NewTicker(d time.Duration) Ticker
+	// AfterFunc 经过 d 之后在新的 goroutine 中执行 f
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer 定时器，与 time.Timer 语义相同
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker 周期定时器，与 time.Ticker 语义相同
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type realClock struct{}
+
+// Real 系统时钟
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{timer: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{ticker: time.NewTicker(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return &realTimer{timer: time.AfterFunc(d, f)}
+}
+
+type realTimer struct{ timer *time.Timer }
+
+func (t *realTimer) C() <-chan time.Time        { return t.timer.C }
+func (t *realTimer) Stop() bool                 { return t.timer.Stop() }
+func (t *realTimer) Reset(d time.Duration) bool { return t.timer.Reset(d) }
+
+type realTicker struct{ ticker *time.Ticker }
+
+func (t *realTicker) C() <-chan time.Time   { return t.ticker.C }
+func (t *realTicker) Stop()                 { t.ticker.Stop() }
+func (t *realTicker) Reset(d time.Duration) { t.ticker.Reset(d) }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var (
+	defaultMu    sync.RWMutex
+	defaultClock Clock = realClock{}
+)
+
+// Default 包级默认时钟，没有通过参数或 context 传入时钟的函数使用该时钟，默认为系统时钟
+func Default() Clock {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultClock
+}
+
+// SetDefault 替换包级默认时钟，返回恢复原时钟的函数，nil 表示系统时钟
+//
+// 仅用于测试，如:
+//
+//	fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+//	defer clock.SetDefault(fake)()
+func SetDefault(c Clock) (restore func()) {
+	if c == nil {
+		c = realClock{}
+	}
+	defaultMu.Lock()
+	previous := defaultClock
+	defaultClock = c
+	defaultMu.Unlock()
+
+	return func() {
+		defaultMu.Lock()
+		defaultClock = previous
+		defaultMu.Unlock()
+	}
+}
+
+// Now 默认时钟的当前时间，可以直接作为 func() time.Time 类型的时间源
+func Now() time.Time {
+	return Default().Now()
+}
+
+// Since 按默认时钟计算自 t 以来经过的时间
+func Since(t time.Time) time.Duration {
+	return Default().Since(t)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type clockKey struct{}
+
+// NewContext 将时钟放入 context
+func NewContext(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// FromContext 从 context 获取时钟，没有时返回默认时钟
+func FromContext(ctx context.Context) Clock {
+	if ctx != nil {
+		if c, ok := ctx.Value(clockKey{}).(Clock); ok && c != nil {
+			return c
+		}
+	}
+	return Default()
+}
+
+// NowContext 按 context 中的时钟返回当前时间
+func NowContext(ctx context.Context) time.Time {
+	return FromContext(ctx).Now()
+}
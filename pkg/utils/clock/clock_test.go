@@ -0,0 +1,139 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)
+
+func TestFakeAdvance(t *testing.T) {
+	fake := NewFake(testStart)
+	assert.Equal(t, testStart, fake.Now())
+
+	late := fake.NewTimer(2 * time.Second)
+	early := fake.After(time.Second)
+	assert.Equal(t, []time.Time{testStart.Add(time.Second), testStart.Add(2 * time.Second)}, fake.Timers())
+
+	fake.Advance(500 * time.Millisecond)
+	select {
+	case <-early:
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	fake.Advance(2 * time.Second)
+	assert.Equal(t, testStart.Add(time.Second), <-early)
+	assert.Equal(t, testStart.Add(2*time.Second), <-late.C())
+	assert.Equal(t, testStart.Add(2500*time.Millisecond), fake.Now())
+	assert.Equal(t, 2500*time.Millisecond, fake.Since(testStart))
+	assert.Empty(t, fake.Timers())
+
+	// 回拨时间不触发定时器
+	timer := fake.NewTimer(time.Second)
+	fake.Set(testStart)
+	assert.Equal(t, testStart, fake.Now())
+	assert.Len(t, fake.Timers(), 1)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+}
+
+func TestFakeTimerReset(t *testing.T) {
+	fake := NewFake(testStart)
+	timer := fake.NewTimer(time.Second)
+	assert.True(t, timer.Reset(time.Minute))
+
+	fake.Advance(time.Second)
+	assert.Empty(t, timer.C())
+	fake.Advance(time.Minute)
+	assert.Equal(t, testStart.Add(time.Minute), <-timer.C())
+	assert.False(t, timer.Reset(time.Second))
+
+	// d 不大于 0 时立即触发
+	assert.Equal(t, fake.Now(), <-fake.After(0))
+}
+
+func TestFakeTicker(t *testing.T) {
+	fake := NewFake(testStart)
+	ticker := fake.NewTicker(time.Second)
+
+	fake.Advance(time.Second)
+	assert.Equal(t, testStart.Add(time.Second), <-ticker.C())
+	// 与 time.Ticker 相同，来不及读取时丢弃多余的触发
+	fake.Advance(3 * time.Second)
+	assert.Equal(t, testStart.Add(2*time.Second), <-ticker.C())
+	assert.Empty(t, ticker.C())
+
+	ticker.Reset(time.Minute)
+	fake.Advance(time.Second)
+	assert.Empty(t, ticker.C())
+	fake.Advance(time.Minute)
+	assert.Equal(t, testStart.Add(4*time.Second+time.Minute), <-ticker.C())
+
+	ticker.Stop()
+	fake.Advance(time.Hour)
+	assert.Empty(t, ticker.C())
+	assert.Panics(t, func() { fake.NewTicker(0) })
+}
+
+func TestFakeAfterFunc(t *testing.T) {
+	fake := NewFake(testStart)
+	done := make(chan struct{})
+	fake.AfterFunc(time.Minute, func() { close(done) })
+	stopped := fake.AfterFunc(time.Minute, func() { t.Error("stopped timer fired") })
+	require.True(t, stopped.Stop())
+
+	fake.Advance(time.Minute)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("AfterFunc not called")
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	fake := NewFake(testStart)
+	woke := make(chan time.Time)
+	go func() {
+		fake.Sleep(time.Hour)
+		woke <- fake.Now()
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(time.Hour)
+	assert.Equal(t, testStart.Add(time.Hour), <-woke)
+}
+
+func TestFixed(t *testing.T) {
+	c := Fixed(testStart)
+	assert.Equal(t, testStart, c.Now())
+	assert.Equal(t, testStart, c.Now())
+	assert.Equal(t, testStart, <-c.After(0))
+}
+
+func TestDefault(t *testing.T) {
+	_, isReal := Default().(realClock)
+	require.True(t, isReal)
+
+	restore := SetDefault(Fixed(testStart))
+	assert.Equal(t, testStart, Now())
+	assert.Equal(t, time.Hour, Since(testStart.Add(-time.Hour)))
+	assert.Equal(t, testStart, NowContext(context.Background()))
+
+	other := testStart.Add(24 * time.Hour)
+	ctx := NewContext(context.Background(), Fixed(other))
+	assert.Equal(t, other, NowContext(ctx))
+	assert.Equal(t, other, FromContext(ctx).Now())
+
+	restore()
+	_, isReal = Default().(realClock)
+	assert.True(t, isReal)
+	assert.WithinDuration(t, time.Now(), Now(), time.Second)
+
+	// nil 表示系统时钟
+	SetDefault(nil)()
+}
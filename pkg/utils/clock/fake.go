@@ -0,0 +1,245 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake 手动推进的时钟
+//
+// 时间只在调用 Advance 或 Set 时变化，到期的定时器按到期时间顺序触发，
+// 调度类代码的测试可以用 BlockUntil 等待被测代码创建好定时器后再推进时间:
+//
+//	fake := clock.NewFake(start)
+//	go scheduler.Run(ctx) // 内部调用 fake.After(time.Hour)
+//	fake.BlockUntil(1)
+//	fake.Advance(time.Hour)
+type Fake struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+var _ Clock = (*Fake)(nil)
+
+// NewFake 创建从 now 开始的手动时钟
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// Fixed 固定时间的时钟，Now 始终返回 t，除 d 不大于 0 的以外定时器不会触发
+func Fixed(t time.Time) Clock {
+	return NewFake(t)
+}
+
+// Now 实现 Clock
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since 实现 Clock
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After 实现 Clock
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// Sleep 阻塞直到时钟被推进 d
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// NewTimer 实现 Clock
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: f, ch: make(chan time.Time, 1)}
+	f.schedule(t, d)
+	return t
+}
+
+// NewTicker 实现 Clock
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	t := &fakeTimer{clock: f, ch: make(chan time.Time, 1), period: d}
+	f.schedule(t, d)
+	return fakeTicker{t}
+}
+
+// AfterFunc 实现 Clock
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &fakeTimer{clock: f, fn: fn}
+	f.schedule(t, d)
+	return t
+}
+
+// Advance 推进时钟 d，期间到期的定时器按到期时间顺序触发
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	target := f.now.Add(d)
+	f.mu.Unlock()
+	f.advanceTo(target)
+}
+
+// Set 将时钟设置为 t，t 早于当前时间时只修改时间，不触发定时器
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	if t.Before(f.now) {
+		f.now = t
+		f.mu.Unlock()
+		return
+	}
+	f.mu.Unlock()
+	f.advanceTo(t)
+}
+
+// Timers 等待中的定时器的到期时间，按时间排序
+func (f *Fake) Timers() []time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	deadlines := make([]time.Time, len(f.timers))
+	for i, t := range f.timers {
+		deadlines[i] = t.at
+	}
+	sort.Slice(deadlines, func(i, j int) bool { return deadlines[i].Before(deadlines[j]) })
+	return deadlines
+}
+
+// BlockUntil 阻塞直到等待中的定时器（含 After、Sleep、Ticker）不少于 n 个
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) advanceTo(target time.Time) {
+	for {
+		f.mu.Lock()
+		next := f.earliest()
+		if next == nil || next.at.After(target) {
+			if target.After(f.now) {
+				f.now = target
+			}
+			f.mu.Unlock()
+			return
+		}
+
+		if next.at.After(f.now) {
+			f.now = next.at
+		}
+		f.remove(next)
+		now := f.now
+		if next.period > 0 {
+			next.at = next.at.Add(next.period)
+			f.add(next)
+		}
+		f.mu.Unlock()
+
+		next.fire(now)
+	}
+}
+
+// schedule 在 d 之后触发 t，d 不大于 0 时立即触发
+func (f *Fake) schedule(t *fakeTimer, d time.Duration) {
+	f.mu.Lock()
+	t.at = f.now.Add(d)
+	if d > 0 || t.period > 0 {
+		f.add(t)
+		f.mu.Unlock()
+		return
+	}
+	now := f.now
+	f.mu.Unlock()
+	t.fire(now)
+}
+
+// earliest 最早到期的定时器，调用方需持有锁
+func (f *Fake) earliest() *fakeTimer {
+	var next *fakeTimer
+	for _, t := range f.timers {
+		if next == nil || t.at.Before(next.at) {
+			next = t
+		}
+	}
+	return next
+}
+
+// add 调用方需持有锁
+func (f *Fake) add(t *fakeTimer) {
+	f.timers = append(f.timers, t)
+	f.cond.Broadcast()
+}
+
+// remove 调用方需持有锁，返回定时器是否在等待中
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i, timer := range f.timers {
+		if timer == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type fakeTimer struct {
+	clock  *Fake
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+	fn     func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+// fire 与 time.Timer 相同，通道已满时丢弃本次触发
+func (t *fakeTimer) fire(now time.Time) {
+	if t.fn != nil {
+		go t.fn()
+		return
+	}
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
+
+// Stop 实现 Timer
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+// Reset 实现 Timer
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	active := t.clock.remove(t)
+	if t.period > 0 {
+		t.period = d
+	}
+	t.clock.mu.Unlock()
+
+	t.clock.schedule(t, d)
+	return active
+}
+
+// fakeTicker 适配 Ticker 接口，Stop 与 Reset 没有返回值
+type fakeTicker struct{ *fakeTimer }
+
+func (t fakeTicker) Stop()                 { t.fakeTimer.Stop() }
+func (t fakeTicker) Reset(d time.Duration) { t.fakeTimer.Reset(d) }
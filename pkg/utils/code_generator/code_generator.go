@@ -5,19 +5,28 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"text/template"
 )
 
 // CodeGenerator 使用 TemplateEngine 渲染并将结果写入磁盘
 type CodeGenerator struct {
 	Engine  TemplateEngine
 	FileExt string
+	// Funcs 渲染输出路径（Scaffold）使用的模板函数，为空时使用 DefaultFuncMap
+	Funcs template.FuncMap
 }
 
+var (
+	_ Generator  = (*CodeGenerator)(nil)
+	_ Scaffolder = (*CodeGenerator)(nil)
+)
+
 // NewCodeGeneratorWithEngine 使用指定的引擎创建生成器
 func NewCodeGeneratorWithEngine(engine TemplateEngine) *CodeGenerator {
 	g := &CodeGenerator{
 		Engine:  engine,
 		FileExt: ".go",
+		Funcs:   DefaultFuncMap(),
 	}
 	return g
 }
@@ -29,18 +38,7 @@ func (g *CodeGenerator) Generate(_ context.Context, opts Options, tplName string
 		return "", os.ErrInvalid
 	}
 
-	// 合并数据：以 opts.Vars 为基础，注入常用字段
-	data := map[string]any{}
-	if opts.Vars != nil {
-		for k, v := range opts.Vars {
-			data[k] = v
-		}
-	}
-	// 常用上下文
-	data["Module"] = opts.Module
-	data["ProjectName"] = opts.ProjectName
-	data["Project"] = opts.ProjectName
-	data["OutDir"] = opts.OutDir
+	data := templateData(opts)
 
 	// 渲染
 	outBytes, err := g.Engine.Render(tplName, data)
@@ -49,13 +47,7 @@ func (g *CodeGenerator) Generate(_ context.Context, opts Options, tplName string
 	}
 
 	// 计算默认输出名称（保持相对目录并去掉模板后缀）
-	defaultOutName := tplName
-	if strings.HasSuffix(defaultOutName, ".tpl") {
-		defaultOutName = strings.TrimSuffix(defaultOutName, ".tpl")
-	} else if strings.HasSuffix(defaultOutName, ".tmpl") {
-		defaultOutName = strings.TrimSuffix(defaultOutName, ".tmpl")
-	}
-	defaultOutName = filepath.FromSlash(defaultOutName)
+	defaultOutName := filepath.FromSlash(trimTemplateSuffix(tplName))
 
 	// 如果用户指定 OutputName，优先处理（规范化、禁止绝对路径）
 	finalRel := defaultOutName
@@ -93,34 +85,63 @@ func (g *CodeGenerator) Generate(_ context.Context, opts Options, tplName string
 	}
 
 	outPath := filepath.Join(opts.OutDir, finalRel)
-	if err = os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+	if err = writeFileAtomic(outPath, outBytes); err != nil {
 		return "", err
 	}
 
-	// 原子写入：先写临时文件再重命名
+	return outPath, nil
+}
+
+// templateData 合并模板数据：以 opts.Vars 为基础，注入常用字段
+func templateData(opts Options) map[string]any {
+	data := map[string]any{}
+	for k, v := range opts.Vars {
+		data[k] = v
+	}
+	// 常用上下文
+	data["Module"] = opts.Module
+	data["ProjectName"] = opts.ProjectName
+	data["Project"] = opts.ProjectName
+	data["OutDir"] = opts.OutDir
+	return data
+}
+
+// trimTemplateSuffix 去掉模板名的 .tpl 或 .tmpl 后缀
+func trimTemplateSuffix(name string) string {
+	if strings.HasSuffix(name, ".tpl") {
+		return strings.TrimSuffix(name, ".tpl")
+	}
+	return strings.TrimSuffix(name, ".tmpl")
+}
+
+// writeFileAtomic 原子写入：先写临时文件再重命名
+func writeFileAtomic(outPath string, data []byte) error {
 	dir := filepath.Dir(outPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
 	tmpFile, err := os.CreateTemp(dir, ".tmp-*")
 	if err != nil {
-		return "", err
+		return err
 	}
 	tmpName := tmpFile.Name()
 
-	_, err = tmpFile.Write(outBytes)
+	_, err = tmpFile.Write(data)
 	if errClose := tmpFile.Close(); err == nil {
 		err = errClose
 	}
 	if err != nil {
 		_ = os.Remove(tmpName)
-		return "", err
+		return err
 	}
 
 	if err = os.Rename(tmpName, outPath); err != nil {
 		_ = os.Remove(tmpName)
-		return "", err
+		return err
 	}
 
 	// 确保目标文件权限
 	_ = os.Chmod(outPath, 0o644)
-
-	return outPath, nil
+	return nil
 }
@@ -8,20 +8,38 @@ import (
 	"strings"
 	"sync"
 	"text/template"
+	"text/template/parse"
 )
 
 // FileTemplateEngine 从磁盘加载并缓存模板
+//
+// 模板源码在加载时读入内存，语法错误在创建时返回。使用了尚未安装的函数的模板在渲染时返回解析错误，
+// 调用 InstallFuncMap 后按新的函数映射重新解析。
 type FileTemplateEngine struct {
 	root      string
+	funcs     template.FuncMap
+	sources   map[string]string
 	templates map[string]*template.Template
+	errs      map[string]error
 	mu        sync.RWMutex
 }
 
 // NewFileTemplateEngine 创建并预加载模板目录（支持 .tpl/.tmpl 后缀）
 func NewFileTemplateEngine(root string) (*FileTemplateEngine, error) {
+	return NewFileTemplateEngineWithFuncs(root, nil)
+}
+
+// NewFileTemplateEngineWithFuncs 创建并预加载模板目录，解析模板前安装 funcs
+func NewFileTemplateEngineWithFuncs(root string, funcs template.FuncMap) (*FileTemplateEngine, error) {
 	e := &FileTemplateEngine{
 		root:      root,
+		funcs:     template.FuncMap{},
+		sources:   make(map[string]string),
 		templates: make(map[string]*template.Template),
+		errs:      make(map[string]error),
+	}
+	for k, v := range funcs {
+		e.funcs[k] = v
 	}
 	if root == "" {
 		root = "."
@@ -55,73 +73,100 @@ func (e *FileTemplateEngine) loadAll() error {
 		}
 		// use slash-separated template key
 		key := filepath.ToSlash(rel)
-		tmpl, err := template.ParseFiles(path)
+		b, err := os.ReadFile(path)
 		if err != nil {
 			return err
 		}
-		e.templates[key] = tmpl
+		if err := checkSyntax(key, string(b)); err != nil {
+			return err
+		}
+		e.sources[key] = string(b)
+		e.parse(key)
 		return nil
 	})
 }
 
+// checkSyntax 检查模板语法，不检查函数是否已定义
+func checkSyntax(key, text string) error {
+	tree := parse.New(key)
+	tree.Mode = parse.SkipFuncCheck
+	_, err := tree.Parse(text, "", "", make(map[string]*parse.Tree))
+	return err
+}
+
+// parse 按当前函数映射解析模板，调用方需持有写锁
+func (e *FileTemplateEngine) parse(key string) {
+	tmpl, err := template.New(filepath.Base(key)).Funcs(e.funcs).Parse(e.sources[key])
+	if err != nil {
+		delete(e.templates, key)
+		e.errs[key] = err
+		return
+	}
+	delete(e.errs, key)
+	e.templates[key] = tmpl
+}
+
 // Render 渲染指定模板（通过相对于 root 的路径名，如 "service/main.tpl"）
 func (e *FileTemplateEngine) Render(tplName string, data any) ([]byte, error) {
 	e.mu.RLock()
-	tmpl, ok := e.templates[tplName]
-	e.mu.RUnlock()
-
-	// 如果未找到，尝试尝试带/不带前缀的匹配（简单容错）
-	if !ok {
-		// 尝试去掉前导"./"
-		alt := strings.TrimPrefix(tplName, "./")
-		e.mu.RLock()
-		tmpl, ok = e.templates[alt]
-		e.mu.RUnlock()
-	}
-	if !ok {
-		// 最后尝试查找同名文件在子目录下（简单尝试）
-		e.mu.RLock()
-		for k, t := range e.templates {
-			if strings.HasSuffix(k, "/"+tplName) || k == tplName {
-				tmpl = t
-				ok = true
-				break
-			}
-		}
-		e.mu.RUnlock()
-	}
+	defer e.mu.RUnlock()
 
+	key, ok := e.lookup(tplName)
 	if !ok {
 		return nil, os.ErrNotExist
 	}
+	if err := e.errs[key]; err != nil {
+		return nil, err
+	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := e.templates[key].Execute(&buf, data); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
 }
 
+// lookup 查找模板名对应的键，调用方需持有读锁
+func (e *FileTemplateEngine) lookup(tplName string) (string, bool) {
+	if _, ok := e.sources[tplName]; ok {
+		return tplName, true
+	}
+
+	// 尝试去掉前导"./"
+	alt := strings.TrimPrefix(tplName, "./")
+	if _, ok := e.sources[alt]; ok {
+		return alt, true
+	}
+
+	// 最后尝试查找同名文件在子目录下（简单尝试）
+	for k := range e.sources {
+		if strings.HasSuffix(k, "/"+tplName) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
 // ListTemplates 列出可用模板名（相对于 root 的路径，使用 '/' 分隔）
 func (e *FileTemplateEngine) ListTemplates() []string {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	out := make([]string, 0, len(e.templates))
-	for k := range e.templates {
+	out := make([]string, 0, len(e.sources))
+	for k := range e.sources {
 		out = append(out, k)
 	}
 	return out
 }
 
+// InstallFuncMap 合并函数映射并按源码重新解析全部模板
 func (e *FileTemplateEngine) InstallFuncMap(funcs template.FuncMap) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	for name, tmpl := range e.templates {
-		t := tmpl.Funcs(funcs)
-		parsed, err := t.Parse(tmpl.Tree.Root.String())
-		if err == nil {
-			e.templates[name] = parsed
-		}
+	for k, v := range funcs {
+		e.funcs[k] = v
+	}
+	for key := range e.sources {
+		e.parse(key)
 	}
 }
@@ -98,3 +98,28 @@ func TestFileTemplateEngine_LoadListRenderAndInstallFuncMap(t *testing.T) {
 		t.Fatalf("unexpected func.tpl output after InstallFuncMap: %q", string(out))
 	}
 }
+
+func TestFileTemplateEngine_SyntaxError(t *testing.T) {
+	td := t.TempDir()
+	writeFile(t, td, "ok.tpl", "Upper: {{up .Val}}")
+	writeFile(t, td, "bad.tpl", "Hello {{.Name")
+
+	if _, err := NewFileTemplateEngine(td); err == nil {
+		t.Fatalf("expected syntax error from NewFileTemplateEngine")
+	}
+	if _, err := NewFileTemplateEngineWithFuncs(td, template.FuncMap{"up": strings.ToUpper}); err == nil {
+		t.Fatalf("expected syntax error from NewFileTemplateEngineWithFuncs")
+	}
+
+	// 只有未定义函数时创建成功，渲染时返回错误
+	if err := os.Remove(filepath.Join(td, "bad.tpl")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	engine, err := NewFileTemplateEngine(td)
+	if err != nil {
+		t.Fatalf("NewFileTemplateEngine error: %v", err)
+	}
+	if _, err = engine.Render("ok.tpl", map[string]string{"Val": "a"}); err == nil {
+		t.Fatalf("expected error rendering ok.tpl without func map")
+	}
+}
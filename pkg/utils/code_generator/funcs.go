@@ -0,0 +1,30 @@
+package code_generator
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/heyinLab/common/pkg/utils/stringcase"
+)
+
+// DefaultFuncMap 常用模板函数，用于渲染输出路径，也可以安装到模板引擎中
+//
+//	snake   UserProfile -> user_profile
+//	camel   user_profile -> userProfile
+//	pascal  user_profile -> UserProfile
+//	kebab   UserProfile -> user-profile
+func DefaultFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"snake":      stringcase.ToSnakeCase,
+		"camel":      stringcase.LowerCamelCase,
+		"pascal":     stringcase.UpperCamelCase,
+		"kebab":      stringcase.KebabCase,
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"replace":    strings.ReplaceAll,
+		"trimPrefix": strings.TrimPrefix,
+		"trimSuffix": strings.TrimSuffix,
+		"hasPrefix":  strings.HasPrefix,
+		"hasSuffix":  strings.HasSuffix,
+	}
+}
@@ -27,6 +27,32 @@ type Generator interface {
 	Generate(ctx context.Context, opts Options, tplName string) (outputPath string, err error)
 }
 
+// ScaffoldOptions 脚手架选项：渲染整棵模板树
+type ScaffoldOptions struct {
+	Options
+
+	// Root 模板树的根（例如 "service"），为空时渲染引擎中的全部模板，输出路径中去掉该前缀
+	Root string
+
+	// Policy 输出文件已存在时的默认处理策略，为空时覆盖
+	Policy Policy
+	// Rules 按输出路径匹配的文件规则，先匹配到的生效
+	Rules []FileRule
+
+	// BeginMarker、EndMarker 合并策略使用的用户代码区域标记，为空时使用 DefaultBeginMarker、DefaultEndMarker
+	BeginMarker string
+	EndMarker   string
+
+	// DryRun 只计算结果与差异，不写入磁盘
+	DryRun bool
+}
+
+// Scaffolder 脚手架生成器：渲染模板树并按策略写入输出目录
+type Scaffolder interface {
+	// Scaffold 渲染 opts.Root 下的全部模板，返回每个输出文件的处理结果
+	Scaffold(ctx context.Context, opts ScaffoldOptions) (*ScaffoldResult, error)
+}
+
 // TemplateEngine 模板引擎接口：加载/渲染/列出模板
 type TemplateEngine interface {
 	// Render 渲染指定模板并返回结果
@@ -0,0 +1,129 @@
+package code_generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultBeginMarker 用户代码区域的开始标记
+	DefaultBeginMarker = "BEGIN USER CODE"
+	// DefaultEndMarker 用户代码区域的结束标记
+	DefaultEndMarker = "END USER CODE"
+)
+
+var (
+	// ErrInvalidMarker 用户代码区域标记不成对或重名
+	ErrInvalidMarker = errors.New("code_generator: invalid user code marker")
+	// ErrMergeConflict 已有文件中的用户代码区域在新生成的内容中不存在
+	ErrMergeConflict = errors.New("code_generator: user code region removed from template")
+)
+
+// region 用户代码区域
+type region struct {
+	name string
+	body [][]byte
+}
+
+// markerName 返回标记行中的区域名，行中不含标记时返回 false
+//
+// 标记可以出现在任意注释语法中，如:
+//
+//	// BEGIN USER CODE: imports
+//	# BEGIN USER CODE: env
+//	<!-- BEGIN USER CODE: footer -->
+func markerName(line []byte, marker string) (string, bool) {
+	i := bytes.Index(line, []byte(marker))
+	if i < 0 {
+		return "", false
+	}
+	name := strings.TrimSpace(string(line[i+len(marker):]))
+	name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(name, "-->"), "*/"))
+	name = strings.TrimSpace(strings.TrimPrefix(name, ":"))
+	return name, true
+}
+
+// parseRegions 解析 src 中的用户代码区域，区域内容不含标记行
+func parseRegions(src []byte, begin, end string) ([]region, error) {
+	var (
+		regions []region
+		current *region
+		seen    = map[string]bool{}
+	)
+	for _, line := range bytes.SplitAfter(src, []byte("\n")) {
+		if name, ok := markerName(line, begin); ok {
+			if current != nil {
+				return nil, fmt.Errorf("%w: nested region %q in %q", ErrInvalidMarker, name, current.name)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("%w: duplicate region %q", ErrInvalidMarker, name)
+			}
+			seen[name] = true
+			current = &region{name: name}
+			continue
+		}
+		if name, ok := markerName(line, end); ok {
+			if current == nil || (name != "" && name != current.name) {
+				return nil, fmt.Errorf("%w: unexpected end of region %q", ErrInvalidMarker, name)
+			}
+			regions = append(regions, *current)
+			current = nil
+			continue
+		}
+		if current != nil {
+			current.body = append(current.body, line)
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("%w: region %q is not closed", ErrInvalidMarker, current.name)
+	}
+	return regions, nil
+}
+
+// mergeRegions 将 existing 中用户代码区域的内容写回新生成的 generated，区域以外的内容以 generated 为准
+func mergeRegions(generated, existing []byte, begin, end string) ([]byte, error) {
+	if _, err := parseRegions(generated, begin, end); err != nil {
+		return nil, err
+	}
+	regions, err := parseRegions(existing, begin, end)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[string][][]byte, len(regions))
+	for _, r := range regions {
+		kept[r.name] = r.body
+	}
+
+	var (
+		out      bytes.Buffer
+		skipping bool
+	)
+	for _, line := range bytes.SplitAfter(generated, []byte("\n")) {
+		if name, ok := markerName(line, begin); ok {
+			out.Write(line)
+			if body, found := kept[name]; found {
+				for _, l := range body {
+					out.Write(l)
+				}
+				delete(kept, name)
+				skipping = true
+			}
+			continue
+		}
+		if _, ok := markerName(line, end); ok {
+			skipping = false
+		}
+		if !skipping {
+			out.Write(line)
+		}
+	}
+
+	for _, r := range regions {
+		if _, lost := kept[r.name]; lost {
+			return nil, fmt.Errorf("%w: %q", ErrMergeConflict, r.name)
+		}
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,299 @@
+package code_generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/tools/imports"
+)
+
+// Policy 输出文件已存在时的处理策略
+type Policy string
+
+const (
+	// PolicyOverwrite 覆盖已有文件
+	PolicyOverwrite Policy = "overwrite"
+	// PolicySkip 保留已有文件，不写入
+	PolicySkip Policy = "skip"
+	// PolicyMerge 以新生成的内容为准，保留已有文件中用户代码区域的内容
+	PolicyMerge Policy = "merge"
+)
+
+// Action 对单个输出文件执行的操作
+type Action string
+
+const (
+	ActionCreate    Action = "create"    // 新建文件
+	ActionOverwrite Action = "overwrite" // 覆盖已有文件
+	ActionMerge     Action = "merge"     // 合并用户代码区域后写入
+	ActionSkip      Action = "skip"      // 已存在，按策略跳过
+	ActionUnchanged Action = "unchanged" // 内容相同，无需写入
+)
+
+// FileRule 文件规则
+type FileRule struct {
+	// Pattern 匹配输出路径（相对于 OutDir，'/' 分隔）的 path.Match 模式，
+	// 不含 '/' 时同时匹配文件名，例如 "*.go"、"internal/*/service.go"
+	Pattern string
+	// Policy 文件已存在时的处理策略，为空时使用 ScaffoldOptions.Policy
+	Policy Policy
+	// When 返回 false 时不生成该文件，参数为模板数据
+	When func(data map[string]any) bool
+}
+
+// FileResult 单个输出文件的处理结果
+type FileResult struct {
+	// Template 模板名
+	Template string
+	// Path 输出文件路径
+	Path string
+	// RelPath 相对于 OutDir 的输出路径，'/' 分隔
+	RelPath string
+	// Action 执行的操作
+	Action Action
+	// Diff 与已有文件的 unified diff，仅 DryRun 时计算
+	Diff string
+}
+
+// ScaffoldResult 脚手架生成结果，按输出路径排序
+type ScaffoldResult struct {
+	Files []FileResult
+}
+
+// Diff 全部文件的 unified diff
+func (r *ScaffoldResult) Diff() string {
+	var sb strings.Builder
+	for _, f := range r.Files {
+		sb.WriteString(f.Diff)
+	}
+	return sb.String()
+}
+
+// Changed 会新建或修改的文件
+func (r *ScaffoldResult) Changed() []FileResult {
+	var out []FileResult
+	for _, f := range r.Files {
+		if f.Action != ActionSkip && f.Action != ActionUnchanged {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Scaffold 渲染 opts.Root 下的全部模板并写入 opts.OutDir。
+//
+// 规则：
+//   - 模板名去掉 Root 前缀与 .tpl/.tmpl 后缀后作为输出路径，路径本身按模板渲染，
+//     如 "{{.Module}}/internal/{{snake .Name}}.go.tpl"；不会追加 FileExt
+//   - 路径渲染为空或含空目录（如 "{{if .Docker}}Dockerfile{{end}}"）时不生成该文件，FileRule.When 同理
+//   - .go 文件按 gofmt 格式化并整理 import
+//   - 输出文件已存在时按 FileRule.Policy 或 opts.Policy 处理
+func (g *CodeGenerator) Scaffold(ctx context.Context, opts ScaffoldOptions) (*ScaffoldResult, error) {
+	if g.Engine == nil {
+		return nil, os.ErrInvalid
+	}
+	if opts.BeginMarker == "" {
+		opts.BeginMarker = DefaultBeginMarker
+	}
+	if opts.EndMarker == "" {
+		opts.EndMarker = DefaultEndMarker
+	}
+
+	data := templateData(opts.Options)
+	root := strings.Trim(filepath.ToSlash(opts.Root), "/")
+
+	names := g.Engine.ListTemplates()
+	sort.Strings(names)
+
+	result := &ScaffoldResult{}
+	seen := map[string]string{}
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rel := strings.TrimPrefix(name, "./")
+		if root != "" {
+			if !strings.HasPrefix(rel, root+"/") {
+				continue
+			}
+			rel = strings.TrimPrefix(rel, root+"/")
+		}
+
+		rel, ok, err := g.renderPath(trimTemplateSuffix(rel), data)
+		if err != nil {
+			return nil, fmt.Errorf("code_generator: render path of %s: %w", name, err)
+		}
+		if !ok {
+			continue
+		}
+
+		rule := matchRule(opts.Rules, rel)
+		if rule.When != nil && !rule.When(data) {
+			continue
+		}
+		if other, dup := seen[rel]; dup {
+			return nil, fmt.Errorf("code_generator: %s and %s both render to %s: %w", other, name, rel, os.ErrExist)
+		}
+		seen[rel] = name
+
+		policy := rule.Policy
+		if policy == "" {
+			policy = opts.Policy
+		}
+		file, err := g.scaffoldFile(name, rel, policy, data, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].RelPath < result.Files[j].RelPath })
+	return result, nil
+}
+
+// scaffoldFile 渲染单个模板，按策略写入或计算差异
+func (g *CodeGenerator) scaffoldFile(name, rel string, policy Policy, data map[string]any, opts ScaffoldOptions) (FileResult, error) {
+	file := FileResult{
+		Template: name,
+		Path:     filepath.Join(opts.OutDir, filepath.FromSlash(rel)),
+		RelPath:  rel,
+	}
+
+	content, err := g.Engine.Render(name, data)
+	if err != nil {
+		return file, err
+	}
+
+	existing, err := os.ReadFile(file.Path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return file, err
+	}
+
+	switch {
+	case !exists:
+		file.Action = ActionCreate
+	case policy == PolicySkip:
+		file.Action = ActionSkip
+		return file, nil
+	case policy == PolicyMerge:
+		file.Action = ActionMerge
+		if content, err = mergeRegions(content, existing, opts.BeginMarker, opts.EndMarker); err != nil {
+			return file, fmt.Errorf("code_generator: merge %s: %w", rel, err)
+		}
+	case policy == "" || policy == PolicyOverwrite:
+		file.Action = ActionOverwrite
+	default:
+		return file, fmt.Errorf("code_generator: unknown policy %q: %w", policy, os.ErrInvalid)
+	}
+
+	if path.Ext(rel) == ".go" {
+		if content, err = imports.Process(file.Path, content, nil); err != nil {
+			return file, fmt.Errorf("code_generator: format %s: %w", rel, err)
+		}
+	}
+
+	if exists && bytes.Equal(content, existing) {
+		file.Action = ActionUnchanged
+		return file, nil
+	}
+
+	if opts.DryRun {
+		file.Diff, err = unifiedDiff(rel, existing, content, exists)
+		return file, err
+	}
+	return file, writeFileAtomic(file.Path, content)
+}
+
+// renderPath 渲染输出路径，路径为空或含空目录时返回 false
+func (g *CodeGenerator) renderPath(rel string, data map[string]any) (string, bool, error) {
+	if strings.Contains(rel, "{{") {
+		funcs := g.Funcs
+		if funcs == nil {
+			funcs = DefaultFuncMap()
+		}
+		tmpl, err := template.New(rel).Funcs(funcs).Parse(rel)
+		if err != nil {
+			return "", false, err
+		}
+		var buf strings.Builder
+		if err = tmpl.Execute(&buf, data); err != nil {
+			return "", false, err
+		}
+		rel = buf.String()
+		if strings.Contains(rel, "<no value>") {
+			return "", false, fmt.Errorf("missing value in %q: %w", rel, os.ErrInvalid)
+		}
+	}
+
+	rel = strings.TrimSpace(filepath.ToSlash(rel))
+	if rel == "" {
+		return "", false, nil
+	}
+	for _, segment := range strings.Split(rel, "/") {
+		if segment == "" {
+			return "", false, nil
+		}
+	}
+
+	// 禁止绝对路径与目录向上穿越
+	rel = path.Clean(rel)
+	if path.IsAbs(rel) || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false, fmt.Errorf("output path %q escapes OutDir: %w", rel, os.ErrInvalid)
+	}
+	return rel, true, nil
+}
+
+// matchRule 返回第一个匹配 rel 的规则
+func matchRule(rules []FileRule, rel string) FileRule {
+	for _, rule := range rules {
+		if ok, _ := path.Match(rule.Pattern, rel); ok {
+			return rule
+		}
+		if !strings.Contains(rule.Pattern, "/") {
+			if ok, _ := path.Match(rule.Pattern, path.Base(rel)); ok {
+				return rule
+			}
+		}
+	}
+	return FileRule{}
+}
+
+// unifiedDiff 计算 git 风格的 unified diff，新建文件以 /dev/null 为原文件
+func unifiedDiff(rel string, from, to []byte, exists bool) (string, error) {
+	fromFile := "a/" + rel
+	if !exists {
+		fromFile = "/dev/null"
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromFile,
+		ToFile:   "b/" + rel,
+		Context:  3,
+	})
+}
+
+// splitLines 按行拆分并保留换行符
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,233 @@
+package code_generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newScaffoldGenerator(t *testing.T, srcs map[string]string) *CodeGenerator {
+	t.Helper()
+	m := make(map[string][]byte, len(srcs))
+	for k, v := range srcs {
+		m[k] = []byte(v)
+	}
+	engine, err := NewEmbeddedTemplateEngineFromMap(m, DefaultFuncMap())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	return NewCodeGeneratorWithEngine(engine)
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s failed: %v", path, err)
+	}
+	return string(b)
+}
+
+func TestScaffold_RendersTreeWithPathTemplates(t *testing.T) {
+	tmp := t.TempDir()
+	g := newScaffoldGenerator(t, map[string]string{
+		"service/{{.ProjectName}}/internal/{{snake .Name}}.go.tpl": "package internal\nfunc  {{pascal .Name}}() string { return strings.ToUpper(\"{{.Name}}\") }\n",
+		"service/{{if .Docker}}Dockerfile{{end}}.tpl":              "FROM scratch\n",
+		"service/{{if .Docker}}deploy/{{end}}k8s.yaml.tpl":         "kind: Deployment\n",
+		"service/README.md.tmpl":                                   "# {{.ProjectName}}\n",
+		"other/ignored.tpl":                                        "ignored\n",
+	})
+
+	res, err := g.Scaffold(context.Background(), ScaffoldOptions{
+		Options: Options{
+			Module:      "github.com/example/mod",
+			ProjectName: "demo",
+			OutDir:      tmp,
+			Vars:        map[string]interface{}{"Name": "UserProfile", "Docker": false},
+		},
+		Root: "service",
+	})
+	if err != nil {
+		t.Fatalf("Scaffold failed: %v", err)
+	}
+
+	var got []string
+	for _, f := range res.Files {
+		if f.Action != ActionCreate {
+			t.Fatalf("unexpected action for %s: %s", f.RelPath, f.Action)
+		}
+		got = append(got, f.RelPath)
+	}
+	want := []string{"README.md", "demo/internal/user_profile.go", "k8s.yaml"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected files: got %v want %v", got, want)
+	}
+
+	// .go 文件经过格式化并补全 import
+	code := readFile(t, filepath.Join(tmp, "demo", "internal", "user_profile.go"))
+	if !strings.Contains(code, "import \"strings\"") || !strings.Contains(code, "func UserProfile() string {") {
+		t.Fatalf("go file not formatted: %q", code)
+	}
+	if _, err = os.Stat(filepath.Join(tmp, "Dockerfile")); !os.IsNotExist(err) {
+		t.Fatalf("conditional file should not be generated, stat err: %v", err)
+	}
+}
+
+func TestScaffold_Policies(t *testing.T) {
+	tmp := t.TempDir()
+	g := newScaffoldGenerator(t, map[string]string{
+		"config.yaml.tpl": "name: {{.ProjectName}}\n",
+		"notes.txt.tpl":   "generated {{.ProjectName}}\n",
+		"handler.go.tpl": `package handler
+
+// BEGIN USER CODE: imports
+// END USER CODE: imports
+
+func Name() string { return "{{.ProjectName}}" }
+
+// BEGIN USER CODE: extra
+func Extra() string { return "default" }
+// END USER CODE: extra
+`,
+	})
+	writeFile(t, tmp, "config.yaml", "name: edited\n")
+	writeFile(t, tmp, "notes.txt", "old\n")
+	writeFile(t, tmp, "handler.go", `package handler
+
+// BEGIN USER CODE: imports
+import "strings"
+// END USER CODE: imports
+
+func Name() string { return "old" }
+
+// BEGIN USER CODE: extra
+func Extra() string { return strings.ToUpper("custom") }
+// END USER CODE: extra
+`)
+
+	opts := ScaffoldOptions{
+		Options: Options{ProjectName: "demo", OutDir: tmp},
+		Policy:  PolicyOverwrite,
+		Rules: []FileRule{
+			{Pattern: "*.yaml", Policy: PolicySkip},
+			{Pattern: "*.go", Policy: PolicyMerge},
+		},
+	}
+	res, err := g.Scaffold(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Scaffold failed: %v", err)
+	}
+
+	actions := map[string]Action{}
+	for _, f := range res.Files {
+		actions[f.RelPath] = f.Action
+	}
+	if actions["config.yaml"] != ActionSkip || actions["notes.txt"] != ActionOverwrite || actions["handler.go"] != ActionMerge {
+		t.Fatalf("unexpected actions: %v", actions)
+	}
+
+	if got := readFile(t, filepath.Join(tmp, "config.yaml")); got != "name: edited\n" {
+		t.Fatalf("skipped file was modified: %q", got)
+	}
+	if got := readFile(t, filepath.Join(tmp, "notes.txt")); got != "generated demo\n" {
+		t.Fatalf("file was not overwritten: %q", got)
+	}
+	handler := readFile(t, filepath.Join(tmp, "handler.go"))
+	if !strings.Contains(handler, `return "demo"`) || !strings.Contains(handler, `strings.ToUpper("custom")`) || strings.Contains(handler, `"default"`) {
+		t.Fatalf("user code not merged: %q", handler)
+	}
+
+	// 再次生成时内容不变
+	res, err = g.Scaffold(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Scaffold failed: %v", err)
+	}
+	if changed := res.Changed(); len(changed) != 0 {
+		t.Fatalf("expected no changes, got %v", changed)
+	}
+}
+
+func TestScaffold_DryRun(t *testing.T) {
+	tmp := t.TempDir()
+	g := newScaffoldGenerator(t, map[string]string{
+		"a.txt.tpl": "line1\nline2 {{.ProjectName}}\n",
+		"b.txt.tpl": "new file\n",
+	})
+	writeFile(t, tmp, "a.txt", "line1\nline2 old\n")
+
+	res, err := g.Scaffold(context.Background(), ScaffoldOptions{
+		Options: Options{ProjectName: "demo", OutDir: tmp},
+		DryRun:  true,
+	})
+	if err != nil {
+		t.Fatalf("Scaffold failed: %v", err)
+	}
+
+	want := "--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n line1\n-line2 old\n+line2 demo\n" +
+		"--- /dev/null\n+++ b/b.txt\n@@ -0,0 +1 @@\n+new file\n"
+	if got := res.Diff(); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if got := readFile(t, filepath.Join(tmp, "a.txt")); got != "line1\nline2 old\n" {
+		t.Fatalf("dry run modified file: %q", got)
+	}
+	if _, err = os.Stat(filepath.Join(tmp, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("dry run created file, stat err: %v", err)
+	}
+}
+
+func TestScaffold_Errors(t *testing.T) {
+	tmp := t.TempDir()
+
+	g := newScaffoldGenerator(t, map[string]string{"{{.Dir}}/x.txt.tpl": "x"})
+	_, err := g.Scaffold(context.Background(), ScaffoldOptions{
+		Options: Options{OutDir: tmp, Vars: map[string]interface{}{"Dir": ".."}},
+	})
+	if !errors.Is(err, os.ErrInvalid) {
+		t.Fatalf("expected os.ErrInvalid for escaping path, got: %v", err)
+	}
+
+	_, err = g.Scaffold(context.Background(), ScaffoldOptions{Options: Options{OutDir: tmp}})
+	if !errors.Is(err, os.ErrInvalid) {
+		t.Fatalf("expected os.ErrInvalid for missing value, got: %v", err)
+	}
+
+	g = newScaffoldGenerator(t, map[string]string{"main.go.tpl": "package main\n\n// BEGIN USER CODE: a\n// END USER CODE: a\n"})
+	writeFile(t, tmp, "main.go", "package main\n\n// BEGIN USER CODE: b\n// END USER CODE: b\n")
+	_, err = g.Scaffold(context.Background(), ScaffoldOptions{Options: Options{OutDir: tmp}, Policy: PolicyMerge})
+	if !errors.Is(err, ErrMergeConflict) {
+		t.Fatalf("expected ErrMergeConflict, got: %v", err)
+	}
+
+	_, err = NewCodeGeneratorWithEngine(nil).Scaffold(context.Background(), ScaffoldOptions{})
+	if !errors.Is(err, os.ErrInvalid) {
+		t.Fatalf("expected os.ErrInvalid when engine is nil, got: %v", err)
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	generated := "# BEGIN USER CODE: env\nA=1\n# END USER CODE: env\n<!-- BEGIN USER CODE: footer -->\n<!-- END USER CODE: footer -->\n"
+	existing := "# BEGIN USER CODE: env\nA=2\nB=3\n# END USER CODE: env\n<!-- BEGIN USER CODE: footer -->\nfoot\n<!-- END USER CODE: footer -->\n"
+
+	got, err := mergeRegions([]byte(generated), []byte(existing), DefaultBeginMarker, DefaultEndMarker)
+	if err != nil {
+		t.Fatalf("mergeRegions failed: %v", err)
+	}
+	if string(got) != existing {
+		t.Fatalf("unexpected merge result: %q", string(got))
+	}
+
+	for _, bad := range []string{
+		"// BEGIN USER CODE: a\n",
+		"// END USER CODE: a\n",
+		"// BEGIN USER CODE: a\n// BEGIN USER CODE: b\n",
+		"// BEGIN USER CODE: a\n// END USER CODE: a\n// BEGIN USER CODE: a\n// END USER CODE: a\n",
+	} {
+		if _, err = mergeRegions([]byte(generated), []byte(bad), DefaultBeginMarker, DefaultEndMarker); !errors.Is(err, ErrInvalidMarker) {
+			t.Fatalf("expected ErrInvalidMarker for %q, got: %v", bad, err)
+		}
+	}
+}
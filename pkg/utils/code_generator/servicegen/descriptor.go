@@ -0,0 +1,121 @@
+package servicegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ErrInvalidDescriptor 描述符集合无法解析
+var ErrInvalidDescriptor = errors.New("servicegen: invalid descriptor set")
+
+// LoadDescriptorSet 读取编译好的描述符集合文件
+//
+// 支持以下格式：
+//   - protoc --include_imports --include_source_info -o out.binpb 生成的 FileDescriptorSet
+//   - buf build -o out.binpb 生成的 buf 镜像（与 FileDescriptorSet 兼容，buf 扩展字段会被忽略）
+//   - 以上两者的 JSON 格式（buf build -o out.json）
+func LoadDescriptorSet(path string) (*descriptorpb.FileDescriptorSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDescriptorSet(data)
+}
+
+// ParseDescriptorSet 解析二进制或 JSON 格式的描述符集合
+func ParseDescriptorSet(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(trimmed, set)
+	} else {
+		err = proto.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, set)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDescriptor, err)
+	}
+	if len(set.GetFile()) == 0 {
+		return nil, fmt.Errorf("%w: no files", ErrInvalidDescriptor)
+	}
+	return set, nil
+}
+
+// NewFiles 构建描述符集合中全部文件的注册表
+//
+// 集合中缺少的依赖（如未使用 --include_imports 时的 google/protobuf/*.proto）从 protoregistry.GlobalFiles 查找。
+func NewFiles(set *descriptorpb.FileDescriptorSet) (*protoregistry.Files, error) {
+	protos := make(map[string]*descriptorpb.FileDescriptorProto, len(set.GetFile()))
+	for _, fd := range set.GetFile() {
+		protos[fd.GetName()] = fd
+	}
+
+	files := &protoregistry.Files{}
+	resolver := &fallbackResolver{local: files}
+	visiting := map[string]bool{}
+
+	var register func(name string) error
+	register = func(name string) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+		fd, ok := protos[name]
+		if !ok {
+			// 不在集合中的依赖由 fallbackResolver 从全局注册表解析
+			if _, err := protoregistry.GlobalFiles.FindFileByPath(name); err != nil {
+				return fmt.Errorf("%w: missing dependency %s", ErrInvalidDescriptor, name)
+			}
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("%w: import cycle at %s", ErrInvalidDescriptor, name)
+		}
+		visiting[name] = true
+		for _, dep := range fd.GetDependency() {
+			if err := register(dep); err != nil {
+				return err
+			}
+		}
+
+		file, err := protodesc.NewFile(fd, resolver)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidDescriptor, err)
+		}
+		return files.RegisterFile(file)
+	}
+
+	for _, fd := range set.GetFile() {
+		if err := register(fd.GetName()); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// fallbackResolver 先从集合中查找，找不到时使用全局注册表
+type fallbackResolver struct {
+	local *protoregistry.Files
+}
+
+func (r *fallbackResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.local.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r *fallbackResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.local.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
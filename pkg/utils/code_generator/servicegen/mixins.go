@@ -0,0 +1,159 @@
+package servicegen
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// valueKind 字段在 proto 中的类型，用于匹配 entgo/mixin 中的字段
+type valueKind int
+
+const (
+	kindOther valueKind = iota
+	kindString
+	kindBool
+	kindInt32
+	kindInt64
+	kindUint32
+	kindUint64
+	kindTimestamp
+	kindStrings
+)
+
+// mixinField mixin 提供的字段
+type mixinField struct {
+	name     string
+	kind     valueKind
+	nillable bool // ent 字段为 Optional().Nillable()
+	settable bool // 由业务代码写入，CRUD 中需要赋值
+	expected bool // 更新时作为乐观锁的期望值写入
+}
+
+// mixinSpec entgo/mixin 中的 mixin 及其字段
+type mixinSpec struct {
+	name       string
+	fields     []mixinField
+	softDelete bool // 需要在 schema 中追加 SoftDelete 的钩子与拦截器
+}
+
+// nillable 同类型的 Optional().Nillable() 字段
+func nillable(kind valueKind, names ...string) []mixinField {
+	fields := make([]mixinField, len(names))
+	for i, name := range names {
+		fields[i] = mixinField{name: name, kind: kind, nillable: true}
+	}
+	return fields
+}
+
+// mixinSpecs 按顺序匹配，字段多的组合 mixin 在前，已被匹配的字段不再参与后续匹配
+var mixinSpecs = []mixinSpec{
+	{name: "Audit", softDelete: true, fields: append(
+		nillable(kindTimestamp, "created_at", "updated_at", "deleted_at"),
+		nillable(kindString, "created_by", "updated_by", "deleted_by")...,
+	)},
+	{name: "SoftDelete", fields: append(
+		nillable(kindTimestamp, "deleted_at"),
+		nillable(kindUint32, "deleted_by")...,
+	)},
+	{name: "TimeAt", fields: nillable(kindTimestamp, "created_at", "updated_at", "deleted_at")},
+	{name: "Time", fields: nillable(kindTimestamp, "create_time", "update_time", "delete_time")},
+	{name: "TimestampAt", fields: nillable(kindInt64, "created_at", "updated_at", "deleted_at")},
+	{name: "Timestamp", fields: nillable(kindInt64, "create_time", "update_time", "delete_time")},
+	{name: "CreatedAt", fields: nillable(kindTimestamp, "created_at")},
+	{name: "UpdatedAt", fields: nillable(kindTimestamp, "updated_at")},
+	{name: "DeletedAt", fields: nillable(kindTimestamp, "deleted_at")},
+	{name: "CreateTime", fields: nillable(kindTimestamp, "create_time")},
+	{name: "UpdateTime", fields: nillable(kindTimestamp, "update_time")},
+	{name: "DeleteTime", fields: nillable(kindTimestamp, "delete_time")},
+	{name: "CreatedAtTimestamp", fields: nillable(kindInt64, "created_at")},
+	{name: "UpdatedAtTimestamp", fields: nillable(kindInt64, "updated_at")},
+	{name: "DeletedAtTimestamp", fields: nillable(kindInt64, "deleted_at")},
+	{name: "CreateTimestamp", fields: nillable(kindInt64, "create_time")},
+	{name: "UpdateTimestamp", fields: nillable(kindInt64, "update_time")},
+	{name: "DeleteTimestamp", fields: nillable(kindInt64, "delete_time")},
+	{name: "OperatorID", fields: nillable(kindUint32, "created_by", "updated_by", "deleted_by")},
+	{name: "OperatorCode", fields: nillable(kindString, "created_by", "updated_by", "deleted_by")},
+	{name: "CreatedBy", fields: nillable(kindUint32, "created_by")},
+	{name: "UpdatedBy", fields: nillable(kindUint32, "updated_by")},
+	{name: "DeletedBy", fields: nillable(kindUint32, "deleted_by")},
+	{name: "CreatedByCode", fields: nillable(kindString, "created_by")},
+	{name: "UpdatedByCode", fields: nillable(kindString, "updated_by")},
+	{name: "DeletedByCode", fields: nillable(kindString, "deleted_by")},
+	{name: "CreateBy", fields: nillable(kindUint32, "create_by")},
+	{name: "UpdateBy", fields: nillable(kindUint32, "update_by")},
+	{name: "DeleteBy", fields: nillable(kindUint32, "delete_by")},
+	{name: "TenantID", fields: nillable(kindUint32, "tenant_id")},
+	{name: "CreatorId", fields: nillable(kindUint32, "creator_id")},
+	{name: "Version", fields: []mixinField{{name: "version", kind: kindUint32, expected: true}}},
+	{name: "SortOrder", fields: []mixinField{{name: "sort_order", kind: kindInt32, nillable: true, settable: true}}},
+	{name: "Remark", fields: []mixinField{{name: "remark", kind: kindString, nillable: true, settable: true}}},
+	{name: "Description", fields: []mixinField{{name: "description", kind: kindString, nillable: true, settable: true}}},
+	{name: "IsEnabled", fields: []mixinField{{name: "is_enabled", kind: kindBool, nillable: true, settable: true}}},
+	{name: "Tag", fields: []mixinField{{name: "tags", kind: kindStrings, settable: true}}},
+}
+
+// idMixins 按 id 字段类型选择的主键 mixin
+var idMixins = map[valueKind]string{
+	kindUint64: "SnowflackId",
+	kindUint32: "AutoIncrementId",
+	kindString: "StringId",
+}
+
+// kindOf 字段的 valueKind，不对应任何 mixin 字段类型时返回 kindOther
+func kindOf(fd protoreflect.FieldDescriptor) valueKind {
+	if fd.IsMap() {
+		return kindOther
+	}
+	if fd.IsList() {
+		if fd.Kind() == protoreflect.StringKind {
+			return kindStrings
+		}
+		return kindOther
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return kindString
+	case protoreflect.BoolKind:
+		return kindBool
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return kindInt32
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return kindInt64
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return kindUint32
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return kindUint64
+	case protoreflect.MessageKind:
+		if fd.Message().FullName() == timestampName {
+			return kindTimestamp
+		}
+	}
+	return kindOther
+}
+
+// matchMixins 按字段名与类型匹配 mixin，返回匹配到的 mixin 与被 mixin 覆盖的字段
+//
+// fields 中不应包含 id 与 oneof 中的字段。
+func matchMixins(fields map[string]protoreflect.FieldDescriptor) ([]mixinSpec, map[string]mixinField) {
+	var (
+		matched []mixinSpec
+		covered = map[string]mixinField{}
+	)
+	for _, spec := range mixinSpecs {
+		ok := true
+		for _, f := range spec.fields {
+			fd, found := fields[f.name]
+			if !found || covered[f.name].name != "" || kindOf(fd) != f.kind {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		matched = append(matched, spec)
+		for _, f := range spec.fields {
+			covered[f.name] = f
+		}
+	}
+	return matched, covered
+}
@@ -0,0 +1,624 @@
+package servicegen
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/heyinLab/common/pkg/utils/stringcase"
+)
+
+const (
+	timestampName protoreflect.FullName = "google.protobuf.Timestamp"
+	structName    protoreflect.FullName = "google.protobuf.Struct"
+	emptyName     protoreflect.FullName = "google.protobuf.Empty"
+)
+
+// GoImport Go 包导入
+type GoImport struct {
+	Alias string
+	Path  string
+}
+
+// String 导入语句，如 v1 "github.com/heyinLab/common/api/gen/go/system/v1"
+func (i GoImport) String() string {
+	return i.Alias + " " + strconv.Quote(i.Path)
+}
+
+// Service 服务模型，用于渲染客户端封装与测试服务
+type Service struct {
+	// Name 服务名，如 SystemInternalService
+	Name string
+	// Comment 服务注释
+	Comment string
+	// Package 客户端包名，如 system
+	Package string
+	// ClientType 客户端类型名，如 SystemClient
+	ClientType string
+	// ServerName 服务发现使用的服务名，如 system-server
+	ServerName string
+	// Module 日志模块名，如 system-client
+	Module string
+	// Proto 服务所在 Go 包的别名
+	Proto string
+	// Imports 方法签名用到的 Go 包
+	Imports []GoImport
+	// Methods 服务方法
+	Methods []*Method
+}
+
+// Method 服务方法
+type Method struct {
+	// Name rpc 名，如 InternalGetCountryInfo
+	Name string
+	// WrapperName 客户端封装的方法名，去掉 Internal 前缀，如 GetCountryInfo
+	WrapperName string
+	// Comment 方法注释，为空时为 "调用 <Name>"
+	Comment string
+	// Input、Output 请求与响应类型，不含 *，如 v1.InternalGetCountryInfoRequest
+	Input  string
+	Output string
+	// Result 客户端封装的返回类型，响应为 google.protobuf.Empty 时为空
+	Result string
+	// ResultField 响应只有一个字段时返回该字段，为空时返回整个响应
+	ResultField string
+	// Zero Result 类型的零值
+	Zero string
+
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// Entity 实体模型，用于渲染 ent schema 与 CRUD 服务
+type Entity struct {
+	// Name 实体名，如 Country
+	Name string
+	// File 输出文件名（不含扩展名），如 country
+	File string
+	// Comment 实体注释
+	Comment string
+	// Package ent 生成的实体包名，如 country
+	Package string
+	// Message proto 消息类型，如 v1.InternalCountry
+	Message string
+	// SchemaImports ent schema 用到的 proto Go 包
+	SchemaImports []GoImport
+	// Imports CRUD 服务用到的 Go 包，包含 ent 生成代码
+	Imports []GoImport
+
+	// IDType 主键的 Go 类型
+	IDType string
+	// Mixins 使用的 entgo/mixin，如 mixin.SnowflackId{}
+	Mixins []string
+	// SoftDelete 需要追加 SoftDelete 的钩子与拦截器
+	SoftDelete bool
+	// Fields ent 字段定义
+	Fields []string
+	// Notes 无法映射的字段说明
+	Notes []string
+
+	// CreateSetters 仅创建时执行的赋值语句，builder 为 b
+	CreateSetters []string
+	// Setters 创建与更新时执行的赋值语句，proto 消息为 m，builder 为 b
+	Setters []string
+	// UpdateSetters 仅更新时执行的赋值语句，如乐观锁的期望版本号
+	UpdateSetters []string
+	// Converters ent 实体 e 转换为 proto 消息 m 的赋值语句
+	Converters []string
+	// DefaultOrder 列表查询的默认排序字段
+	DefaultOrder string
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// qualifier 为 proto 类型分配 Go 包别名
+type qualifier struct {
+	prefix  string
+	aliases map[string]string
+	used    map[string]bool
+	imports []GoImport
+}
+
+func newQualifier(prefix string) *qualifier {
+	return &qualifier{
+		prefix:  prefix,
+		aliases: map[string]string{},
+		used:    map[string]bool{},
+	}
+}
+
+// importPath 导入 Go 包，返回别名
+//
+// 依次尝试 names 中的别名，均已被占用时在第一个别名后追加序号。
+func (q *qualifier) importPath(importPath string, names ...string) string {
+	if alias, ok := q.aliases[importPath]; ok {
+		return alias
+	}
+	alias := ""
+	for _, name := range names {
+		if !q.used[name] {
+			alias = name
+			break
+		}
+	}
+	for i := 2; alias == ""; i++ {
+		if name := names[0] + "_" + strconv.Itoa(i); !q.used[name] {
+			alias = name
+		}
+	}
+	q.aliases[importPath] = alias
+	q.used[alias] = true
+	q.imports = append(q.imports, GoImport{Alias: alias, Path: importPath})
+	return alias
+}
+
+// importFile 导入 proto 文件对应的 Go 包
+//
+// 导入路径以版本目录结尾时（如 system/v1）优先使用版本号作为别名，与手写客户端的 v1 一致，
+// 否则使用 Go 包名。
+func (q *qualifier) importFile(file protoreflect.FileDescriptor) string {
+	importPath, name := goPackage(file, q.prefix)
+	if base := path.Base(importPath); versionDir.MatchString(base) && base != name {
+		return q.importPath(importPath, base, name)
+	}
+	return q.importPath(importPath, name)
+}
+
+// typeName proto 消息或枚举的 Go 类型名，如 v1.InternalCountry、v1.InternalItem_Dimension
+func (q *qualifier) typeName(d protoreflect.Descriptor) string {
+	file := d.ParentFile()
+	name := strings.TrimPrefix(string(d.FullName()), string(file.Package())+".")
+	return q.importFile(file) + "." + goCamelCase(name)
+}
+
+// goType 字段的 Go 类型
+func (q *qualifier) goType(fd protoreflect.FieldDescriptor) string {
+	if fd.IsMap() {
+		return "map[" + q.elemType(fd.MapKey()) + "]" + q.elemType(fd.MapValue())
+	}
+	if fd.IsList() {
+		return "[]" + q.elemType(fd)
+	}
+	if isPointerScalar(fd) {
+		return "*" + q.elemType(fd)
+	}
+	return q.elemType(fd)
+}
+
+// elemType 不考虑 repeated 与 optional 的 Go 类型
+func (q *qualifier) elemType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "*" + q.typeName(fd.Message())
+	case protoreflect.EnumKind:
+		return q.typeName(fd.Enum())
+	}
+	return scalarTypes[fd.Kind()]
+}
+
+var scalarTypes = map[protoreflect.Kind]string{
+	protoreflect.BoolKind:     "bool",
+	protoreflect.StringKind:   "string",
+	protoreflect.BytesKind:    "[]byte",
+	protoreflect.DoubleKind:   "float64",
+	protoreflect.FloatKind:    "float32",
+	protoreflect.Int32Kind:    "int32",
+	protoreflect.Sint32Kind:   "int32",
+	protoreflect.Sfixed32Kind: "int32",
+	protoreflect.Int64Kind:    "int64",
+	protoreflect.Sint64Kind:   "int64",
+	protoreflect.Sfixed64Kind: "int64",
+	protoreflect.Uint32Kind:   "uint32",
+	protoreflect.Fixed32Kind:  "uint32",
+	protoreflect.Uint64Kind:   "uint64",
+	protoreflect.Fixed64Kind:  "uint64",
+}
+
+// entFieldTypes 标量对应的 ent 字段构造函数
+var entFieldTypes = map[protoreflect.Kind]string{
+	protoreflect.BoolKind:     "Bool",
+	protoreflect.StringKind:   "String",
+	protoreflect.BytesKind:    "Bytes",
+	protoreflect.DoubleKind:   "Float",
+	protoreflect.FloatKind:    "Float32",
+	protoreflect.Int32Kind:    "Int32",
+	protoreflect.Sint32Kind:   "Int32",
+	protoreflect.Sfixed32Kind: "Int32",
+	protoreflect.EnumKind:     "Int32",
+	protoreflect.Int64Kind:    "Int64",
+	protoreflect.Sint64Kind:   "Int64",
+	protoreflect.Sfixed64Kind: "Int64",
+	protoreflect.Uint32Kind:   "Uint32",
+	protoreflect.Fixed32Kind:  "Uint32",
+	protoreflect.Uint64Kind:   "Uint64",
+	protoreflect.Fixed64Kind:  "Uint64",
+}
+
+// isPointerScalar 字段在生成代码中是否为标量指针（proto3 optional 或 proto2 标量）
+func isPointerScalar(fd protoreflect.FieldDescriptor) bool {
+	return fd.HasPresence() && !fd.IsList() && fd.Message() == nil
+}
+
+// isRealOneof 字段是否属于非 proto3 optional 的 oneof
+func isRealOneof(fd protoreflect.FieldDescriptor) bool {
+	oneof := fd.ContainingOneof()
+	return oneof != nil && !oneof.IsSynthetic()
+}
+
+var (
+	packageNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9_]`)
+	versionDir           = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// goPackage 文件对应的 Go 包导入路径与包名
+//
+// go_package 不含域名（如 "system/v1;v1"）且设置了 prefix 时，与 buf managed 模式的 go_package_prefix 一致，
+// 导入路径为 prefix/system/v1；go_package 为空时使用 proto 文件所在目录。
+func goPackage(file protoreflect.FileDescriptor, prefix string) (string, string) {
+	var goPkg string
+	if opts, ok := file.Options().(*descriptorpb.FileOptions); ok {
+		goPkg = opts.GetGoPackage()
+	}
+	importPath, name, _ := strings.Cut(goPkg, ";")
+	if importPath == "" {
+		importPath = path.Dir(file.Path())
+	}
+	if first, _, _ := strings.Cut(importPath, "/"); prefix != "" && !strings.Contains(first, ".") {
+		importPath = path.Join(prefix, importPath)
+	}
+	if name == "" {
+		name = packageNameSanitizer.ReplaceAllString(path.Base(importPath), "_")
+	}
+	return importPath, name
+}
+
+// leadingComment 描述符的第一行前置注释
+func leadingComment(d protoreflect.Descriptor) string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	for _, line := range strings.Split(loc.LeadingComments, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// buildService 构建服务模型
+func buildService(sd protoreflect.ServiceDescriptor, prefix string) *Service {
+	q := newQualifier(prefix)
+	title := trimAffix(trimAffix(string(sd.Name()), "", "Service"), "", "Internal")
+
+	svc := &Service{
+		Name:       string(sd.Name()),
+		Comment:    leadingComment(sd),
+		Package:    strings.ToLower(title),
+		ClientType: title + "Client",
+		ServerName: stringcase.KebabCase(title) + "-server",
+		Module:     stringcase.KebabCase(title) + "-client",
+		Proto:      q.importFile(sd.ParentFile()),
+	}
+	if svc.Comment == "" {
+		svc.Comment = title + "服务"
+	}
+
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		m := &Method{
+			Name:            string(md.Name()),
+			WrapperName:     trimAffix(string(md.Name()), "Internal", ""),
+			Comment:         leadingComment(md),
+			Input:           q.typeName(md.Input()),
+			Output:          q.typeName(md.Output()),
+			ClientStreaming: md.IsStreamingClient(),
+			ServerStreaming: md.IsStreamingServer(),
+		}
+		if m.Comment == "" {
+			m.Comment = "调用 " + m.Name
+		}
+
+		out := md.Output()
+		switch fields := out.Fields(); {
+		case out.FullName() == emptyName:
+		case fields.Len() == 1 && !isRealOneof(fields.Get(0)):
+			m.Result = q.goType(fields.Get(0))
+			m.ResultField = goCamelCase(string(fields.Get(0).Name()))
+		default:
+			m.Result = "*" + m.Output
+		}
+		m.Zero = zeroValue(m.Result)
+		svc.Methods = append(svc.Methods, m)
+	}
+	svc.Imports = q.imports
+	return svc
+}
+
+// zeroValue Go 类型的零值
+func zeroValue(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["):
+		return "nil"
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	}
+	return "0"
+}
+
+// entityMessages 服务方法响应中的实体消息
+//
+// 对每个非流式方法，响应中唯一一个与服务同包的消息字段（可以是 repeated）视为实体，
+// 如 InternalGetCountryInfoResponse.country、InternalListCountriesResponse.countries。
+func entityMessages(sd protoreflect.ServiceDescriptor) []protoreflect.MessageDescriptor {
+	var (
+		out  []protoreflect.MessageDescriptor
+		seen = map[protoreflect.FullName]bool{}
+	)
+	methods := sd.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		if md.IsStreamingClient() || md.IsStreamingServer() {
+			continue
+		}
+
+		var candidate protoreflect.MessageDescriptor
+		count := 0
+		fields := md.Output().Fields()
+		for j := 0; j < fields.Len(); j++ {
+			fd := fields.Get(j)
+			if fd.IsMap() || fd.Message() == nil || fd.Message().ParentFile().Package() != sd.ParentFile().Package() {
+				continue
+			}
+			candidate = fd.Message()
+			count++
+		}
+		if count == 1 && !seen[candidate.FullName()] {
+			seen[candidate.FullName()] = true
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+// buildEntity 构建实体模型，entPackage 为 ent 生成代码的导入路径
+func buildEntity(md protoreflect.MessageDescriptor, prefix, entPackage string) *Entity {
+	name := trimAffix(string(md.Name()), "Internal", "Info")
+	pkg := strings.ToLower(name)
+
+	// 模板中直接使用的包名不能分配给 proto 包
+	q := newQualifier(prefix)
+	for _, reserved := range []string{"context", "ent", "entgoQuery", "field", "log", "mixin", "schema", pkg} {
+		q.used[reserved] = true
+	}
+
+	e := &Entity{
+		Name:    name,
+		File:    stringcase.ToSnakeCase(name),
+		Comment: leadingComment(md),
+		Package: pkg,
+		Message: q.typeName(md),
+	}
+	if e.Comment == "" {
+		e.Comment = name
+	}
+
+	fields := md.Fields()
+	byName := make(map[string]protoreflect.FieldDescriptor, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); fd.Name() != "id" && !isRealOneof(fd) {
+			byName[string(fd.Name())] = fd
+		}
+	}
+
+	// 主键
+	e.IDType = "int"
+	if fd := fields.ByName("id"); fd != nil && !isRealOneof(fd) && !fd.IsList() && !fd.IsMap() && scalarTypes[fd.Kind()] != "" {
+		e.IDType = scalarTypes[fd.Kind()]
+		if mixin, ok := idMixins[kindOf(fd)]; ok {
+			e.Mixins = append(e.Mixins, "mixin."+mixin+"{}")
+		} else {
+			e.Fields = append(e.Fields, fmt.Sprintf("field.%s(%q)", entFieldTypes[fd.Kind()], "id"))
+		}
+		if kindOf(fd) != kindUint64 && kindOf(fd) != kindUint32 {
+			e.CreateSetters = append(e.CreateSetters, "b.SetID(m.GetId())")
+		}
+		e.Converters = append(e.Converters, "m.Id = e.ID")
+	}
+
+	matched, covered := matchMixins(byName)
+	for _, spec := range matched {
+		e.Mixins = append(e.Mixins, "mixin."+spec.name+"{}")
+		e.SoftDelete = e.SoftDelete || spec.softDelete
+	}
+
+	oneofs := map[protoreflect.FullName]bool{}
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Name() == "id" {
+			continue
+		}
+		if isRealOneof(fd) {
+			oneof := fd.ContainingOneof()
+			if !oneofs[oneof.FullName()] {
+				oneofs[oneof.FullName()] = true
+				e.Notes = append(e.Notes, oneofNote(oneof))
+			}
+			continue
+		}
+
+		c := conversion{
+			q:         q,
+			fd:        fd,
+			entName:   entPascal(string(fd.Name())),
+			protoName: goCamelCase(string(fd.Name())),
+		}
+		if f, ok := covered[string(fd.Name())]; ok {
+			c.nillable = f.nillable
+			switch {
+			case f.settable:
+				e.Setters = append(e.Setters, c.setter())
+			case f.expected:
+				e.UpdateSetters = append(e.UpdateSetters, c.setter())
+			}
+			e.Converters = append(e.Converters, c.toProto())
+			continue
+		}
+
+		field, nillable := entField(q, fd)
+		c.nillable = nillable
+		e.Fields = append(e.Fields, field)
+		e.Setters = append(e.Setters, c.setter())
+		e.Converters = append(e.Converters, c.toProto())
+	}
+
+	e.DefaultOrder = e.Package + ".FieldID"
+	for _, name := range []string{"created_at", "create_time"} {
+		if _, ok := byName[name]; ok {
+			e.DefaultOrder = e.Package + ".Field" + entPascal(name)
+			break
+		}
+	}
+
+	e.SchemaImports = append([]GoImport(nil), q.imports...)
+	e.Imports = q.imports
+	if entPackage != "" {
+		e.Imports = append(e.Imports,
+			GoImport{Alias: "ent", Path: entPackage},
+			GoImport{Alias: pkg, Path: entPackage + "/" + pkg},
+		)
+	}
+	return e
+}
+
+// oneofNote oneof 字段的说明
+func oneofNote(oneof protoreflect.OneofDescriptor) string {
+	var names []string
+	fields := oneof.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		names = append(names, string(fields.Get(i).Name()))
+	}
+	return fmt.Sprintf("oneof %s（%s）未生成字段，需要手动映射", oneof.Name(), strings.Join(names, "、"))
+}
+
+// entField 非 mixin 字段的 ent 定义，返回定义与 ent 字段是否为指针
+func entField(q *qualifier, fd protoreflect.FieldDescriptor) (string, bool) {
+	name := string(fd.Name())
+
+	var (
+		def      string
+		nillable bool
+	)
+	switch {
+	case fd.IsMap(), fd.IsList() && fd.Kind() != protoreflect.StringKind:
+		def = fmt.Sprintf("field.JSON(%q, %s{}).\nOptional()", name, jsonType(q, fd))
+	case fd.IsList():
+		def = fmt.Sprintf("field.Strings(%q).\nOptional()", name)
+	case isTimestamp(fd):
+		def = fmt.Sprintf("field.Time(%q).\nOptional().\nNillable()", name)
+		nillable = true
+	case isStruct(fd):
+		def = fmt.Sprintf("field.JSON(%q, map[string]any{}).\nOptional()", name)
+	case fd.Message() != nil:
+		def = fmt.Sprintf("field.JSON(%q, %s{}).\nOptional()", name, jsonType(q, fd))
+	case fd.HasPresence():
+		def = fmt.Sprintf("field.%s(%q).\nOptional().\nNillable()", entFieldTypes[fd.Kind()], name)
+		nillable = true
+	default:
+		def = fmt.Sprintf("field.%s(%q)", entFieldTypes[fd.Kind()], name)
+	}
+
+	if comment := leadingComment(fd); comment != "" {
+		def += fmt.Sprintf(".\nComment(%q)", comment)
+	}
+	return def, nillable
+}
+
+// jsonType field.JSON 使用的 Go 值，如 &v1.InternalItem_Dimension、[]int64、map[string]string
+func jsonType(q *qualifier, fd protoreflect.FieldDescriptor) string {
+	typ := q.goType(fd)
+	if strings.HasPrefix(typ, "*") {
+		return "&" + typ[1:]
+	}
+	return typ
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// conversion proto 字段与 ent 字段之间的赋值语句
+type conversion struct {
+	q         *qualifier
+	fd        protoreflect.FieldDescriptor
+	entName   string // ent 字段的 Go 名，如 TenantID
+	protoName string // proto 字段的 Go 名，如 TenantId
+	nillable  bool   // ent 字段为指针
+}
+
+// setter 将 proto 消息 m 的字段写入 builder b
+func (c conversion) setter() string {
+	fd := c.fd
+	switch {
+	case isTimestamp(fd):
+		return fmt.Sprintf("if m.%s != nil {\nb.Set%s(m.%s.AsTime())\n}", c.protoName, c.entName, c.protoName)
+	case isStruct(fd):
+		return fmt.Sprintf("b.Set%s(m.Get%s().AsMap())", c.entName, c.protoName)
+	case isPointerScalar(fd) && fd.Enum() != nil:
+		return fmt.Sprintf("if m.%s != nil {\nb.Set%s(int32(*m.%s))\n}", c.protoName, c.entName, c.protoName)
+	case fd.Enum() != nil && !fd.IsList() && !fd.IsMap():
+		return fmt.Sprintf("b.Set%s(int32(m.Get%s()))", c.entName, c.protoName)
+	case isPointerScalar(fd) && c.nillable:
+		return fmt.Sprintf("b.SetNillable%s(m.%s)", c.entName, c.protoName)
+	case isPointerScalar(fd):
+		return fmt.Sprintf("if m.%s != nil {\nb.Set%s(*m.%s)\n}", c.protoName, c.entName, c.protoName)
+	}
+	return fmt.Sprintf("b.Set%s(m.Get%s())", c.entName, c.protoName)
+}
+
+// toProto 将 ent 实体 e 的字段写入 proto 消息 m
+func (c conversion) toProto() string {
+	fd := c.fd
+	from := "e." + c.entName
+	if c.nillable {
+		from = "*" + from
+	}
+
+	var value string
+	switch {
+	case isTimestamp(fd):
+		value = fmt.Sprintf("%s.New(%s)", c.q.importFile(fd.Message().ParentFile()), from)
+	case isStruct(fd):
+		return fmt.Sprintf("if e.%s != nil {\nm.%s, _ = %s.NewStruct(e.%s)\n}",
+			c.entName, c.protoName, c.q.importFile(fd.Message().ParentFile()), c.entName)
+	case fd.Enum() != nil && !fd.IsList() && !fd.IsMap():
+		value = fmt.Sprintf("%s(%s)", c.q.typeName(fd.Enum()), from)
+		if isPointerScalar(fd) {
+			value += ".Enum()"
+		}
+	case isPointerScalar(fd) && c.nillable:
+		return fmt.Sprintf("m.%s = e.%s", c.protoName, c.entName)
+	case isPointerScalar(fd):
+		value = "&e." + c.entName
+	default:
+		value = from
+	}
+
+	if c.nillable {
+		return fmt.Sprintf("if e.%s != nil {\nm.%s = %s\n}", c.entName, c.protoName, value)
+	}
+	return fmt.Sprintf("m.%s = %s", c.protoName, value)
+}
+
+func isTimestamp(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsList() && !fd.IsMap() && fd.Message() != nil && fd.Message().FullName() == timestampName
+}
+
+func isStruct(fd protoreflect.FieldDescriptor) bool {
+	return !fd.IsList() && !fd.IsMap() && fd.Message() != nil && fd.Message().FullName() == structName
+}
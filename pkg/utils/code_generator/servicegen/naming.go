@@ -0,0 +1,72 @@
+package servicegen
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// entAcronyms ent 生成代码时保持全大写的缩写，与 entc/gen 相同
+var entAcronyms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "AWS": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GB": true, "GUID": true, "HCL": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "KB": true, "LHS": true, "MAC": true, "MB": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "SSO": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "UID": true, "URI": true, "URL": true, "UTF8": true, "UUID": true,
+	"VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// entPascal ent 生成的字段名，如 default_language_id -> DefaultLanguageID
+func entPascal(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	for i, w := range words {
+		if upper := strings.ToUpper(w); entAcronyms[upper] {
+			words[i] = upper
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return strings.Join(words, "")
+}
+
+// goCamelCase protoc-gen-go 生成的字段名，如 default_language_id -> DefaultLanguageId
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// 跳过 '.'，后面的小写字母在下一轮转为大写
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// 跳过 '_'，后面的小写字母在下一轮转为大写
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool { return 'a' <= c && c <= 'z' }
+func isASCIIDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// trimAffix 去掉 s 的前缀 prefix 与后缀 suffix，结果为空时返回 s
+func trimAffix(s, prefix, suffix string) string {
+	out := strings.TrimSuffix(strings.TrimPrefix(s, prefix), suffix)
+	if out == "" {
+		return s
+	}
+	return out
+}
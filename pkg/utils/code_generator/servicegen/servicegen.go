@@ -0,0 +1,286 @@
+// Package servicegen 根据编译好的 proto 描述符生成服务脚手架
+//
+// 读取 protoc/buf 生成的 FileDescriptorSet 或 buf 镜像，为每个服务生成：
+//   - 客户端封装 <pkg>/client.go、<pkg>/config.go，与 pkg/system 等手写客户端结构一致
+//   - 测试服务 <pkg>/<pkg>test/server.go，基于 bufconn 的内存 gRPC 服务
+//   - ent schema 骨架 ent/schema/<entity>.go，按字段名与类型匹配 entgo/mixin
+//   - CRUD 服务桩 service/<entity>.go，列表查询使用 entgo/query
+//
+// 实体为服务方法响应中唯一的同包消息字段，如 InternalGetCountryInfoResponse.country。
+// CRUD 服务桩的列表查询使用 Modify，ent 代码需要以 --feature sql/modifier 生成。
+package servicegen
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/heyinLab/common/pkg/utils/code_generator"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Target 生成的代码类型
+type Target string
+
+const (
+	TargetClient  Target = "client"  // 客户端封装
+	TargetFake    Target = "fake"    // 测试服务
+	TargetSchema  Target = "schema"  // ent schema 骨架
+	TargetService Target = "service" // CRUD 服务桩
+)
+
+// AllTargets 全部代码类型
+var AllTargets = []Target{TargetClient, TargetFake, TargetSchema, TargetService}
+
+// defaultPolicies 各类型默认的文件处理策略：客户端与测试服务完全由生成器维护，骨架与服务桩只生成一次
+var defaultPolicies = map[Target]code_generator.Policy{
+	TargetClient:  code_generator.PolicyOverwrite,
+	TargetFake:    code_generator.PolicyOverwrite,
+	TargetSchema:  code_generator.PolicySkip,
+	TargetService: code_generator.PolicySkip,
+}
+
+// Options 生成选项
+type Options struct {
+	// OutDir 输出目录
+	OutDir string
+
+	// GoPackagePrefix 与 buf managed 模式的 go_package_prefix 相同，
+	// 用于 go_package 不含域名的文件，如 github.com/heyinLab/common/api/gen/go
+	GoPackagePrefix string
+	// EntPackage ent 生成代码的导入路径，如 github.com/heyinLab/xxx/internal/data/ent，生成 CRUD 服务桩时必填
+	EntPackage string
+
+	// Services 需要生成的服务，服务名或全名，为空时生成描述符集合中的全部服务
+	Services []string
+	// Entities 需要生成的实体消息，消息名或全名，为空时从服务方法的响应中识别
+	Entities []string
+	// Targets 生成的代码类型，为空时生成全部
+	Targets []Target
+
+	// Policy 输出文件已存在时的处理策略，为空时客户端与测试服务覆盖，ent schema 与服务桩跳过
+	Policy code_generator.Policy
+	// DryRun 只计算结果与差异，不写入磁盘
+	DryRun bool
+}
+
+// Generate 根据描述符集合生成服务脚手架，返回每个输出文件的处理结果
+func Generate(ctx context.Context, set *descriptorpb.FileDescriptorSet, opts Options) (*code_generator.ScaffoldResult, error) {
+	targets := map[Target]bool{}
+	for _, t := range opts.Targets {
+		if _, ok := defaultPolicies[t]; !ok {
+			return nil, fmt.Errorf("servicegen: unknown target %q: %w", t, os.ErrInvalid)
+		}
+		targets[t] = true
+	}
+	if len(targets) == 0 {
+		for _, t := range AllTargets {
+			targets[t] = true
+		}
+	}
+	if targets[TargetService] && opts.EntPackage == "" {
+		return nil, fmt.Errorf("servicegen: EntPackage is required for service target: %w", os.ErrInvalid)
+	}
+
+	files, err := NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+	services, err := selectServices(set, files, opts.Services)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := selectEntities(files, services, opts.Entities)
+	if err != nil {
+		return nil, err
+	}
+
+	gen, err := newGenerator()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &code_generator.ScaffoldResult{}
+	scaffold := func(target Target, root string, vars map[string]any) error {
+		policy := opts.Policy
+		if policy == "" {
+			policy = defaultPolicies[target]
+		}
+		res, err := gen.Scaffold(ctx, code_generator.ScaffoldOptions{
+			Options: code_generator.Options{OutDir: opts.OutDir, Vars: vars},
+			Root:    root,
+			Policy:  policy,
+			DryRun:  opts.DryRun,
+		})
+		if err != nil {
+			return err
+		}
+		result.Files = append(result.Files, res.Files...)
+		return nil
+	}
+
+	for _, sd := range services {
+		svc := buildService(sd, opts.GoPackagePrefix)
+		for _, target := range []Target{TargetClient, TargetFake} {
+			if !targets[target] {
+				continue
+			}
+			if err = scaffold(target, string(target), map[string]any{"Service": svc}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, md := range messages {
+		entity := buildEntity(md, opts.GoPackagePrefix, opts.EntPackage)
+		for _, target := range []Target{TargetSchema, TargetService} {
+			if !targets[target] {
+				continue
+			}
+			if err = scaffold(target, string(target), map[string]any{"Entity": entity}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if targets[TargetService] && len(messages) > 0 {
+		if err = scaffold(TargetService, "common", nil); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].RelPath < result.Files[j].RelPath })
+	for i := 1; i < len(result.Files); i++ {
+		if prev, cur := result.Files[i-1], result.Files[i]; prev.RelPath == cur.RelPath {
+			return nil, fmt.Errorf("servicegen: %s and %s both render to %s: %w", prev.Template, cur.Template, cur.RelPath, os.ErrExist)
+		}
+	}
+	return result, nil
+}
+
+// GenerateFile 读取描述符集合文件并生成服务脚手架，见 LoadDescriptorSet 与 Generate
+func GenerateFile(ctx context.Context, path string, opts Options) (*code_generator.ScaffoldResult, error) {
+	set, err := LoadDescriptorSet(path)
+	if err != nil {
+		return nil, err
+	}
+	return Generate(ctx, set, opts)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// newGenerator 使用内置模板与 DefaultFuncMap 创建生成器
+func newGenerator() (*code_generator.CodeGenerator, error) {
+	srcs := map[string][]byte{}
+	err := fs.WalkDir(templateFS, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := templateFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		srcs[strings.TrimPrefix(path, "templates/")] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := code_generator.NewEmbeddedTemplateEngineFromMap(srcs, code_generator.DefaultFuncMap())
+	if err != nil {
+		return nil, err
+	}
+	return code_generator.NewCodeGeneratorWithEngine(engine), nil
+}
+
+// selectServices 描述符集合中需要生成的服务，按文件与定义顺序排列
+func selectServices(set *descriptorpb.FileDescriptorSet, files *protoregistry.Files, names []string) ([]protoreflect.ServiceDescriptor, error) {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var out []protoreflect.ServiceDescriptor
+	found := map[string]bool{}
+	for _, fdp := range set.GetFile() {
+		file, err := files.FindFileByPath(fdp.GetName())
+		if err != nil {
+			return nil, err
+		}
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			sd := services.Get(i)
+			if len(wanted) > 0 && !wanted[string(sd.Name())] && !wanted[string(sd.FullName())] {
+				continue
+			}
+			found[string(sd.Name())] = true
+			found[string(sd.FullName())] = true
+			out = append(out, sd)
+		}
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("servicegen: service %s not found: %w", name, os.ErrNotExist)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("servicegen: no services in descriptor set: %w", os.ErrNotExist)
+	}
+	return out, nil
+}
+
+// selectEntities 需要生成的实体消息，names 为空时从服务方法的响应中识别
+func selectEntities(files *protoregistry.Files, services []protoreflect.ServiceDescriptor, names []string) ([]protoreflect.MessageDescriptor, error) {
+	var out []protoreflect.MessageDescriptor
+	if len(names) == 0 {
+		seen := map[protoreflect.FullName]bool{}
+		for _, sd := range services {
+			for _, md := range entityMessages(sd) {
+				if !seen[md.FullName()] {
+					seen[md.FullName()] = true
+					out = append(out, md)
+				}
+			}
+		}
+		return out, nil
+	}
+
+	for _, name := range names {
+		md, err := findMessage(files, services, name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, md)
+	}
+	return out, nil
+}
+
+// findMessage 按全名或服务所在包中的消息名查找消息
+func findMessage(files *protoregistry.Files, services []protoreflect.ServiceDescriptor, name string) (protoreflect.MessageDescriptor, error) {
+	candidates := []protoreflect.FullName{protoreflect.FullName(name)}
+	for _, sd := range services {
+		candidates = append(candidates, sd.ParentFile().Package().Append(protoreflect.Name(name)))
+	}
+	for _, full := range candidates {
+		if !full.IsValid() {
+			continue
+		}
+		if d, err := files.FindDescriptorByName(full); err == nil {
+			if md, ok := d.(protoreflect.MessageDescriptor); ok {
+				return md, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("servicegen: message %s not found: %w", name, os.ErrNotExist)
+}
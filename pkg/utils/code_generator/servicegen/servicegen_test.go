@@ -0,0 +1,288 @@
+package servicegen
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	systemv1 "github.com/heyinLab/common/api/gen/go/system/v1"
+	"github.com/heyinLab/common/pkg/utils/code_generator"
+)
+
+// go test ./pkg/utils/code_generator/servicegen -run TestGenerate_Golden -update
+var update = flag.Bool("update", false, "update golden files")
+
+const (
+	demoPath   = "testdata/demo.binpb"
+	goldenDir  = "testdata/golden"
+	demoPrefix = "github.com/heyinLab/common/api/gen/go"
+	demoEnt    = "github.com/example/catalog/internal/data/ent"
+)
+
+func demoOptions(outDir string) Options {
+	return Options{
+		OutDir:          outDir,
+		GoPackagePrefix: demoPrefix,
+		EntPackage:      demoEnt,
+	}
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	tmp := t.TempDir()
+	res, err := GenerateFile(context.Background(), demoPath, demoOptions(tmp))
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	var got []string
+	for _, f := range res.Files {
+		if f.Action != code_generator.ActionCreate {
+			t.Fatalf("%s: unexpected action %q", f.RelPath, f.Action)
+		}
+		got = append(got, filepath.ToSlash(f.RelPath))
+	}
+
+	if *update {
+		if err = os.RemoveAll(goldenDir); err != nil {
+			t.Fatalf("remove golden dir failed: %v", err)
+		}
+		for _, rel := range got {
+			data, err := os.ReadFile(filepath.Join(tmp, rel))
+			if err != nil {
+				t.Fatalf("read output failed: %v", err)
+			}
+			golden := filepath.Join(goldenDir, rel+".golden")
+			if err = os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+				t.Fatalf("mkdir failed: %v", err)
+			}
+			if err = os.WriteFile(golden, data, 0o644); err != nil {
+				t.Fatalf("write golden failed: %v", err)
+			}
+		}
+	}
+
+	var want []string
+	err = filepath.WalkDir(goldenDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(goldenDir, path)
+		if err != nil {
+			return err
+		}
+		want = append(want, strings.TrimSuffix(filepath.ToSlash(rel), ".golden"))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk golden dir failed: %v", err)
+	}
+	sort.Strings(want)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("generated files mismatch:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	for _, rel := range got {
+		gotData, err := os.ReadFile(filepath.Join(tmp, rel))
+		if err != nil {
+			t.Fatalf("read output failed: %v", err)
+		}
+		wantData, err := os.ReadFile(filepath.Join(goldenDir, rel+".golden"))
+		if err != nil {
+			t.Fatalf("read golden failed: %v", err)
+		}
+		if string(gotData) != string(wantData) {
+			t.Errorf("%s differs from golden file, run with -update to regenerate:\n%s", rel, gotData)
+		}
+	}
+}
+
+func TestGenerate_PolicyAndDryRun(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	if _, err := GenerateFile(ctx, demoPath, demoOptions(tmp)); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	schema := filepath.Join(tmp, "ent", "schema", "item.go")
+	edited := []byte("package schema\n")
+	if err := os.WriteFile(schema, edited, 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	client := filepath.Join(tmp, "catalog", "client.go")
+	if err := os.WriteFile(client, []byte("package catalog\n"), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	opts := demoOptions(tmp)
+	opts.DryRun = true
+	res, err := GenerateFile(ctx, demoPath, opts)
+	if err != nil {
+		t.Fatalf("GenerateFile dry run failed: %v", err)
+	}
+	actions := map[string]code_generator.Action{}
+	for _, f := range res.Files {
+		actions[filepath.ToSlash(f.RelPath)] = f.Action
+		if filepath.ToSlash(f.RelPath) == "catalog/client.go" && f.Diff == "" {
+			t.Fatalf("expected diff for overwritten client")
+		}
+	}
+	if actions["catalog/client.go"] != code_generator.ActionOverwrite {
+		t.Fatalf("client action: got %q", actions["catalog/client.go"])
+	}
+	if actions["ent/schema/item.go"] != code_generator.ActionSkip {
+		t.Fatalf("schema action: got %q", actions["ent/schema/item.go"])
+	}
+
+	b, err := os.ReadFile(client)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if string(b) != "package catalog\n" {
+		t.Fatalf("dry run must not write files")
+	}
+
+	opts.DryRun = false
+	if _, err = GenerateFile(ctx, demoPath, opts); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if b, _ = os.ReadFile(schema); string(b) != string(edited) {
+		t.Fatalf("schema should be kept: %q", b)
+	}
+	if b, _ = os.ReadFile(client); !strings.HasPrefix(string(b), "// Code generated by servicegen") {
+		t.Fatalf("client should be regenerated: %q", b)
+	}
+}
+
+func TestGenerate_Select(t *testing.T) {
+	opts := demoOptions(t.TempDir())
+	opts.Services = []string{"api.catalog.v1.CatalogInternalService"}
+	opts.Targets = []Target{TargetClient, TargetSchema}
+	opts.DryRun = true
+
+	res, err := GenerateFile(context.Background(), demoPath, opts)
+	if err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	var got []string
+	for _, f := range res.Files {
+		got = append(got, filepath.ToSlash(f.RelPath))
+	}
+	want := "catalog/client.go,catalog/config.go,ent/schema/item.go"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected files: got %v want %s", got, want)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	set, err := LoadDescriptorSet(demoPath)
+	if err != nil {
+		t.Fatalf("LoadDescriptorSet failed: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{"MissingEntPackage", Options{OutDir: t.TempDir()}, os.ErrInvalid},
+		{"UnknownTarget", Options{OutDir: t.TempDir(), Targets: []Target{"docs"}}, os.ErrInvalid},
+		{"UnknownService", Options{OutDir: t.TempDir(), Targets: []Target{TargetClient}, Services: []string{"Nope"}}, os.ErrNotExist},
+		{"UnknownEntity", Options{OutDir: t.TempDir(), Targets: []Target{TargetSchema}, Entities: []string{"Nope"}}, os.ErrNotExist},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Generate(context.Background(), set, tc.opts)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestGenerate_SystemDescriptor(t *testing.T) {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(systemv1.File_system_v1_system_internal_proto),
+	}}
+	opts := Options{OutDir: t.TempDir(), Targets: []Target{TargetClient}, DryRun: true}
+	res, err := Generate(context.Background(), set, opts)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(res.Files) != 2 || filepath.ToSlash(res.Files[0].RelPath) != "system/client.go" {
+		t.Fatalf("unexpected files: %+v", res.Files)
+	}
+	diff := res.Files[0].Diff
+	for _, want := range []string{"func (s *SystemClient) ListCountries(", "func (s *SystemClient) GetCountryInfo(", `v1 "github.com/heyinLab/common/api/gen/go/system/v1"`} {
+		if !strings.Contains(diff, want) {
+			t.Fatalf("client.go missing %q:\n%s", want, diff)
+		}
+	}
+}
+
+func TestParseDescriptorSet(t *testing.T) {
+	binary, err := os.ReadFile(demoPath)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	set, err := ParseDescriptorSet(binary)
+	if err != nil {
+		t.Fatalf("parse binary failed: %v", err)
+	}
+
+	data, err := protojson.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal json failed: %v", err)
+	}
+	fromJSON, err := ParseDescriptorSet(data)
+	if err != nil {
+		t.Fatalf("parse json failed: %v", err)
+	}
+	if len(fromJSON.GetFile()) != len(set.GetFile()) {
+		t.Fatalf("file count mismatch: %d vs %d", len(fromJSON.GetFile()), len(set.GetFile()))
+	}
+
+	for _, bad := range [][]byte{nil, []byte("{}"), []byte("{bad"), []byte{0xff, 0xff}} {
+		if _, err = ParseDescriptorSet(bad); !errors.Is(err, ErrInvalidDescriptor) {
+			t.Fatalf("%q: expected ErrInvalidDescriptor, got %v", bad, err)
+		}
+	}
+}
+
+func TestNaming(t *testing.T) {
+	pascal := map[string]string{
+		"id":          "ID",
+		"tenant_id":   "TenantID",
+		"image_url":   "ImageURL",
+		"created_at":  "CreatedAt",
+		"http_status": "HTTPStatus",
+		"sort_order":  "SortOrder",
+	}
+	for in, want := range pascal {
+		if got := entPascal(in); got != want {
+			t.Errorf("entPascal(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	camel := map[string]string{
+		"tenant_id":  "TenantId",
+		"image_url":  "ImageUrl",
+		"v2_name":    "V2Name",
+		"_private":   "XPrivate",
+		"sort_order": "SortOrder",
+	}
+	for in, want := range camel {
+		if got := goCamelCase(in); got != want {
+			t.Errorf("goCamelCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
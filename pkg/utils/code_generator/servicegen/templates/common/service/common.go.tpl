@@ -0,0 +1,25 @@
+package service
+
+import "errors"
+
+// ErrNilRequest 请求为空
+var ErrNilRequest = errors.New("service: nil request")
+
+// PagingRequest 列表查询参数，规则见 entgo/query
+type PagingRequest struct {
+	// Page 当前页码，默认为 1
+	Page int32
+	// PageSize 每页的行数，默认为 10
+	PageSize int32
+	// NoPaging 为 true 时不分页
+	NoPaging bool
+
+	// Query AND 过滤条件，JSON 对象或对象数组，如 {"name__contains":"a"}
+	Query string
+	// OrQuery OR 过滤条件，格式同 Query
+	OrQuery string
+	// OrderBy 排序条件，字段名前加 - 为降序，如 ["-created_at"]
+	OrderBy []string
+	// FieldMask 查询的字段，为空时查询全部字段
+	FieldMask []string
+}
@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+
+	"github.com/heyinLab/common/pkg/utils/entgo/mixin"
+{{- range .Entity.SchemaImports}}
+	{{.}}
+{{- end}}
+)
+
+// {{.Entity.Name}} {{.Entity.Comment}}
+type {{.Entity.Name}} struct {
+	ent.Schema
+}
+
+// Annotations of the {{.Entity.Name}}.
+func ({{.Entity.Name}}) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		schema.Comment({{printf "%q" .Entity.Comment}}),
+	}
+}
+
+// Fields of the {{.Entity.Name}}.
+func ({{.Entity.Name}}) Fields() []ent.Field {
+	return []ent.Field{
+{{- range .Entity.Fields}}
+		{{.}},
+{{- end}}
+{{- range .Entity.Notes}}
+
+		// TODO: {{.}}
+{{- end}}
+
+		// BEGIN USER CODE: fields
+		// END USER CODE: fields
+	}
+}
+
+// Mixin of the {{.Entity.Name}}.
+func ({{.Entity.Name}}) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+{{- range .Entity.Mixins}}
+		{{.}},
+{{- end}}
+	}
+}
+{{- if .Entity.SoftDelete}}
+
+// Hooks of the {{.Entity.Name}}.
+func ({{.Entity.Name}}) Hooks() []ent.Hook {
+	return mixin.SoftDelete{}.Hooks()
+}
+
+// Interceptors of the {{.Entity.Name}}.
+func ({{.Entity.Name}}) Interceptors() []ent.Interceptor {
+	return mixin.SoftDelete{}.Interceptors()
+}
+{{- end}}
+
+// BEGIN USER CODE: methods
+// END USER CODE: methods
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	entgoQuery "github.com/heyinLab/common/pkg/utils/entgo/query"
+{{- range .Entity.Imports}}
+	{{.}}
+{{- end}}
+)
+
+// {{.Entity.Name}}Service {{.Entity.Comment}}的增删改查
+type {{.Entity.Name}}Service struct {
+	client *ent.Client
+	log    *log.Helper
+}
+
+// New{{.Entity.Name}}Service 创建{{.Entity.Comment}}服务
+func New{{.Entity.Name}}Service(client *ent.Client, logger log.Logger) *{{.Entity.Name}}Service {
+	return &{{.Entity.Name}}Service{
+		client: client,
+		log:    log.NewHelper(log.With(logger, "module", "{{.Entity.File}}/service")),
+	}
+}
+
+// Get 获取{{.Entity.Comment}}
+func (s *{{.Entity.Name}}Service) Get(ctx context.Context, id {{.Entity.IDType}}) (*{{.Entity.Message}}, error) {
+	e, err := s.client.{{.Entity.Name}}.Get(ctx, id)
+	if err != nil {
+		s.log.WithContext(ctx).Errorf("获取{{.Entity.Comment}}失败:id=%v,error=%v", id, err)
+		return nil, err
+	}
+	return s.toProto(e), nil
+}
+
+// List 查询{{.Entity.Comment}}列表，返回当前页数据与总数，过滤、排序与分页规则见 entgo/query
+func (s *{{.Entity.Name}}Service) List(ctx context.Context, req *PagingRequest) ([]*{{.Entity.Message}}, int, error) {
+	if req == nil {
+		req = &PagingRequest{}
+	}
+
+	err, whereSelectors, querySelectors := entgoQuery.BuildQuerySelector(
+		req.Query, req.OrQuery,
+		req.Page, req.PageSize, req.NoPaging,
+		req.OrderBy, {{.Entity.DefaultOrder}},
+		req.FieldMask,
+	)
+	if err != nil {
+		s.log.WithContext(ctx).Errorf("解析{{.Entity.Comment}}查询条件失败:error=%v", err)
+		return nil, 0, err
+	}
+
+	builder := s.client.{{.Entity.Name}}.Query()
+	if querySelectors != nil {
+		builder.Modify(querySelectors...)
+	}
+	entities, err := builder.All(ctx)
+	if err != nil {
+		s.log.WithContext(ctx).Errorf("查询{{.Entity.Comment}}列表失败:error=%v", err)
+		return nil, 0, err
+	}
+
+	total, err := s.client.{{.Entity.Name}}.Query().Modify(whereSelectors...).Count(ctx)
+	if err != nil {
+		s.log.WithContext(ctx).Errorf("统计{{.Entity.Comment}}数量失败:error=%v", err)
+		return nil, 0, err
+	}
+
+	items := make([]*{{.Entity.Message}}, 0, len(entities))
+	for _, e := range entities {
+		items = append(items, s.toProto(e))
+	}
+	return items, total, nil
+}
+
+// Create 创建{{.Entity.Comment}}
+func (s *{{.Entity.Name}}Service) Create(ctx context.Context, m *{{.Entity.Message}}) (*{{.Entity.Message}}, error) {
+	if m == nil {
+		return nil, ErrNilRequest
+	}
+
+	b := s.client.{{.Entity.Name}}.Create()
+{{- range .Entity.CreateSetters}}
+	{{.}}
+{{- end}}
+{{- range .Entity.Setters}}
+	{{.}}
+{{- end}}
+
+	e, err := b.Save(ctx)
+	if err != nil {
+		s.log.WithContext(ctx).Errorf("创建{{.Entity.Comment}}失败:error=%v", err)
+		return nil, err
+	}
+	return s.toProto(e), nil
+}
+
+// Update 更新{{.Entity.Comment}}
+func (s *{{.Entity.Name}}Service) Update(ctx context.Context, id {{.Entity.IDType}}, m *{{.Entity.Message}}) (*{{.Entity.Message}}, error) {
+	if m == nil {
+		return nil, ErrNilRequest
+	}
+
+	b := s.client.{{.Entity.Name}}.UpdateOneID(id)
+{{- range .Entity.UpdateSetters}}
+	{{.}}
+{{- end}}
+{{- range .Entity.Setters}}
+	{{.}}
+{{- end}}
+
+	e, err := b.Save(ctx)
+	if err != nil {
+		s.log.WithContext(ctx).Errorf("更新{{.Entity.Comment}}失败:id=%v,error=%v", id, err)
+		return nil, err
+	}
+	return s.toProto(e), nil
+}
+
+// Delete 删除{{.Entity.Comment}}
+func (s *{{.Entity.Name}}Service) Delete(ctx context.Context, id {{.Entity.IDType}}) error {
+	if err := s.client.{{.Entity.Name}}.DeleteOneID(id).Exec(ctx); err != nil {
+		s.log.WithContext(ctx).Errorf("删除{{.Entity.Comment}}失败:id=%v,error=%v", id, err)
+		return err
+	}
+	return nil
+}
+
+// toProto 转换为 {{.Entity.Message}}
+func (s *{{.Entity.Name}}Service) toProto(e *ent.{{.Entity.Name}}) *{{.Entity.Message}} {
+	if e == nil {
+		return nil
+	}
+
+	m := &{{.Entity.Message}}{}
+{{- range .Entity.Converters}}
+	{{.}}
+{{- end}}
+	return m
+}
+
+// BEGIN USER CODE: methods
+// END USER CODE: methods
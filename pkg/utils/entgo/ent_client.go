@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"time"
 
-	"entgo.io/ent/dialect"
 	"github.com/go-kratos/kratos/v2/log"
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
@@ -135,69 +134,17 @@ func Rollback[T Rollbacker](tx T, err error) error {
 	return err
 }
 
-// QueryAllChildrenIds 使用CTE递归查询所有子节点ID
+// QueryAllChildrenIds 查询所有子孙节点ID，支持 MySQL、PostgreSQL 与 SQLite
+//
+// 更多树形操作（祖先、路径、移动、排序、物化路径）见 Tree。
 func QueryAllChildrenIds[T EntClientInterface](ctx context.Context, entClient *EntClient[T], tableName string, parentID uint32) ([]uint32, error) {
-	var query string
-	switch entClient.Driver().Dialect() {
-	case dialect.MySQL:
-		query = fmt.Sprintf(`
-			WITH RECURSIVE all_descendants AS (
-				SELECT 
-					id,
-					parent_id,
-					name,
-					1 AS depth
-				FROM %s
-				WHERE parent_id = ?
-				
-				UNION ALL
-				
-				SELECT 
-					p.id,
-					p.parent_id,
-					p.name,
-					ad.depth + 1 AS depth
-				FROM %s p
-					INNER JOIN all_descendants ad
-				ON p.parent_id = ad.id
-			)
-			SELECT id FROM all_descendants;
-		`, tableName, tableName)
-
-	case dialect.Postgres:
-		query = fmt.Sprintf(`
-        WITH RECURSIVE all_descendants AS (
-            SELECT *
-			FROM %s
-			WHERE parent_id = $1
-            UNION ALL
-            SELECT p.*
-			FROM %s p
-            	INNER JOIN all_descendants ad
-			ON p.parent_id = ad.id
-        )
-        SELECT id FROM all_descendants;
-    `, tableName, tableName)
-	}
-
-	rows := &sql.Rows{}
-	if err := entClient.Query(ctx, query, []any{parentID}, rows); err != nil {
+	childIDs, err := NewTree(entClient.Driver(), TreeTable{Table: tableName}).DescendantIDs(ctx, parentID, 0)
+	if err != nil {
 		log.Errorf("query child nodes failed: %s", err.Error())
 		return nil, errors.New("query child nodes failed: " + err.Error())
 	}
-	defer rows.Close()
-
-	childIDs := make([]uint32, 0)
-	for rows.Next() {
-		var id uint32
-
-		if err := rows.Scan(&id); err != nil {
-			log.Errorf("scan child node failed: %s", err.Error())
-			return nil, errors.New("scan child node failed")
-		}
-
-		childIDs = append(childIDs, id)
+	if childIDs == nil {
+		childIDs = make([]uint32, 0)
 	}
-
 	return childIDs, nil
 }
@@ -0,0 +1,138 @@
+package mixin
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/heyinLab/common/pkg/middleware/auth"
+	"github.com/heyinLab/common/pkg/utils/clock"
+	"github.com/heyinLab/common/pkg/utils/stringcase"
+)
+
+// currentTime 返回字段默认值使用的当前时间
+//
+// ent 计算字段默认值时没有 context，使用 clock.Default()，测试中可以通过 clock.SetDefault 替换。
+func currentTime() time.Time {
+	return clock.Now()
+}
+
+// currentTimeContext 返回钩子使用的当前时间，优先使用 clock.NewContext 放入 ctx 的时钟
+func currentTimeContext(ctx context.Context) time.Time {
+	return clock.NowContext(ctx)
+}
+
+// currentTimeMilli 返回审计字段使用的当前时间（毫秒时间戳）
+func currentTimeMilli() int64 {
+	return currentTime().UnixMilli()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// setOperator 将 context 中的操作者写入 column，已显式设置的值不会被覆盖
+//
+// 列类型通过生成代码中的 Set<Column> 方法推断：
+//   - string：写入操作者标识（用户编码或 api_key:<id>）
+//   - uint32/uint64：写入 API Key ID，用户没有数值ID时不写入
+func setOperator(ctx context.Context, m ent.Mutation, column string) error {
+	if _, ok := m.Field(column); ok {
+		return nil
+	}
+
+	setter := reflect.ValueOf(m).MethodByName("Set" + stringcase.UpperCamelCase(column))
+	if !setter.IsValid() || setter.Type().NumIn() != 1 {
+		return nil
+	}
+
+	op := auth.GetOperator(ctx)
+	switch setter.Type().In(0).Kind() {
+	case reflect.String:
+		if identity := op.Identity(); identity != "" {
+			return m.SetField(column, identity)
+		}
+	case reflect.Uint32:
+		if op.ID != 0 && op.ID <= uint64(^uint32(0)) {
+			return m.SetField(column, uint32(op.ID))
+		}
+	case reflect.Uint64:
+		if op.ID != 0 {
+			return m.SetField(column, op.ID)
+		}
+	}
+	return nil
+}
+
+// operatorHook 在 op 命中时将操作者写入 column
+func operatorHook(column string, op ent.Op) ent.Hook {
+	return hookOn(
+		func(next ent.Mutator) ent.Mutator {
+			return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+				if err := setOperator(ctx, m, column); err != nil {
+					return nil, err
+				}
+				return next.Mutate(ctx, m)
+			})
+		},
+		op,
+	)
+}
+
+// deleterHook 在更新操作写入删除时间（软删除）时，将操作者写入 column
+func deleterHook(column, deletedColumn string) ent.Hook {
+	return hookOn(
+		func(next ent.Mutator) ent.Mutator {
+			return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+				if _, ok := m.Field(deletedColumn); ok {
+					if err := setOperator(ctx, m, column); err != nil {
+						return nil, err
+					}
+				}
+				return next.Mutate(ctx, m)
+			})
+		},
+		ent.OpUpdate|ent.OpUpdateOne,
+	)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var _ ent.Mixin = (*Audit)(nil)
+
+// Audit 审计字段
+//
+// 包含 created_at/updated_at/deleted_at 与 created_by/updated_by/deleted_by（操作者编码），
+// 时间来自 clock 包的时钟（钩子中为 clock.FromContext(ctx)），操作者来自 auth.GetOperator(ctx)。
+//
+// Audit 自身不提供软删除，需要时在 schema 中追加 SoftDelete 的钩子与拦截器：
+//
+//	func (User) Hooks() []ent.Hook { return mixin.SoftDelete{}.Hooks() }
+//	func (User) Interceptors() []ent.Interceptor { return mixin.SoftDelete{}.Interceptors() }
+type Audit struct{ mixin.Schema }
+
+func (Audit) Fields() []ent.Field {
+	var fields []ent.Field
+	fields = append(fields, CreatedAt{}.Fields()...)
+	fields = append(fields, UpdatedAt{}.Fields()...)
+	fields = append(fields, DeletedAt{}.Fields()...)
+	fields = append(fields, CreatedByCode{}.Fields()...)
+	fields = append(fields, UpdatedByCode{}.Fields()...)
+	fields = append(fields, DeletedByCode{}.Fields()...)
+	return fields
+}
+
+// Indexes of the Audit mixin.
+func (Audit) Indexes() []ent.Index {
+	return DeletedAt{}.Indexes()
+}
+
+// Hooks of the Audit mixin.
+func (Audit) Hooks() []ent.Hook {
+	var hooks []ent.Hook
+	hooks = append(hooks, CreatedByCode{}.Hooks()...)
+	hooks = append(hooks, UpdatedByCode{}.Hooks()...)
+	hooks = append(hooks, DeletedByCode{}.Hooks()...)
+	return hooks
+}
@@ -0,0 +1,104 @@
+package mixin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"entgo.io/ent"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heyinLab/common/pkg/middleware/auth"
+	"github.com/heyinLab/common/pkg/middleware/common"
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// fakeCodeMutation 模拟字符串型操作者列的生成代码
+type fakeCodeMutation struct {
+	*fakeMutation
+}
+
+func (m fakeCodeMutation) SetCreatedBy(v string) { m.fields["created_by"] = v }
+func (m fakeCodeMutation) SetUpdatedBy(v string) { m.fields["updated_by"] = v }
+func (m fakeCodeMutation) SetDeletedBy(v string) { m.fields["deleted_by"] = v }
+
+func TestSetOperator(t *testing.T) {
+	userCtx := auth.NewContext(context.Background(), &auth.Claims{UserCode: "U001"})
+	apiKeyCtx := context.WithValue(context.Background(), common.KeyAuthType, common.AuthTypeOpenAPI)
+	apiKeyCtx = context.WithValue(apiKeyCtx, common.KeyAPIKeyID, uint64(9))
+
+	t.Run("StringColumn", func(t *testing.T) {
+		m := fakeCodeMutation{newFakeMutation(ent.OpCreate)}
+		require.NoError(t, setOperator(userCtx, m, "created_by"))
+		require.Equal(t, "U001", m.fields["created_by"])
+
+		m = fakeCodeMutation{newFakeMutation(ent.OpCreate)}
+		require.NoError(t, setOperator(apiKeyCtx, m, "created_by"))
+		require.Equal(t, "api_key:9", m.fields["created_by"])
+	})
+
+	t.Run("NumericColumn", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdate)
+		require.NoError(t, setOperator(apiKeyCtx, m, "deleted_by"))
+		require.Equal(t, uint32(9), m.fields["deleted_by"])
+
+		m = newFakeMutation(ent.OpUpdate)
+		require.NoError(t, setOperator(userCtx, m, "deleted_by"))
+		require.Empty(t, m.fields)
+	})
+
+	t.Run("KeepExplicitValue", func(t *testing.T) {
+		m := fakeCodeMutation{newFakeMutation(ent.OpCreate)}
+		m.fields["created_by"] = "admin"
+		require.NoError(t, setOperator(userCtx, m, "created_by"))
+		require.Equal(t, "admin", m.fields["created_by"])
+	})
+
+	t.Run("UnknownColumn", func(t *testing.T) {
+		m := fakeCodeMutation{newFakeMutation(ent.OpCreate)}
+		require.NoError(t, setOperator(userCtx, m, "owner"))
+		require.Empty(t, m.fields)
+	})
+}
+
+func TestAudit_Hooks(t *testing.T) {
+	ctx := auth.NewContext(context.Background(), &auth.Claims{UserCode: "U001"})
+	chain := func(next ent.Mutator) ent.Mutator {
+		hooks := Audit{}.Hooks()
+		for i := len(hooks) - 1; i >= 0; i-- {
+			next = hooks[i](next)
+		}
+		return next
+	}
+
+	m := fakeCodeMutation{newFakeMutation(ent.OpCreate)}
+	_, err := chain(&recordMutator{}).Mutate(ctx, m)
+	require.NoError(t, err)
+	require.Equal(t, "U001", m.fields["created_by"])
+	require.Equal(t, "U001", m.fields["updated_by"])
+	require.NotContains(t, m.fields, "deleted_by")
+
+	m = fakeCodeMutation{newFakeMutation(ent.OpUpdateOne)}
+	m.fields[fieldDeletedAt] = time.Now()
+	_, err = chain(&recordMutator{}).Mutate(ctx, m)
+	require.NoError(t, err)
+	require.NotContains(t, m.fields, "created_by")
+	require.Equal(t, "U001", m.fields["updated_by"])
+	require.Equal(t, "U001", m.fields["deleted_by"])
+}
+
+func TestClock(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// 钩子使用 context 中的时钟
+	ctx := clock.NewContext(context.Background(), clock.Fixed(fixed))
+	require.Equal(t, fixed, currentTimeContext(ctx))
+
+	// 字段默认值没有 context，使用默认时钟
+	defer clock.SetDefault(clock.Fixed(fixed))()
+	desc := CreatedAt{}.Fields()[0].Descriptor()
+	require.Equal(t, fixed, desc.Default.(func() time.Time)())
+
+	desc = UpdatedAtTimestamp{}.Fields()[0].Descriptor()
+	require.Equal(t, fixed.UnixMilli(), desc.UpdateDefault.(func() int64)())
+}
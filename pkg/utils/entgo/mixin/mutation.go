@@ -0,0 +1,101 @@
+package mixin
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// predicateMutation 由 entc 生成的 <T>Mutation 都实现了该接口
+type predicateMutation interface {
+	ent.Mutation
+	WhereP(...func(*sql.Selector))
+}
+
+// predicateQuery 由 entc 生成的 <T>Query 都实现了该接口
+type predicateQuery interface {
+	WhereP(...func(*sql.Selector))
+}
+
+// opMutation 由 entc 生成的 <T>Mutation 都实现了该接口
+type opMutation interface {
+	ent.Mutation
+	SetOp(ent.Op)
+}
+
+// clientMutator 由 entc 生成的 Client 实现了该接口
+type clientMutator interface {
+	Mutate(context.Context, ent.Mutation) (ent.Value, error)
+}
+
+// mutateWithClient 通过 <T>Mutation.Client() 重新执行 mutation。
+//
+// 生成代码中的 Client() 返回具体的 *ent.Client 类型，mixin 包无法直接引用，因此这里通过反射调用。
+// 当 mutation 的 Op 被改写（例如 Delete 改写为 Update）后，必须走 Client.Mutate 才能执行新的操作。
+func mutateWithClient(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+	method := reflect.ValueOf(m).MethodByName("Client")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil, fmt.Errorf("mixin: mutation %T does not expose Client()", m)
+	}
+	client, ok := method.Call(nil)[0].Interface().(clientMutator)
+	if !ok {
+		return nil, fmt.Errorf("mixin: client of mutation %T does not implement Mutate", m)
+	}
+	return client.Mutate(ctx, m)
+}
+
+// selectorType 存储层谓词的类型，生成代码中的 predicate.<T> 均以此为底层类型
+var selectorType = reflect.TypeOf(func(*sql.Selector) {})
+
+// asPredicateQuery 将查询转换为 predicateQuery
+//
+// 生成代码中的 <T>Query 只提供 Where(...predicate.<T>)，仅 intercept 包装后才提供 WhereP，
+// 拦截器收到的是未包装的查询，因此这里通过反射将存储层谓词转换为 predicate.<T> 后调用 Where。
+func asPredicateQuery(q any) (predicateQuery, bool) {
+	if pq, ok := q.(predicateQuery); ok {
+		return pq, true
+	}
+	method := reflect.ValueOf(q).MethodByName("Where")
+	if !method.IsValid() {
+		return nil, false
+	}
+	typ := method.Type()
+	if !typ.IsVariadic() || typ.NumIn() != 1 {
+		return nil, false
+	}
+	elem := typ.In(0).Elem()
+	if !selectorType.ConvertibleTo(elem) {
+		return nil, false
+	}
+	return reflectWhere{method: method, elem: elem}, true
+}
+
+// reflectWhere 通过反射调用 Where 的 predicateQuery
+type reflectWhere struct {
+	method reflect.Value
+	elem   reflect.Type
+}
+
+func (w reflectWhere) WhereP(ps ...func(*sql.Selector)) {
+	args := make([]reflect.Value, len(ps))
+	for i, p := range ps {
+		args[i] = reflect.ValueOf(p).Convert(w.elem)
+	}
+	w.method.Call(args)
+}
+
+// hookOn 仅在 mutation 的操作类型命中 op 时执行 hook，等价于生成代码中的 hook.On
+func hookOn(hk ent.Hook, op ent.Op) ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		hooked := hk(next)
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			if m.Op().Is(op) {
+				return hooked.Mutate(ctx, m)
+			}
+			return next.Mutate(ctx, m)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package mixin
+
+import (
+	"context"
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+)
+
+// fakeMutation 模拟 entc 生成的 <T>Mutation，仅实现 mixin 用到的方法
+type fakeMutation struct {
+	ent.Mutation
+
+	op         ent.Op
+	fields     map[string]ent.Value
+	added      map[string]ent.Value
+	cleared    map[string]bool
+	predicates []func(*sql.Selector)
+	client     *fakeClient
+}
+
+func newFakeMutation(op ent.Op) *fakeMutation {
+	return &fakeMutation{
+		op:      op,
+		fields:  map[string]ent.Value{},
+		added:   map[string]ent.Value{},
+		cleared: map[string]bool{},
+		client:  &fakeClient{},
+	}
+}
+
+func (m *fakeMutation) Op() ent.Op          { return m.op }
+func (m *fakeMutation) SetOp(op ent.Op)     { m.op = op }
+func (m *fakeMutation) Type() string        { return "Fake" }
+func (m *fakeMutation) Client() *fakeClient { return m.client }
+
+// SetDeletedBy 模拟数值型 deleted_by 列的生成代码
+func (m *fakeMutation) SetDeletedBy(v uint32) { m.fields["deleted_by"] = v }
+
+func (m *fakeMutation) Fields() []string {
+	fields := make([]string, 0, len(m.fields))
+	for name := range m.fields {
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+func (m *fakeMutation) Field(name string) (ent.Value, bool) {
+	v, ok := m.fields[name]
+	return v, ok
+}
+
+func (m *fakeMutation) SetField(name string, value ent.Value) error {
+	m.fields[name] = value
+	return nil
+}
+
+func (m *fakeMutation) ResetField(name string) error {
+	delete(m.fields, name)
+	return nil
+}
+
+func (m *fakeMutation) AddedField(name string) (ent.Value, bool) {
+	v, ok := m.added[name]
+	return v, ok
+}
+
+// AddField 与生成代码一致：uint32 的 version 字段只接受 int32 增量
+func (m *fakeMutation) AddField(name string, value ent.Value) error {
+	if name == "version" {
+		if _, ok := value.(int32); !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+	}
+	m.added[name] = value
+	return nil
+}
+
+func (m *fakeMutation) FieldCleared(name string) bool {
+	return m.cleared[name]
+}
+
+func (m *fakeMutation) ClearField(name string) error {
+	m.cleared[name] = true
+	return nil
+}
+
+func (m *fakeMutation) WhereP(ps ...func(*sql.Selector)) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// where 将累积的谓词渲染为 SQL，便于断言
+func (m *fakeMutation) where() (string, []any) {
+	return renderPredicates(m.predicates)
+}
+
+// fakeClient 模拟 entc 生成的 Client
+type fakeClient struct {
+	mutated []ent.Mutation
+	result  ent.Value
+	err     error
+}
+
+func (c *fakeClient) Mutate(_ context.Context, m ent.Mutation) (ent.Value, error) {
+	c.mutated = append(c.mutated, m)
+	return c.result, c.err
+}
+
+// fakeQuery 模拟 entc 生成的 <T>Query
+type fakeQuery struct {
+	predicates []func(*sql.Selector)
+}
+
+func (q *fakeQuery) WhereP(ps ...func(*sql.Selector)) {
+	q.predicates = append(q.predicates, ps...)
+}
+
+func (q *fakeQuery) where() (string, []any) {
+	return renderPredicates(q.predicates)
+}
+
+// typedPredicate 与生成代码中的 predicate.<T> 相同
+type typedPredicate func(*sql.Selector)
+
+// typedQuery 与生成代码中的 <T>Query 相同，只提供 Where(...predicate.<T>)
+type typedQuery struct {
+	predicates []typedPredicate
+}
+
+func (q *typedQuery) Where(ps ...typedPredicate) *typedQuery {
+	q.predicates = append(q.predicates, ps...)
+	return q
+}
+
+func (q *typedQuery) where() (string, []any) {
+	ps := make([]func(*sql.Selector), len(q.predicates))
+	for i, p := range q.predicates {
+		ps[i] = p
+	}
+	return renderPredicates(ps)
+}
+
+func renderPredicates(ps []func(*sql.Selector)) (string, []any) {
+	s := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("t"))
+	for _, p := range ps {
+		p(s)
+	}
+	query, args := s.Query()
+	return query, args
+}
+
+// recordMutator 记录调用的 next mutator
+type recordMutator struct {
+	called bool
+	value  ent.Value
+}
+
+func (r *recordMutator) Mutate(_ context.Context, m ent.Mutation) (ent.Value, error) {
+	r.called = true
+	if r.value != nil {
+		return r.value, nil
+	}
+	return fmt.Sprintf("%s:%s", m.Type(), m.Op()), nil
+}
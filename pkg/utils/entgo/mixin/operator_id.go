@@ -19,6 +19,13 @@ func (CreateBy) Fields() []ent.Field {
 	}
 }
 
+// Hooks of the CreateBy mixin.
+func (CreateBy) Hooks() []ent.Hook {
+	return []ent.Hook{
+		operatorHook("create_by", ent.OpCreate),
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 var _ ent.Mixin = (*UpdateBy)(nil)
@@ -34,6 +41,13 @@ func (UpdateBy) Fields() []ent.Field {
 	}
 }
 
+// Hooks of the UpdateBy mixin.
+func (UpdateBy) Hooks() []ent.Hook {
+	return []ent.Hook{
+		operatorHook("update_by", ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne),
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 var _ ent.Mixin = (*DeleteBy)(nil)
@@ -49,6 +63,13 @@ func (DeleteBy) Fields() []ent.Field {
 	}
 }
 
+// Hooks of the DeleteBy mixin.
+func (DeleteBy) Hooks() []ent.Hook {
+	return []ent.Hook{
+		deleterHook("delete_by", "delete_time"),
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 var _ ent.Mixin = (*CreatedBy)(nil)
@@ -64,6 +85,13 @@ func (CreatedBy) Fields() []ent.Field {
 	}
 }
 
+// Hooks of the CreatedBy mixin.
+func (CreatedBy) Hooks() []ent.Hook {
+	return []ent.Hook{
+		operatorHook("created_by", ent.OpCreate),
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 var _ ent.Mixin = (*UpdatedBy)(nil)
@@ -79,6 +107,13 @@ func (UpdatedBy) Fields() []ent.Field {
 	}
 }
 
+// Hooks of the UpdatedBy mixin.
+func (UpdatedBy) Hooks() []ent.Hook {
+	return []ent.Hook{
+		operatorHook("updated_by", ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne),
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 var _ ent.Mixin = (*DeletedBy)(nil)
@@ -94,6 +129,13 @@ func (DeletedBy) Fields() []ent.Field {
 	}
 }
 
+// Hooks of the DeletedBy mixin.
+func (DeletedBy) Hooks() []ent.Hook {
+	return []ent.Hook{
+		deleterHook("deleted_by", "deleted_at"),
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 var _ ent.Mixin = (*OperatorID)(nil)
@@ -107,3 +149,109 @@ func (OperatorID) Fields() []ent.Field {
 	fields = append(fields, DeletedBy{}.Fields()...)
 	return fields
 }
+
+// Hooks of the OperatorID mixin.
+func (OperatorID) Hooks() []ent.Hook {
+	var hooks []ent.Hook
+	hooks = append(hooks, CreatedBy{}.Hooks()...)
+	hooks = append(hooks, UpdatedBy{}.Hooks()...)
+	hooks = append(hooks, DeletedBy{}.Hooks()...)
+	return hooks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var _ ent.Mixin = (*CreatedByCode)(nil)
+
+// CreatedByCode 创建者编码，存储用户编码或 api_key:<id>
+type CreatedByCode struct{ mixin.Schema }
+
+func (CreatedByCode) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("created_by").
+			Comment("创建者编码").
+			MaxLen(64).
+			Immutable().
+			Optional().
+			Nillable(),
+	}
+}
+
+// Hooks of the CreatedByCode mixin.
+func (CreatedByCode) Hooks() []ent.Hook {
+	return []ent.Hook{
+		operatorHook("created_by", ent.OpCreate),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var _ ent.Mixin = (*UpdatedByCode)(nil)
+
+// UpdatedByCode 更新者编码，存储用户编码或 api_key:<id>
+type UpdatedByCode struct{ mixin.Schema }
+
+func (UpdatedByCode) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("updated_by").
+			Comment("更新者编码").
+			MaxLen(64).
+			Optional().
+			Nillable(),
+	}
+}
+
+// Hooks of the UpdatedByCode mixin.
+func (UpdatedByCode) Hooks() []ent.Hook {
+	return []ent.Hook{
+		operatorHook("updated_by", ent.OpCreate|ent.OpUpdate|ent.OpUpdateOne),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var _ ent.Mixin = (*DeletedByCode)(nil)
+
+// DeletedByCode 删除者编码，存储用户编码或 api_key:<id>
+type DeletedByCode struct{ mixin.Schema }
+
+func (DeletedByCode) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("deleted_by").
+			Comment("删除者编码").
+			MaxLen(64).
+			Optional().
+			Nillable(),
+	}
+}
+
+// Hooks of the DeletedByCode mixin.
+func (DeletedByCode) Hooks() []ent.Hook {
+	return []ent.Hook{
+		deleterHook("deleted_by", "deleted_at"),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var _ ent.Mixin = (*OperatorCode)(nil)
+
+// OperatorCode 操作者编码，字符串版本的 OperatorID
+type OperatorCode struct{ mixin.Schema }
+
+func (OperatorCode) Fields() []ent.Field {
+	var fields []ent.Field
+	fields = append(fields, CreatedByCode{}.Fields()...)
+	fields = append(fields, UpdatedByCode{}.Fields()...)
+	fields = append(fields, DeletedByCode{}.Fields()...)
+	return fields
+}
+
+// Hooks of the OperatorCode mixin.
+func (OperatorCode) Hooks() []ent.Hook {
+	var hooks []ent.Hook
+	hooks = append(hooks, CreatedByCode{}.Hooks()...)
+	hooks = append(hooks, UpdatedByCode{}.Hooks()...)
+	hooks = append(hooks, DeletedByCode{}.Hooks()...)
+	return hooks
+}
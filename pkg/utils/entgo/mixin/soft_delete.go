@@ -1,10 +1,30 @@
 package mixin
 
 import (
+	"context"
+
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/schema/index"
 	"entgo.io/ent/schema/mixin"
 )
 
+const (
+	fieldDeletedAt = "deleted_at"
+	fieldDeletedBy = "deleted_by"
+)
+
+// 确保 SoftDelete 实现了 ent.Mixin 接口
+var _ ent.Mixin = (*SoftDelete)(nil)
+
+// SoftDelete 软删除
+//
+// 除了 deleted_at/deleted_by 字段外，还提供：
+//   - 拦截器：查询时默认过滤 deleted_at IS NOT NULL 的记录
+//   - 钩子：Delete/DeleteOne 被改写为 Update，写入 deleted_at 与 deleted_by
+//
+// 需要查询已删除数据或物理删除时，使用 SkipSoftDelete / HardDelete 包装 context。
 type SoftDelete struct {
 	mixin.Schema
 }
@@ -15,3 +35,125 @@ func (SoftDelete) Fields() []ent.Field {
 	fields = append(fields, DeletedBy{}.Fields()...)
 	return fields
 }
+
+// Indexes of the SoftDelete mixin.
+func (SoftDelete) Indexes() []ent.Index {
+	return DeletedAt{}.Indexes()
+}
+
+// Interceptors of the SoftDelete mixin.
+func (d SoftDelete) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		ent.TraverseFunc(func(ctx context.Context, q ent.Query) error {
+			if IsSkipSoftDelete(ctx) {
+				return nil
+			}
+			if pq, ok := asPredicateQuery(q); ok {
+				d.P(pq)
+			}
+			return nil
+		}),
+	}
+}
+
+// Hooks of the SoftDelete mixin.
+func (d SoftDelete) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hookOn(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					if IsSkipSoftDelete(ctx) || isHardDelete(ctx) {
+						return next.Mutate(ctx, m)
+					}
+
+					mx, ok := m.(opMutation)
+					if !ok {
+						return next.Mutate(ctx, m)
+					}
+					if pm, ok := m.(predicateMutation); ok {
+						d.P(pm)
+					}
+
+					mx.SetOp(ent.OpUpdate)
+					if err := mx.SetField(fieldDeletedAt, currentTimeContext(ctx)); err != nil {
+						return nil, err
+					}
+					if err := setOperator(ctx, mx, fieldDeletedBy); err != nil {
+						return nil, err
+					}
+
+					return mutateWithClient(ctx, mx)
+				})
+			},
+			ent.OpDelete|ent.OpDeleteOne,
+		),
+	}
+}
+
+// P 为查询或变更添加 deleted_at IS NULL 条件
+func (SoftDelete) P(w predicateQuery) {
+	w.WhereP(sql.FieldIsNull(fieldDeletedAt))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type softDeleteKey struct{}
+
+type hardDeleteKey struct{}
+
+// SkipSoftDelete 返回跳过软删除逻辑的 context：查询包含已删除记录，删除为物理删除
+func SkipSoftDelete(parent context.Context) context.Context {
+	return context.WithValue(parent, softDeleteKey{}, true)
+}
+
+// IsSkipSoftDelete 判断 context 是否跳过软删除逻辑
+func IsSkipSoftDelete(ctx context.Context) bool {
+	skip, _ := ctx.Value(softDeleteKey{}).(bool)
+	return skip
+}
+
+// HardDelete 返回物理删除的 context，查询仍然过滤已删除记录
+func HardDelete(parent context.Context) context.Context {
+	return context.WithValue(parent, hardDeleteKey{}, true)
+}
+
+func isHardDelete(ctx context.Context) bool {
+	hard, _ := ctx.Value(hardDeleteKey{}).(bool)
+	return hard
+}
+
+// softDeleteUpdater 由 entc 生成的 <T>Update 与 <T>UpdateOne 实现了该接口
+type softDeleteUpdater[M ent.Mutation] interface {
+	Mutation() M
+	Exec(context.Context) error
+}
+
+// Restore 恢复被软删除的记录，清空 deleted_at 与 deleted_by
+//
+// 示例:
+//
+//	err := mixin.Restore(ctx, client.User.Update().Where(user.IDEQ(id)))
+func Restore[M ent.Mutation](ctx context.Context, u softDeleteUpdater[M]) error {
+	m := u.Mutation()
+	if err := m.ClearField(fieldDeletedAt); err != nil {
+		return err
+	}
+	if err := m.ClearField(fieldDeletedBy); err != nil {
+		return err
+	}
+	if pm, ok := any(m).(predicateMutation); ok {
+		pm.WhereP(sql.FieldNotNull(fieldDeletedAt))
+	}
+	return u.Exec(SkipSoftDelete(ctx))
+}
+
+// SoftDeleteUniqueIndex 创建与软删除共存的唯一索引
+//
+// 索引只约束未删除的记录（deleted_at IS NULL），已删除的记录不再占用唯一值。
+// 部分索引仅 PostgreSQL 与 SQLite 支持；MySQL 会忽略 WHERE 条件，
+// 此时需要自行将 deleted_at 纳入唯一键并使用非 NULL 的默认值。
+func SoftDeleteUniqueIndex(fields ...string) ent.Index {
+	return index.Fields(fields...).
+		Unique().
+		Annotations(entsql.IndexWhere(fieldDeletedAt + " IS NULL"))
+}
@@ -0,0 +1,121 @@
+package mixin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"entgo.io/ent"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heyinLab/common/pkg/middleware/auth"
+	"github.com/heyinLab/common/pkg/middleware/common"
+)
+
+func TestSoftDelete_Interceptor(t *testing.T) {
+	traverser := SoftDelete{}.Interceptors()[0].(ent.Traverser)
+
+	t.Run("Default", func(t *testing.T) {
+		q := &fakeQuery{}
+		require.NoError(t, traverser.Traverse(context.Background(), q))
+		query, _ := q.where()
+		require.Equal(t, `SELECT * FROM "t" WHERE "t"."deleted_at" IS NULL`, query)
+	})
+
+	t.Run("TypedWhere", func(t *testing.T) {
+		q := &typedQuery{}
+		require.NoError(t, traverser.Traverse(context.Background(), q))
+		query, _ := q.where()
+		require.Equal(t, `SELECT * FROM "t" WHERE "t"."deleted_at" IS NULL`, query)
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		q := &fakeQuery{}
+		require.NoError(t, traverser.Traverse(SkipSoftDelete(context.Background()), q))
+		require.Empty(t, q.predicates)
+	})
+}
+
+func TestSoftDelete_Hook(t *testing.T) {
+	hook := SoftDelete{}.Hooks()[0]
+
+	t.Run("DeleteToUpdate", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), common.KeyAuthType, common.AuthTypeOpenAPI)
+		ctx = context.WithValue(ctx, common.KeyAPIKeyID, uint64(7))
+
+		m := newFakeMutation(ent.OpDeleteOne)
+		next := &recordMutator{}
+		_, err := hook(next).Mutate(ctx, m)
+		require.NoError(t, err)
+
+		require.False(t, next.called)
+		require.Equal(t, ent.OpUpdate, m.Op())
+		require.IsType(t, time.Time{}, m.fields[fieldDeletedAt])
+		require.Equal(t, uint32(7), m.fields[fieldDeletedBy])
+		require.Len(t, m.client.mutated, 1)
+
+		query, _ := m.where()
+		require.Contains(t, query, `"deleted_at" IS NULL`)
+	})
+
+	t.Run("UserOperatorWithoutID", func(t *testing.T) {
+		ctx := auth.NewContext(context.Background(), &auth.Claims{UserCode: "u1"})
+
+		m := newFakeMutation(ent.OpDelete)
+		_, err := hook(&recordMutator{}).Mutate(ctx, m)
+		require.NoError(t, err)
+		require.Equal(t, ent.OpUpdate, m.Op())
+		_, ok := m.fields[fieldDeletedBy]
+		require.False(t, ok)
+	})
+
+	t.Run("HardDelete", func(t *testing.T) {
+		m := newFakeMutation(ent.OpDelete)
+		next := &recordMutator{}
+		_, err := hook(next).Mutate(HardDelete(context.Background()), m)
+		require.NoError(t, err)
+		require.True(t, next.called)
+		require.Equal(t, ent.OpDelete, m.Op())
+		require.Empty(t, m.client.mutated)
+	})
+
+	t.Run("UpdateUntouched", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdateOne)
+		next := &recordMutator{}
+		_, err := hook(next).Mutate(context.Background(), m)
+		require.NoError(t, err)
+		require.True(t, next.called)
+		require.Empty(t, m.fields)
+	})
+}
+
+type fakeUpdater struct {
+	m   *fakeMutation
+	ctx context.Context
+}
+
+func (u *fakeUpdater) Mutation() *fakeMutation { return u.m }
+
+func (u *fakeUpdater) Exec(ctx context.Context) error {
+	u.ctx = ctx
+	return nil
+}
+
+func TestRestore(t *testing.T) {
+	u := &fakeUpdater{m: newFakeMutation(ent.OpUpdate)}
+	require.NoError(t, Restore(context.Background(), u))
+
+	require.True(t, u.m.cleared[fieldDeletedAt])
+	require.True(t, u.m.cleared[fieldDeletedBy])
+	require.True(t, IsSkipSoftDelete(u.ctx))
+
+	query, _ := u.m.where()
+	require.Contains(t, query, `"deleted_at" IS NOT NULL`)
+}
+
+func TestSoftDeleteUniqueIndex(t *testing.T) {
+	desc := SoftDeleteUniqueIndex("tenant_id", "code").Descriptor()
+	require.True(t, desc.Unique)
+	require.Equal(t, []string{"tenant_id", "code"}, desc.Fields)
+	require.Len(t, desc.Annotations, 1)
+}
@@ -1,15 +1,34 @@
 package mixin
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
 	"entgo.io/ent/schema/mixin"
+	"github.com/go-kratos/kratos/v2/log"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+	"github.com/heyinLab/common/pkg/middleware/auth"
 )
 
+const fieldTenantID = "tenant_id"
+
 // 确保 TenantID 实现了 ent.Mixin 接口
 var _ ent.Mixin = (*TenantID)(nil)
 
+// TenantID 租户隔离
+//
+// 除了 tenant_id 字段外，还提供：
+//   - 拦截器：查询自动追加 tenant_id = 当前租户
+//   - 钩子：创建时写入 tenant_id，更新/删除只作用于当前租户的数据
+//
+// 当前租户优先取 WithTenantID 注入的ID，其次通过 SetTenantResolver 注册的解析器解析 Claims.TenantCode。
+// 平台级任务需要跨租户访问时，使用 WithPlatformScope 包装 context。
 type TenantID struct{ mixin.Schema }
 
 func (TenantID) Fields() []ent.Field {
@@ -28,3 +47,181 @@ func (TenantID) Indexes() []ent.Index {
 		index.Fields("tenant_id"),
 	}
 }
+
+// Interceptors of the TenantID.
+func (TenantID) Interceptors() []ent.Interceptor {
+	return []ent.Interceptor{
+		ent.TraverseFunc(func(ctx context.Context, q ent.Query) error {
+			if IsPlatformScope(ctx) {
+				return nil
+			}
+			pq, ok := asPredicateQuery(q)
+			if !ok {
+				return nil
+			}
+			tenantID, err := TenantIDFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			pq.WhereP(sql.FieldEQ(fieldTenantID, tenantID))
+			return nil
+		}),
+	}
+}
+
+// Hooks of the TenantID.
+func (TenantID) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hookOn(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					if IsPlatformScope(ctx) {
+						return next.Mutate(ctx, m)
+					}
+					tenantID, err := TenantIDFromContext(ctx)
+					if err != nil {
+						return nil, err
+					}
+					if v, ok := m.Field(fieldTenantID); ok {
+						if id, _ := v.(uint32); id != tenantID {
+							return nil, businessErrors.WrapError(businessErrors.ErrAccessForbidden, "禁止跨租户写入数据")
+						}
+						return next.Mutate(ctx, m)
+					}
+					if err = m.SetField(fieldTenantID, tenantID); err != nil {
+						return nil, err
+					}
+					return next.Mutate(ctx, m)
+				})
+			},
+			ent.OpCreate,
+		),
+		hookOn(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					if IsPlatformScope(ctx) {
+						return next.Mutate(ctx, m)
+					}
+					pm, ok := m.(predicateMutation)
+					if !ok {
+						return next.Mutate(ctx, m)
+					}
+					tenantID, err := TenantIDFromContext(ctx)
+					if err != nil {
+						return nil, err
+					}
+					// 其他租户的数据不会命中条件，UpdateOne/DeleteOne 将返回 NotFound
+					pm.WhereP(sql.FieldEQ(fieldTenantID, tenantID))
+					return next.Mutate(ctx, m)
+				})
+			},
+			ent.OpUpdate|ent.OpUpdateOne|ent.OpDelete|ent.OpDeleteOne,
+		),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// TenantResolver 将租户编码解析为租户ID
+type TenantResolver interface {
+	ResolveTenantID(ctx context.Context, tenantCode string) (uint32, error)
+}
+
+// TenantResolverFunc 函数形式的 TenantResolver
+type TenantResolverFunc func(ctx context.Context, tenantCode string) (uint32, error)
+
+// ResolveTenantID 实现 TenantResolver 接口
+func (f TenantResolverFunc) ResolveTenantID(ctx context.Context, tenantCode string) (uint32, error) {
+	return f(ctx, tenantCode)
+}
+
+var (
+	tenantResolverMu sync.RWMutex
+	tenantResolver   TenantResolver
+)
+
+// SetTenantResolver 注册全局的租户解析器，通常在服务启动时调用一次
+func SetTenantResolver(r TenantResolver) {
+	tenantResolverMu.Lock()
+	defer tenantResolverMu.Unlock()
+	tenantResolver = r
+}
+
+func getTenantResolver() TenantResolver {
+	tenantResolverMu.RLock()
+	defer tenantResolverMu.RUnlock()
+	return tenantResolver
+}
+
+// cachedTenantResolver 缓存解析成功的租户编码
+type cachedTenantResolver struct {
+	resolver TenantResolver
+	cache    sync.Map
+}
+
+// NewCachedTenantResolver 创建带缓存的租户解析器，租户编码与ID的映射在进程内不会变化
+func NewCachedTenantResolver(r TenantResolver) TenantResolver {
+	return &cachedTenantResolver{resolver: r}
+}
+
+func (c *cachedTenantResolver) ResolveTenantID(ctx context.Context, tenantCode string) (uint32, error) {
+	if v, ok := c.cache.Load(tenantCode); ok {
+		return v.(uint32), nil
+	}
+	id, err := c.resolver.ResolveTenantID(ctx, tenantCode)
+	if err != nil {
+		return 0, err
+	}
+	c.cache.Store(tenantCode, id)
+	return id, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type tenantIDKey struct{}
+
+type platformScopeKey struct{}
+
+// WithTenantID 将已解析的租户ID注入 context，优先于 Claims.TenantCode
+func WithTenantID(parent context.Context, tenantID uint32) context.Context {
+	return context.WithValue(parent, tenantIDKey{}, tenantID)
+}
+
+// TenantIDFromContext 获取当前请求的租户ID
+func TenantIDFromContext(ctx context.Context) (uint32, error) {
+	if id, ok := ctx.Value(tenantIDKey{}).(uint32); ok {
+		return id, nil
+	}
+
+	claims, ok := auth.FromContext(ctx)
+	if !ok || claims == nil || claims.TenantCode == "" {
+		return 0, businessErrors.ErrTenantMissing
+	}
+
+	resolver := getTenantResolver()
+	if resolver == nil {
+		return 0, fmt.Errorf("mixin: tenant resolver is not configured, tenant_code=%s", claims.TenantCode)
+	}
+	return resolver.ResolveTenantID(ctx, claims.TenantCode)
+}
+
+// WithPlatformScope 返回平台级作用域的 context，跳过租户隔离
+//
+// 仅用于平台级任务（数据迁移、统计、定时任务等），reason 必填并会记录审计日志。
+func WithPlatformScope(parent context.Context, reason string) context.Context {
+	op := auth.GetOperator(parent)
+	log.Context(parent).Warnf("进入平台级作用域，跳过租户隔离: reason=%s, operator_type=%s, operator=%s", reason, op.Type, op.Identity())
+	return context.WithValue(parent, platformScopeKey{}, reason)
+}
+
+// IsPlatformScope 判断 context 是否处于平台级作用域
+func IsPlatformScope(ctx context.Context) bool {
+	_, ok := ctx.Value(platformScopeKey{}).(string)
+	return ok
+}
+
+// PlatformScopeReason 获取进入平台级作用域的原因
+func PlatformScopeReason(ctx context.Context) string {
+	reason, _ := ctx.Value(platformScopeKey{}).(string)
+	return reason
+}
@@ -0,0 +1,108 @@
+package mixin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"entgo.io/ent"
+	"github.com/stretchr/testify/require"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+	"github.com/heyinLab/common/pkg/middleware/auth"
+)
+
+func TestTenantIDFromContext(t *testing.T) {
+	calls := 0
+	SetTenantResolver(NewCachedTenantResolver(TenantResolverFunc(func(_ context.Context, code string) (uint32, error) {
+		calls++
+		if code == "t1" {
+			return 1001, nil
+		}
+		return 0, errors.New("unknown tenant")
+	})))
+	defer SetTenantResolver(nil)
+
+	_, err := TenantIDFromContext(context.Background())
+	require.ErrorIs(t, err, businessErrors.ErrTenantMissing)
+
+	ctx := auth.NewContext(context.Background(), &auth.Claims{TenantCode: "t1"})
+	for i := 0; i < 3; i++ {
+		id, err := TenantIDFromContext(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1001), id)
+	}
+	require.Equal(t, 1, calls)
+
+	id, err := TenantIDFromContext(WithTenantID(ctx, 42))
+	require.NoError(t, err)
+	require.Equal(t, uint32(42), id)
+
+	_, err = TenantIDFromContext(auth.NewContext(context.Background(), &auth.Claims{TenantCode: "t2"}))
+	require.Error(t, err)
+}
+
+func TestTenantID_Interceptor(t *testing.T) {
+	traverser := TenantID{}.Interceptors()[0].(ent.Traverser)
+
+	q := &fakeQuery{}
+	require.NoError(t, traverser.Traverse(WithTenantID(context.Background(), 7), q))
+	query, args := q.where()
+	require.Equal(t, `SELECT * FROM "t" WHERE "t"."tenant_id" = $1`, query)
+	require.Equal(t, []any{uint32(7)}, args)
+
+	tq := &typedQuery{}
+	require.NoError(t, traverser.Traverse(WithTenantID(context.Background(), 7), tq))
+	query, args = tq.where()
+	require.Equal(t, `SELECT * FROM "t" WHERE "t"."tenant_id" = $1`, query)
+	require.Equal(t, []any{uint32(7)}, args)
+
+	require.Error(t, traverser.Traverse(context.Background(), &fakeQuery{}))
+
+	q = &fakeQuery{}
+	require.NoError(t, traverser.Traverse(WithPlatformScope(context.Background(), "test"), q))
+	require.Empty(t, q.predicates)
+}
+
+func TestTenantID_Hooks(t *testing.T) {
+	hooks := TenantID{}.Hooks()
+	ctx := WithTenantID(context.Background(), 7)
+
+	t.Run("CreateStamp", func(t *testing.T) {
+		m := newFakeMutation(ent.OpCreate)
+		_, err := hooks[0](&recordMutator{}).Mutate(ctx, m)
+		require.NoError(t, err)
+		require.Equal(t, uint32(7), m.fields[fieldTenantID])
+	})
+
+	t.Run("CreateCrossTenant", func(t *testing.T) {
+		m := newFakeMutation(ent.OpCreate)
+		m.fields[fieldTenantID] = uint32(8)
+		next := &recordMutator{}
+		_, err := hooks[0](next).Mutate(ctx, m)
+		require.Error(t, err)
+		require.False(t, next.called)
+	})
+
+	t.Run("UpdateScoped", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdateOne)
+		_, err := hooks[1](&recordMutator{}).Mutate(ctx, m)
+		require.NoError(t, err)
+		query, args := m.where()
+		require.Contains(t, query, `"tenant_id" = $1`)
+		require.Equal(t, []any{uint32(7)}, args)
+	})
+
+	t.Run("DeleteWithoutTenant", func(t *testing.T) {
+		m := newFakeMutation(ent.OpDelete)
+		_, err := hooks[1](&recordMutator{}).Mutate(context.Background(), m)
+		require.ErrorIs(t, err, businessErrors.ErrTenantMissing)
+	})
+
+	t.Run("PlatformScope", func(t *testing.T) {
+		m := newFakeMutation(ent.OpDelete)
+		_, err := hooks[1](&recordMutator{}).Mutate(WithPlatformScope(context.Background(), "cleanup"), m)
+		require.NoError(t, err)
+		require.Empty(t, m.predicates)
+	})
+}
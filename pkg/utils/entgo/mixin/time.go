@@ -20,7 +20,8 @@ func (CreatedAt) Fields() []ent.Field {
 			Comment("创建时间").
 			Immutable().
 			Optional().
-			Nillable(),
+			Nillable().
+			Default(currentTime),
 	}
 }
 
@@ -36,7 +37,9 @@ func (UpdatedAt) Fields() []ent.Field {
 		field.Time("updated_at").
 			Comment("更新时间").
 			Optional().
-			Nillable(),
+			Nillable().
+			Default(currentTime).
+			UpdateDefault(currentTime),
 	}
 }
 
@@ -90,7 +93,8 @@ func (CreateTime) Fields() []ent.Field {
 			Comment("创建时间").
 			Immutable().
 			Optional().
-			Nillable(),
+			Nillable().
+			Default(currentTime),
 	}
 }
 
@@ -106,7 +110,9 @@ func (UpdateTime) Fields() []ent.Field {
 		field.Time("update_time").
 			Comment("更新时间").
 			Optional().
-			Nillable(),
+			Nillable().
+			Default(currentTime).
+			UpdateDefault(currentTime),
 	}
 }
 
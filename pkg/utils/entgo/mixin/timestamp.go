@@ -1,8 +1,6 @@
 package mixin
 
 import (
-	"time"
-
 	"entgo.io/ent"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/mixin"
@@ -22,7 +20,7 @@ func (CreateTimestamp) Fields() []ent.Field {
 			Immutable().
 			Optional().
 			Nillable().
-			DefaultFunc(time.Now().UnixMilli),
+			DefaultFunc(currentTimeMilli),
 	}
 }
 
@@ -40,7 +38,7 @@ func (UpdateTimestamp) Fields() []ent.Field {
 			Comment("更新时间").
 			Optional().
 			Nillable().
-			UpdateDefault(time.Now().UnixMilli),
+			UpdateDefault(currentTimeMilli),
 	}
 }
 
@@ -88,7 +86,7 @@ func (CreatedAtTimestamp) Fields() []ent.Field {
 			Immutable().
 			Optional().
 			Nillable().
-			DefaultFunc(time.Now().UnixMilli),
+			DefaultFunc(currentTimeMilli),
 	}
 }
 
@@ -106,7 +104,7 @@ func (UpdatedAtTimestamp) Fields() []ent.Field {
 			Comment("更新时间").
 			Optional().
 			Nillable().
-			UpdateDefault(time.Now().UnixMilli),
+			UpdateDefault(currentTimeMilli),
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 	"entgo.io/ent/schema/mixin"
 )
 
@@ -44,3 +45,30 @@ func (Tree[T]) Edges() []ent.Edge {
 			From("parent").Unique().Field("parent_id"),
 	}
 }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+var _ ent.Mixin = (*TreePath)(nil)
+
+// TreePath 物化路径，格式为 "/1/5/9/"，配合 entgo.Tree 使用，
+// 子孙查询转换为 path LIKE '/1/5/%'，可以利用索引且不依赖递归 CTE。
+type TreePath struct {
+	mixin.Schema
+}
+
+func (TreePath) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("path").
+			Comment("物化路径").
+			MaxLen(1024).
+			Optional().
+			Nillable(),
+	}
+}
+
+// Indexes of the TreePath mixin.
+func (TreePath) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("path"),
+	}
+}
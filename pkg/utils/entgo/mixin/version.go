@@ -1,13 +1,30 @@
 package mixin
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/mixin"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
 )
 
-// 注意：使用此 Mixin 时，需要在 Ent 客户端配置 Hook 或 Interceptor
-// 来在更新操作前自动检查并递增版本号。
+const fieldVersion = "version"
+
+// 使用此 Mixin 时，更新操作会自动检查并递增版本号（乐观锁）：
+//
+//	// 调用方将读取到的版本号作为期望版本写入 mutation
+//	err := client.User.UpdateOneID(id).SetVersion(u.Version).SetName("foo").Exec(ctx)
+//	// 或者通过 context 传入
+//	err := client.User.UpdateOneID(id).SetName("foo").Exec(mixin.WithExpectedVersion(ctx, u.Version))
+//
+// 钩子会将期望版本转换为 WHERE version = ?，并改写为 SET version = version + 1。
+// 没有记录被更新时返回 *VersionConflictError。
 
 var _ ent.Mixin = (*Version)(nil)
 
@@ -20,3 +37,148 @@ func (Version) Fields() []ent.Field {
 			Default(1), // 初始版本为 1
 	}
 }
+
+// Hooks of the Version mixin.
+func (Version) Hooks() []ent.Hook {
+	return []ent.Hook{
+		hookOn(
+			func(next ent.Mutator) ent.Mutator {
+				return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+					pm, ok := m.(predicateMutation)
+					if !ok {
+						return next.Mutate(ctx, m)
+					}
+
+					expected, hasExpected := expectedVersion(ctx, m)
+					if hasExpected {
+						if err := m.ResetField(fieldVersion); err != nil {
+							return nil, err
+						}
+					}
+
+					// 单条更新必须携带期望版本；批量更新可以只递增版本号
+					skip := isSkipVersionCheck(ctx)
+					if !hasExpected && !skip && m.Op().Is(ent.OpUpdateOne) {
+						return nil, businessErrors.WrapError(businessErrors.ErrMissingParameter, "缺少期望的版本号")
+					}
+					checked := hasExpected && !skip
+					if checked {
+						pm.WhereP(sql.FieldEQ(fieldVersion, expected))
+					}
+					// ent 生成的 AddField 对无符号字段使用有符号的增量类型，uint32 字段为 int32
+					if err := m.AddField(fieldVersion, int32(1)); err != nil {
+						return nil, err
+					}
+
+					v, err := next.Mutate(ctx, m)
+					if !checked {
+						return v, err
+					}
+					if err != nil {
+						if isNotFound(err) {
+							return nil, &VersionConflictError{Type: m.Type(), Expected: expected, err: err}
+						}
+						return nil, err
+					}
+					if n, ok := v.(int); ok && n == 0 {
+						return nil, &VersionConflictError{Type: m.Type(), Expected: expected}
+					}
+					return v, nil
+				})
+			},
+			ent.OpUpdate|ent.OpUpdateOne,
+		),
+	}
+}
+
+// expectedVersion 获取期望版本，mutation 中显式设置的值优先于 context
+func expectedVersion(ctx context.Context, m ent.Mutation) (uint32, bool) {
+	if v, ok := m.Field(fieldVersion); ok {
+		if version, ok := v.(uint32); ok {
+			return version, true
+		}
+	}
+	version, ok := ctx.Value(expectedVersionKey{}).(uint32)
+	return version, ok
+}
+
+// isNotFound 判断是否为 entc 生成的 *NotFoundError
+func isNotFound(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		t := reflect.TypeOf(err)
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Name() == "NotFoundError" {
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type expectedVersionKey struct{}
+
+type skipVersionCheckKey struct{}
+
+// WithExpectedVersion 将期望版本注入 context
+func WithExpectedVersion(parent context.Context, version uint32) context.Context {
+	return context.WithValue(parent, expectedVersionKey{}, version)
+}
+
+// SkipVersionCheck 返回跳过版本校验的 context，版本号仍会递增
+func SkipVersionCheck(parent context.Context) context.Context {
+	return context.WithValue(parent, skipVersionCheckKey{}, true)
+}
+
+func isSkipVersionCheck(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipVersionCheckKey{}).(bool)
+	return skip
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// VersionConflictError 乐观锁冲突
+type VersionConflictError struct {
+	Type     string // 实体类型
+	Expected uint32 // 期望的版本号
+	err      error
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("mixin: version conflict on %s, expected version %d", e.Type, e.Expected)
+}
+
+// Unwrap 使 errors.Is(err, businessErrors.ErrDataConflict) 成立
+func (e *VersionConflictError) Unwrap() []error {
+	if e.err != nil {
+		return []error{businessErrors.ErrDataConflict, e.err}
+	}
+	return []error{businessErrors.ErrDataConflict}
+}
+
+// IsVersionConflict 判断是否为乐观锁冲突
+func IsVersionConflict(err error) bool {
+	var e *VersionConflictError
+	return errors.As(err, &e)
+}
+
+// RetryOnVersionConflict 在乐观锁冲突时重试 read-modify-write 操作
+//
+// fn 每次都需要重新读取最新数据并携带新的版本号更新，attempts 为最大尝试次数。
+func RetryOnVersionConflict(ctx context.Context, attempts int, fn func(ctx context.Context) error) error {
+	if attempts <= 0 {
+		attempts = 1
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = fn(ctx); err == nil || !IsVersionConflict(err) {
+			return err
+		}
+	}
+	return err
+}
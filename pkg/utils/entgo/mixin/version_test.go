@@ -0,0 +1,107 @@
+package mixin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"entgo.io/ent"
+	"github.com/stretchr/testify/require"
+
+	businessErrors "github.com/heyinLab/common/pkg/errors"
+)
+
+// NotFoundError 模拟 entc 生成的 *NotFoundError
+type NotFoundError struct{}
+
+func (*NotFoundError) Error() string { return "ent: fake not found" }
+
+type errMutator struct{ err error }
+
+func (e errMutator) Mutate(context.Context, ent.Mutation) (ent.Value, error) { return nil, e.err }
+
+func TestVersion_Hook(t *testing.T) {
+	hook := Version{}.Hooks()[0]
+
+	t.Run("ExpectedFromMutation", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdateOne)
+		m.fields[fieldVersion] = uint32(3)
+
+		_, err := hook(&recordMutator{}).Mutate(context.Background(), m)
+		require.NoError(t, err)
+
+		_, set := m.fields[fieldVersion]
+		require.False(t, set)
+		require.Equal(t, int32(1), m.added[fieldVersion])
+		query, args := m.where()
+		require.Contains(t, query, `"version" = $1`)
+		require.Equal(t, []any{uint32(3)}, args)
+	})
+
+	t.Run("ExpectedFromContext", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdate)
+		_, err := hook(&recordMutator{value: 1}).Mutate(WithExpectedVersion(context.Background(), 5), m)
+		require.NoError(t, err)
+		_, args := m.where()
+		require.Equal(t, []any{uint32(5)}, args)
+	})
+
+	t.Run("MissingExpected", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdateOne)
+		next := &recordMutator{}
+		_, err := hook(next).Mutate(context.Background(), m)
+		require.Error(t, err)
+		require.False(t, next.called)
+	})
+
+	t.Run("BulkWithoutExpected", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdate)
+		_, err := hook(&recordMutator{value: 0}).Mutate(context.Background(), m)
+		require.NoError(t, err)
+		require.Empty(t, m.predicates)
+		require.Equal(t, int32(1), m.added[fieldVersion])
+	})
+
+	t.Run("ConflictOnNotFound", func(t *testing.T) {
+		m := newFakeMutation(ent.OpUpdateOne)
+		m.fields[fieldVersion] = uint32(3)
+		_, err := hook(errMutator{err: &NotFoundError{}}).Mutate(context.Background(), m)
+		require.True(t, IsVersionConflict(err))
+		require.ErrorIs(t, err, businessErrors.ErrDataConflict)
+	})
+
+	t.Run("ConflictOnZeroRows", func(t *testing.T) {
+		_, err := hook(&recordMutator{value: 0}).Mutate(WithExpectedVersion(context.Background(), 1), newFakeMutation(ent.OpUpdate))
+		require.True(t, IsVersionConflict(err))
+	})
+}
+
+func TestRetryOnVersionConflict(t *testing.T) {
+	calls := 0
+	err := RetryOnVersionConflict(context.Background(), 3, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return &VersionConflictError{Type: "Fake", Expected: uint32(calls)}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+
+	calls = 0
+	err = RetryOnVersionConflict(context.Background(), 2, func(context.Context) error {
+		calls++
+		return &VersionConflictError{Type: "Fake"}
+	})
+	require.True(t, IsVersionConflict(err))
+	require.Equal(t, 2, calls)
+
+	boom := errors.New("boom")
+	calls = 0
+	err = RetryOnVersionConflict(context.Background(), 5, func(context.Context) error {
+		calls++
+		return boom
+	})
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, 1, calls)
+}
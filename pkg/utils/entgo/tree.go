@@ -0,0 +1,615 @@
+package entgo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"entgo.io/ent/dialect"
+
+	entSql "entgo.io/ent/dialect/sql"
+)
+
+// maxTreeDepth 递归查询的最大深度，防止脏数据中的环导致无限递归（MySQL 默认 cte_max_recursion_depth 为 1000）
+const maxTreeDepth = 512
+
+// treePathSeparator 物化路径分隔符，路径格式为 "/1/5/9/"
+const treePathSeparator = "/"
+
+var (
+	// ErrTreeCycle 移动节点到自身或其子孙节点下
+	ErrTreeCycle = errors.New("entgo: cannot move a node under itself or its descendants")
+	// ErrTreeNodeNotFound 节点不存在
+	ErrTreeNodeNotFound = errors.New("entgo: tree node not found")
+	// ErrUnsupportedDialect 不支持的数据库方言
+	ErrUnsupportedDialect = errors.New("entgo: unsupported dialect")
+)
+
+// TreeTable 树形表的结构描述
+type TreeTable struct {
+	// Table 表名
+	Table string
+	// IDColumn 主键列，默认 id
+	IDColumn string
+	// ParentColumn 父节点列，默认 parent_id
+	ParentColumn string
+	// SortColumn 兄弟节点排序列（对应 mixin.SortOrder），为空时按主键排序
+	SortColumn string
+	// PathColumn 物化路径列（对应 mixin.TreePath），为空时使用递归 CTE 查询
+	PathColumn string
+}
+
+// TreeNode 树节点
+type TreeNode struct {
+	ID        uint32
+	ParentID  *uint32
+	Depth     int    // 相对查询起点的深度，子节点为 1
+	SortOrder int32  // 未配置 SortColumn 时为 0
+	Path      string // 未配置 PathColumn 时为空
+}
+
+// Tree 树形表操作，支持 MySQL、PostgreSQL 与 SQLite
+type Tree struct {
+	drv   *entSql.Driver
+	table TreeTable
+}
+
+// NewTree 创建树形表操作
+func NewTree(drv *entSql.Driver, table TreeTable) *Tree {
+	if table.IDColumn == "" {
+		table.IDColumn = "id"
+	}
+	if table.ParentColumn == "" {
+		table.ParentColumn = "parent_id"
+	}
+	return &Tree{drv: drv, table: table}
+}
+
+// Children 查询直接子节点，parentID 为 nil 时查询根节点
+func (t *Tree) Children(ctx context.Context, parentID *uint32) ([]TreeNode, error) {
+	if err := t.checkDialect(); err != nil {
+		return nil, err
+	}
+	return t.children(ctx, t.drv, parentID)
+}
+
+// Descendants 查询子孙节点，按深度、排序值、主键排序。maxDepth <= 0 表示不限深度
+func (t *Tree) Descendants(ctx context.Context, id uint32, maxDepth int) ([]TreeNode, error) {
+	if err := t.checkDialect(); err != nil {
+		return nil, err
+	}
+	if maxDepth <= 0 || maxDepth > maxTreeDepth {
+		maxDepth = maxTreeDepth
+	}
+	if t.table.PathColumn != "" {
+		return t.descendantsByPath(ctx, t.drv, id, maxDepth)
+	}
+	return t.descendantsByCTE(ctx, t.drv, id, maxDepth)
+}
+
+// DescendantIDs 查询子孙节点ID
+func (t *Tree) DescendantIDs(ctx context.Context, id uint32, maxDepth int) ([]uint32, error) {
+	nodes, err := t.Descendants(ctx, id, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint32, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids, nil
+}
+
+// Ancestors 查询祖先节点，从根节点开始排列，不包含节点自身
+func (t *Tree) Ancestors(ctx context.Context, id uint32) ([]TreeNode, error) {
+	if err := t.checkDialect(); err != nil {
+		return nil, err
+	}
+	return t.ancestors(ctx, t.drv, id)
+}
+
+// PathFromRoot 查询从根节点到该节点的ID路径，包含节点自身
+func (t *Tree) PathFromRoot(ctx context.Context, id uint32) ([]uint32, error) {
+	ancestors, err := t.Ancestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint32, 0, len(ancestors)+1)
+	for _, n := range ancestors {
+		ids = append(ids, n.ID)
+	}
+	return append(ids, id), nil
+}
+
+// Move 将节点及其子树移动到 newParentID 下，newParentID 为 nil 时移动为根节点
+//
+// 目标父节点是节点自身或其子孙时返回 ErrTreeCycle。配置了 SortColumn 时节点排在新兄弟节点的最后，
+// 配置了 PathColumn 时同步更新整棵子树的物化路径。
+func (t *Tree) Move(ctx context.Context, id uint32, newParentID *uint32) error {
+	if err := t.checkDialect(); err != nil {
+		return err
+	}
+
+	tx, err := t.drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	if err = t.move(ctx, tx, id, newParentID); err != nil {
+		return Rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// Reorder 按 orderedIDs 的顺序重新设置兄弟节点的排序值（从 0 开始）
+func (t *Tree) Reorder(ctx context.Context, parentID *uint32, orderedIDs []uint32) error {
+	if err := t.checkDialect(); err != nil {
+		return err
+	}
+	if t.table.SortColumn == "" {
+		return errors.New("entgo: tree sort column is not configured")
+	}
+
+	tx, err := t.drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	if err = t.reorder(ctx, tx, parentID, orderedIDs); err != nil {
+		return Rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// RefreshPath 根据父节点路径重新计算节点的物化路径，通常在创建节点后调用
+func (t *Tree) RefreshPath(ctx context.Context, id uint32) error {
+	if err := t.checkPath(); err != nil {
+		return err
+	}
+	node, err := t.node(ctx, t.drv, id)
+	if err != nil {
+		return err
+	}
+	parentPath := ""
+	if node.ParentID != nil {
+		parent, err := t.node(ctx, t.drv, *node.ParentID)
+		if err != nil {
+			return err
+		}
+		parentPath = parent.Path
+	}
+	return t.exec(ctx, t.drv,
+		fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", t.q(t.table.Table), t.q(t.table.PathColumn), t.q(t.table.IDColumn)),
+		BuildTreePath(parentPath, id), id,
+	)
+}
+
+// RebuildPaths 根据 parent_id 重建整张表的物化路径
+func (t *Tree) RebuildPaths(ctx context.Context) error {
+	if err := t.checkPath(); err != nil {
+		return err
+	}
+
+	tx, err := t.drv.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	if err = t.rebuildPaths(ctx, tx); err != nil {
+		return Rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// BuildTreePath 根据父节点路径生成物化路径，根节点的 parentPath 为空
+func BuildTreePath(parentPath string, id uint32) string {
+	if parentPath == "" {
+		parentPath = treePathSeparator
+	}
+	return parentPath + strconv.FormatUint(uint64(id), 10) + treePathSeparator
+}
+
+// ParseTreePath 解析物化路径中的节点ID，从根节点开始排列
+func ParseTreePath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.Trim(path, treePathSeparator), treePathSeparator)
+	ids := make([]uint32, 0, len(parts))
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("entgo: invalid tree path %q: %w", path, err)
+		}
+		ids = append(ids, uint32(id))
+	}
+	return ids, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (t *Tree) checkDialect() error {
+	switch t.drv.Dialect() {
+	case dialect.MySQL, dialect.Postgres, dialect.SQLite:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedDialect, t.drv.Dialect())
+	}
+}
+
+func (t *Tree) checkPath() error {
+	if err := t.checkDialect(); err != nil {
+		return err
+	}
+	if t.table.PathColumn == "" {
+		return errors.New("entgo: tree path column is not configured")
+	}
+	return nil
+}
+
+// q 按方言转义标识符
+func (t *Tree) q(ident string) string {
+	if t.drv.Dialect() == dialect.MySQL {
+		return "`" + ident + "`"
+	}
+	return `"` + ident + `"`
+}
+
+// rebind 将 ? 占位符转换为方言对应的占位符
+func (t *Tree) rebind(query string) string {
+	if t.drv.Dialect() != dialect.Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// selectColumns 返回查询节点所需的列，别名为 alias
+func (t *Tree) selectColumns(alias string) string {
+	cols := []string{
+		alias + "." + t.q(t.table.IDColumn),
+		alias + "." + t.q(t.table.ParentColumn),
+	}
+	if t.table.SortColumn != "" {
+		cols = append(cols, alias+"."+t.q(t.table.SortColumn))
+	} else {
+		cols = append(cols, "0")
+	}
+	if t.table.PathColumn != "" {
+		cols = append(cols, alias+"."+t.q(t.table.PathColumn))
+	} else {
+		cols = append(cols, "''")
+	}
+	return strings.Join(cols, ", ")
+}
+
+// orderBy 兄弟节点的排序
+func (t *Tree) orderBy(alias string) string {
+	if t.table.SortColumn != "" {
+		return alias + "." + t.q(t.table.SortColumn) + ", " + alias + "." + t.q(t.table.IDColumn)
+	}
+	return alias + "." + t.q(t.table.IDColumn)
+}
+
+func (t *Tree) exec(ctx context.Context, eq dialect.ExecQuerier, query string, args ...any) error {
+	return eq.Exec(ctx, t.rebind(query), args, nil)
+}
+
+// queryNodes 执行查询并扫描为节点，query 的列顺序需与 selectColumns 一致，withDepth 时末尾多一列 depth
+func (t *Tree) queryNodes(ctx context.Context, eq dialect.ExecQuerier, withDepth bool, query string, args ...any) ([]TreeNode, error) {
+	rows := &entSql.Rows{}
+	if err := eq.Query(ctx, t.rebind(query), args, rows); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []TreeNode
+	for rows.Next() {
+		var (
+			node      TreeNode
+			parentID  sql.NullInt64
+			sortOrder sql.NullInt64
+			path      sql.NullString
+		)
+		dest := []any{&node.ID, &parentID, &sortOrder, &path}
+		if withDepth {
+			dest = append(dest, &node.Depth)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if parentID.Valid && parentID.Int64 > 0 {
+			pid := uint32(parentID.Int64)
+			node.ParentID = &pid
+		}
+		node.SortOrder = int32(sortOrder.Int64)
+		node.Path = path.String
+		nodes = append(nodes, node)
+	}
+	return nodes, rows.Err()
+}
+
+func (t *Tree) node(ctx context.Context, eq dialect.ExecQuerier, id uint32) (*TreeNode, error) {
+	nodes, err := t.queryNodes(ctx, eq, false,
+		fmt.Sprintf("SELECT %s FROM %s n WHERE n.%s = ?", t.selectColumns("n"), t.q(t.table.Table), t.q(t.table.IDColumn)),
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: id=%d", ErrTreeNodeNotFound, id)
+	}
+	return &nodes[0], nil
+}
+
+// parentCondition 生成父节点条件，根节点的 parent_id 可能为 NULL 或 0
+func (t *Tree) parentCondition(alias string, parentID *uint32) (string, []any) {
+	col := alias + "." + t.q(t.table.ParentColumn)
+	if parentID == nil || *parentID == 0 {
+		return fmt.Sprintf("(%s IS NULL OR %s = 0)", col, col), nil
+	}
+	return col + " = ?", []any{*parentID}
+}
+
+func (t *Tree) children(ctx context.Context, eq dialect.ExecQuerier, parentID *uint32) ([]TreeNode, error) {
+	cond, args := t.parentCondition("n", parentID)
+	nodes, err := t.queryNodes(ctx, eq, false,
+		fmt.Sprintf("SELECT %s FROM %s n WHERE %s ORDER BY %s", t.selectColumns("n"), t.q(t.table.Table), cond, t.orderBy("n")),
+		args...,
+	)
+	for i := range nodes {
+		nodes[i].Depth = 1
+	}
+	return nodes, err
+}
+
+func (t *Tree) descendantsByCTE(ctx context.Context, eq dialect.ExecQuerier, id uint32, maxDepth int) ([]TreeNode, error) {
+	table, idCol, parentCol := t.q(t.table.Table), t.q(t.table.IDColumn), t.q(t.table.ParentColumn)
+	query := fmt.Sprintf(`WITH RECURSIVE descendants (node_id, depth) AS (
+	SELECT c.%[2]s, 1 FROM %[1]s c WHERE c.%[3]s = ?
+	UNION ALL
+	SELECT c.%[2]s, d.depth + 1 FROM %[1]s c INNER JOIN descendants d ON c.%[3]s = d.node_id WHERE d.depth < ?
+)
+SELECT %[4]s, d.depth FROM descendants d INNER JOIN %[1]s n ON n.%[2]s = d.node_id ORDER BY d.depth, %[5]s`,
+		table, idCol, parentCol, t.selectColumns("n"), t.orderBy("n"),
+	)
+	return t.queryNodes(ctx, eq, true, query, id, maxDepth)
+}
+
+func (t *Tree) descendantsByPath(ctx context.Context, eq dialect.ExecQuerier, id uint32, maxDepth int) ([]TreeNode, error) {
+	node, err := t.node(ctx, eq, id)
+	if err != nil {
+		return nil, err
+	}
+	if node.Path == "" {
+		return nil, fmt.Errorf("entgo: tree path of node %d is empty, call RefreshPath or RebuildPaths first", id)
+	}
+	nodes, err := t.queryNodes(ctx, eq, false,
+		fmt.Sprintf("SELECT %s FROM %s n WHERE n.%s LIKE ? AND n.%s <> ?",
+			t.selectColumns("n"), t.q(t.table.Table), t.q(t.table.PathColumn), t.q(t.table.IDColumn)),
+		node.Path+"%", id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.Count(node.Path, treePathSeparator)
+	result := nodes[:0]
+	for _, n := range nodes {
+		n.Depth = strings.Count(n.Path, treePathSeparator) - base
+		if n.Depth <= maxDepth {
+			result = append(result, n)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b TreeNode) int {
+		if a.Depth != b.Depth {
+			return a.Depth - b.Depth
+		}
+		if a.SortOrder != b.SortOrder {
+			return int(a.SortOrder) - int(b.SortOrder)
+		}
+		return int(a.ID) - int(b.ID)
+	})
+	return result, nil
+}
+
+func (t *Tree) ancestors(ctx context.Context, eq dialect.ExecQuerier, id uint32) ([]TreeNode, error) {
+	if t.table.PathColumn != "" {
+		return t.ancestorsByPath(ctx, eq, id)
+	}
+
+	table, idCol, parentCol := t.q(t.table.Table), t.q(t.table.IDColumn), t.q(t.table.ParentColumn)
+	query := fmt.Sprintf(`WITH RECURSIVE ancestors (node_id, parent_id, depth) AS (
+	SELECT c.%[2]s, c.%[3]s, 0 FROM %[1]s c WHERE c.%[2]s = ?
+	UNION ALL
+	SELECT p.%[2]s, p.%[3]s, a.depth + 1 FROM %[1]s p INNER JOIN ancestors a ON p.%[2]s = a.parent_id WHERE a.depth < ?
+)
+SELECT %[4]s, a.depth FROM ancestors a INNER JOIN %[1]s n ON n.%[2]s = a.node_id WHERE a.depth > 0 ORDER BY a.depth DESC`,
+		table, idCol, parentCol, t.selectColumns("n"),
+	)
+	nodes, err := t.queryNodes(ctx, eq, true, query, id, maxTreeDepth)
+	if err != nil {
+		return nil, err
+	}
+	// 深度以根节点为起点，与 Descendants 的方向保持一致
+	for i := range nodes {
+		nodes[i].Depth = i
+	}
+	return nodes, nil
+}
+
+func (t *Tree) ancestorsByPath(ctx context.Context, eq dialect.ExecQuerier, id uint32) ([]TreeNode, error) {
+	node, err := t.node(ctx, eq, id)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := ParseTreePath(node.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 && ids[len(ids)-1] == id {
+		ids = ids[:len(ids)-1]
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]any, 0, len(ids))
+	for _, v := range ids {
+		args = append(args, v)
+	}
+	nodes, err := t.queryNodes(ctx, eq, false,
+		fmt.Sprintf("SELECT %s FROM %s n WHERE n.%s IN (%s)", t.selectColumns("n"), t.q(t.table.Table), t.q(t.table.IDColumn), placeholders),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(nodes, func(a, b TreeNode) int {
+		return slices.Index(ids, a.ID) - slices.Index(ids, b.ID)
+	})
+	for i := range nodes {
+		nodes[i].Depth = i
+	}
+	return nodes, nil
+}
+
+func (t *Tree) move(ctx context.Context, eq dialect.ExecQuerier, id uint32, newParentID *uint32) error {
+	node, err := t.node(ctx, eq, id)
+	if err != nil {
+		return err
+	}
+
+	var parentPath string
+	if newParentID != nil && *newParentID != 0 {
+		if *newParentID == id {
+			return ErrTreeCycle
+		}
+		parent, err := t.node(ctx, eq, *newParentID)
+		if err != nil {
+			return err
+		}
+		ancestors, err := t.ancestors(ctx, eq, parent.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range ancestors {
+			if a.ID == id {
+				return ErrTreeCycle
+			}
+		}
+		parentPath = parent.Path
+	} else {
+		newParentID = nil
+	}
+
+	table, idCol := t.q(t.table.Table), t.q(t.table.IDColumn)
+	if newParentID == nil {
+		err = t.exec(ctx, eq, fmt.Sprintf("UPDATE %s SET %s = NULL WHERE %s = ?", table, t.q(t.table.ParentColumn), idCol), id)
+	} else {
+		err = t.exec(ctx, eq, fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", table, t.q(t.table.ParentColumn), idCol), *newParentID, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	if t.table.SortColumn != "" {
+		siblings, err := t.children(ctx, eq, newParentID)
+		if err != nil {
+			return err
+		}
+		var next int32
+		for _, s := range siblings {
+			if s.ID != id && s.SortOrder >= next {
+				next = s.SortOrder + 1
+			}
+		}
+		if err = t.exec(ctx, eq, fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", table, t.q(t.table.SortColumn), idCol), next, id); err != nil {
+			return err
+		}
+	}
+
+	if t.table.PathColumn != "" && node.Path != "" {
+		newPath := BuildTreePath(parentPath, id)
+		pathCol := t.q(t.table.PathColumn)
+		var concat string
+		if t.drv.Dialect() == dialect.MySQL {
+			concat = fmt.Sprintf("CONCAT(?, SUBSTR(%s, ?))", pathCol)
+		} else {
+			concat = fmt.Sprintf("CAST(? AS VARCHAR(1024)) || SUBSTR(%s, ?)", pathCol)
+		}
+		if err = t.exec(ctx, eq,
+			fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s LIKE ?", table, pathCol, concat, pathCol),
+			newPath, len(node.Path)+1, node.Path+"%",
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Tree) reorder(ctx context.Context, eq dialect.ExecQuerier, parentID *uint32, orderedIDs []uint32) error {
+	siblings, err := t.children(ctx, eq, parentID)
+	if err != nil {
+		return err
+	}
+	for _, id := range orderedIDs {
+		if !slices.ContainsFunc(siblings, func(n TreeNode) bool { return n.ID == id }) {
+			return fmt.Errorf("%w: id=%d is not a child of the given parent", ErrTreeNodeNotFound, id)
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", t.q(t.table.Table), t.q(t.table.SortColumn), t.q(t.table.IDColumn))
+	for i, id := range orderedIDs {
+		if err = t.exec(ctx, eq, query, int32(i), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Tree) rebuildPaths(ctx context.Context, eq dialect.ExecQuerier) error {
+	query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", t.q(t.table.Table), t.q(t.table.PathColumn), t.q(t.table.IDColumn))
+
+	type item struct {
+		parentID *uint32
+		path     string
+	}
+	queue := []item{{}}
+	visited := map[uint32]bool{}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		children, err := t.children(ctx, eq, cur.parentID)
+		if err != nil {
+			return err
+		}
+		for _, c := range children {
+			if visited[c.ID] {
+				return fmt.Errorf("%w: id=%d", ErrTreeCycle, c.ID)
+			}
+			visited[c.ID] = true
+
+			path := BuildTreePath(cur.path, c.ID)
+			if err = t.exec(ctx, eq, query, path, c.ID); err != nil {
+				return err
+			}
+			id := c.ID
+			queue = append(queue, item{parentID: &id, path: path})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package entgo
+
+import (
+	"context"
+	"testing"
+
+	"entgo.io/ent/dialect"
+	entSql "entgo.io/ent/dialect/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTree 创建如下结构的树：
+//
+//	1
+//	├── 2
+//	│   ├── 4
+//	│   └── 5
+//	│       └── 7
+//	└── 3
+//	    └── 6
+//	8
+func newTestTree(t *testing.T, withPath bool) *Tree {
+	drv, err := entSql.Open(dialect.SQLite, "file:tree?mode=memory&_fk=1")
+	require.NoError(t, err)
+	drv.DB().SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = drv.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, drv.Exec(ctx, `CREATE TABLE menus (id INTEGER PRIMARY KEY, parent_id INTEGER NULL, sort_order INTEGER DEFAULT 0, path VARCHAR(1024) NULL)`, []any{}, nil))
+
+	rows := [][]any{
+		{1, nil, 0}, {2, 1, 1}, {3, 1, 0}, {4, 2, 1}, {5, 2, 0}, {6, 3, 0}, {7, 5, 0}, {8, nil, 1},
+	}
+	for _, r := range rows {
+		require.NoError(t, drv.Exec(ctx, `INSERT INTO menus (id, parent_id, sort_order) VALUES (?, ?, ?)`, r, nil))
+	}
+
+	table := TreeTable{Table: "menus", SortColumn: "sort_order"}
+	if withPath {
+		table.PathColumn = "path"
+	}
+	tree := NewTree(drv, table)
+	if withPath {
+		require.NoError(t, tree.RebuildPaths(ctx))
+	}
+	return tree
+}
+
+func nodeIDs(nodes []TreeNode) []uint32 {
+	ids := make([]uint32, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func uint32Ptr(v uint32) *uint32 { return &v }
+
+func TestTree(t *testing.T) {
+	for _, withPath := range []bool{false, true} {
+		name := "CTE"
+		if withPath {
+			name = "MaterializedPath"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			tree := newTestTree(t, withPath)
+
+			roots, err := tree.Children(ctx, nil)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{1, 8}, nodeIDs(roots))
+
+			children, err := tree.Children(ctx, uint32Ptr(1))
+			require.NoError(t, err)
+			require.Equal(t, []uint32{3, 2}, nodeIDs(children))
+
+			descendants, err := tree.Descendants(ctx, 1, 0)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{3, 2, 5, 6, 4, 7}, nodeIDs(descendants))
+			require.Equal(t, 3, descendants[len(descendants)-1].Depth)
+
+			descendants, err = tree.Descendants(ctx, 1, 2)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{3, 2, 5, 6, 4}, nodeIDs(descendants))
+
+			ancestors, err := tree.Ancestors(ctx, 7)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{1, 2, 5}, nodeIDs(ancestors))
+
+			path, err := tree.PathFromRoot(ctx, 7)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{1, 2, 5, 7}, path)
+
+			// 环检测
+			require.ErrorIs(t, tree.Move(ctx, 2, uint32Ptr(7)), ErrTreeCycle)
+			require.ErrorIs(t, tree.Move(ctx, 2, uint32Ptr(2)), ErrTreeCycle)
+			require.ErrorIs(t, tree.Move(ctx, 2, uint32Ptr(99)), ErrTreeNodeNotFound)
+
+			// 移动子树到另一个根节点下
+			require.NoError(t, tree.Move(ctx, 5, uint32Ptr(8)))
+			path, err = tree.PathFromRoot(ctx, 7)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{8, 5, 7}, path)
+
+			ids, err := tree.DescendantIDs(ctx, 8, 0)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{5, 7}, ids)
+
+			ids, err = tree.DescendantIDs(ctx, 2, 0)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{4}, ids)
+
+			// 移动为根节点，排在最后
+			require.NoError(t, tree.Move(ctx, 3, nil))
+			roots, err = tree.Children(ctx, nil)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{1, 8, 3}, nodeIDs(roots))
+			require.Equal(t, int32(2), roots[2].SortOrder)
+
+			ancestors, err = tree.Ancestors(ctx, 6)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{3}, nodeIDs(ancestors))
+
+			// 兄弟节点排序
+			require.NoError(t, tree.Reorder(ctx, nil, []uint32{3, 1, 8}))
+			roots, err = tree.Children(ctx, nil)
+			require.NoError(t, err)
+			require.Equal(t, []uint32{3, 1, 8}, nodeIDs(roots))
+			require.ErrorIs(t, tree.Reorder(ctx, nil, []uint32{2}), ErrTreeNodeNotFound)
+
+			if withPath {
+				node, err := tree.node(ctx, tree.drv, 7)
+				require.NoError(t, err)
+				require.Equal(t, "/8/5/7/", node.Path)
+			}
+		})
+	}
+}
+
+func TestQueryAllChildrenIds(t *testing.T) {
+	tree := newTestTree(t, false)
+	client := NewEntClient[*entSql.Driver](tree.drv, tree.drv)
+
+	ids, err := QueryAllChildrenIds(context.Background(), client, "menus", 2)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint32{4, 5, 7}, ids)
+
+	ids, err = QueryAllChildrenIds(context.Background(), client, "menus", 7)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func TestTreePath(t *testing.T) {
+	require.Equal(t, "/1/", BuildTreePath("", 1))
+	require.Equal(t, "/1/5/9/", BuildTreePath("/1/5/", 9))
+
+	ids, err := ParseTreePath("/1/5/9/")
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1, 5, 9}, ids)
+
+	_, err = ParseTreePath("/1/x/")
+	require.Error(t, err)
+}
+
+func TestTree_Rebind(t *testing.T) {
+	tree := NewTree(entSql.OpenDB(dialect.Postgres, nil), TreeTable{Table: "menus"})
+	require.Equal(t, `SELECT * FROM "menus" WHERE a = $1 AND b = $2`, tree.rebind(`SELECT * FROM `+tree.q("menus")+` WHERE a = ? AND b = ?`))
+
+	tree = NewTree(entSql.OpenDB(dialect.MySQL, nil), TreeTable{Table: "menus"})
+	require.Equal(t, "SELECT * FROM `menus` WHERE a = ?", tree.rebind(`SELECT * FROM `+tree.q("menus")+` WHERE a = ?`))
+}
@@ -0,0 +1,169 @@
+package geoip
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// DefaultCacheSize 默认缓存条数
+const DefaultCacheSize = 10000
+
+// CacheOption Cache 配置项
+type CacheOption func(*Cache)
+
+// WithCacheSize 设置最大缓存条数
+func WithCacheSize(size int) CacheOption {
+	return func(c *Cache) {
+		if size > 0 {
+			c.size = size
+		}
+	}
+}
+
+// WithCacheTTL 设置缓存有效期，默认不过期
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *Cache) { c.ttl = ttl }
+}
+
+// WithCacheClock 设置判断缓存过期的时钟，默认为 clock.Default()
+func WithCacheClock(clk clock.Clock) CacheOption {
+	return func(c *Cache) {
+		if clk != nil {
+			c.clock = clk
+		}
+	}
+}
+
+// WithCacheErrors 缓存 ErrNotFound 结果，避免重复查询数据库中不存在的 IP
+func WithCacheErrors() CacheOption {
+	return func(c *Cache) { c.cacheErrors = true }
+}
+
+type cacheEntry struct {
+	ip       string
+	res      Result
+	err      error
+	expireAt time.Time
+}
+
+// Cache 带 LRU 缓存的 GeoIP
+//
+// 只缓存成功的结果（WithCacheErrors 时也缓存 ErrNotFound），数据库重新加载后应调用 Purge。
+type Cache struct {
+	geo         GeoIP
+	size        int
+	ttl         time.Duration
+	cacheErrors bool
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	clock   clock.Clock
+
+	hits   uint64
+	misses uint64
+}
+
+var _ GeoIP = (*Cache)(nil)
+
+// NewCache 为 geo 增加 LRU 缓存
+func NewCache(geo GeoIP, opts ...CacheOption) *Cache {
+	c := &Cache{
+		geo:     geo,
+		size:    DefaultCacheSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		clock:   clock.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Query 实现 GeoIP
+func (c *Cache) Query(queryIp string) (Result, error) {
+	// 同一地址的不同写法（如 IPv4 映射地址）共用缓存
+	key := queryIp
+	if addr, err := ParseIP(queryIp); err == nil {
+		key = addr.String()
+	}
+
+	if entry, ok := c.get(key); ok {
+		res := entry.res
+		res.IP = queryIp
+		return res, entry.err
+	}
+
+	res, err := c.geo.Query(queryIp)
+	if err == nil || (c.cacheErrors && errors.Is(err, ErrNotFound)) {
+		c.put(key, res, err)
+	}
+	return res, err
+}
+
+func (c *Cache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expireAt.IsZero() && c.clock.Now().After(entry.expireAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		c.misses++
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	c.hits++
+	return entry, true
+}
+
+func (c *Cache) put(key string, res Result, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &cacheEntry{ip: key, res: res, err: err}
+	if c.ttl > 0 {
+		entry.expireAt = c.clock.Now().Add(c.ttl)
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).ip)
+	}
+}
+
+// Purge 清空缓存
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+// Len 当前缓存条数
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Stats 返回命中与未命中次数
+func (c *Cache) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
@@ -0,0 +1,93 @@
+package geoip
+
+import "strings"
+
+// Chain 组合多个数据源的 GeoIP
+//
+// 查询顺序:
+//  1. 内网及保留地址直接返回 PrivateResult，不查询数据源
+//  2. 依次查询 providers，使用第一个成功的结果
+//  3. 结果所在国家配置了更精确的数据源时（Prefer），用其省、市、运营商覆盖结果，
+//     国家代码、经纬度、时区、ASN 等保留原结果
+//
+// 示例（境外使用 GeoLite，中国大陆使用纯真库细化到省市与运营商）:
+//
+//	geo, _ := geolite.NewClient()
+//	qq := qqwry.NewClient()
+//	resolver := geoip.NewChain(geo, qq).Prefer("CN", qq)
+type Chain struct {
+	providers []GeoIP
+	preferred map[string]GeoIP
+}
+
+var _ GeoIP = (*Chain)(nil)
+
+// NewChain 创建组合查询，providers 按顺序作为后备
+func NewChain(providers ...GeoIP) *Chain {
+	return &Chain{providers: providers, preferred: make(map[string]GeoIP)}
+}
+
+// Prefer 为国家（ISO 3166-1 alpha-2）设置更精确的数据源
+func (c *Chain) Prefer(countryCode string, provider GeoIP) *Chain {
+	c.preferred[strings.ToUpper(countryCode)] = provider
+	return c
+}
+
+// Query 实现 GeoIP
+func (c *Chain) Query(queryIp string) (Result, error) {
+	addr, err := ParseIP(queryIp)
+	if err != nil {
+		return Result{IP: queryIp}, err
+	}
+	if IsReserved(addr) {
+		return PrivateResult(queryIp), nil
+	}
+
+	var (
+		res     Result
+		found   GeoIP
+		lastErr error = ErrNotFound
+	)
+	for _, provider := range c.providers {
+		if res, err = provider.Query(queryIp); err == nil {
+			found = provider
+			break
+		}
+		// 数据源不支持或没有记录时继续查询下一个
+		lastErr = err
+	}
+	if found == nil {
+		return Result{IP: queryIp}, lastErr
+	}
+
+	preferred, ok := c.preferred[strings.ToUpper(res.CountryCode)]
+	if !ok || preferred == found {
+		return res, nil
+	}
+	refined, err := preferred.Query(queryIp)
+	if err != nil {
+		// 精确数据源查询失败时仍使用原结果
+		return res, nil
+	}
+	return merge(res, refined), nil
+}
+
+// merge 用更精确的数据源的省、市、运营商覆盖结果
+func merge(base, refined Result) Result {
+	if refined.Province != "" {
+		base.Province = refined.Province
+	}
+	if refined.City != "" {
+		base.City = refined.City
+	}
+	if refined.ISP != "" {
+		base.ISP = refined.ISP
+	}
+	if base.TimeZone == "" {
+		base.TimeZone = refined.TimeZone
+	}
+	if base.Source != "" && refined.Source != "" {
+		base.Source += "+" + refined.Source
+	}
+	return base
+}
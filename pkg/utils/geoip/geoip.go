@@ -1,5 +1,21 @@
 package geoip
 
+import "errors"
+
+var (
+	// ErrInvalidIP IP 地址格式错误
+	ErrInvalidIP = errors.New("invalid ip address")
+
+	// ErrNotFound 数据库中没有该 IP 的记录
+	ErrNotFound = errors.New("ip not found")
+
+	// ErrUnsupportedIP 数据源不支持该类型的 IP，如纯真库只支持 IPv4
+	ErrUnsupportedIP = errors.New("ip is not supported by the provider")
+)
+
+// privateName 内网及保留地址的地区名称
+const privateName = "局域网"
+
 // Result 归属地信息
 type Result struct {
 	IP       string `json:"ip"`
@@ -7,6 +23,33 @@ type Result struct {
 	Province string `json:"province"` // 省
 	City     string `json:"city"`     // 城市
 	ISP      string `json:"isp"`      // 服务提供商
+
+	CountryCode      string   `json:"country_code,omitempty"`      // ISO 3166-1 alpha-2 国家代码
+	SubdivisionCodes []string `json:"subdivision_codes,omitempty"` // ISO 3166-2 行政区代码（不含国家前缀），由大到小
+	Latitude         float64  `json:"latitude,omitempty"`          // 纬度
+	Longitude        float64  `json:"longitude,omitempty"`         // 经度
+	TimeZone         string   `json:"time_zone,omitempty"`         // IANA 时区，如 Asia/Shanghai
+	ASN              uint32   `json:"asn,omitempty"`               // 自治系统号
+	ASOrganization   string   `json:"as_organization,omitempty"`   // 自治系统所属组织
+
+	Private bool   `json:"private,omitempty"` // 内网或保留地址
+	Source  string `json:"source,omitempty"`  // 数据来源，如 geolite、qqwry
+}
+
+// HasLocation 是否包含经纬度
+func (r Result) HasLocation() bool {
+	return r.Latitude != 0 || r.Longitude != 0
+}
+
+// PrivateResult 内网及保留地址的查询结果
+func PrivateResult(ip string) Result {
+	return Result{
+		IP:       ip,
+		Country:  privateName,
+		Province: privateName,
+		City:     privateName,
+		Private:  true,
+	}
 }
 
 // GeoIP 客户端
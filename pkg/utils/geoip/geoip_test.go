@@ -1 +1,208 @@
 package geoip
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// fakeGeoIP 按 IP 返回固定结果的数据源
+type fakeGeoIP struct {
+	source  string
+	results map[string]Result
+	err     error
+	calls   atomic.Int32
+}
+
+func (f *fakeGeoIP) Query(queryIp string) (Result, error) {
+	f.calls.Add(1)
+	if f.err != nil {
+		return Result{IP: queryIp}, f.err
+	}
+	res, ok := f.results[queryIp]
+	if !ok {
+		return Result{IP: queryIp}, ErrNotFound
+	}
+	res.IP = queryIp
+	res.Source = f.source
+	return res, nil
+}
+
+func TestParseIP(t *testing.T) {
+	addr, err := ParseIP(" ::ffff:47.108.149.89 ")
+	require.NoError(t, err)
+	require.True(t, addr.Is4())
+	require.Equal(t, "47.108.149.89", addr.String())
+
+	addr, err = ParseIP("[2001:4860:4860::8888]")
+	require.NoError(t, err)
+	require.True(t, addr.Is6())
+
+	addr, err = ParseIP("fe80::1%eth0")
+	require.NoError(t, err)
+	require.Equal(t, "fe80::1", addr.String())
+
+	_, err = ParseIP("300.1.1.1")
+	require.ErrorIs(t, err, ErrInvalidIP)
+	_, err = ParseIP("")
+	require.ErrorIs(t, err, ErrInvalidIP)
+}
+
+func TestIsReserved(t *testing.T) {
+	for _, ip := range []string{
+		"10.1.2.3", "172.16.0.1", "192.168.1.1", "127.0.0.1", "169.254.1.1", "100.64.0.1",
+		"0.0.0.0", "255.255.255.255", "224.0.0.1", "192.0.2.10", "::1", "::", "fc00::1", "fe80::1",
+		"2001:db8::1", "ff02::1", "::ffff:192.168.1.1",
+	} {
+		require.True(t, IsReservedIP(ip), ip)
+	}
+	for _, ip := range []string{"47.108.149.89", "8.8.8.8", "172.32.0.1", "2001:4860:4860::8888", "240e::1", "invalid"} {
+		require.False(t, IsReservedIP(ip), ip)
+	}
+}
+
+func TestChain(t *testing.T) {
+	geolite := &fakeGeoIP{source: "geolite", results: map[string]Result{
+		"47.108.149.89": {
+			Country: "中国", CountryCode: "CN", Province: "四川", City: "成都",
+			SubdivisionCodes: []string{"SC"}, Latitude: 30.66, Longitude: 104.06, TimeZone: "Asia/Shanghai", ASN: 37963,
+		},
+		"8.8.8.8":              {Country: "美国", CountryCode: "US", TimeZone: "America/Chicago", ASN: 15169},
+		"2001:4860:4860::8888": {Country: "美国", CountryCode: "US"},
+	}}
+	qqwry := &fakeGeoIP{source: "qqwry", results: map[string]Result{
+		"47.108.149.89": {Country: "中国", CountryCode: "CN", Province: "四川省", City: "成都市", ISP: "阿里云"},
+		"1.2.3.4":       {Country: "中国", CountryCode: "CN", Province: "浙江省", City: "杭州市"},
+	}}
+	chain := NewChain(geolite, qqwry).Prefer("cn", qqwry)
+
+	// 中国大陆地址使用纯真库的省市与运营商，保留 GeoLite 的代码、经纬度与 ASN
+	res, err := chain.Query("47.108.149.89")
+	require.NoError(t, err)
+	require.Equal(t, "四川省", res.Province)
+	require.Equal(t, "成都市", res.City)
+	require.Equal(t, "阿里云", res.ISP)
+	require.Equal(t, "CN", res.CountryCode)
+	require.Equal(t, []string{"SC"}, res.SubdivisionCodes)
+	require.Equal(t, uint32(37963), res.ASN)
+	require.True(t, res.HasLocation())
+	require.Equal(t, "geolite+qqwry", res.Source)
+
+	// 境外地址只使用 GeoLite
+	calls := qqwry.calls.Load()
+	res, err = chain.Query("8.8.8.8")
+	require.NoError(t, err)
+	require.Equal(t, "US", res.CountryCode)
+	require.Equal(t, "geolite", res.Source)
+	require.Equal(t, calls, qqwry.calls.Load())
+
+	res, err = chain.Query("2001:4860:4860::8888")
+	require.NoError(t, err)
+	require.Equal(t, "US", res.CountryCode)
+
+	// GeoLite 没有记录时使用后备数据源
+	res, err = chain.Query("1.2.3.4")
+	require.NoError(t, err)
+	require.Equal(t, "qqwry", res.Source)
+	require.Equal(t, "杭州市", res.City)
+
+	// 内网地址不查询数据源
+	calls = geolite.calls.Load()
+	res, err = chain.Query("192.168.1.1")
+	require.NoError(t, err)
+	require.True(t, res.Private)
+	require.Equal(t, "局域网", res.City)
+	require.Equal(t, calls, geolite.calls.Load())
+
+	_, err = chain.Query("9.9.9.9")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = chain.Query("not-an-ip")
+	require.ErrorIs(t, err, ErrInvalidIP)
+}
+
+func TestCache(t *testing.T) {
+	source := &fakeGeoIP{source: "fake", results: map[string]Result{
+		"1.1.1.1": {CountryCode: "AU"},
+		"2.2.2.2": {CountryCode: "FR"},
+		"3.3.3.3": {CountryCode: "US"},
+	}}
+	fake := clock.NewFake(time.Unix(0, 0))
+	cache := NewCache(source, WithCacheSize(2), WithCacheTTL(time.Minute), WithCacheClock(fake))
+
+	res, err := cache.Query("1.1.1.1")
+	require.NoError(t, err)
+	require.Equal(t, "AU", res.CountryCode)
+	// IPv4 映射地址命中同一条缓存，返回调用方传入的 IP
+	res, err = cache.Query("::ffff:1.1.1.1")
+	require.NoError(t, err)
+	require.Equal(t, "::ffff:1.1.1.1", res.IP)
+	require.Equal(t, int32(1), source.calls.Load())
+
+	// 超出容量时淘汰最久未使用的
+	_, _ = cache.Query("2.2.2.2")
+	_, _ = cache.Query("1.1.1.1")
+	_, _ = cache.Query("3.3.3.3")
+	require.Equal(t, 2, cache.Len())
+	calls := source.calls.Load()
+	_, _ = cache.Query("1.1.1.1")
+	require.Equal(t, calls, source.calls.Load())
+	_, _ = cache.Query("2.2.2.2")
+	require.Equal(t, calls+1, source.calls.Load())
+
+	// 过期后重新查询
+	fake.Advance(2 * time.Minute)
+	_, _ = cache.Query("2.2.2.2")
+	require.Equal(t, calls+2, source.calls.Load())
+
+	// 默认不缓存错误
+	_, err = cache.Query("4.4.4.4")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, _ = cache.Query("4.4.4.4")
+	require.Equal(t, calls+4, source.calls.Load())
+
+	hits, misses := cache.Stats()
+	require.Equal(t, uint64(3), hits)
+	require.Equal(t, uint64(7), misses)
+
+	cache.Purge()
+	require.Equal(t, 0, cache.Len())
+}
+
+type fakeReloader struct {
+	reloads atomic.Int32
+}
+
+func (f *fakeReloader) Reload() error {
+	f.reloads.Add(1)
+	return nil
+}
+
+func TestWatchFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	require.NoError(t, os.WriteFile(path, []byte("v1"), 0o644))
+
+	reloader := &fakeReloader{}
+	var purged atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchFiles(ctx, 10*time.Millisecond, reloader, func() { purged.Add(1) }, path)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(0), reloader.reloads.Load())
+
+	require.NoError(t, os.WriteFile(path, []byte("v2-longer"), 0o644))
+	require.Eventually(t, func() bool { return reloader.reloads.Load() == 1 }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return purged.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
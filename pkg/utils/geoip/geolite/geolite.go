@@ -3,75 +3,206 @@ package geolite
 import (
 	"errors"
 	"net"
+	"os"
+	"sync/atomic"
 
-	"github.com/go-kratos/kratos/v2/log"
 	"github.com/oschwald/geoip2-golang"
 
 	"github.com/heyinLab/common/pkg/utils/geoip"
 	"github.com/heyinLab/common/pkg/utils/geoip/geolite/assets"
 )
 
-const defaultOutputLanguage = "zh-CN"
+const (
+	defaultOutputLanguage  = "zh-CN"
+	fallbackOutputLanguage = "en"
+
+	// Source 查询结果的数据来源
+	Source = "geolite"
+)
+
+// Option 客户端配置项
+type Option func(*Client)
+
+// WithLanguage 设置输出的语言，默认为：zh-CN，没有该语言的名称时使用英文
+func WithLanguage(code string) Option {
+	return func(c *Client) { c.outputLanguage = code }
+}
+
+// WithCityFile 从磁盘加载 GeoLite2-City 数据库，代替内置数据库，支持 Reload
+func WithCityFile(path string) Option {
+	return func(c *Client) { c.cityPath = path }
+}
+
+// WithASNFile 从磁盘加载 GeoLite2-ASN 数据库，查询结果中会包含 ASN 信息，支持 Reload
+func WithASNFile(path string) Option {
+	return func(c *Client) { c.asnPath = path }
+}
+
+// WithASNData 使用 GeoLite2-ASN 数据库内容，查询结果中会包含 ASN 信息
+func WithASNData(data []byte) Option {
+	return func(c *Client) { c.asnData = data }
+}
+
+// databases 一组同时加载的数据库，重新加载时整体替换
+type databases struct {
+	city *geoip2.Reader
+	asn  *geoip2.Reader
+}
 
 // Client 地理位置解析结构体
 type Client struct {
-	db             *geoip2.Reader
+	cityPath       string
+	asnPath        string
+	asnData        []byte
 	outputLanguage string
+
+	dbs atomic.Pointer[databases]
 }
 
-// NewClient .
-func NewClient() (*Client, error) {
-	db, err := geoip2.FromBytes(assets.GeoLite2CityData)
-	if err != nil {
+var (
+	_ geoip.GeoIP    = (*Client)(nil)
+	_ geoip.Reloader = (*Client)(nil)
+)
+
+// NewClient 创建客户端，默认使用内置的 GeoLite2-City 数据库
+func NewClient(opts ...Option) (*Client, error) {
+	c := &Client{outputLanguage: defaultOutputLanguage}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.Reload(); err != nil {
 		return nil, err
 	}
-	return &Client{db: db, outputLanguage: defaultOutputLanguage}, nil
+	return c, nil
+}
+
+// Reload 重新加载数据库文件，失败时继续使用原数据库
+//
+// 数据库文件读入内存后加载，正在进行的查询不受影响。
+func (g *Client) Reload() error {
+	cityData := assets.GeoLite2CityData
+	if g.cityPath != "" {
+		data, err := os.ReadFile(g.cityPath)
+		if err != nil {
+			return err
+		}
+		cityData = data
+	}
+	city, err := geoip2.FromBytes(cityData)
+	if err != nil {
+		return err
+	}
+
+	dbs := &databases{city: city}
+	asnData := g.asnData
+	if g.asnPath != "" {
+		if asnData, err = os.ReadFile(g.asnPath); err != nil {
+			return err
+		}
+	}
+	if len(asnData) > 0 {
+		if dbs.asn, err = geoip2.FromBytes(asnData); err != nil {
+			return err
+		}
+	}
+
+	g.dbs.Store(dbs)
+	return nil
+}
+
+// Files 返回从磁盘加载的数据库文件，用于 geoip.WatchFiles
+func (g *Client) Files() []string {
+	var files []string
+	for _, path := range []string{g.cityPath, g.asnPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files
 }
 
 // Close 关闭客户端
 func (g *Client) Close() error {
-	if g.db == nil {
+	dbs := g.dbs.Swap(nil)
+	if dbs == nil {
 		return nil
 	}
-	return g.db.Close()
+	if dbs.asn != nil {
+		_ = dbs.asn.Close()
+	}
+	return dbs.city.Close()
 }
 
 // SetLanguage 设置输出的语言，默认为：zh-CN
+//
+// Deprecated: 使用 WithLanguage，SetLanguage 与查询并发调用不安全。
 func (g *Client) SetLanguage(code string) {
 	g.outputLanguage = code
 }
 
-// query 查询城市级别数据
-func (g *Client) query(ip net.IP) (city *geoip2.City, err error) {
-	return g.db.City(ip)
+// name 返回指定语言的名称
+func (g *Client) name(names map[string]string) string {
+	if name, ok := names[g.outputLanguage]; ok {
+		return name
+	}
+	return names[fallbackOutputLanguage]
 }
 
-// Query 通过IP获取地区
+// Query 通过IP获取地区，支持 IPv4 与 IPv6
 func (g *Client) Query(rawIP string) (ret geoip.Result, err error) {
-	ip := net.ParseIP(rawIP)
-	if ip == nil {
-		return ret, errors.New("invalid ip address")
+	ret.IP = rawIP
+	addr, err := geoip.ParseIP(rawIP)
+	if err != nil {
+		return ret, err
 	}
-
-	isPrivate := IsPrivateIP(ip)
-	if isPrivate {
-		ret.Country = "局域网"
-		ret.Province = "局域网"
-		ret.City = "局域网"
+	if geoip.IsReserved(addr) {
+		ret = geoip.PrivateResult(rawIP)
+		ret.Source = Source
 		return ret, nil
 	}
 
-	record, err := g.query(ip)
+	dbs := g.dbs.Load()
+	if dbs == nil {
+		return ret, errors.New("geolite: client is closed")
+	}
+
+	ip := net.IP(addr.AsSlice())
+	record, err := dbs.city.City(ip)
 	if err != nil {
-		log.Fatal(err)
 		return ret, err
 	}
 
-	ret.Country = record.Country.Names[g.outputLanguage]
+	country := record.Country
+	if country.IsoCode == "" {
+		// 部分地址只有注册国家
+		country = record.RegisteredCountry
+	}
+	if country.IsoCode == "" && record.Location.Latitude == 0 && record.Location.Longitude == 0 {
+		return ret, geoip.ErrNotFound
+	}
+
+	ret.Source = Source
+	ret.Country = g.name(country.Names)
+	ret.CountryCode = country.IsoCode
 	if len(record.Subdivisions) > 0 {
-		ret.Province = record.Subdivisions[0].Names[g.outputLanguage]
+		ret.Province = g.name(record.Subdivisions[0].Names)
+	}
+	for _, subdivision := range record.Subdivisions {
+		if subdivision.IsoCode != "" {
+			ret.SubdivisionCodes = append(ret.SubdivisionCodes, subdivision.IsoCode)
+		}
+	}
+	ret.City = g.name(record.City.Names)
+	ret.Latitude = record.Location.Latitude
+	ret.Longitude = record.Location.Longitude
+	ret.TimeZone = record.Location.TimeZone
+
+	if dbs.asn != nil {
+		if asn, err := dbs.asn.ASN(ip); err == nil && asn.AutonomousSystemNumber > 0 {
+			ret.ASN = uint32(asn.AutonomousSystemNumber)
+			ret.ASOrganization = asn.AutonomousSystemOrganization
+		}
 	}
-	ret.City = record.City.Names[g.outputLanguage]
 
-	return
+	return ret, nil
 }
@@ -0,0 +1,75 @@
+package geoip
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// reservedPrefixes 内网及保留地址段（IANA Special-Purpose Address Registry），不在任何数据库中
+var reservedPrefixes = func() []netip.Prefix {
+	cidrs := []string{
+		// IPv4
+		"0.0.0.0/8",       // 本网络
+		"10.0.0.0/8",      // 私有地址
+		"100.64.0.0/10",   // 运营商级 NAT
+		"127.0.0.0/8",     // 回环地址
+		"169.254.0.0/16",  // 链路本地地址
+		"172.16.0.0/12",   // 私有地址
+		"192.0.0.0/24",    // IETF 协议分配
+		"192.0.2.0/24",    // 文档示例 TEST-NET-1
+		"192.88.99.0/24",  // 6to4 中继（已废弃）
+		"192.168.0.0/16",  // 私有地址
+		"198.18.0.0/15",   // 基准测试
+		"198.51.100.0/24", // 文档示例 TEST-NET-2
+		"203.0.113.0/24",  // 文档示例 TEST-NET-3
+		"224.0.0.0/4",     // 组播
+		"240.0.0.0/4",     // 保留地址（含广播地址）
+
+		// IPv6
+		"::/128",         // 未指定地址
+		"::1/128",        // 回环地址
+		"64:ff9b:1::/48", // 本地 IPv4/IPv6 转换
+		"100::/64",       // 丢弃前缀
+		"2001:db8::/32",  // 文档示例
+		"fc00::/7",       // 唯一本地地址（ULA）
+		"fe80::/10",      // 链路本地地址
+		"fec0::/10",      // 站点本地地址（已废弃，兼容保留）
+		"ff00::/8",       // 组播
+	}
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefixes = append(prefixes, netip.MustParsePrefix(cidr))
+	}
+	return prefixes
+}()
+
+// ParseIP 解析 IPv4/IPv6 地址
+//
+// 支持 IPv4 映射的 IPv6 地址（::ffff:1.2.3.4，返回 IPv4）与带 zone 的地址（fe80::1%eth0），
+// 前后空白会被忽略。
+func ParseIP(raw string) (netip.Addr, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "["), "]")
+	addr, err := netip.ParseAddr(raw)
+	if err != nil {
+		return netip.Addr{}, ErrInvalidIP
+	}
+	return addr.WithZone("").Unmap(), nil
+}
+
+// IsReserved 判断是否为内网或保留地址，这些地址无需查询数据库
+func IsReserved(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReservedIP 同 IsReserved，无效地址返回 false
+func IsReservedIP(raw string) bool {
+	addr, err := ParseIP(raw)
+	return err == nil && IsReserved(addr)
+}
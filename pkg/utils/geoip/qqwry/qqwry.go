@@ -3,87 +3,157 @@ package qqwry
 import (
 	"encoding/binary"
 	"errors"
-	"net"
+	"os"
 	"strings"
-	"sync"
+	"sync/atomic"
 
 	"github.com/heyinLab/common/pkg/utils/geoip"
 
 	"github.com/heyinLab/common/pkg/utils/geoip/qqwry/assets"
 )
 
-type Client struct {
-	data    []byte
-	dataLen uint32
-	ipCache sync.Map
+// Source 查询结果的数据来源
+const Source = "qqwry"
 
-	IPNum int64
+// chinaName 纯真库中中国大陆地址的国家名称
+const chinaName = "中国"
 
+// database 纯真库数据，重新加载时整体替换
+type database struct {
+	data     []byte
 	startPos uint32
 	endPos   uint32
 }
 
-func NewClient() *Client {
-	cli := &Client{
-		ipCache: sync.Map{},
+// newDatabase 解析文件头并校验索引区范围
+func newDatabase(data []byte) (*database, error) {
+	if len(data) < 8 {
+		return nil, errors.New("qqwry: invalid database")
 	}
+	db := &database{data: data}
+	db.startPos, db.endPos = db.readHeader()
+	if db.startPos > db.endPos || uint64(db.endPos)+ipRecordLength > uint64(len(data)) ||
+		(db.endPos-db.startPos)%ipRecordLength != 0 {
+		return nil, errors.New("qqwry: invalid database index")
+	}
+	return db, nil
+}
+
+type Client struct {
+	path string
+	db   atomic.Pointer[database]
+
+	// IPNum 创建时数据库中的 IP 段数量，重新加载后使用 Count
+	IPNum int64
+}
 
-	cli.init()
+var (
+	_ geoip.GeoIP    = (*Client)(nil)
+	_ geoip.Reloader = (*Client)(nil)
+)
 
+// NewClient 使用内置的纯真库创建客户端
+func NewClient() *Client {
+	db, err := newDatabase(assets.QQWryDat)
+	if err != nil {
+		panic(err)
+	}
+	cli := &Client{}
+	cli.db.Store(db)
+	cli.IPNum = cli.Count()
 	return cli
 }
 
-func (c *Client) init() {
-	c.startPos, c.endPos = c.readHeader()
-	c.IPNum = int64((c.endPos-c.startPos)/ipRecordLength + 1)
+// NewClientFromFile 从磁盘加载纯真库，支持 Reload
+func NewClientFromFile(path string) (*Client, error) {
+	cli := &Client{path: path}
+	if err := cli.Reload(); err != nil {
+		return nil, err
+	}
+	cli.IPNum = cli.Count()
+	return cli, nil
+}
+
+// Reload 重新加载数据库文件，失败时继续使用原数据；使用内置数据库时无操作
+func (c *Client) Reload() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	db, err := newDatabase(data)
+	if err != nil {
+		return err
+	}
+	c.db.Store(db)
+	return nil
+}
+
+// Files 返回从磁盘加载的数据库文件，用于 geoip.WatchFiles
+func (c *Client) Files() []string {
+	if c.path == "" {
+		return nil
+	}
+	return []string{c.path}
+}
+
+// Count 返回数据库中的 IP 段数量
+func (c *Client) Count() int64 {
+	db := c.db.Load()
+	return int64((db.endPos-db.startPos)/ipRecordLength + 1)
 }
 
-// parseIp 解析IP
+// parseIp 解析IP，IPv4 映射的 IPv6 地址按 IPv4 处理，其他 IPv6 地址纯真库不支持
 func (c *Client) parseIp(queryIp string) (uint32, error) {
-	ip := net.ParseIP(queryIp).To4()
-	if ip == nil {
-		return 0, errors.New("ip is not ipv4")
+	addr, err := geoip.ParseIP(queryIp)
+	if err != nil {
+		return 0, err
+	}
+	if !addr.Is4() {
+		return 0, geoip.ErrUnsupportedIP
 	}
-	ip32 := binary.BigEndian.Uint32(ip)
-	return ip32, nil
+	ip4 := addr.As4()
+	return binary.BigEndian.Uint32(ip4[:]), nil
 }
 
 // readHeader 读取文件头
-func (c *Client) readHeader() (uint32, uint32) {
-	startPos := binary.LittleEndian.Uint32(assets.QQWryDat[:4])
-	endPos := binary.LittleEndian.Uint32(assets.QQWryDat[4:8])
+func (db *database) readHeader() (uint32, uint32) {
+	startPos := binary.LittleEndian.Uint32(db.data[:4])
+	endPos := binary.LittleEndian.Uint32(db.data[4:8])
 	return startPos, endPos
 }
 
 // readMode 获取偏移值类型
-func (c *Client) readMode(offset uint32) byte {
-	return assets.QQWryDat[offset]
+func (db *database) readMode(offset uint32) byte {
+	return db.data[offset]
 }
 
 // readIpRecord 读取IP记录 前4字节：起始IP，后3字节：偏移量
-func (c *Client) readIpRecord(offset uint32) (ip32 uint32, ipOffset uint32) {
-	buf := assets.QQWryDat[offset : offset+ipRecordLength]
+func (db *database) readIpRecord(offset uint32) (ip32 uint32, ipOffset uint32) {
+	buf := db.data[offset : offset+ipRecordLength]
 	ip32 = binary.LittleEndian.Uint32(buf[:4])
 	ipOffset = byte3ToUInt32(buf[4:])
 	return ip32, ipOffset
 }
 
 // locateIP 定位IP
-func (c *Client) locateIP(ip32 uint32) int32 {
+func (db *database) locateIP(ip32 uint32) int32 {
 	var _ip32 uint32
 	var _ipOffset uint32
 	var offset uint32
 
 	var mid uint32
-	i := c.startPos
-	j := c.endPos
+	i := db.startPos
+	j := db.endPos
 	for {
 		mid = getMiddleOffset(i, j)
-		_ip32, _ipOffset = c.readIpRecord(mid)
+		_ip32, _ipOffset = db.readIpRecord(mid)
 
 		if j-i == ipRecordLength {
 			offset = _ipOffset
-			_ip32, _ipOffset = c.readIpRecord(mid + ipRecordLength)
+			_ip32, _ipOffset = db.readIpRecord(mid + ipRecordLength)
 			if ip32 < _ip32 {
 				break
 			} else {
@@ -106,41 +176,43 @@ func (c *Client) locateIP(ip32 uint32) int32 {
 }
 
 // readArea 读取区域
-func (c *Client) readArea(offset uint32) []byte {
-	mode := c.readMode(offset)
+func (db *database) readArea(offset uint32) []byte {
+	mode := db.readMode(offset)
 	if mode == redirectMode1 || mode == redirectMode2 {
-		areaOffset := c.readUInt24(int32(offset) + 1)
+		areaOffset := db.readUInt24(int32(offset) + 1)
 		if areaOffset == 0 {
 			return []byte{}
 		}
-		return c.readString(areaOffset)
+		return db.readString(areaOffset)
 	}
 
-	return c.readString(offset)
+	return db.readString(offset)
 }
 
 // readString 获取字符串
-func (c *Client) readString(offset uint32) []byte {
+func (db *database) readString(offset uint32) []byte {
 	data := make([]byte, 0, 30)
-	for i := offset; i < uint32(len(assets.QQWryDat)); i++ {
-		if assets.QQWryDat[i] == 0 {
-			data = assets.QQWryDat[offset:i]
+	for i := offset; i < uint32(len(db.data)); i++ {
+		if db.data[i] == 0 {
+			data = db.data[offset:i]
 			break
 		}
 	}
 	return data
 }
 
-func (c *Client) readUInt24(offset int32) uint32 {
-	i := uint32(assets.QQWryDat[offset+0]) & 0xFF
-	i |= (uint32(assets.QQWryDat[offset+1]) << 8) & 0xFF00
-	i |= (uint32(assets.QQWryDat[offset+2]) << 16) & 0xFF0000
+func (db *database) readUInt24(offset int32) uint32 {
+	i := uint32(db.data[offset+0]) & 0xFF
+	i |= (uint32(db.data[offset+1]) << 8) & 0xFF00
+	i |= (uint32(db.data[offset+2]) << 16) & 0xFF0000
 	return i
 }
 
+// Query 通过IP获取地区
+//
+// 纯真库只收录 IPv4，IPv4 映射的 IPv6 地址（::ffff:1.2.3.4）按 IPv4 查询，其他 IPv6 地址返回 geoip.ErrUnsupportedIP。
 func (c *Client) Query(queryIp string) (res geoip.Result, err error) {
 	res.IP = queryIp
-	res.Country = "中国"
 
 	ip32, err := c.parseIp(queryIp)
 	if err != nil {
@@ -148,74 +220,64 @@ func (c *Client) Query(queryIp string) (res geoip.Result, err error) {
 	}
 
 	// 判断是否为内网IP
-	isPrivate := IsPrivateIP(queryIp)
-	if isPrivate {
-		res.Province = "局域网"
-		res.City = "局域网"
+	if IsPrivateIP(queryIp) {
+		res = geoip.PrivateResult(queryIp)
+		res.Source = Source
 		return
 	}
 
-	offset := c.locateIP(ip32)
+	db := c.db.Load()
+	offset := db.locateIP(ip32)
 	if offset <= 0 {
-		err = errors.New("ip not found")
+		err = geoip.ErrNotFound
 		return
 	}
+	res.Source = Source
 
 	//读取第一个字节判断是否是标志字节
 	offset += 4
-	mode := c.readMode(uint32(offset))
+	mode := db.readMode(uint32(offset))
 
 	var _area []byte
-	var area string
 
 	var ispPos uint32
 	switch mode {
 	case redirectMode1:
-		posC := c.readUInt24(offset + 1)
-		mode = c.readMode(posC)
+		posC := db.readUInt24(offset + 1)
+		mode = db.readMode(posC)
 		posCA := posC
 		if mode == redirectMode2 {
-			posCA = c.readUInt24(int32(posC) + 1)
+			posCA = db.readUInt24(int32(posC) + 1)
 			posC += 4
 		}
-		_area = c.readString(posCA)
+		_area = db.readString(posCA)
 		if mode != redirectMode2 {
-			posC += uint32(len(area) + 1)
+			posC += uint32(len(_area) + 1)
 		}
 		ispPos = posC
 
 	case redirectMode2:
-		posCA := c.readUInt24(offset + 1)
-		_area = c.readString(posCA)
+		posCA := db.readUInt24(offset + 1)
+		_area = db.readString(posCA)
 		ispPos = uint32(offset) + 4
 
 	default:
 		posCA := offset + 0
-		_area = c.readString(uint32(posCA))
-		ispPos = uint32(offset) + uint32(len(area)) + 1
+		_area = db.readString(uint32(posCA))
+		ispPos = uint32(offset) + uint32(len(_area)) + 1
 	}
 
 	if len(_area) != 0 {
-		area = strings.TrimSpace(gb18030Decode(_area))
-
-		areas := SpiltAddress(area)
-		if len(areas) == 2 {
-			res.Province = areas[0]
-			res.City = areas[1]
-		} else if len(areas) == 1 {
-			res.City = areas[0]
-		} else {
-			res.City = area
-		}
+		setArea(&res, strings.TrimSpace(gb18030Decode(_area)))
 	}
 
-	ispMode := assets.QQWryDat[ispPos]
+	ispMode := db.data[ispPos]
 	if ispMode == redirectMode1 || ispMode == redirectMode2 {
-		ispPos = c.readUInt24(int32(ispPos + 1))
+		ispPos = db.readUInt24(int32(ispPos + 1))
 	}
 	if ispPos > 0 {
 		var _isp []byte
-		_isp = c.readString(ispPos)
+		_isp = db.readString(ispPos)
 		res.ISP = strings.TrimSpace(gb18030Decode(_isp))
 		if res.ISP != "" {
 			if strings.Contains(res.ISP, "CZ88.NET") {
@@ -226,3 +288,34 @@ func (c *Client) Query(queryIp string) (res geoip.Result, err error) {
 
 	return
 }
+
+// setArea 解析地区字符串
+//
+// 中国大陆地址为"四川省成都市"形式，拆分为省、市；其他地址以国家名称开头，如"美国–加利福尼亚州–洛杉矶"。
+func setArea(res *geoip.Result, area string) {
+	area = strings.TrimPrefix(area, chinaName+"–")
+	areas := SpiltAddress(area)
+	if len(areas) > 0 || area == chinaName {
+		res.Country = chinaName
+		res.CountryCode = "CN"
+		if len(areas) >= 2 {
+			res.Province = areas[0]
+			res.City = areas[1]
+		} else if len(areas) == 1 {
+			res.City = areas[0]
+		}
+		return
+	}
+
+	parts := strings.FieldsFunc(area, func(r rune) bool { return r == '–' || r == '-' || r == ' ' })
+	if len(parts) == 0 {
+		return
+	}
+	res.Country = parts[0]
+	if len(parts) >= 3 {
+		res.Province = parts[1]
+		res.City = parts[2]
+	} else if len(parts) == 2 {
+		res.City = parts[1]
+	}
+}
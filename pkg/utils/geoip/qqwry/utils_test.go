@@ -3,9 +3,40 @@ package qqwry
 import (
 	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/heyinLab/common/pkg/utils/geoip"
 )
 
 func TestSpiltAddress(t *testing.T) {
 	names := SpiltAddress("浙江省杭州市西湖区")
 	fmt.Println(names)
 }
+
+func TestSetArea(t *testing.T) {
+	var res geoip.Result
+	setArea(&res, "四川省成都市")
+	assert.Equal(t, "中国", res.Country)
+	assert.Equal(t, "CN", res.CountryCode)
+	assert.Equal(t, "四川省", res.Province)
+	assert.Equal(t, "成都市", res.City)
+
+	res = geoip.Result{}
+	setArea(&res, "美国–加利福尼亚州–洛杉矶")
+	assert.Equal(t, "美国", res.Country)
+	assert.Equal(t, "", res.CountryCode)
+	assert.Equal(t, "加利福尼亚州", res.Province)
+	assert.Equal(t, "洛杉矶", res.City)
+
+	res = geoip.Result{}
+	setArea(&res, "中国")
+	assert.Equal(t, "CN", res.CountryCode)
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	assert.True(t, IsPrivateIP("192.168.1.1"))
+	assert.True(t, IsPrivateIP("::ffff:10.0.0.1"))
+	assert.True(t, IsPrivateIP("invalid"))
+	assert.False(t, IsPrivateIP("47.108.149.89"))
+}
@@ -0,0 +1,82 @@
+package geoip
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// DefaultReloadInterval 默认检查数据库文件变化的间隔
+const DefaultReloadInterval = time.Minute
+
+// Reloader 支持从磁盘重新加载数据库的 GeoIP
+type Reloader interface {
+	// Reload 重新加载数据库文件，失败时继续使用原数据
+	Reload() error
+}
+
+// WatchFiles 定期检查文件的修改时间与大小，变化时调用 reload，阻塞直到 ctx 结束
+//
+// 数据库文件通常由定时任务下载后替换，建议先写临时文件再 rename，避免读到不完整的文件。
+// onReload 可用于在重新加载后清空缓存，可以为 nil。定时器使用 clock.FromContext(ctx) 的时钟。
+func WatchFiles(ctx context.Context, interval time.Duration, reloader Reloader, onReload func(), paths ...string) error {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	logger := log.NewHelper(log.With(log.GetLogger(), "module", "geoip"))
+
+	stats := make([]fileStat, len(paths))
+	for i, path := range paths {
+		stats[i] = statFile(path)
+	}
+
+	ticker := clock.FromContext(ctx).NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+
+		changed := false
+		for i, path := range paths {
+			if current := statFile(path); !current.equal(stats[i]) {
+				stats[i] = current
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := reloader.Reload(); err != nil {
+			logger.Errorf("重新加载 IP 数据库失败: files=%v, error=%v", paths, err)
+			continue
+		}
+		logger.Infof("重新加载 IP 数据库成功: files=%v", paths)
+		if onReload != nil {
+			onReload()
+		}
+	}
+}
+
+type fileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s fileStat) equal(other fileStat) bool {
+	return s.size == other.size && s.modTime.Equal(other.modTime)
+}
+
+func statFile(path string) fileStat {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStat{}
+	}
+	return fileStat{modTime: info.ModTime(), size: info.Size()}
+}
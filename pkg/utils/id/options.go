@@ -0,0 +1,54 @@
+package id
+
+import (
+	"time"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// Option 订单号与 Sonyflake 生成器配置项
+type Option func(*options)
+
+type options struct {
+	clock     clock.Clock
+	startTime time.Time
+	machineID *uint16
+}
+
+// WithClock 设置读取当前时间的时钟，默认为 clock.Default()
+//
+// 对 NewSonyflake 只在创建时生效，见 NewSonyflake。
+func WithClock(c clock.Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+// WithStartTime 设置 NewSonyflake 的起始时间，默认为 sonyflake 的 2014-09-01 00:00:00 UTC
+func WithStartTime(t time.Time) Option {
+	return func(o *options) { o.startTime = t }
+}
+
+// WithMachineID 设置 NewSonyflake 的机器ID，默认为本机私有 IPv4 地址的低 16 位
+func WithMachineID(machineID uint16) Option {
+	return func(o *options) { o.machineID = &machineID }
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// now 当前时间，未设置时钟时读取 clock.Default()
+func (o options) now() time.Time {
+	if o.clock != nil {
+		return o.clock.Now()
+	}
+	return clock.Now()
+}
+
+// now 按 opts 中的时钟返回当前时间
+func now(opts []Option) time.Time {
+	return newOptions(opts).now()
+}
@@ -21,12 +21,12 @@ func (c *idCounter) Increase() uint32 {
 
 var orderIdIndex idCounter
 
-// GenerateOrderIdWithRandom 生成20位订单号，前缀 + 时间戳 + 随机数
-func GenerateOrderIdWithRandom(prefix string, tm *time.Time) string {
+// GenerateOrderIdWithRandom 生成20位订单号，前缀 + 时间戳 + 随机数，tm 为 nil 时使用当前时间
+func GenerateOrderIdWithRandom(prefix string, tm *time.Time, opts ...Option) string {
 	// 前缀 + 时间戳（14位） + 随机数（4位）
 
 	if tm == nil {
-		tm = trans.Time(time.Now())
+		tm = trans.Time(now(opts))
 	}
 
 	timestamp := tm.Format("20060102150405")
@@ -36,10 +36,10 @@ func GenerateOrderIdWithRandom(prefix string, tm *time.Time) string {
 	return fmt.Sprintf("%s%s%d", prefix, timestamp, randNum)
 }
 
-// GenerateOrderIdWithIncreaseIndex 生成20位订单号，前缀+时间+自增长索引
-func GenerateOrderIdWithIncreaseIndex(prefix string, tm *time.Time) string {
+// GenerateOrderIdWithIncreaseIndex 生成20位订单号，前缀+时间+自增长索引，tm 为 nil 时使用当前时间
+func GenerateOrderIdWithIncreaseIndex(prefix string, tm *time.Time, opts ...Option) string {
 	if tm == nil {
-		tm = trans.Time(time.Now())
+		tm = trans.Time(now(opts))
 	}
 
 	timestamp := tm.Format("20060102150405")
@@ -50,12 +50,11 @@ func GenerateOrderIdWithIncreaseIndex(prefix string, tm *time.Time) string {
 }
 
 // GenerateOrderIdWithTenantId 带商户ID的订单ID生成器：202506041234567890123
-func GenerateOrderIdWithTenantId(tenantID string) string {
+func GenerateOrderIdWithTenantId(tenantID string, opts ...Option) string {
 	// 时间戳（14位） + 商户ID（固定 5 位） + 随机数（4位）
 
-	// 时间戳部分（精确到毫秒）
-	now := time.Now()
-	timestamp := now.Format("20060102150405")
+	// 时间戳部分（精确到秒）
+	timestamp := now(opts).Format("20060102150405")
 
 	// 商户ID部分（截取或补零到5位）
 	tenantPart := tenantID
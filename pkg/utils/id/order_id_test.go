@@ -2,11 +2,14 @@ package id
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 func TestGenerateOrderIdWithRandom(t *testing.T) {
@@ -83,6 +86,22 @@ func TestGenerateOrderIdWithTenantId(t *testing.T) {
 	assert.Regexp(t, `^\d{4}$`, randomPart)
 }
 
+func TestGenerateOrderIdWithClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 6, 4, 12, 34, 56, 0, time.Local))
+
+	assert.Equal(t, "20250604123456M9876", GenerateOrderIdWithTenantId("M9876", WithClock(fake))[:19])
+	assert.True(t, strings.HasPrefix(GenerateOrderIdWithRandom("PT", nil, WithClock(fake)), "PT20250604123456"))
+	assert.True(t, strings.HasPrefix(GenerateOrderIdWithIncreaseIndex("PT", nil, WithClock(fake)), "PT20250604123456"))
+
+	// 指定了 tm 时不读取时钟
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	assert.True(t, strings.HasPrefix(GenerateOrderIdWithRandom("PT", &tm, WithClock(fake)), "PT20240102030405"))
+
+	// 未设置时钟时使用默认时钟
+	defer clock.SetDefault(fake)()
+	assert.True(t, strings.HasPrefix(GenerateOrderIdWithTenantId("M9876"), "20250604123456"))
+}
+
 func TestGenerateOrderIdWithTenantIdCollision(t *testing.T) {
 	tenantID := "M9876"
 	count := 1000 // 生成订单号的数量
@@ -15,6 +15,10 @@ type SnowflakeNode struct {
 	sync.Mutex
 }
 
+// NewSnowflakeNode 创建 github.com/bwmarrin/snowflake 节点
+//
+// snowflake 的起始时间是包级变量 snowflake.Epoch，节点生成ID时直接读取系统单调时钟，
+// 因此无法为单个节点注入时钟或起始时间；需要自定义起始时间时，应在创建任何节点之前设置 snowflake.Epoch。
 func NewSnowflakeNode(workerId int64) (*SnowflakeNode, error) {
 	node, err := snowflake.NewNode(workerId)
 	return &SnowflakeNode{
@@ -2,15 +2,42 @@ package id
 
 import (
 	"sync"
+	"time"
 
 	"github.com/sony/sonyflake"
 )
 
+// defaultSonyflakeStartTime sonyflake 默认的起始时间
+var defaultSonyflakeStartTime = time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC)
+
 var (
 	sf   *sonyflake.Sonyflake
 	sfMu sync.Mutex
 )
 
+// NewSonyflake 创建 github.com/sony/sonyflake 生成器
+//
+// sonyflake 每次生成ID都直接读取系统时钟，不支持注入时钟，因此 WithClock 只在创建时生效：
+// 起始时间按时钟与系统时间的差值平移，使ID中的时间（按起始时间解码）从时钟的当前时间开始，
+// 之后随系统时间推进，推进时钟不影响已创建的生成器。
+// 起始时间晚于时钟的当前时间时返回 sonyflake.ErrStartTimeAhead。
+func NewSonyflake(opts ...Option) (*sonyflake.Sonyflake, error) {
+	o := newOptions(opts)
+	startTime := o.startTime
+	if startTime.IsZero() {
+		startTime = defaultSonyflakeStartTime
+	}
+	if o.clock != nil {
+		startTime = startTime.Add(time.Since(o.clock.Now()))
+	}
+	settings := sonyflake.Settings{StartTime: startTime}
+	if o.machineID != nil {
+		machineID := *o.machineID
+		settings.MachineID = func() (uint16, error) { return machineID, nil }
+	}
+	return sonyflake.New(settings)
+}
+
 func NewSonyflakeID() (uint64, error) {
 	// 64 位 ID = 39 位时间戳 + 8 位机器 ID + 16 位序列号
 
@@ -4,8 +4,12 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/sony/sonyflake"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 func TestNewGUIDv4(t *testing.T) {
@@ -257,6 +261,28 @@ func TestConcurrentNewSonyflakeIDCollisionRate(t *testing.T) {
 	t.Logf("生成了 %d 个 Sonyflake ID，无碰撞。", testCount)
 }
 
+func TestNewSonyflake(t *testing.T) {
+	now := time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)
+	s, err := NewSonyflake(WithClock(clock.Fixed(now)), WithMachineID(7))
+	assert.NoError(t, err)
+
+	id, err := s.NextID()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), sonyflake.MachineID(id))
+	// ID 中的时间按默认起始时间解码为时钟的当前时间
+	assert.WithinDuration(t, now, defaultSonyflakeStartTime.Add(sonyflake.ElapsedTime(id)), time.Second)
+
+	start := now.Add(-time.Hour)
+	s, err = NewSonyflake(WithClock(clock.Fixed(now)), WithStartTime(start), WithMachineID(7))
+	assert.NoError(t, err)
+	id, err = s.NextID()
+	assert.NoError(t, err)
+	assert.InDelta(t, time.Hour, sonyflake.ElapsedTime(id), float64(time.Second))
+
+	_, err = NewSonyflake(WithClock(clock.Fixed(now)), WithStartTime(now.Add(time.Hour)), WithMachineID(7))
+	assert.ErrorIs(t, err, sonyflake.ErrStartTimeAhead)
+}
+
 func TestNewMongoObjectID(t *testing.T) {
 	// 测试生成的 ObjectID 是否非空
 	id := NewMongoObjectID()
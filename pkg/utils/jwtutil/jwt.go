@@ -1,12 +1,15 @@
 package jwtutil
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/go-kratos/kratos/v2/encoding"
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 // ParseJWTPayload 使用 github.com/golang-jwt/jwt/v5 从 JWT 中解析出 payload
@@ -215,13 +218,18 @@ func ExtractJWTFromRequest(r *http.Request) (string, error) {
 
 // GenerateShortLivedJWT 生成短期有效的JWT
 func GenerateShortLivedJWT(payload jwt.MapClaims, secretKey []byte, signingMethod jwt.SigningMethod, duration time.Duration) (string, error) {
+	return GenerateShortLivedJWTContext(context.Background(), payload, secretKey, signingMethod, duration)
+}
+
+// GenerateShortLivedJWTContext 生成短期有效的JWT，过期时间按 ctx 中的时钟计算
+func GenerateShortLivedJWTContext(ctx context.Context, payload jwt.MapClaims, secretKey []byte, signingMethod jwt.SigningMethod, duration time.Duration) (string, error) {
 	// 检查密钥是否为空
 	if len(secretKey) == 0 {
 		return "", fmt.Errorf("secret key cannot be empty")
 	}
 
 	// 设置过期时间
-	expirationTime := time.Now().Add(duration).Unix()
+	expirationTime := clock.NowContext(ctx).Add(duration).Unix()
 	payload["exp"] = expirationTime
 
 	// 创建一个新的 JWT Token
@@ -285,6 +293,11 @@ func ValidateJWTAlgorithm(tokenString string, expectedAlgorithm string) (bool, e
 
 // IsJWTExpired 检查JWT是否过期
 func IsJWTExpired(tokenString string) (bool, error) {
+	return IsJWTExpiredContext(context.Background(), tokenString)
+}
+
+// IsJWTExpiredContext 按 ctx 中的时钟检查JWT是否过期
+func IsJWTExpiredContext(ctx context.Context, tokenString string) (bool, error) {
 	// 解析 JWT 的 payload
 	claims, err := ParseJWTPayload(tokenString)
 	if err != nil {
@@ -298,7 +311,7 @@ func IsJWTExpired(tokenString string) (bool, error) {
 	}
 
 	// 检查当前时间是否超过 `exp`
-	if time.Now().After(exp.Time) {
+	if clock.NowContext(ctx).After(exp.Time) {
 		return true, nil
 	}
 
@@ -1,6 +1,7 @@
 package jwtutil
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/heyinLab/common/pkg/utils/clock"
+
 	_ "github.com/go-kratos/kratos/v2/encoding/json"
 	_ "github.com/go-kratos/kratos/v2/encoding/proto"
 )
@@ -300,6 +303,26 @@ func TestGenerateShortLivedJWT(t *testing.T) {
 	assert.Empty(t, token)
 }
 
+func TestJWTWithContextClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC))
+	ctx := clock.NewContext(context.Background(), fake)
+
+	token, err := GenerateShortLivedJWTContext(ctx, jwt.MapClaims{"sub": "userId"}, []byte("secret"), jwt.SigningMethodHS256, time.Hour)
+	assert.NoError(t, err)
+	claims, err := ParseJWTPayload(token)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(fake.Now().Add(time.Hour).Unix()), claims["exp"])
+
+	isExpired, err := IsJWTExpiredContext(ctx, token)
+	assert.NoError(t, err)
+	assert.False(t, isExpired)
+
+	fake.Advance(2 * time.Hour)
+	isExpired, err = IsJWTExpiredContext(ctx, token)
+	assert.NoError(t, err)
+	assert.True(t, isExpired)
+}
+
 func TestValidateJWTAudience(t *testing.T) {
 	secretKey := []byte("secret")
 
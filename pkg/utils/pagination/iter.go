@@ -0,0 +1,159 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrTotalChanged 遍历过程中总数发生变化，继续翻页可能重复或遗漏数据
+var ErrTotalChanged = errors.New("pagination: total changed during iteration")
+
+// Page 一页数据，Total 为总数，小于 0 表示未知
+type Page[T any] struct {
+	Items []T
+	Total int64
+}
+
+// Fetcher 拉取第 page 页（从 1 开始）的数据
+type Fetcher[T any] func(ctx context.Context, page, pageSize int32) (Page[T], error)
+
+// Option 分页迭代配置项
+type Option func(*iterOptions)
+
+type iterOptions struct {
+	pageSize   int32
+	prefetch   bool
+	maxPages   int32
+	totalCheck bool
+}
+
+// WithPageSize 设置每页数量，服务端可能限制最大值
+func WithPageSize(size int32) Option {
+	return func(o *iterOptions) { o.pageSize = size }
+}
+
+// WithPrefetch 处理当前页时并发拉取下一页
+func WithPrefetch(prefetch bool) Option {
+	return func(o *iterOptions) { o.prefetch = prefetch }
+}
+
+// WithMaxPages 最多拉取的页数，0 表示不限制
+func WithMaxPages(n int32) Option {
+	return func(o *iterOptions) { o.maxPages = n }
+}
+
+// WithTotalCheck 总数变化时是否以 ErrTotalChanged 终止，默认开启
+func WithTotalCheck(check bool) Option {
+	return func(o *iterOptions) { o.totalCheck = check }
+}
+
+type pageResult[T any] struct {
+	page Page[T]
+	err  error
+}
+
+// Iterate 返回逐条遍历分页数据的迭代器
+//
+// 遇到空页、已遍历数量达到总数（总数未知时为不满一页）或达到最大页数时结束；出错时产出一次 (零值, err) 后结束。
+// 调用方 break 时会取消尚未完成的预取请求。
+//
+// 示例:
+//
+//	for tenant, err := range pagination.Iterate(ctx, fetch, pagination.WithPageSize(20)) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Iterate[T any](ctx context.Context, fetch Fetcher[T], opts ...Option) iter.Seq2[T, error] {
+	o := &iterOptions{pageSize: DefaultPageSize, totalCheck: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.pageSize <= 0 {
+		o.pageSize = DefaultPageSize
+	}
+
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var zero T
+		start := func(page int32) <-chan pageResult[T] {
+			ch := make(chan pageResult[T], 1)
+			go func() {
+				p, err := fetch(ctx, page, o.pageSize)
+				ch <- pageResult[T]{page: p, err: err}
+			}()
+			return ch
+		}
+		// 未被消费的预取结果在返回前等待，避免 goroutine 泄漏
+		var pending <-chan pageResult[T]
+		defer func() {
+			if pending != nil {
+				cancel()
+				<-pending
+			}
+		}()
+
+		var (
+			seen  int64
+			total int64 = -1
+		)
+		for page := int32(1); o.maxPages <= 0 || page <= o.maxPages; page++ {
+			var result pageResult[T]
+			if pending != nil {
+				result = <-pending
+				pending = nil
+			} else {
+				p, err := fetch(ctx, page, o.pageSize)
+				result = pageResult[T]{page: p, err: err}
+			}
+			if result.err != nil {
+				yield(zero, result.err)
+				return
+			}
+
+			current := result.page
+			if o.totalCheck && total >= 0 && current.Total >= 0 && current.Total != total {
+				yield(zero, fmt.Errorf("%w: %d -> %d at page %d", ErrTotalChanged, total, current.Total, page))
+				return
+			}
+			if current.Total >= 0 {
+				total = current.Total
+			}
+			if len(current.Items) == 0 {
+				return
+			}
+			seen += int64(len(current.Items))
+
+			// 总数未知时，不满一页视为最后一页
+			more := seen < total || (total < 0 && int32(len(current.Items)) >= o.pageSize)
+			if more && o.prefetch && (o.maxPages <= 0 || page < o.maxPages) {
+				pending = start(page + 1)
+			}
+			for _, item := range current.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// Collect 遍历全部数据，出错时返回已获取的数据与错误
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,121 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	mu    sync.Mutex
+	items []int
+	calls []int32
+	// grow 在拉取第 grow 页后追加一条数据，模拟遍历过程中的写入
+	grow int32
+}
+
+func (s *fakeSource) fetch(ctx context.Context, page, pageSize int32) (Page[int], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return Page[int]{}, err
+	}
+	s.calls = append(s.calls, page)
+	total := int64(len(s.items))
+	start := min(int(GetPageOffset(page, pageSize)), len(s.items))
+	end := min(start+int(pageSize), len(s.items))
+	items := s.items[start:end]
+	if page == s.grow {
+		s.items = append(s.items, len(s.items))
+	}
+	return Page[int]{Items: items, Total: total}, nil
+}
+
+func newFakeSource(n int) *fakeSource {
+	s := &fakeSource{}
+	for i := range n {
+		s.items = append(s.items, i)
+	}
+	return s
+}
+
+func TestIterate(t *testing.T) {
+	for _, prefetch := range []bool{false, true} {
+		src := newFakeSource(7)
+		items, err := Collect(Iterate(context.Background(), src.fetch, WithPageSize(3), WithPrefetch(prefetch)))
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, items)
+		// 已遍历数量达到总数后不再请求
+		require.Equal(t, []int32{1, 2, 3}, src.calls)
+	}
+
+	src := newFakeSource(0)
+	items, err := Collect(Iterate(context.Background(), src.fetch))
+	require.NoError(t, err)
+	require.Empty(t, items)
+	require.Equal(t, []int32{1}, src.calls)
+}
+
+func TestIterate_UnknownTotal(t *testing.T) {
+	src := newFakeSource(6)
+	fetch := func(ctx context.Context, page, pageSize int32) (Page[int], error) {
+		p, err := src.fetch(ctx, page, pageSize)
+		p.Total = -1
+		return p, err
+	}
+	items, err := Collect(Iterate(context.Background(), fetch, WithPageSize(3)))
+	require.NoError(t, err)
+	require.Len(t, items, 6)
+	// 满页时需要再请求一页确认结束
+	require.Equal(t, []int32{1, 2, 3}, src.calls)
+}
+
+func TestIterate_EarlyTermination(t *testing.T) {
+	src := newFakeSource(100)
+	var got []int
+	for item, err := range Iterate(context.Background(), src.fetch, WithPageSize(10), WithPrefetch(true)) {
+		require.NoError(t, err)
+		got = append(got, item)
+		if item == 14 {
+			break
+		}
+	}
+	require.Len(t, got, 15)
+	// 最多预取了下一页
+	require.LessOrEqual(t, len(src.calls), 3)
+
+	src = newFakeSource(100)
+	items, err := Collect(Iterate(context.Background(), src.fetch, WithPageSize(10), WithMaxPages(2)))
+	require.NoError(t, err)
+	require.Len(t, items, 20)
+}
+
+func TestIterate_TotalChanged(t *testing.T) {
+	src := newFakeSource(6)
+	src.grow = 1
+	items, err := Collect(Iterate(context.Background(), src.fetch, WithPageSize(3)))
+	require.ErrorIs(t, err, ErrTotalChanged)
+	require.Equal(t, []int{0, 1, 2}, items)
+
+	src = newFakeSource(6)
+	src.grow = 1
+	items, err = Collect(Iterate(context.Background(), src.fetch, WithPageSize(3), WithTotalCheck(false)))
+	require.NoError(t, err)
+	require.Len(t, items, 7)
+}
+
+func TestIterate_Error(t *testing.T) {
+	boom := errors.New("boom")
+	fetch := func(ctx context.Context, page, pageSize int32) (Page[int], error) {
+		if page == 2 {
+			return Page[int]{}, boom
+		}
+		return Page[int]{Items: []int{1, 2}, Total: 10}, nil
+	}
+	items, err := Collect(Iterate(context.Background(), fetch, WithPageSize(2), WithPrefetch(true)))
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, []int{1, 2}, items)
+}
@@ -0,0 +1,30 @@
+package timeutil
+
+import "time"
+
+// DaysInMonth 返回指定年月的天数
+func DaysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// AddMonths 增加 months 个月，日期超出目标月份天数时取目标月份的最后一天
+//
+// 与 time.AddDate 不同，1月31日加1个月得到2月28日（或29日），而不是3月3日。
+// 连续的账期应当始终从同一个起始时间计算，避免 31日 -> 28日 -> 28日 的漂移。
+func AddMonths(t time.Time, months int) time.Time {
+	year, month, day := addMonthsDate(t, months)
+	hour, minute, sec := t.Clock()
+	return time.Date(year, month, day, hour, minute, sec, t.Nanosecond(), t.Location())
+}
+
+// addMonthsDate 返回 t 的日期增加 months 个月后的年月日，日期超出目标月份天数时取最后一天
+func addMonthsDate(t time.Time, months int) (int, time.Month, int) {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	return first.Year(), first.Month(), min(day, DaysInMonth(first.Year(), first.Month()))
+}
+
+// AddYears 增加 years 年，2月29日在平年取2月28日
+func AddYears(t time.Time, years int) time.Time {
+	return AddMonths(t, years*12)
+}
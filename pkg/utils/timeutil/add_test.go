@@ -0,0 +1,26 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddMonths(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	start := time.Date(2024, 1, 31, 10, 30, 0, 0, loc)
+
+	assert.Equal(t, time.Date(2024, 2, 29, 10, 30, 0, 0, loc), AddMonths(start, 1))
+	assert.Equal(t, time.Date(2024, 3, 31, 10, 30, 0, 0, loc), AddMonths(start, 2))
+	assert.Equal(t, time.Date(2024, 4, 30, 10, 30, 0, 0, loc), AddMonths(start, 3))
+	assert.Equal(t, time.Date(2023, 12, 31, 10, 30, 0, 0, loc), AddMonths(start, -1))
+	assert.Equal(t, time.Date(2025, 2, 28, 10, 30, 0, 0, loc), AddMonths(start, 13))
+
+	leap := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), AddYears(leap, 1))
+	assert.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), AddYears(leap, 4))
+
+	assert.Equal(t, 29, DaysInMonth(2024, time.February))
+	assert.Equal(t, 31, DaysInMonth(2024, time.December))
+}
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 // ReferenceTime Return the standard Golang reference time (2006-01-02T15:04:05.999999999Z07:00)
@@ -100,9 +102,9 @@ func ParseFirstValue(layout string, values []string) (time.Time, error) {
 	}
 	numVals := len(values)
 	if numVals == 0 {
-		return time.Now(), errors.New("no time values supplied")
+		return clock.Now(), errors.New("no time values supplied")
 	}
-	return time.Now(), fmt.Errorf("no valid string of [%v] supplied values", strconv.Itoa(numVals))
+	return clock.Now(), fmt.Errorf("no valid string of [%v] supplied values", strconv.Itoa(numVals))
 }
 
 // ParseOrZero returns a parsed time.Time or the RFC-3339 zero time.
@@ -118,7 +120,7 @@ func ParseOrZero(layout, value string) time.Time {
 func ParseFirst(layouts []string, value string) (time.Time, error) {
 	value = strings.TrimSpace(value)
 	if len(value) == 0 || len(layouts) == 0 {
-		return time.Now(), fmt.Errorf(
+		return clock.Now(), fmt.Errorf(
 			"requires value [%v] and at least one layout [%v]", value, strings.Join(layouts, ","))
 	}
 	for _, layout := range layouts {
@@ -130,7 +132,7 @@ func ParseFirst(layouts []string, value string) (time.Time, error) {
 			return dt, nil
 		}
 	}
-	return time.Now(), fmt.Errorf("cannot parse time [%v] with layouts [%v]",
+	return clock.Now(), fmt.Errorf("cannot parse time [%v] with layouts [%v]",
 		value, strings.Join(layouts, ","))
 }
 
@@ -0,0 +1,422 @@
+package timeutil
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+// ErrInvalidPeriod 周期单位或数量无效
+var ErrInvalidPeriod = errors.New("timeutil: invalid period")
+
+// Unit 周期单位
+type Unit int
+
+const (
+	UnitDay     Unit = iota + 1 // 自然日
+	UnitWeek                    // 自然周（默认 ISO 周，周一为起始）
+	UnitMonth                   // 自然月
+	UnitQuarter                 // 自然季度
+	UnitYear                    // 自然年
+)
+
+var unitNames = map[Unit]string{
+	UnitDay:     "day",
+	UnitWeek:    "week",
+	UnitMonth:   "month",
+	UnitQuarter: "quarter",
+	UnitYear:    "year",
+}
+
+func (u Unit) String() string {
+	if name, ok := unitNames[u]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unit(%d)", int(u))
+}
+
+// ParseUnit 解析周期单位，支持 day/week/month/quarter/year 及 DAILY/WEEKLY/MONTHLY/QUARTERLY/YEARLY，不区分大小写
+func ParseUnit(s string) (Unit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "day", "days", "daily", "d":
+		return UnitDay, nil
+	case "week", "weeks", "weekly", "w":
+		return UnitWeek, nil
+	case "month", "months", "monthly", "m":
+		return UnitMonth, nil
+	case "quarter", "quarters", "quarterly", "q":
+		return UnitQuarter, nil
+	case "year", "years", "yearly", "annually", "y":
+		return UnitYear, nil
+	}
+	return 0, fmt.Errorf("%w: unknown unit %q", ErrInvalidPeriod, s)
+}
+
+// months 以月为基础的单位对应的月数，以天为基础的单位返回 0
+func (u Unit) months() int {
+	switch u {
+	case UnitMonth:
+		return 1
+	case UnitQuarter:
+		return 3
+	case UnitYear:
+		return 12
+	}
+	return 0
+}
+
+// days 以天为基础的单位对应的天数，以月为基础的单位返回 0
+func (u Unit) days() int {
+	switch u {
+	case UnitDay:
+		return 1
+	case UnitWeek:
+		return 7
+	}
+	return 0
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Period 周期，N 个 Unit，如 Every(2, UnitWeek) 表示双周
+type Period struct {
+	N    int
+	Unit Unit
+}
+
+// 常用周期
+var (
+	Daily     = Period{N: 1, Unit: UnitDay}
+	Weekly    = Period{N: 1, Unit: UnitWeek}
+	Monthly   = Period{N: 1, Unit: UnitMonth}
+	Quarterly = Period{N: 1, Unit: UnitQuarter}
+	Yearly    = Period{N: 1, Unit: UnitYear}
+)
+
+// Every 创建 n 个 unit 的周期
+func Every(n int, unit Unit) Period {
+	return Period{N: n, Unit: unit}
+}
+
+// Validate 校验周期是否有效
+func (p Period) Validate() error {
+	if p.N < 1 {
+		return fmt.Errorf("%w: n must be positive, got %d", ErrInvalidPeriod, p.N)
+	}
+	if _, ok := unitNames[p.Unit]; !ok {
+		return fmt.Errorf("%w: unknown unit %d", ErrInvalidPeriod, int(p.Unit))
+	}
+	return nil
+}
+
+func (p Period) String() string {
+	if p.N == 1 {
+		return p.Unit.String()
+	}
+	return fmt.Sprintf("%d %ss", p.N, p.Unit)
+}
+
+// AddTo 在 t 的基础上增加 k 个周期，按 t 所在时区的日历计算
+//
+// 按月计算的周期日期超出目标月份天数时取最后一天（1月31日 + 1个月 = 2月28日），
+// 与 AddMonths 一致；落在夏令时跳过的时段时取跳变后的第一个时刻。
+func (p Period) AddTo(t time.Time, k int) time.Time {
+	hour, minute, sec := t.Clock()
+	if months := p.Unit.months(); months > 0 {
+		year, month, day := addMonthsDate(t, k*p.N*months)
+		return civilTime(year, month, day, hour, minute, sec, t.Nanosecond(), t.Location())
+	}
+	year, month, day := t.Date()
+	return civilTime(year, month, day+k*p.N*p.Unit.days(), hour, minute, sec, t.Nanosecond(), t.Location())
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Window 时间窗口 [Start, End)
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains t 是否在窗口内
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// Duration 窗口时长，跨夏令时切换时不等于名义时长
+func (w Window) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// RangeTime 返回闭区间 [Start, End-1s]，与 GetTodayRangeTime 等函数的 23:59:59 结束时间一致
+func (w Window) RangeTime() (time.Time, time.Time) {
+	return w.Start, w.End.Add(-time.Second)
+}
+
+// RangeDateString 返回起止日期字符串（比如：2023-05-01 2023-05-31）
+func (w Window) RangeDateString() (string, string) {
+	start, end := w.RangeTime()
+	return start.Format(DateLayout), end.Format(DateLayout)
+}
+
+func (w Window) String() string {
+	return "[" + w.Start.Format(time.RFC3339) + ", " + w.End.Format(time.RFC3339) + ")"
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// PeriodOption 周期引擎配置项
+type PeriodOption func(*PeriodEngine)
+
+// WithClock 设置时钟，默认为 clock.Default()，测试中可注入 clock.NewFake 或 clock.Fixed
+func WithClock(c clock.Clock) PeriodOption {
+	return func(e *PeriodEngine) {
+		if c != nil {
+			e.clock = c
+		}
+	}
+}
+
+// WithLocation 设置时区，默认为 GetDefaultTimeLocation
+func WithLocation(loc *time.Location) PeriodOption {
+	return func(e *PeriodEngine) {
+		if loc != nil {
+			e.loc = loc
+		}
+	}
+}
+
+// WithWeekStart 设置每周的第一天，默认为周一（ISO 8601）
+func WithWeekStart(day time.Weekday) PeriodOption {
+	return func(e *PeriodEngine) { e.weekStart = day }
+}
+
+// WithAnchor 设置周期的起算时间，窗口为 [anchor + k*p, anchor + (k+1)*p)
+//
+// 用于按订阅开始时间计算的账期，如 1月31日 10:00 起算的月度窗口为
+// 1月31日、2月28日、3月31日的 10:00。设置后 WithWeekStart 不再生效。
+func WithAnchor(anchor time.Time) PeriodOption {
+	return func(e *PeriodEngine) { e.anchor = anchor }
+}
+
+// PeriodEngine 周期引擎，按指定时区与时间源计算自然日/周/月/季/年及自定义 N 个单位的周期窗口
+//
+// 与 GetTodayRangeTime 等函数不同，时区与时间源均可指定，适用于按租户时区计算与
+// 定价规则中 DAILY/WEEKLY/MONTHLY/YEARLY 的重置周期。
+// 未设置 WithAnchor 时，N 个单位的周期从 2000-01-01（周为其后第一个每周起始日）起对齐。
+//
+// 示例:
+//
+//	engine := timeutil.NewPeriodEngine(timeutil.WithLocation(loc))
+//	today := engine.Current(timeutil.Daily)
+//	lastMonth := engine.Previous(timeutil.Monthly)
+//	for w := range engine.Windows(timeutil.Weekly, from, to) {
+//	    ...
+//	}
+type PeriodEngine struct {
+	clock     clock.Clock
+	loc       *time.Location
+	weekStart time.Weekday
+	anchor    time.Time
+}
+
+// NewPeriodEngine 创建周期引擎
+func NewPeriodEngine(opts ...PeriodOption) *PeriodEngine {
+	e := &PeriodEngine{
+		clock:     clock.Default(),
+		weekStart: time.Monday,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.loc == nil {
+		e.loc = GetDefaultTimeLocation()
+	}
+	if e.loc == nil {
+		e.loc = time.Local
+	}
+	return e
+}
+
+// Location 引擎使用的时区
+func (e *PeriodEngine) Location() *time.Location {
+	return e.loc
+}
+
+// Now 当前时间，已转换为引擎的时区
+func (e *PeriodEngine) Now() time.Time {
+	return e.clock.Now().In(e.loc)
+}
+
+// Current 当前时间所在的窗口
+func (e *PeriodEngine) Current(p Period) Window {
+	return e.At(p, e.clock.Now())
+}
+
+// Previous 当前时间所在窗口的上一个窗口
+func (e *PeriodEngine) Previous(p Period) Window {
+	return e.Shift(p, e.clock.Now(), -1)
+}
+
+// Next 当前时间所在窗口的下一个窗口
+func (e *PeriodEngine) Next(p Period) Window {
+	return e.Shift(p, e.clock.Now(), 1)
+}
+
+// At t 所在的窗口，周期无效时返回零值
+func (e *PeriodEngine) At(p Period, t time.Time) Window {
+	return e.Shift(p, t, 0)
+}
+
+// Shift t 所在窗口之后第 k 个窗口，k 为负数时向前，周期无效时返回零值
+func (e *PeriodEngine) Shift(p Period, t time.Time, k int) Window {
+	if p.Validate() != nil {
+		return Window{}
+	}
+	anchor := e.anchorFor(p.Unit)
+	i := e.index(p, anchor, t.In(e.loc)) + k
+	return Window{Start: p.AddTo(anchor, i), End: p.AddTo(anchor, i+1)}
+}
+
+// Truncate 返回 t 所在自然日/周/月/季/年的起始时间，不受 WithAnchor 影响
+func (e *PeriodEngine) Truncate(t time.Time, unit Unit) time.Time {
+	t = t.In(e.loc)
+	year, month, day := t.Date()
+
+	switch unit {
+	case UnitDay:
+		return civilTime(year, month, day, 0, 0, 0, 0, e.loc)
+	case UnitWeek:
+		offset := (int(t.Weekday()) - int(e.weekStart) + 7) % 7
+		return civilTime(year, month, day-offset, 0, 0, 0, 0, e.loc)
+	case UnitMonth:
+		return civilTime(year, month, 1, 0, 0, 0, 0, e.loc)
+	case UnitQuarter:
+		return civilTime(year, month-(month-1)%3, 1, 0, 0, 0, 0, e.loc)
+	case UnitYear:
+		return civilTime(year, time.January, 1, 0, 0, 0, 0, e.loc)
+	}
+	return t
+}
+
+// Windows 遍历与 [from, to) 有交集的所有窗口，周期无效或 from 不早于 to 时不产出
+func (e *PeriodEngine) Windows(p Period, from, to time.Time) iter.Seq[Window] {
+	return func(yield func(Window) bool) {
+		if p.Validate() != nil || !from.Before(to) {
+			return
+		}
+		anchor := e.anchorFor(p.Unit)
+		for i := e.index(p, anchor, from.In(e.loc)); ; i++ {
+			w := Window{Start: p.AddTo(anchor, i), End: p.AddTo(anchor, i+1)}
+			if !w.Start.Before(to) || !yield(w) {
+				return
+			}
+		}
+	}
+}
+
+// Split 返回与 [from, to) 有交集的所有窗口
+func (e *PeriodEngine) Split(p Period, from, to time.Time) []Window {
+	var windows []Window
+	for w := range e.Windows(p, from, to) {
+		windows = append(windows, w)
+	}
+	return windows
+}
+
+// anchorFor 周期的起算时间，未指定时按自然日历对齐
+func (e *PeriodEngine) anchorFor(unit Unit) time.Time {
+	if !e.anchor.IsZero() {
+		return e.anchor.In(e.loc)
+	}
+	// 2000-01-01 为年、季、月的起点
+	anchor := civilTime(2000, time.January, 1, 0, 0, 0, 0, e.loc)
+	if unit == UnitWeek {
+		offset := (int(e.weekStart) - int(anchor.Weekday()) + 7) % 7
+		anchor = civilTime(2000, time.January, 1+offset, 0, 0, 0, 0, e.loc)
+	}
+	return anchor
+}
+
+// index t 所在窗口相对 anchor 的序号
+func (e *PeriodEngine) index(p Period, anchor, t time.Time) int {
+	var units int
+	if months := p.Unit.months(); months > 0 {
+		units = ((t.Year()-anchor.Year())*12 + int(t.Month()-anchor.Month())) / months
+	} else {
+		units = civilDays(t, anchor) / p.Unit.days()
+	}
+
+	// 先按日历估算，再根据时分秒与月末对齐修正
+	i := floorDiv(units, p.N)
+	for p.AddTo(anchor, i).After(t) {
+		i--
+	}
+	for !p.AddTo(anchor, i+1).After(t) {
+		i++
+	}
+	return i
+}
+
+// civilDays t 与 anchor 相差的日历天数
+func civilDays(t, anchor time.Time) int {
+	ty, tm, td := t.Date()
+	ay, am, ad := anchor.Date()
+	days := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour)
+	return int(days)
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// civilTime 返回 loc 时区下的本地时间
+//
+// 与 time.Date 不同，本地时间落在夏令时跳过的时段时返回跳变后的第一个时刻，
+// 落在重复的时段时返回较早的时刻，保证窗口起点单调且不重叠。
+func civilTime(year int, month time.Month, day, hour, minute, sec, nsec int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, minute, sec, nsec, loc)
+	wall := time.Date(year, month, day, hour, minute, sec, nsec, time.UTC)
+
+	_, before := t.Add(-24 * time.Hour).Zone()
+	_, after := t.Add(24 * time.Hour).Zone()
+	if before == after {
+		return t
+	}
+
+	early := wall.Add(-time.Duration(max(before, after)) * time.Second).In(loc)
+	late := wall.Add(-time.Duration(min(before, after)) * time.Second).In(loc)
+	switch {
+	case wallEqual(early, wall):
+		return early
+	case wallEqual(late, wall):
+		return late
+	}
+
+	// 跳过的时段：二分查找偏移量切换的时刻
+	lo, hi := early, late
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, off := mid.Zone(); off == before {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Second)
+}
+
+// wallEqual t 的本地时间是否为 wall（以 UTC 表示）
+func wallEqual(t, wall time.Time) bool {
+	year, month, day := t.Date()
+	hour, minute, sec := t.Clock()
+	return time.Date(year, month, day, hour, minute, sec, t.Nanosecond(), time.UTC).Equal(wall)
+}
@@ -0,0 +1,174 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestPeriodEngineCurrent(t *testing.T) {
+	loc := mustLoadLocation(t, "Asia/Shanghai")
+	// 2024-05-15 星期三 10:30
+	now := time.Date(2024, 5, 15, 10, 30, 0, 0, loc)
+	engine := NewPeriodEngine(WithLocation(loc), WithClock(clock.Fixed(now.UTC())))
+
+	tests := []struct {
+		period     Period
+		start, end time.Time
+	}{
+		{Daily, time.Date(2024, 5, 15, 0, 0, 0, 0, loc), time.Date(2024, 5, 16, 0, 0, 0, 0, loc)},
+		{Weekly, time.Date(2024, 5, 13, 0, 0, 0, 0, loc), time.Date(2024, 5, 20, 0, 0, 0, 0, loc)},
+		{Monthly, time.Date(2024, 5, 1, 0, 0, 0, 0, loc), time.Date(2024, 6, 1, 0, 0, 0, 0, loc)},
+		{Quarterly, time.Date(2024, 4, 1, 0, 0, 0, 0, loc), time.Date(2024, 7, 1, 0, 0, 0, 0, loc)},
+		{Yearly, time.Date(2024, 1, 1, 0, 0, 0, 0, loc), time.Date(2025, 1, 1, 0, 0, 0, 0, loc)},
+		{Every(2, UnitWeek), time.Date(2024, 5, 6, 0, 0, 0, 0, loc), time.Date(2024, 5, 20, 0, 0, 0, 0, loc)},
+		{Every(6, UnitMonth), time.Date(2024, 1, 1, 0, 0, 0, 0, loc), time.Date(2024, 7, 1, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period.String(), func(t *testing.T) {
+			w := engine.Current(tt.period)
+			assert.True(t, tt.start.Equal(w.Start), "start: %s", w)
+			assert.True(t, tt.end.Equal(w.End), "end: %s", w)
+			assert.True(t, w.Contains(now))
+		})
+	}
+
+	prev := engine.Previous(Monthly)
+	assert.True(t, time.Date(2024, 4, 1, 0, 0, 0, 0, loc).Equal(prev.Start))
+	start, end := prev.RangeTime()
+	assert.Equal(t, "2024-04-01 00:00:00", start.Format(TimeLayout))
+	assert.Equal(t, "2024-04-30 23:59:59", end.Format(TimeLayout))
+
+	next := engine.Next(Weekly)
+	assert.True(t, time.Date(2024, 5, 20, 0, 0, 0, 0, loc).Equal(next.Start))
+
+	sunday := NewPeriodEngine(WithLocation(loc), WithWeekStart(time.Sunday))
+	assert.True(t, time.Date(2024, 5, 12, 0, 0, 0, 0, loc).Equal(sunday.At(Weekly, now).Start))
+
+	assert.Equal(t, Window{}, engine.Current(Every(0, UnitDay)))
+	assert.ErrorIs(t, Period{N: 1}.Validate(), ErrInvalidPeriod)
+}
+
+func TestPeriodEngineTimezone(t *testing.T) {
+	// 同一时刻在不同时区属于不同的自然日
+	instant := time.Date(2024, 5, 15, 20, 0, 0, 0, time.UTC)
+
+	shanghai := NewPeriodEngine(WithLocation(mustLoadLocation(t, "Asia/Shanghai")))
+	start, _ := shanghai.At(Daily, instant).RangeDateString()
+	assert.Equal(t, "2024-05-16", start)
+
+	newYork := NewPeriodEngine(WithLocation(mustLoadLocation(t, "America/New_York")))
+	start, _ = newYork.At(Daily, instant).RangeDateString()
+	assert.Equal(t, "2024-05-15", start)
+}
+
+func TestPeriodEngineAnchor(t *testing.T) {
+	loc := mustLoadLocation(t, "Asia/Shanghai")
+	anchor := time.Date(2024, 1, 31, 10, 0, 0, 0, loc)
+	engine := NewPeriodEngine(WithLocation(loc), WithAnchor(anchor))
+
+	var starts []string
+	for w := range engine.Windows(Monthly, anchor, time.Date(2024, 5, 1, 0, 0, 0, 0, loc)) {
+		starts = append(starts, w.Start.Format(TimeLayout))
+	}
+	assert.Equal(t, []string{
+		"2024-01-31 10:00:00",
+		"2024-02-29 10:00:00",
+		"2024-03-31 10:00:00",
+		"2024-04-30 10:00:00",
+	}, starts)
+
+	// 月末对齐不漂移：3月31日 09:00 仍属于 2月29日 开始的窗口
+	w := engine.At(Monthly, time.Date(2024, 3, 31, 9, 0, 0, 0, loc))
+	assert.Equal(t, "2024-02-29 10:00:00", w.Start.Format(TimeLayout))
+
+	// 起算时间之前的窗口
+	w = engine.At(Monthly, time.Date(2023, 12, 15, 0, 0, 0, 0, loc))
+	assert.Equal(t, "2023-11-30 10:00:00", w.Start.Format(TimeLayout))
+	assert.Equal(t, "2023-12-31 10:00:00", w.End.Format(TimeLayout))
+
+	days := NewPeriodEngine(WithLocation(loc), WithAnchor(time.Date(2024, 1, 1, 4, 0, 0, 0, loc)))
+	w = days.At(Every(3, UnitDay), time.Date(2024, 1, 4, 3, 0, 0, 0, loc))
+	assert.Equal(t, "2024-01-01 04:00:00", w.Start.Format(TimeLayout))
+	assert.Equal(t, "2024-01-04 04:00:00", w.End.Format(TimeLayout))
+}
+
+func TestPeriodEngineDST(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	engine := NewPeriodEngine(WithLocation(newYork))
+
+	// 2024-03-10 02:00 跳到 03:00，当天只有 23 小时
+	w := engine.At(Daily, time.Date(2024, 3, 10, 12, 0, 0, 0, newYork))
+	assert.Equal(t, 23*time.Hour, w.Duration())
+
+	// 2024-11-03 01:00-02:00 重复，当天有 25 小时
+	w = engine.At(Daily, time.Date(2024, 11, 3, 12, 0, 0, 0, newYork))
+	assert.Equal(t, 25*time.Hour, w.Duration())
+
+	// 每天 02:30 重置，跳过的时段取跳变后的第一个时刻
+	anchored := NewPeriodEngine(WithLocation(newYork), WithAnchor(time.Date(2024, 3, 1, 2, 30, 0, 0, newYork)))
+	w = anchored.At(Daily, time.Date(2024, 3, 10, 12, 0, 0, 0, newYork))
+	assert.Equal(t, "2024-03-10 03:00:00 EDT", w.Start.Format("2006-01-02 15:04:05 MST"))
+	w = anchored.At(Daily, time.Date(2024, 3, 9, 12, 0, 0, 0, newYork))
+	assert.Equal(t, "2024-03-10 03:00:00 EDT", w.End.Format("2006-01-02 15:04:05 MST"))
+
+	// 圣地亚哥 2024-09-08 00:00 跳到 01:00，当天从 01:00 开始
+	santiago := mustLoadLocation(t, "America/Santiago")
+	w = NewPeriodEngine(WithLocation(santiago)).At(Daily, time.Date(2024, 9, 8, 12, 0, 0, 0, santiago))
+	assert.Equal(t, "2024-09-08 01:00:00", w.Start.Format(TimeLayout))
+	assert.Equal(t, 23*time.Hour, w.Duration())
+}
+
+func TestPeriodEngineTruncate(t *testing.T) {
+	loc := mustLoadLocation(t, "Asia/Shanghai")
+	engine := NewPeriodEngine(WithLocation(loc), WithAnchor(time.Date(2024, 1, 31, 10, 0, 0, 0, loc)))
+	tm := time.Date(2024, 8, 17, 15, 4, 5, 0, loc)
+
+	assert.Equal(t, "2024-08-17 00:00:00", engine.Truncate(tm, UnitDay).Format(TimeLayout))
+	assert.Equal(t, "2024-08-12 00:00:00", engine.Truncate(tm, UnitWeek).Format(TimeLayout))
+	assert.Equal(t, "2024-08-01 00:00:00", engine.Truncate(tm, UnitMonth).Format(TimeLayout))
+	assert.Equal(t, "2024-07-01 00:00:00", engine.Truncate(tm, UnitQuarter).Format(TimeLayout))
+	assert.Equal(t, "2024-01-01 00:00:00", engine.Truncate(tm, UnitYear).Format(TimeLayout))
+}
+
+func TestPeriodEngineSplit(t *testing.T) {
+	loc := mustLoadLocation(t, "Asia/Shanghai")
+	engine := NewPeriodEngine(WithLocation(loc))
+
+	windows := engine.Split(Weekly, time.Date(2024, 5, 15, 0, 0, 0, 0, loc), time.Date(2024, 6, 1, 0, 0, 0, 0, loc))
+	require.Len(t, windows, 3)
+	assert.Equal(t, "2024-05-13", windows[0].Start.Format(DateLayout))
+	assert.Equal(t, "2024-05-27", windows[2].Start.Format(DateLayout))
+
+	assert.Empty(t, engine.Split(Daily, time.Date(2024, 5, 15, 0, 0, 0, 0, loc), time.Date(2024, 5, 15, 0, 0, 0, 0, loc)))
+
+	count := 0
+	for range engine.Windows(Daily, time.Date(2024, 1, 1, 0, 0, 0, 0, loc), time.Date(2025, 1, 1, 0, 0, 0, 0, loc)) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, count)
+}
+
+func TestParseUnit(t *testing.T) {
+	for s, want := range map[string]Unit{"DAILY": UnitDay, "week": UnitWeek, "Monthly": UnitMonth, "quarterly": UnitQuarter, "YEARLY": UnitYear} {
+		unit, err := ParseUnit(s)
+		require.NoError(t, err)
+		assert.Equal(t, want, unit)
+	}
+	_, err := ParseUnit("hourly")
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	assert.Equal(t, "3 months", Every(3, UnitMonth).String())
+}
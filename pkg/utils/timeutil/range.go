@@ -1,10 +1,20 @@
 package timeutil
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
+)
 
 // GetYesterdayRangeTime 获取区间时间 - 昨天
 func GetYesterdayRangeTime() (time.Time, time.Time) {
-	now := time.Now().AddDate(0, 0, -1)
+	return GetYesterdayRangeTimeContext(context.Background())
+}
+
+// GetYesterdayRangeTimeContext 获取区间时间 - 昨天，按 ctx 中的时钟计算
+func GetYesterdayRangeTimeContext(ctx context.Context) (time.Time, time.Time) {
+	now := clock.NowContext(ctx).AddDate(0, 0, -1)
 	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endDate := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
 	return startDate, endDate
@@ -12,7 +22,12 @@ func GetYesterdayRangeTime() (time.Time, time.Time) {
 
 // GetTodayRangeTime 获取区间时间 - 今天
 func GetTodayRangeTime() (time.Time, time.Time) {
-	now := time.Now()
+	return GetTodayRangeTimeContext(context.Background())
+}
+
+// GetTodayRangeTimeContext 获取区间时间 - 今天，按 ctx 中的时钟计算
+func GetTodayRangeTimeContext(ctx context.Context) (time.Time, time.Time) {
+	now := clock.NowContext(ctx)
 	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endDate := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
 	return startDate, endDate
@@ -20,7 +35,12 @@ func GetTodayRangeTime() (time.Time, time.Time) {
 
 // GetLastMonthRangeTime 获取区间时间 - 上个月
 func GetLastMonthRangeTime() (time.Time, time.Time) {
-	now := time.Now().AddDate(0, -1, 0)
+	return GetLastMonthRangeTimeContext(context.Background())
+}
+
+// GetLastMonthRangeTimeContext 获取区间时间 - 上个月，按 ctx 中的时钟计算
+func GetLastMonthRangeTimeContext(ctx context.Context) (time.Time, time.Time) {
+	now := clock.NowContext(ctx).AddDate(0, -1, 0)
 	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	lastDay := firstDay.AddDate(0, 1, -1)
 	endDate := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 23, 59, 59, 0, now.Location())
@@ -29,7 +49,12 @@ func GetLastMonthRangeTime() (time.Time, time.Time) {
 
 // GetCurrentMonthRangeTime 获取区间时间 - 本月
 func GetCurrentMonthRangeTime() (time.Time, time.Time) {
-	now := time.Now()
+	return GetCurrentMonthRangeTimeContext(context.Background())
+}
+
+// GetCurrentMonthRangeTimeContext 获取区间时间 - 本月，按 ctx 中的时钟计算
+func GetCurrentMonthRangeTimeContext(ctx context.Context) (time.Time, time.Time) {
+	now := clock.NowContext(ctx)
 	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	lastDay := firstDay.AddDate(0, 1, -1)
 	endDate := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 23, 59, 59, 0, now.Location())
@@ -38,7 +63,12 @@ func GetCurrentMonthRangeTime() (time.Time, time.Time) {
 
 // GetCurrentYearRangeTime 获取区间时间 - 今年
 func GetCurrentYearRangeTime() (time.Time, time.Time) {
-	now := time.Now()
+	return GetCurrentYearRangeTimeContext(context.Background())
+}
+
+// GetCurrentYearRangeTimeContext 获取区间时间 - 今年，按 ctx 中的时钟计算
+func GetCurrentYearRangeTimeContext(ctx context.Context) (time.Time, time.Time) {
+	now := clock.NowContext(ctx)
 	firstDay := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
 	lastDay := firstDay.AddDate(1, 0, -1)
 	endDate := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 23, 59, 59, 0, now.Location())
@@ -47,7 +77,12 @@ func GetCurrentYearRangeTime() (time.Time, time.Time) {
 
 // GetLastYearRangeTime 获取区间时间 - 去年
 func GetLastYearRangeTime() (time.Time, time.Time) {
-	now := time.Now().AddDate(-1, 0, 0)
+	return GetLastYearRangeTimeContext(context.Background())
+}
+
+// GetLastYearRangeTimeContext 获取区间时间 - 去年，按 ctx 中的时钟计算
+func GetLastYearRangeTimeContext(ctx context.Context) (time.Time, time.Time) {
+	now := clock.NowContext(ctx).AddDate(-1, 0, 0)
 	firstDay := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
 	lastDay := firstDay.AddDate(1, 0, -1)
 	endDate := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 23, 59, 59, 0, now.Location())
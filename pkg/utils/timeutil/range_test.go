@@ -1,11 +1,14 @@
 package timeutil
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 func TestGetCurrentTimeRangeDateString(t *testing.T) {
@@ -28,6 +31,33 @@ func TestGetCurrentRangeTime(t *testing.T) {
 	fmt.Println(GetLastYearRangeTime())
 }
 
+func TestGetRangeTimeContext(t *testing.T) {
+	ctx := clock.NewContext(context.Background(), clock.Fixed(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)))
+	day := func(y int, m time.Month, d, h, minute, sec int) time.Time {
+		return time.Date(y, m, d, h, minute, sec, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		fn         func(context.Context) (time.Time, time.Time)
+		start, end time.Time
+	}{
+		{"today", GetTodayRangeTimeContext, day(2025, 3, 1, 0, 0, 0), day(2025, 3, 1, 23, 59, 59)},
+		{"yesterday", GetYesterdayRangeTimeContext, day(2025, 2, 28, 0, 0, 0), day(2025, 2, 28, 23, 59, 59)},
+		{"current month", GetCurrentMonthRangeTimeContext, day(2025, 3, 1, 0, 0, 0), day(2025, 3, 31, 23, 59, 59)},
+		{"last month", GetLastMonthRangeTimeContext, day(2025, 2, 1, 0, 0, 0), day(2025, 2, 28, 23, 59, 59)},
+		{"current year", GetCurrentYearRangeTimeContext, day(2025, 1, 1, 0, 0, 0), day(2025, 12, 31, 23, 59, 59)},
+		{"last year", GetLastYearRangeTimeContext, day(2024, 1, 1, 0, 0, 0), day(2024, 12, 31, 23, 59, 59)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.fn(ctx)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+}
+
 func TestGetCurrentTimeRangeTimeString(t *testing.T) {
 	fmt.Println(GetTodayRangeTimeString())
 	fmt.Println(GetCurrentMonthRangeTimeString())
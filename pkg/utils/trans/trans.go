@@ -1,9 +1,12 @@
 package trans
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 func String(a string) *string {
@@ -165,8 +168,13 @@ func Time(a time.Time) *time.Time {
 }
 
 func TimeValue(a *time.Time) time.Time {
+	return TimeValueContext(context.Background(), a)
+}
+
+// TimeValueContext a 为 nil 时返回 ctx 中时钟的当前时间
+func TimeValueContext(ctx context.Context, a *time.Time) time.Time {
 	if a == nil {
-		return time.Now()
+		return clock.NowContext(ctx)
 	}
 	return *a
 }
@@ -1,11 +1,14 @@
 package trans
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/heyinLab/common/pkg/utils/clock"
 )
 
 func Test_Trans(t *testing.T) {
@@ -169,6 +172,15 @@ func Test_Trans(t *testing.T) {
 	assert.Equal(t, time.Now(), TimeValue(nil))
 }
 
+func TestTimeValueContext(t *testing.T) {
+	now := time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)
+	ctx := clock.NewContext(context.Background(), clock.Fixed(now))
+
+	assert.Equal(t, now, TimeValueContext(ctx, nil))
+	tm := now.Add(-time.Hour)
+	assert.Equal(t, tm, TimeValueContext(ctx, &tm))
+}
+
 func TestUUID(t *testing.T) {
 	t.Run("ToUuidPtr_NilString", func(t *testing.T) {
 		var str *string
@@ -0,0 +1,89 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Fake 确定性的本地翻译器，不访问网络，用于测试与离线环境
+//
+// 译文格式为 "[targetLang] 原文"，占位符原样保留；可以通过 Set 预置指定的译文。
+type Fake struct {
+	mu      sync.Mutex
+	fixed   map[Key]string
+	calls   int
+	sources int
+	err     error
+}
+
+// NewFake 创建本地翻译器
+func NewFake() *Fake {
+	return &Fake{fixed: make(map[Key]string)}
+}
+
+// Set 预置译文
+func (f *Fake) Set(source, sourceLang, targetLang, translation string) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fixed[NewKey(source, sourceLang, targetLang)] = translation
+	return f
+}
+
+// FailWith 之后的翻译均返回 err，传入 nil 恢复
+func (f *Fake) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+// Calls 翻译调用次数，批量翻译计为一次
+func (f *Fake) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls
+}
+
+// Sources 已翻译的原文条数
+func (f *Fake) Sources() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.sources
+}
+
+func (f *Fake) Translate(source, sourceLang, targetLang string) (string, error) {
+	results, err := f.TranslateBatch(context.Background(), []string{source}, sourceLang, targetLang)
+	if err != nil {
+		return "", err
+	}
+	return results[0], nil
+}
+
+func (f *Fake) TranslateBatch(ctx context.Context, sources []string, sourceLang, targetLang string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.sources += len(sources)
+
+	results := make([]string, len(sources))
+	for i, source := range sources {
+		if translation, ok := f.fixed[NewKey(source, sourceLang, targetLang)]; ok {
+			results[i] = translation
+			continue
+		}
+		results[i] = fmt.Sprintf("[%s] %s", targetLang, source)
+	}
+	return results, nil
+}
@@ -0,0 +1,123 @@
+package google
+
+import (
+	"context"
+	"fmt"
+
+	translateV2 "cloud.google.com/go/translate"
+	translateV3 "cloud.google.com/go/translate/apiv3"
+	"cloud.google.com/go/translate/apiv3/translatepb"
+	"golang.org/x/text/language"
+	"google.golang.org/api/option"
+
+	"github.com/heyinLab/common/pkg/utils/translator"
+)
+
+var _ translator.BatchTranslator = (*Translator)(nil)
+
+// TranslateBatch 批量翻译，v2、v3 一次请求翻译全部文本，v1 逐条翻译
+func (t *Translator) TranslateBatch(ctx context.Context, sources []string, sourceLang, targetLang string) ([]string, error) {
+	switch t.options.version {
+	default:
+		fallthrough
+	case "v1":
+		results := make([]string, len(sources))
+		for i, source := range sources {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			text, err := t.TranslateV1(source, sourceLang, targetLang)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = text
+		}
+		return results, nil
+
+	case "v2":
+		return t.translateBatchV2(ctx, sources, sourceLang, targetLang)
+
+	case "v3":
+		return t.translateBatchV3(ctx, sources, sourceLang, targetLang)
+	}
+}
+
+func (t *Translator) translateBatchV2(ctx context.Context, sources []string, sourceLang, targetLang string) ([]string, error) {
+	target, err := language.Parse(targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := optionsV2(sourceLang)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.clientV2 == nil {
+		client, err := translateV2.NewClient(ctx, option.WithAPIKey(t.options.apiKey))
+		if err != nil {
+			return nil, err
+		}
+		t.clientV2 = client
+	}
+
+	resp, err := t.clientV2.Translate(ctx, sources, target, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) != len(sources) {
+		return nil, fmt.Errorf("Translate: expected %d translations, got %d", len(sources), len(resp))
+	}
+
+	results := make([]string, len(resp))
+	for i, r := range resp {
+		results[i] = r.Text
+	}
+	return results, nil
+}
+
+// optionsV2 v2 接口的翻译选项，始终使用纯文本格式，否则 v2 默认按 HTML 处理并返回转义后的文本
+func optionsV2(sourceLang string) (*translateV2.Options, error) {
+	opts := &translateV2.Options{Format: translateV2.Text}
+	if sourceLang != "" && sourceLang != "auto" {
+		source, err := language.Parse(sourceLang)
+		if err != nil {
+			return nil, err
+		}
+		opts.Source = source
+	}
+	return opts, nil
+}
+
+func (t *Translator) translateBatchV3(ctx context.Context, sources []string, sourceLang, targetLang string) ([]string, error) {
+	if t.clientV3 == nil {
+		client, err := translateV3.NewTranslationClient(ctx, option.WithAPIKey(t.options.apiKey))
+		if err != nil {
+			return nil, fmt.Errorf("NewTranslationClient: %w", err)
+		}
+		t.clientV3 = client
+	}
+
+	req := &translatepb.TranslateTextRequest{
+		TargetLanguageCode: targetLang,
+		MimeType:           "text/plain",
+		Contents:           sources,
+	}
+	if sourceLang != "auto" {
+		req.SourceLanguageCode = sourceLang
+	}
+
+	resp, err := t.clientV3.TranslateText(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("TranslateText: %w", err)
+	}
+	if len(resp.GetTranslations()) != len(sources) {
+		return nil, fmt.Errorf("TranslateText: expected %d translations, got %d", len(sources), len(resp.GetTranslations()))
+	}
+
+	results := make([]string, len(sources))
+	for i, translation := range resp.GetTranslations() {
+		results[i] = translation.GetTranslatedText()
+	}
+	return results, nil
+}
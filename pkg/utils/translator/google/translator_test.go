@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	translateV2 "cloud.google.com/go/translate"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/text/language"
 )
@@ -96,3 +97,16 @@ func TestLanguageParse(t *testing.T) {
 	fmt.Println(lang)
 	fmt.Println(language.English.String())
 }
+
+func TestOptionsV2(t *testing.T) {
+	opts, err := optionsV2("auto")
+	assert.Nil(t, err)
+	assert.Equal(t, &translateV2.Options{Format: translateV2.Text}, opts)
+
+	opts, err = optionsV2("en")
+	assert.Nil(t, err)
+	assert.Equal(t, &translateV2.Options{Source: language.English, Format: translateV2.Text}, opts)
+
+	_, err = optionsV2("not a language")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,141 @@
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Key 翻译记忆的键
+type Key struct {
+	Text       string // 原文
+	SourceLang string // 源语言
+	TargetLang string // 目标语言
+}
+
+// NewKey 创建翻译记忆的键，语言代码统一为小写并使用连字符
+func NewKey(text, sourceLang, targetLang string) Key {
+	return Key{Text: text, SourceLang: normalizeLang(sourceLang), TargetLang: normalizeLang(targetLang)}
+}
+
+// Hash 键的摘要，用于持久化存储
+func (k Key) Hash() string {
+	sum := sha256.Sum256([]byte(k.SourceLang + "\x00" + k.TargetLang + "\x00" + k.Text))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeLang 统一语言代码格式，如 zh_CN => zh-cn
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// Memory 翻译记忆（缓存），保存已翻译的内容，避免重复调用翻译服务
+type Memory interface {
+	// Get 查询译文
+	Get(ctx context.Context, key Key) (string, bool, error)
+	// Set 保存译文
+	Set(ctx context.Context, key Key, translation string) error
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MapMemory 进程内翻译记忆
+type MapMemory struct {
+	mu    sync.RWMutex
+	items map[Key]string
+}
+
+// NewMapMemory 创建进程内翻译记忆
+func NewMapMemory() *MapMemory {
+	return &MapMemory{items: make(map[Key]string)}
+}
+
+func (m *MapMemory) Get(_ context.Context, key Key) (string, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	translation, ok := m.items[key]
+	return translation, ok, nil
+}
+
+func (m *MapMemory) Set(_ context.Context, key Key, translation string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items[key] = translation
+	return nil
+}
+
+// Len 已保存的条数
+func (m *MapMemory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.items)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// SQLiteMemory 基于 SQLite 文件的持久化翻译记忆
+type SQLiteMemory struct {
+	db *sql.DB
+}
+
+// NewSQLiteMemory 打开或创建翻译记忆数据库文件
+func NewSQLiteMemory(path string) (*SQLiteMemory, error) {
+	if path == "" {
+		return nil, errors.New("translator: memory path is empty")
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`
+CREATE TABLE IF NOT EXISTS translation_memory
+(
+    hash        TEXT PRIMARY KEY,
+    source_lang TEXT,
+    target_lang TEXT,
+    source      TEXT,
+    translation TEXT
+);
+`)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &SQLiteMemory{db: db}, nil
+}
+
+func (m *SQLiteMemory) Get(ctx context.Context, key Key) (string, bool, error) {
+	var translation string
+	err := m.db.QueryRowContext(ctx, "SELECT translation FROM translation_memory WHERE hash = ? LIMIT 1;", key.Hash()).Scan(&translation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return translation, true, nil
+}
+
+func (m *SQLiteMemory) Set(ctx context.Context, key Key, translation string) error {
+	_, err := m.db.ExecContext(ctx, `INSERT INTO translation_memory (hash, source_lang, target_lang, source, translation) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(hash) DO UPDATE SET translation = excluded.translation;`,
+		key.Hash(), key.SourceLang, key.TargetLang, key.Text, translation)
+	return err
+}
+
+// Close 关闭数据库
+func (m *SQLiteMemory) Close() error {
+	return m.db.Close()
+}
@@ -0,0 +1,203 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrPlaceholderLost 翻译结果丢失了受保护的占位符
+var ErrPlaceholderLost = errors.New("translator: placeholder lost in translation")
+
+// DefaultProtectPatterns 默认受保护的内容：HTML 标签与实体、{{var}}、${var}、{var}、printf 格式符
+var DefaultProtectPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`<[^<>]+>`),
+	regexp.MustCompile(`&(?:[a-zA-Z]+|#[0-9]+|#x[0-9a-fA-F]+);`),
+	regexp.MustCompile(`\{\{[^{}]*\}\}`),
+	regexp.MustCompile(`\$\{[^{}]*\}`),
+	regexp.MustCompile(`\{[A-Za-z0-9_.]+\}`),
+	regexp.MustCompile(`%(?:\d+\$)?[-+ #0]*\d*(?:\.\d+)?[sdvfgqxXt%]`),
+}
+
+// 占位符使用不常见的括号，翻译引擎一般会原样保留
+const (
+	placeholderOpen  = "⟦"
+	placeholderClose = "⟧"
+)
+
+var placeholderRegexp = regexp.MustCompile(`⟦\s*(\d+)\s*⟧`)
+
+// protector 翻译前将受保护内容替换为占位符，翻译后还原
+type protector struct {
+	patterns []*regexp.Regexp
+	glossary *Glossary
+}
+
+// span 受保护的区间
+type span struct {
+	start, end int
+	value      string // 还原后的内容
+}
+
+// protect 替换受保护内容，返回替换后的文本与占位符对应的还原内容
+func (p *protector) protect(text, targetLang string) (string, []string) {
+	var spans []span
+	for _, re := range p.patterns {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			spans = append(spans, span{start: loc[0], end: loc[1], value: text[loc[0]:loc[1]]})
+		}
+	}
+	if p.glossary != nil {
+		spans = append(spans, p.glossary.spans(text, targetLang)...)
+	}
+	if len(spans) == 0 {
+		return text, nil
+	}
+
+	// 先出现、较长的区间优先，丢弃重叠的区间
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+
+	var sb strings.Builder
+	values := make([]string, 0, len(spans))
+	last := 0
+	for _, s := range spans {
+		if s.start < last {
+			continue
+		}
+		sb.WriteString(text[last:s.start])
+		sb.WriteString(placeholderOpen + strconv.Itoa(len(values)) + placeholderClose)
+		values = append(values, s.value)
+		last = s.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), values
+}
+
+// restore 还原占位符，任一占位符丢失时返回 ErrPlaceholderLost
+func (p *protector) restore(text string, values []string) (string, error) {
+	if len(values) == 0 {
+		return text, nil
+	}
+
+	seen := make([]bool, len(values))
+	result := placeholderRegexp.ReplaceAllStringFunc(text, func(m string) string {
+		idx, err := strconv.Atoi(placeholderRegexp.FindStringSubmatch(m)[1])
+		if err != nil || idx >= len(values) {
+			return m
+		}
+		seen[idx] = true
+		return values[idx]
+	})
+	for i, ok := range seen {
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrPlaceholderLost, values[i])
+		}
+	}
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// Glossary 术语表
+//
+// 术语按目标语言替换为指定译法，不翻译的词（品牌名、产品名等）原样保留。
+// 匹配区分大小写，字母数字组成的术语要求整词匹配。
+type Glossary struct {
+	terms map[string]map[string]string // term -> targetLang -> translation
+	keep  map[string]struct{}
+}
+
+// NewGlossary 创建术语表
+func NewGlossary() *Glossary {
+	return &Glossary{
+		terms: make(map[string]map[string]string),
+		keep:  make(map[string]struct{}),
+	}
+}
+
+// Add 添加术语在目标语言下的译法
+func (g *Glossary) Add(term, targetLang, translation string) *Glossary {
+	if term == "" {
+		return g
+	}
+	if g.terms[term] == nil {
+		g.terms[term] = make(map[string]string)
+	}
+	g.terms[term][normalizeLang(targetLang)] = translation
+	return g
+}
+
+// Keep 添加不翻译的词
+func (g *Glossary) Keep(terms ...string) *Glossary {
+	for _, term := range terms {
+		if term != "" {
+			g.keep[term] = struct{}{}
+		}
+	}
+	return g
+}
+
+// spans 查找文本中的术语
+func (g *Glossary) spans(text, targetLang string) []span {
+	targetLang = normalizeLang(targetLang)
+
+	var spans []span
+	find := func(term, value string) {
+		for offset := 0; offset < len(text); {
+			idx := strings.Index(text[offset:], term)
+			if idx < 0 {
+				return
+			}
+			start := offset + idx
+			end := start + len(term)
+			if isWordBoundary(text, start, end, term) {
+				spans = append(spans, span{start: start, end: end, value: value})
+			}
+			offset = end
+		}
+	}
+
+	for term := range g.keep {
+		find(term, term)
+	}
+	for term, translations := range g.terms {
+		if translation, ok := translations[targetLang]; ok {
+			find(term, translation)
+		}
+	}
+	return spans
+}
+
+// isWordBoundary 字母数字开头或结尾的术语要求与相邻字符之间是词边界
+func isWordBoundary(text string, start, end int, term string) bool {
+	first, _ := utf8.DecodeRuneInString(term)
+	if isWordRune(first) && start > 0 {
+		prev, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isWordRune(prev) {
+			return false
+		}
+	}
+	last, _ := utf8.DecodeLastRuneInString(term)
+	if isWordRune(last) && end < len(text) {
+		next, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordRune 拉丁字母与数字，中日韩文字没有词边界
+func isWordRune(r rune) bool {
+	return r < unicode.MaxLatin1 && (unicode.IsLetter(r) || unicode.IsDigit(r)) || r == '_'
+}
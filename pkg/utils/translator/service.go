@@ -0,0 +1,202 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"golang.org/x/time/rate"
+)
+
+// DefaultBatchSize 每次调用翻译服务的最大条数
+const DefaultBatchSize = 50
+
+type serviceOptions struct {
+	memory    Memory
+	glossary  *Glossary
+	patterns  []*regexp.Regexp
+	limiter   *rate.Limiter
+	batchSize int
+	logger    *log.Helper
+}
+
+// ServiceOption 翻译服务配置项
+type ServiceOption func(*serviceOptions)
+
+// WithMemory 设置翻译记忆，命中时不再调用翻译服务
+func WithMemory(memory Memory) ServiceOption {
+	return func(o *serviceOptions) { o.memory = memory }
+}
+
+// WithGlossary 设置术语表
+func WithGlossary(glossary *Glossary) ServiceOption {
+	return func(o *serviceOptions) { o.glossary = glossary }
+}
+
+// WithProtectPatterns 设置受保护内容的正则，默认为 DefaultProtectPatterns，传入空值表示不保护
+func WithProtectPatterns(patterns ...*regexp.Regexp) ServiceOption {
+	return func(o *serviceOptions) { o.patterns = patterns }
+}
+
+// WithRateLimit 限制调用翻译服务的频率，perSecond 为每秒调用次数（一次批量调用计为一次）
+func WithRateLimit(perSecond float64, burst int) ServiceOption {
+	return func(o *serviceOptions) {
+		if burst < 1 {
+			burst = 1
+		}
+		o.limiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+	}
+}
+
+// WithBatchSize 设置每次调用翻译服务的最大条数
+func WithBatchSize(size int) ServiceOption {
+	return func(o *serviceOptions) {
+		if size > 0 {
+			o.batchSize = size
+		}
+	}
+}
+
+// WithLogger 设置日志
+func WithLogger(logger log.Logger) ServiceOption {
+	return func(o *serviceOptions) { o.logger = log.NewHelper(log.With(logger, "module", "translator")) }
+}
+
+// Service 翻译服务
+//
+// 在底层翻译器之上提供批量翻译、翻译记忆、术语表、HTML 与占位符保护及限流，
+// 本身也实现了 Translator 与 BatchTranslator，可以直接替换原有翻译器。
+//
+// 示例:
+//
+//	svc := translator.NewService(google.NewTranslator(google.WithVersion("v3"), google.WithApiKey(key)),
+//		translator.WithMemory(memory),
+//		translator.WithGlossary(translator.NewGlossary().Keep("HeyIn").Add("套餐", "en", "Plan")),
+//		translator.WithRateLimit(5, 1),
+//	)
+//	texts, err := svc.TranslateBatch(ctx, []string{"你好", "<b>{name}</b> 的套餐"}, "zh-CN", "en")
+type Service struct {
+	backend   Translator
+	opts      serviceOptions
+	protector *protector
+}
+
+// NewService 创建翻译服务
+func NewService(backend Translator, opts ...ServiceOption) *Service {
+	o := serviceOptions{
+		patterns:  DefaultProtectPatterns,
+		batchSize: DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = log.NewHelper(log.With(log.GetLogger(), "module", "translator"))
+	}
+
+	return &Service{
+		backend:   backend,
+		opts:      o,
+		protector: &protector{patterns: o.patterns, glossary: o.glossary},
+	}
+}
+
+// Translate 翻译单条文本
+func (s *Service) Translate(source, sourceLang, targetLang string) (string, error) {
+	results, err := s.TranslateBatch(context.Background(), []string{source}, sourceLang, targetLang)
+	if err != nil {
+		return "", err
+	}
+	return results[0], nil
+}
+
+// TranslateBatch 批量翻译
+//
+// 空白文本与源、目标语言相同时原样返回；重复的文本只翻译一次；
+// 翻译记忆未命中的文本按批次调用翻译服务，成功后写入翻译记忆。
+func (s *Service) TranslateBatch(ctx context.Context, sources []string, sourceLang, targetLang string) ([]string, error) {
+	results := make([]string, len(sources))
+	if len(sources) == 0 {
+		return results, nil
+	}
+
+	sameLang := normalizeLang(sourceLang) == normalizeLang(targetLang)
+
+	// 待翻译的文本，相同文本只翻译一次
+	pending := make(map[string][]int)
+	var order []string
+	for i, source := range sources {
+		if sameLang || strings.TrimSpace(source) == "" {
+			results[i] = source
+			continue
+		}
+
+		if s.opts.memory != nil {
+			translation, ok, err := s.opts.memory.Get(ctx, NewKey(source, sourceLang, targetLang))
+			if err != nil {
+				s.opts.logger.WithContext(ctx).Warnf("查询翻译记忆失败: %v", err)
+			} else if ok {
+				results[i] = translation
+				continue
+			}
+		}
+
+		if _, ok := pending[source]; !ok {
+			order = append(order, source)
+		}
+		pending[source] = append(pending[source], i)
+	}
+
+	for start := 0; start < len(order); start += s.opts.batchSize {
+		end := min(start+s.opts.batchSize, len(order))
+		translations, err := s.translateChunk(ctx, order[start:end], sourceLang, targetLang)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, source := range order[start:end] {
+			for _, i := range pending[source] {
+				results[i] = translations[j]
+			}
+			if s.opts.memory != nil {
+				if err = s.opts.memory.Set(ctx, NewKey(source, sourceLang, targetLang), translations[j]); err != nil {
+					s.opts.logger.WithContext(ctx).Warnf("保存翻译记忆失败: %v", err)
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// translateChunk 保护占位符后调用翻译服务，并还原占位符
+func (s *Service) translateChunk(ctx context.Context, sources []string, sourceLang, targetLang string) ([]string, error) {
+	protected := make([]string, len(sources))
+	values := make([][]string, len(sources))
+	for i, source := range sources {
+		protected[i], values[i] = s.protector.protect(source, targetLang)
+	}
+
+	if s.opts.limiter != nil {
+		if err := s.opts.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	translations, err := Batch(ctx, s.backend, protected, sourceLang, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	if len(translations) != len(sources) {
+		return nil, fmt.Errorf("translator: expected %d translations, got %d", len(sources), len(translations))
+	}
+
+	for i := range translations {
+		if translations[i], err = s.protector.restore(translations[i], values[i]); err != nil {
+			return nil, fmt.Errorf("translate %q: %w", sources[i], err)
+		}
+	}
+	return translations, nil
+}
@@ -0,0 +1,126 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceTranslateBatch(t *testing.T) {
+	fake := NewFake()
+	memory := NewMapMemory()
+	svc := NewService(fake, WithMemory(memory), WithBatchSize(2))
+
+	results, err := svc.TranslateBatch(context.Background(), []string{"你好", "世界", "你好", " ", "再见"}, "zh-CN", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"[en] 你好", "[en] 世界", "[en] 你好", " ", "[en] 再见"}, results)
+	// 去重后 3 条，每批 2 条
+	assert.Equal(t, 2, fake.Calls())
+	assert.Equal(t, 3, fake.Sources())
+	assert.Equal(t, 3, memory.Len())
+
+	// 命中翻译记忆，语言代码大小写不敏感
+	results, err = svc.TranslateBatch(context.Background(), []string{"世界", "你好"}, "zh_cn", "EN")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"[en] 世界", "[en] 你好"}, results)
+	assert.Equal(t, 2, fake.Calls())
+
+	// 源语言与目标语言相同时不翻译
+	text, err := svc.Translate("你好", "en", "en")
+	require.NoError(t, err)
+	assert.Equal(t, "你好", text)
+	assert.Equal(t, 2, fake.Calls())
+
+	fake.FailWith(errors.New("quota exceeded"))
+	_, err = svc.TranslateBatch(context.Background(), []string{"新的"}, "zh-CN", "en")
+	assert.Error(t, err)
+	assert.Equal(t, 3, memory.Len())
+}
+
+func TestServiceProtect(t *testing.T) {
+	fake := NewFake().
+		Set("⟦0⟧Hello⟦1⟧, ⟦2⟧ has ⟦3⟧ items", "en", "es", "⟦0⟧Hola⟦1⟧, ⟦ 2 ⟧ tiene ⟦3⟧ artículos")
+	svc := NewService(fake)
+
+	text, err := svc.Translate("<b>Hello</b>, {name} has %d items", "en", "es")
+	require.NoError(t, err)
+	assert.Equal(t, "<b>Hola</b>, {name} tiene %d artículos", text)
+
+	text, err = svc.Translate("Hi {{user.name}}, see ${url} &amp; more", "en", "fr")
+	require.NoError(t, err)
+	assert.Equal(t, "[fr] Hi {{user.name}}, see ${url} &amp; more", text)
+
+	// 翻译引擎丢失占位符
+	fake.Set("⟦0⟧ bye", "en", "de", "tschüss")
+	_, err = svc.Translate("{name} bye", "en", "de")
+	assert.ErrorIs(t, err, ErrPlaceholderLost)
+}
+
+func TestServiceGlossary(t *testing.T) {
+	glossary := NewGlossary().
+		Keep("HeyIn", "Pro").
+		Add("套餐", "en", "Plan").
+		Add("套餐", "ja", "プラン")
+	svc := NewService(NewFake(), WithGlossary(glossary))
+
+	text, err := svc.Translate("HeyIn Pro 套餐", "zh-CN", "en")
+	require.NoError(t, err)
+	assert.Equal(t, "[en] HeyIn Pro Plan", text)
+
+	text, err = svc.Translate("HeyIn Pro 套餐", "zh-CN", "ja")
+	require.NoError(t, err)
+	assert.Equal(t, "[ja] HeyIn Pro プラン", text)
+
+	// 整词匹配，Product 中的 Pro 不受保护
+	p := &protector{glossary: glossary}
+	protected, values := p.protect("Product Pro", "en")
+	assert.Equal(t, "Product ⟦0⟧", protected)
+	assert.Equal(t, []string{"Pro"}, values)
+}
+
+func TestServiceRateLimit(t *testing.T) {
+	fake := NewFake()
+	svc := NewService(fake, WithBatchSize(1), WithRateLimit(20, 1))
+
+	start := time.Now()
+	_, err := svc.TranslateBatch(context.Background(), []string{"a", "b", "c"}, "en", "de")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, 3, fake.Calls())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = svc.TranslateBatch(ctx, []string{"d"}, "en", "de")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSQLiteMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.db")
+	memory, err := NewSQLiteMemory(path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := NewKey("你好", "zh-CN", "en")
+	require.NoError(t, memory.Set(ctx, key, "Hello"))
+	require.NoError(t, memory.Set(ctx, key, "Hi"))
+	require.NoError(t, memory.Close())
+
+	// 重新打开后仍然可以命中
+	memory, err = NewSQLiteMemory(path)
+	require.NoError(t, err)
+	defer memory.Close()
+
+	text, ok, err := memory.Get(ctx, NewKey("你好", "zh_CN", "EN"))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Hi", text)
+
+	_, ok, err = memory.Get(ctx, NewKey("你好", "zh-CN", "ja"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
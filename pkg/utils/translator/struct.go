@@ -0,0 +1,119 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// FillStruct 补全多语言 Struct 中缺失的语言
+//
+// i18n 字段的格式为 {"zh-CN": {"name": "...", "description": "..."}, "en": {...}}，
+// 以 sourceLang 的内容为原文，将 targetLangs 中缺失或为空的字符串字段翻译后写入，
+// 已有的译文不会被覆盖，非字符串字段直接复制。返回新翻译的条数。
+//
+// 翻译失败时返回错误，s 保持不变：补全在已有语言的副本上进行，全部语言翻译完成后才写回 s。
+func FillStruct(ctx context.Context, t Translator, s *structpb.Struct, sourceLang string, targetLangs ...string) (int, error) {
+	if s == nil {
+		return 0, nil
+	}
+	source, ok := s.GetFields()[sourceLang]
+	if !ok || source == nil {
+		return 0, fmt.Errorf("translator: source language %q not found in struct", sourceLang)
+	}
+
+	filled := 0
+	targets := make(map[string]*structpb.Value, len(targetLangs))
+	for _, targetLang := range targetLangs {
+		if targetLang == "" || targetLang == sourceLang {
+			continue
+		}
+
+		existing := s.Fields[targetLang]
+		if existing != nil {
+			existing = proto.Clone(existing).(*structpb.Value)
+		}
+		var jobs []fillJob
+		target := fillValue(source, existing, &jobs)
+		if len(jobs) > 0 {
+			sources := make([]string, len(jobs))
+			for i, job := range jobs {
+				sources[i] = job.source
+			}
+			translations, err := Batch(ctx, t, sources, sourceLang, targetLang)
+			if err != nil {
+				return 0, fmt.Errorf("translate %s: %w", targetLang, err)
+			}
+			for i, job := range jobs {
+				job.set(translations[i])
+			}
+			filled += len(jobs)
+		}
+		targets[targetLang] = target
+	}
+	for targetLang, target := range targets {
+		s.Fields[targetLang] = target
+	}
+	return filled, nil
+}
+
+// fillJob 待翻译的字符串，set 将译文写回目标位置
+type fillJob struct {
+	source string
+	set    func(string)
+}
+
+// fillValue 以 source 为模板补全 target，返回补全后的值
+func fillValue(source, target *structpb.Value, jobs *[]fillJob) *structpb.Value {
+	switch src := source.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		if target.GetStringValue() != "" {
+			return target
+		}
+		if strings.TrimSpace(src.StringValue) == "" {
+			return structpb.NewStringValue(src.StringValue)
+		}
+		value := structpb.NewStringValue("")
+		*jobs = append(*jobs, fillJob{source: src.StringValue, set: func(s string) {
+			value.Kind = &structpb.Value_StringValue{StringValue: s}
+		}})
+		return value
+
+	case *structpb.Value_StructValue:
+		dst := target.GetStructValue()
+		if dst == nil {
+			if target != nil && target.GetKind() != nil {
+				if _, isNull := target.GetKind().(*structpb.Value_NullValue); !isNull {
+					return target
+				}
+			}
+			dst = &structpb.Struct{Fields: make(map[string]*structpb.Value)}
+		}
+		if dst.Fields == nil {
+			dst.Fields = make(map[string]*structpb.Value)
+		}
+		for key, value := range src.StructValue.GetFields() {
+			dst.Fields[key] = fillValue(value, dst.Fields[key], jobs)
+		}
+		return structpb.NewStructValue(dst)
+
+	case *structpb.Value_ListValue:
+		if len(target.GetListValue().GetValues()) > 0 {
+			return target
+		}
+		values := make([]*structpb.Value, len(src.ListValue.GetValues()))
+		for i, value := range src.ListValue.GetValues() {
+			values[i] = fillValue(value, nil, jobs)
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values})
+
+	default:
+		if target != nil && target.GetKind() != nil {
+			return target
+		}
+		return proto.Clone(source).(*structpb.Value)
+	}
+}
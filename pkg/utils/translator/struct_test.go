@@ -0,0 +1,86 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestFillStruct(t *testing.T) {
+	s, err := structpb.NewStruct(map[string]interface{}{
+		"zh-CN": map[string]interface{}{
+			"name":        "专业版",
+			"description": "适合 {count} 人的团队",
+			"features":    []interface{}{"无限存储", "优先支持"},
+			"sort":        1,
+		},
+		"en": map[string]interface{}{
+			"name": "Professional",
+		},
+	})
+	require.NoError(t, err)
+
+	fake := NewFake()
+	filled, err := FillStruct(context.Background(), NewService(fake), s, "zh-CN", "en", "ja", "zh-CN")
+	require.NoError(t, err)
+	assert.Equal(t, 7, filled)
+	// 每种语言一次批量调用
+	assert.Equal(t, 2, fake.Calls())
+
+	m := s.AsMap()
+	en := m["en"].(map[string]interface{})
+	assert.Equal(t, "Professional", en["name"])
+	assert.Equal(t, "[en] 适合 {count} 人的团队", en["description"])
+	assert.Equal(t, []interface{}{"[en] 无限存储", "[en] 优先支持"}, en["features"])
+	assert.Equal(t, float64(1), en["sort"])
+
+	ja := m["ja"].(map[string]interface{})
+	assert.Equal(t, "[ja] 专业版", ja["name"])
+
+	// 已补全的语言不再翻译
+	filled, err = FillStruct(context.Background(), fake, s, "zh-CN", "en", "ja")
+	require.NoError(t, err)
+	assert.Equal(t, 0, filled)
+	assert.Equal(t, 2, fake.Calls())
+
+	_, err = FillStruct(context.Background(), fake, s, "fr", "en")
+	assert.Error(t, err)
+}
+
+func TestFillStruct_Error(t *testing.T) {
+	s, err := structpb.NewStruct(map[string]interface{}{
+		"zh-CN": map[string]interface{}{
+			"name":        "专业版",
+			"description": "适合团队",
+			"tags":        map[string]interface{}{"level": "高级"},
+		},
+		"en": map[string]interface{}{
+			"name": "Professional",
+			"tags": map[string]interface{}{},
+		},
+	})
+	require.NoError(t, err)
+	original := proto.Clone(s)
+
+	fake := NewFake()
+	fake.FailWith(errors.New("quota exceeded"))
+
+	// 翻译失败时已有语言不被改动，也不会写入部分补全的语言
+	filled, err := FillStruct(context.Background(), fake, s, "zh-CN", "en", "ja")
+	require.Error(t, err)
+	assert.Zero(t, filled)
+	assert.True(t, proto.Equal(original, s), "struct modified: %v", s.AsMap())
+
+	fake.FailWith(nil)
+	filled, err = FillStruct(context.Background(), fake, s, "zh-CN", "en")
+	require.NoError(t, err)
+	assert.Equal(t, 2, filled)
+	en := s.AsMap()["en"].(map[string]interface{})
+	assert.Equal(t, "Professional", en["name"])
+	assert.Equal(t, map[string]interface{}{"level": "[en] 高级"}, en["tags"])
+}
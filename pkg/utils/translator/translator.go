@@ -1,7 +1,37 @@
 package translator
 
+import "context"
+
 // Translator 翻译器
 type Translator interface {
 	// Translate 翻译
 	Translate(source, sourceLang, targetLang string) (string, error)
 }
+
+// BatchTranslator 支持批量翻译的翻译器
+type BatchTranslator interface {
+	Translator
+
+	// TranslateBatch 批量翻译，返回结果与 sources 一一对应
+	TranslateBatch(ctx context.Context, sources []string, sourceLang, targetLang string) ([]string, error)
+}
+
+// Batch 批量翻译，翻译器不支持批量时逐条翻译
+func Batch(ctx context.Context, t Translator, sources []string, sourceLang, targetLang string) ([]string, error) {
+	if bt, ok := t.(BatchTranslator); ok {
+		return bt.TranslateBatch(ctx, sources, sourceLang, targetLang)
+	}
+
+	results := make([]string, len(sources))
+	for i, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		text, err := t.Translate(source, sourceLang, targetLang)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = text
+	}
+	return results, nil
+}